@@ -46,6 +46,10 @@ const (
 	OperationRemoveBroker CruiseControlTaskOperation = "remove_broker"
 	// OperationRebalance means a Cruise Control rebalance operation
 	OperationRebalance CruiseControlTaskOperation = "rebalance"
+	// OperationRemoveDisks means a Cruise Control remove_disks operation
+	OperationRemoveDisks CruiseControlTaskOperation = "remove_disks"
+	// OperationDemoteBroker means a Cruise Control demote_broker operation
+	OperationDemoteBroker CruiseControlTaskOperation = "demote_broker"
 	// OperationStatus means a Cruise Control status operation
 	OperationStatus CruiseControlTaskOperation = "status"
 	// KafkaAccessTypeRead states that a user wants consume access to a topic
@@ -66,6 +70,15 @@ const (
 	TLSJKSKeyStore string = "keystore.jks"
 	// TLSJKSTrustStore is where a JKS truststore is stored in a user secret when requested
 	TLSJKSTrustStore string = "truststore.jks"
+	// TLSPKCS12KeyStore is where a PKCS#12 keystore is stored in a user secret when requested
+	TLSPKCS12KeyStore string = "keystore.p12"
+	// TLSPKCS12TrustStore is where a PKCS#12 truststore is stored in a user secret when requested
+	TLSPKCS12TrustStore string = "truststore.p12"
+	// TLSPEMKeyStore is where the PEM bundle of the certificate chain and the encrypted private key is stored
+	// in a user secret when requested
+	TLSPEMKeyStore string = "keystore.pem"
+	// TLSPEMTrustStore is where the PEM encoded CA certificates are stored in a user secret when the PEM bundle is requested
+	TLSPEMTrustStore string = "truststore.pem"
 	// CoreCACertKey is where ca certificates are stored in user certificates
 	CoreCACertKey string = "ca.crt"
 	// CaChainPem is where CA certificate(s) are stored as a chain for user secret
@@ -82,6 +95,6 @@ const (
 	PeerCertKey string = "peerCert"
 	// PeerPrivateKeyKey stores the peer private key
 	PeerPrivateKeyKey string = "peerKey"
-	// PasswordKey stores the JKS password
+	// PasswordKey stores the password of the keystores and of the private key in the PEM bundle
 	PasswordKey string = "password"
 )
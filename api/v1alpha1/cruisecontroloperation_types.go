@@ -29,6 +29,11 @@ const (
 	ErrorPolicyRetry ErrorPolicyType = "retry"
 	// DefaultRetryBackOffDurationSec defines the time between retries of the failed tasks.
 	DefaultRetryBackOffDurationSec = 30
+	// PreemptionPolicyNever means the operation waits for the running operation to finish.
+	PreemptionPolicyNever PreemptionPolicyType = "Never"
+	// PreemptionPolicyPreemptLowerPriority means the operation stops the running operation when it has lower priority
+	// and the stopped operation is requeued for execution.
+	PreemptionPolicyPreemptLowerPriority PreemptionPolicyType = "PreemptLowerPriority"
 )
 
 //+kubebuilder:object:root=true
@@ -67,17 +72,45 @@ type CruiseControlOperationSpec struct {
 	// Value can be only zero and positive integers
 	// +kubebuilder:validation:Minimum=0
 	TTLSecondsAfterFinished *int `json:"ttlSecondsAfterFinished,omitempty"`
+	// Priority defines the position of the operation in the execution queue.
+	// Operations with higher priority are executed first, operations with the same priority
+	// are ordered by their operation type and creation time.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// PreemptionPolicy defines whether the operation can stop a running operation with lower priority.
+	// When it is "PreemptLowerPriority", the running operation with lower priority is stopped and requeued for execution.
+	// When it is "Never", the operation waits for the running operation to finish.
+	// +kubebuilder:validation:Enum=Never;PreemptLowerPriority
+	// +kubebuilder:default=Never
+	// +optional
+	PreemptionPolicy PreemptionPolicyType `json:"preemptionPolicy,omitempty"`
+	// When Cancel is true, the Koperator stops the execution of the operation in Cruise Control, waits for
+	// its task to stop and marks the operation as cancelled. Operations waiting for execution
+	// are cancelled instantly. Cancelled operations are not executed again.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
 }
 
 // ErrorPolicyType defines methods of handling Cruise Control user task errors.
 type ErrorPolicyType string
 
+// PreemptionPolicyType defines whether an operation can stop a running operation with lower priority.
+type PreemptionPolicyType string
+
 // CruiseControlOperationStatus defines the observed state of CruiseControlOperation.
 type CruiseControlOperationStatus struct {
 	CurrentTask *CruiseControlTask  `json:"currentTask,omitempty"`
 	ErrorPolicy ErrorPolicyType     `json:"errorPolicy"`
 	RetryCount  int                 `json:"retryCount"`
 	FailedTasks []CruiseControlTask `json:"failedTasks,omitempty"`
+	// QueuePosition is the position of the operation among the operations waiting for execution
+	// for the same Kafka cluster, starting from 1. It is not set when the operation is not waiting for execution.
+	// +optional
+	QueuePosition *int `json:"queuePosition,omitempty"`
+	// PreemptedBy is the name of the operation that preempted the execution of the current task.
+	// It is cleared when the current task is executed again.
+	// +optional
+	PreemptedBy string `json:"preemptedBy,omitempty"`
 }
 
 // CruiseControlTask defines the observed state of the Cruise Control user task.
@@ -108,6 +141,19 @@ func (c CruiseControlOperation) GetTTLSecondsAfterFinished() *int {
 	return c.Spec.TTLSecondsAfterFinished
 }
 
+// GetPriority returns Spec.Priority
+func (c CruiseControlOperation) GetPriority() int32 {
+	return c.Spec.Priority
+}
+
+// CanPreempt returns true if the operation is allowed to stop the provided running operation.
+func (o *CruiseControlOperation) CanPreempt(running *CruiseControlOperation) bool {
+	if o == nil || running == nil {
+		return false
+	}
+	return o.Spec.PreemptionPolicy == PreemptionPolicyPreemptLowerPriority && running.GetPriority() < o.GetPriority()
+}
+
 func (task *CruiseControlTask) SetDefaults() {
 	task.Finished = nil
 	task.State = ""
@@ -165,13 +211,20 @@ func (o *CruiseControlOperation) CurrentTaskOperation() CruiseControlTaskOperati
 	return o.CurrentTask().Operation
 }
 
+// IsWaitingForFirstExecution returns true if the current task has not been executed yet.
+// Preempted operations have their current task reset so they are waiting for execution again.
 func (o *CruiseControlOperation) IsWaitingForFirstExecution() bool {
-	if o.CurrentTaskState() == "" && o.CurrentTaskID() == "" && o.Status.RetryCount == 0 {
+	if o.CurrentTaskState() == "" && o.CurrentTaskID() == "" && (o.Status.RetryCount == 0 || o.IsPreempted()) {
 		return true
 	}
 	return false
 }
 
+// IsPreempted returns true if the execution of the current task was stopped by an operation with higher priority.
+func (o *CruiseControlOperation) IsPreempted() bool {
+	return o.Status.PreemptedBy != ""
+}
+
 func (o *CruiseControlOperation) IsInProgress() bool {
 	if o.CurrentTaskID() != "" && (o.CurrentTaskState() == v1beta1.CruiseControlTaskActive || o.CurrentTaskState() == v1beta1.CruiseControlTaskInExecution) {
 		return true
@@ -180,7 +233,17 @@ func (o *CruiseControlOperation) IsInProgress() bool {
 }
 
 func (o *CruiseControlOperation) IsDone() bool {
-	return (o.IsPaused() && o.CurrentTaskState() == v1beta1.CruiseControlTaskCompletedWithError) || o.IsFinished()
+	return (o.IsPaused() && o.CurrentTaskState() == v1beta1.CruiseControlTaskCompletedWithError) || o.IsFinished() || o.IsCancelled()
+}
+
+// IsCancelRequested returns true if the cancellation of the operation is requested but it has not been cancelled yet.
+func (o *CruiseControlOperation) IsCancelRequested() bool {
+	return o.Spec.Cancel && !o.IsCancelled()
+}
+
+// IsCancelled returns true if the operation has been cancelled.
+func (o *CruiseControlOperation) IsCancelled() bool {
+	return o.CurrentTaskState() == v1beta1.CruiseControlTaskCancelled
 }
 
 func (o *CruiseControlOperation) IsPaused() bool {
@@ -221,5 +284,7 @@ func (o *CruiseControlOperation) IsCurrentTaskFinished() bool {
 
 func (o *CruiseControlOperation) IsCurrentTaskOperationValid() bool {
 	return o.CurrentTaskOperation() == OperationAddBroker ||
-		o.CurrentTaskOperation() == OperationRebalance || o.CurrentTaskOperation() == OperationRemoveBroker || o.CurrentTaskOperation() == OperationStopExecution
+		o.CurrentTaskOperation() == OperationRebalance || o.CurrentTaskOperation() == OperationRemoveBroker ||
+		o.CurrentTaskOperation() == OperationRemoveDisks || o.CurrentTaskOperation() == OperationDemoteBroker ||
+		o.CurrentTaskOperation() == OperationStopExecution
 }
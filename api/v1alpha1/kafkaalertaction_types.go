@@ -0,0 +1,135 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AlertActionSucceeded means the command of the alert has been executed.
+	AlertActionSucceeded AlertActionResult = "Succeeded"
+	// AlertActionSkipped means the command of the alert has not been executed, it is going to be retried
+	// when the alert is received again.
+	AlertActionSkipped AlertActionResult = "Skipped"
+	// AlertActionFailed means the execution of the command of the alert returned an error.
+	AlertActionFailed AlertActionResult = "Failed"
+	// AlertActionDryRun means the command of the alert has been evaluated in dry-run mode, the message of
+	// the history entry contains the mutation the command would have made.
+	AlertActionDryRun AlertActionResult = "DryRun"
+	// AlertActionResolved means the alert has been resolved, so the command is going to be executed again
+	// when the alert fires next time.
+	AlertActionResolved AlertActionResult = "Resolved"
+	// MaxAlertActionHistoryLength is the number of entries kept in the history of a KafkaAlertAction.
+	MaxAlertActionHistoryLength = 10
+)
+
+// AlertActionResult is the outcome of the processing of an alert.
+type AlertActionResult string
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:JSONPath=".spec.command",name="Command",type="string"
+//+kubebuilder:printcolumn:JSONPath=".spec.clusterName",name="Cluster",type="string"
+//+kubebuilder:printcolumn:JSONPath=".status.alertStatus",name="Alert status",type="string"
+//+kubebuilder:printcolumn:JSONPath=".status.processed",name="Processed",type="boolean"
+//+kubebuilder:printcolumn:JSONPath=".status.lastResult",name="Last result",type="string"
+//+kubebuilder:printcolumn:JSONPath=".status.lastExecuted",name="Last executed",type="date"
+//+kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type="date"
+
+// KafkaAlertAction is the Schema for the kafkaalertactions API.
+// It records the processing of a Prometheus alert routed to the Koperator, one resource per alert fingerprint,
+// so an alert which has already been processed is not executed again after the restart of the operator.
+// It is owned by the KafkaCluster of the alert and deleted once the alert is resolved and the cooldown of its command is over.
+type KafkaAlertAction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafkaAlertActionSpec   `json:"spec,omitempty"`
+	Status KafkaAlertActionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// KafkaAlertActionList contains a list of KafkaAlertAction.
+type KafkaAlertActionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafkaAlertAction `json:"items"`
+}
+
+// KafkaAlertActionSpec identifies the alert the KafkaAlertAction belongs to.
+type KafkaAlertActionSpec struct {
+	// Fingerprint is the fingerprint of the alert calculated from its labels.
+	Fingerprint string `json:"fingerprint"`
+	// Command is the value of the "command" annotation of the alert.
+	Command string `json:"command"`
+	// ClusterName is the name of the KafkaCluster the alert belongs to.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// Labels are the labels of the alert.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// KafkaAlertActionStatus defines the observed state of KafkaAlertAction.
+type KafkaAlertActionStatus struct {
+	// AlertStatus is the last received status of the alert, either firing or resolved.
+	// +optional
+	AlertStatus string `json:"alertStatus,omitempty"`
+	// Processed is true when the command of the firing alert has been executed.
+	// It is reset when the alert is resolved.
+	// +optional
+	Processed bool `json:"processed,omitempty"`
+	// LastResult is the outcome of the last processing of the alert.
+	// +optional
+	LastResult AlertActionResult `json:"lastResult,omitempty"`
+	// LastExecuted is the time the command of the alert has been executed last time.
+	// +optional
+	LastExecuted *metav1.Time `json:"lastExecuted,omitempty"`
+	// History contains the last state changes of the processing of the alert, the oldest entry first.
+	// +optional
+	History []KafkaAlertActionHistoryEntry `json:"history,omitempty"`
+}
+
+// KafkaAlertActionHistoryEntry is a state change of the processing of an alert.
+type KafkaAlertActionHistoryEntry struct {
+	Time   metav1.Time       `json:"time"`
+	Result AlertActionResult `json:"result"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KafkaAlertAction{}, &KafkaAlertActionList{})
+}
+
+// AddHistoryEntry records the result in the history when it differs from the last entry,
+// keeping at most MaxAlertActionHistoryLength entries.
+func (a *KafkaAlertAction) AddHistoryEntry(now time.Time, result AlertActionResult, message string) {
+	a.Status.LastResult = result
+	if n := len(a.Status.History); n > 0 && a.Status.History[n-1].Result == result && a.Status.History[n-1].Message == message {
+		return
+	}
+	a.Status.History = append(a.Status.History, KafkaAlertActionHistoryEntry{
+		Time:    metav1.NewTime(now),
+		Result:  result,
+		Message: message,
+	})
+	if len(a.Status.History) > MaxAlertActionHistoryLength {
+		a.Status.History = a.Status.History[len(a.Status.History)-MaxAlertActionHistoryLength:]
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKafkaAlertActionAddHistoryEntry(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	action := &KafkaAlertAction{}
+
+	action.AddHistoryEntry(now, AlertActionSkipped, "")
+	action.AddHistoryEntry(now.Add(time.Minute), AlertActionSkipped, "")
+	assert.Equal(t, len(action.Status.History), 1)
+	assert.Equal(t, action.Status.History[0].Time, metav1.NewTime(now))
+
+	action.AddHistoryEntry(now.Add(2*time.Minute), AlertActionFailed, "error")
+	assert.Equal(t, len(action.Status.History), 2)
+	assert.Equal(t, action.Status.LastResult, AlertActionFailed)
+
+	for i := 0; i < MaxAlertActionHistoryLength; i++ {
+		result := AlertActionSucceeded
+		if i%2 == 0 {
+			result = AlertActionResolved
+		}
+		action.AddHistoryEntry(now.Add(time.Duration(3+i)*time.Minute), result, "")
+	}
+	assert.Equal(t, len(action.Status.History), MaxAlertActionHistoryLength)
+	assert.Equal(t, action.Status.History[0].Result, AlertActionResolved)
+	assert.Equal(t, action.Status.LastResult, AlertActionSucceeded)
+}
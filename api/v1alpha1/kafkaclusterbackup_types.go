@@ -0,0 +1,161 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// KafkaClusterBackupLabelKey is the label key of the VolumeSnapshots referencing the KafkaClusterBackup they belong to
+	KafkaClusterBackupLabelKey = "kafkaClusterBackup"
+	// VolumeSnapshotAPIGroup is the API group of the CSI VolumeSnapshot resources
+	VolumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	// VolumeSnapshotAPIVersion is the API version of the CSI VolumeSnapshot resources
+	VolumeSnapshotAPIVersion = "v1"
+	// VolumeSnapshotKind is the kind of the CSI VolumeSnapshot resources
+	VolumeSnapshotKind = "VolumeSnapshot"
+	// KafkaClusterBackupRunning means the volume snapshots of the brokers are being taken
+	KafkaClusterBackupRunning KafkaClusterBackupState = "Running"
+	// KafkaClusterBackupSucceeded means the volume snapshots of all the brokers are ready to use
+	KafkaClusterBackupSucceeded KafkaClusterBackupState = "Succeeded"
+	// KafkaClusterBackupFailed means the backup could not be completed
+	KafkaClusterBackupFailed KafkaClusterBackupState = "Failed"
+	// BrokerBackupDraining means the partition leaderships are being moved off the broker by Cruise Control
+	BrokerBackupDraining BrokerBackupState = "Draining"
+	// BrokerBackupSnapshotting means the volume snapshots of the broker have been requested but not all of them are ready
+	BrokerBackupSnapshotting BrokerBackupState = "Snapshotting"
+	// BrokerBackupRestoringLeadership means the volume snapshots of the broker are ready to use, or the backup of the
+	// broker has failed, and the partition leaderships are being moved back to the broker by Cruise Control
+	BrokerBackupRestoringLeadership BrokerBackupState = "RestoringLeadership"
+	// BrokerBackupSucceeded means the volume snapshots of the broker are ready to use
+	BrokerBackupSucceeded BrokerBackupState = "Succeeded"
+	// BrokerBackupFailed means the backup of the broker could not be completed
+	BrokerBackupFailed BrokerBackupState = "Failed"
+)
+
+// KafkaClusterBackupState defines the state of a KafkaClusterBackup
+type KafkaClusterBackupState string
+
+// BrokerBackupState defines the state of the backup of a single broker
+type BrokerBackupState string
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:JSONPath=".spec.clusterRef.name",name="Cluster",type="string"
+//+kubebuilder:printcolumn:JSONPath=".status.state",name="State",type="string"
+//+kubebuilder:printcolumn:JSONPath=".status.completionTime",name="Completed",type="date"
+//+kubebuilder:printcolumn:JSONPath=".metadata.creationTimestamp",name="Age",type="date"
+
+// KafkaClusterBackup is the Schema for the kafkaclusterbackups API.
+// It takes CSI VolumeSnapshots of the data volumes of every broker of a KafkaCluster, one broker at a time.
+// A KafkaCluster can provision its persistent volume claims from these snapshots using spec.restoreFromBackup.
+type KafkaClusterBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafkaClusterBackupSpec   `json:"spec,omitempty"`
+	Status KafkaClusterBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// KafkaClusterBackupList contains a list of KafkaClusterBackup.
+type KafkaClusterBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafkaClusterBackup `json:"items"`
+}
+
+// KafkaClusterBackupSpec defines the desired state of KafkaClusterBackup.
+type KafkaClusterBackupSpec struct {
+	// ClusterRef references the KafkaCluster whose broker volumes are backed up.
+	ClusterRef ClusterReference `json:"clusterRef"`
+	// VolumeSnapshotClassName is the name of the VolumeSnapshotClass used for the snapshots.
+	// When it is not set the default VolumeSnapshotClass of the CSI driver is used.
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+	// DrainLeadership moves the partition leaderships off each broker with a demote_broker CruiseControlOperation
+	// before its volumes are snapshotted, so no new data is written to the volumes by the broker as a leader
+	// while the snapshots are taken. Once the snapshots of the broker are ready its leaderships are moved back
+	// with a rebalance CruiseControlOperation, before the next broker is drained. The leaderships are moved back
+	// also when the backup of the broker fails. The rebalance balances the leader replicas, which may move replicas
+	// as well when moving the leaderships alone can not balance them.
+	// +optional
+	DrainLeadership bool `json:"drainLeadership,omitempty"`
+}
+
+// KafkaClusterBackupStatus defines the observed state of KafkaClusterBackup.
+type KafkaClusterBackupStatus struct {
+	// +optional
+	State KafkaClusterBackupState `json:"state,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Brokers contains the backup state of the brokers keyed by the broker ID.
+	// +optional
+	Brokers map[string]BrokerBackupStatus `json:"brokers,omitempty"`
+}
+
+// BrokerBackupStatus defines the backup state of a single broker.
+type BrokerBackupStatus struct {
+	// +optional
+	State BrokerBackupState `json:"state,omitempty"`
+	// DemoteOperationReference references the demote_broker CruiseControlOperation which drains the leaderships
+	// off the broker.
+	// +optional
+	DemoteOperationReference *corev1.LocalObjectReference `json:"demoteOperationReference,omitempty"`
+	// RestoreLeadershipOperationReference references the rebalance CruiseControlOperation which moves the
+	// leaderships back to the broker.
+	// +optional
+	RestoreLeadershipOperationReference *corev1.LocalObjectReference `json:"restoreLeadershipOperationReference,omitempty"`
+	// BackupFailed is set when the backup of the broker has failed while its leaderships were drained. The broker gets
+	// into the Failed state once its leaderships are moved back.
+	// +optional
+	BackupFailed bool `json:"backupFailed,omitempty"`
+	// +optional
+	Snapshots []VolumeSnapshotStatus `json:"snapshots,omitempty"`
+}
+
+// VolumeSnapshotStatus describes the VolumeSnapshot of a broker data volume.
+type VolumeSnapshotStatus struct {
+	MountPath                 string `json:"mountPath"`
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+	VolumeSnapshotName        string `json:"volumeSnapshotName"`
+	// +optional
+	ReadyToUse bool `json:"readyToUse,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KafkaClusterBackup{}, &KafkaClusterBackupList{})
+}
+
+// IsDone returns true when the backup has either succeeded or failed.
+func (b *KafkaClusterBackup) IsDone() bool {
+	return b.Status.State == KafkaClusterBackupSucceeded || b.Status.State == KafkaClusterBackupFailed
+}
+
+// VolumeSnapshotName returns the name of the ready to use VolumeSnapshot of the given broker data volume.
+func (s KafkaClusterBackupStatus) VolumeSnapshotName(brokerID, mountPath string) (string, bool) {
+	for _, snapshot := range s.Brokers[brokerID].Snapshots {
+		if snapshot.MountPath == mountPath && snapshot.ReadyToUse {
+			return snapshot.VolumeSnapshotName, true
+		}
+	}
+	return "", false
+}
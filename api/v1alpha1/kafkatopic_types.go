@@ -15,12 +15,22 @@
 package v1alpha1
 
 import (
+	"strconv"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	MinPartitions        = -1
 	MinReplicationFactor = -1
+
+	// TopicConfigRemoteStorageEnable is the topic configuration enabling the remote log storage of the topic
+	TopicConfigRemoteStorageEnable = "remote.storage.enable"
+	// TopicConfigLocalRetentionMs is the topic configuration of the time the log segments are kept on the brokers
+	TopicConfigLocalRetentionMs = "local.retention.ms"
+	// TopicConfigLocalRetentionBytes is the topic configuration of the size of the log segments kept on the brokers
+	TopicConfigLocalRetentionBytes = "local.retention.bytes"
 )
 
 // KafkaTopicSpec defines the desired state of KafkaTopic
@@ -35,6 +45,27 @@ type KafkaTopicSpec struct {
 	ReplicationFactor int32             `json:"replicationFactor"`
 	Config            map[string]string `json:"config,omitempty"`
 	ClusterRef        ClusterReference  `json:"clusterRef"`
+	// RemoteStorage configures the remote log storage of the topic, it requires tiered storage to be enabled
+	// on all the brokers of the KafkaCluster. The overall retention of the topic is still set by retention.ms
+	// and retention.bytes in the config.
+	// +optional
+	RemoteStorage *TopicRemoteStorage `json:"remoteStorage,omitempty"`
+}
+
+// TopicRemoteStorage defines the remote log storage settings of a topic
+type TopicRemoteStorage struct {
+	// Enabled sets remote.storage.enable of the topic
+	Enabled bool `json:"enabled"`
+	// LocalRetentionMs sets local.retention.ms of the topic, the time the log segments are kept on the brokers
+	// before they are only available in the remote storage
+	// +kubebuilder:validation:Minimum=-2
+	// +optional
+	LocalRetentionMs *int64 `json:"localRetentionMs,omitempty"`
+	// LocalRetentionBytes sets local.retention.bytes of the topic, the size of the log segments kept on the brokers
+	// before they are only available in the remote storage
+	// +kubebuilder:validation:Minimum=-2
+	// +optional
+	LocalRetentionBytes *int64 `json:"localRetentionBytes,omitempty"`
 }
 
 // KafkaTopicStatus defines the observed state of KafkaTopic
@@ -74,3 +105,27 @@ type KafkaTopicList struct {
 func init() {
 	SchemeBuilder.Register(&KafkaTopic{}, &KafkaTopicList{})
 }
+
+// GetConfig returns the configuration of the topic including the remote log storage settings
+func (s *KafkaTopicSpec) GetConfig() map[string]string {
+	if s.RemoteStorage == nil {
+		return s.Config
+	}
+	config := make(map[string]string, len(s.Config)+3)
+	for k, v := range s.Config {
+		config[k] = v
+	}
+	config[TopicConfigRemoteStorageEnable] = strconv.FormatBool(s.RemoteStorage.Enabled)
+	if s.RemoteStorage.LocalRetentionMs != nil {
+		config[TopicConfigLocalRetentionMs] = strconv.FormatInt(*s.RemoteStorage.LocalRetentionMs, 10)
+	}
+	if s.RemoteStorage.LocalRetentionBytes != nil {
+		config[TopicConfigLocalRetentionBytes] = strconv.FormatInt(*s.RemoteStorage.LocalRetentionBytes, 10)
+	}
+	return config
+}
+
+// IsRemoteStorageEnabled returns true when the remote log storage is enabled for the topic
+func (s *KafkaTopicSpec) IsRemoteStorageEnabled() bool {
+	return strings.EqualFold(s.GetConfig()[TopicConfigRemoteStorageEnable], "true")
+}
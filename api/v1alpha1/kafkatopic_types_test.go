@@ -0,0 +1,66 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestKafkaTopicSpecGetConfig(t *testing.T) {
+	localRetentionMs := int64(3600000)
+	testCases := []struct {
+		testName      string
+		spec          KafkaTopicSpec
+		expected      map[string]string
+		remoteStorage bool
+	}{
+		{
+			testName:      "no remote storage",
+			spec:          KafkaTopicSpec{Config: map[string]string{"retention.ms": "604800000"}},
+			expected:      map[string]string{"retention.ms": "604800000"},
+			remoteStorage: false,
+		},
+		{
+			testName:      "remote storage enabled through the config",
+			spec:          KafkaTopicSpec{Config: map[string]string{"remote.storage.enable": "true"}},
+			expected:      map[string]string{"remote.storage.enable": "true"},
+			remoteStorage: true,
+		},
+		{
+			testName: "remote storage settings",
+			spec: KafkaTopicSpec{
+				Config: map[string]string{"retention.ms": "604800000"},
+				RemoteStorage: &TopicRemoteStorage{
+					Enabled:          true,
+					LocalRetentionMs: &localRetentionMs,
+				},
+			},
+			expected: map[string]string{
+				"retention.ms":          "604800000",
+				"remote.storage.enable": "true",
+				"local.retention.ms":    "3600000",
+			},
+			remoteStorage: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			assert.DeepEqual(t, test.spec.GetConfig(), test.expected)
+			assert.Equal(t, test.spec.IsRemoteStorageEnabled(), test.remoteStorage)
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1beta1"
 
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,6 +28,8 @@ import (
 const (
 	// default certificate duration if kafkauser.spec.expirationSeconds is not set
 	defaultCertificateDuration = time.Hour * 24 * 90
+	// default percentage of the certificate lifetime after which it is renewed if kafkauser.spec.renewalPercentage is not set
+	defaultCertificateRenewalPercentage = 67
 	// CertManagerSignerNamePrefix is acceptable pki backend signerName prefix for cert-manager
 	CertManagerSignerNamePrefix string = "clusterissuers.cert-manager.io"
 )
@@ -44,17 +47,29 @@ type KafkaUserSpec struct {
 	IncludeJKS     bool              `json:"includeJKS,omitempty"`
 	CreateCert     *bool             `json:"createCert,omitempty"`
 	PKIBackendSpec *PKIBackendSpec   `json:"pkiBackendSpec,omitempty"`
+	// keystoreFormats lists the formats the certificate is stored in besides the PEM encoded certificate and key,
+	// includeJKS is equivalent to listing jks. The keystores and the private key of the PEM bundle are protected
+	// by the generated password stored in the secret.
+	// +optional
+	KeystoreFormats []v1beta1.KeystoreFormat `json:"keystoreFormats,omitempty"`
 	// expirationSeconds is the requested duration of validity of the issued certificate.
 	// The minimum valid value for expirationSeconds is 3600 i.e. 1h.
 	// When it is not specified the default validation duration is 90 days
 	// +optional
 	// +kubebuilder:validation:Minimum=3600
 	ExpirationSeconds *int32 `json:"expirationSeconds,omitempty"`
+	// renewalPercentage is the percentage of the certificate's lifetime after which a new certificate is requested.
+	// It is used by the k8s-csr pki backend, the current certificate is kept in the secret until the new one is issued.
+	// When it is not specified the certificate is renewed after 67% of its lifetime
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	RenewalPercentage *int32 `json:"renewalPercentage,omitempty"`
 }
 
 type PKIBackendSpec struct {
 	IssuerRef *cmmeta.ObjectReference `json:"issuerRef,omitempty"`
-	// +kubebuilder:validation:Enum={"cert-manager","k8s-csr"}
+	// +kubebuilder:validation:Enum={"cert-manager","k8s-csr","vault"}
 	PKIBackend string `json:"pkiBackend"`
 	// SignerName indicates requested signer, and is a qualified name.
 	SignerName string `json:"signerName,omitempty"`
@@ -74,6 +89,8 @@ type UserTopicGrant struct {
 type KafkaUserStatus struct {
 	State UserState `json:"state"`
 	ACLs  []string  `json:"acls,omitempty"`
+	// CertificateNotAfter is the expiration time of the user's current certificate
+	CertificateNotAfter *metav1.Time `json:"certificateNotAfter,omitempty"`
 }
 
 // KafkaUser is the Schema for the kafka users API
@@ -133,3 +150,39 @@ func (spec *KafkaUserSpec) GetExpirationSeconds() int32 {
 	}
 	return *spec.ExpirationSeconds
 }
+
+// GetRenewalPercentage returns the percentage of the certificate's lifetime after which it should be renewed
+func (spec *KafkaUserSpec) GetRenewalPercentage() int32 {
+	if spec.RenewalPercentage == nil {
+		return defaultCertificateRenewalPercentage
+	}
+	return *spec.RenewalPercentage
+}
+
+// GetKeystoreFormats returns the keystore formats the certificate should be stored in, including jks when includeJKS is set
+func (spec *KafkaUserSpec) GetKeystoreFormats() []v1beta1.KeystoreFormat {
+	formats := make([]v1beta1.KeystoreFormat, 0, len(spec.KeystoreFormats)+1)
+	if spec.IncludeJKS {
+		formats = append(formats, v1beta1.KeystoreFormatJKS)
+	}
+	for _, format := range spec.KeystoreFormats {
+		if !containsKeystoreFormat(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	return formats
+}
+
+// HasKeystoreFormat returns true if the certificate should be stored in the given keystore format
+func (spec *KafkaUserSpec) HasKeystoreFormat(format v1beta1.KeystoreFormat) bool {
+	return containsKeystoreFormat(spec.GetKeystoreFormats(), format)
+}
+
+func containsKeystoreFormat(formats []v1beta1.KeystoreFormat, format v1beta1.KeystoreFormat) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
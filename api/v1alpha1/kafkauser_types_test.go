@@ -101,3 +101,58 @@ func TestKafkaUserSpecValidateAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaUserSpecGetRenewalPercentage(t *testing.T) {
+	t.Parallel()
+	spec := KafkaUserSpec{}
+	assert.Equal(t, int32(defaultCertificateRenewalPercentage), spec.GetRenewalPercentage())
+
+	percentage := int32(50)
+	spec.RenewalPercentage = &percentage
+	assert.Equal(t, int32(50), spec.GetRenewalPercentage())
+}
+
+func TestKafkaUserSpecGetKeystoreFormats(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		spec KafkaUserSpec
+		want []v1beta1.KeystoreFormat
+	}{
+		{
+			name: "no keystores",
+			spec: KafkaUserSpec{},
+			want: []v1beta1.KeystoreFormat{},
+		},
+		{
+			name: "includeJKS",
+			spec: KafkaUserSpec{IncludeJKS: true},
+			want: []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatJKS},
+		},
+		{
+			name: "includeJKS and keystore formats",
+			spec: KafkaUserSpec{
+				IncludeJKS:      true,
+				KeystoreFormats: []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPEMBundle, v1beta1.KeystoreFormatJKS, v1beta1.KeystoreFormatPKCS12},
+			},
+			want: []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatJKS, v1beta1.KeystoreFormatPEMBundle, v1beta1.KeystoreFormatPKCS12},
+		},
+		{
+			name: "duplicated keystore formats",
+			spec: KafkaUserSpec{
+				KeystoreFormats: []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPKCS12, v1beta1.KeystoreFormatPKCS12},
+			},
+			want: []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPKCS12},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.DeepEqual(t, tt.spec.GetKeystoreFormats(), tt.want)
+			for _, format := range tt.want {
+				assert.Assert(t, tt.spec.HasKeystoreFormat(format))
+			}
+		})
+	}
+}
@@ -22,10 +22,42 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerBackupStatus) DeepCopyInto(out *BrokerBackupStatus) {
+	*out = *in
+	if in.DemoteOperationReference != nil {
+		in, out := &in.DemoteOperationReference, &out.DemoteOperationReference
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.RestoreLeadershipOperationReference != nil {
+		in, out := &in.RestoreLeadershipOperationReference, &out.RestoreLeadershipOperationReference
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]VolumeSnapshotStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerBackupStatus.
+func (in *BrokerBackupStatus) DeepCopy() *BrokerBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterReference) DeepCopyInto(out *ClusterReference) {
 	*out = *in
@@ -135,6 +167,11 @@ func (in *CruiseControlOperationStatus) DeepCopyInto(out *CruiseControlOperation
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.QueuePosition != nil {
+		in, out := &in.QueuePosition, &out.QueuePosition
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CruiseControlOperationStatus.
@@ -189,6 +226,234 @@ func (in *CruiseControlTask) DeepCopy() *CruiseControlTask {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaAlertAction) DeepCopyInto(out *KafkaAlertAction) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaAlertAction.
+func (in *KafkaAlertAction) DeepCopy() *KafkaAlertAction {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaAlertAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaAlertAction) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaAlertActionHistoryEntry) DeepCopyInto(out *KafkaAlertActionHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaAlertActionHistoryEntry.
+func (in *KafkaAlertActionHistoryEntry) DeepCopy() *KafkaAlertActionHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaAlertActionHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaAlertActionList) DeepCopyInto(out *KafkaAlertActionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KafkaAlertAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaAlertActionList.
+func (in *KafkaAlertActionList) DeepCopy() *KafkaAlertActionList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaAlertActionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaAlertActionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaAlertActionSpec) DeepCopyInto(out *KafkaAlertActionSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaAlertActionSpec.
+func (in *KafkaAlertActionSpec) DeepCopy() *KafkaAlertActionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaAlertActionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaAlertActionStatus) DeepCopyInto(out *KafkaAlertActionStatus) {
+	*out = *in
+	if in.LastExecuted != nil {
+		in, out := &in.LastExecuted, &out.LastExecuted
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]KafkaAlertActionHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaAlertActionStatus.
+func (in *KafkaAlertActionStatus) DeepCopy() *KafkaAlertActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaAlertActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaClusterBackup) DeepCopyInto(out *KafkaClusterBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterBackup.
+func (in *KafkaClusterBackup) DeepCopy() *KafkaClusterBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaClusterBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaClusterBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaClusterBackupList) DeepCopyInto(out *KafkaClusterBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KafkaClusterBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterBackupList.
+func (in *KafkaClusterBackupList) DeepCopy() *KafkaClusterBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaClusterBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KafkaClusterBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaClusterBackupSpec) DeepCopyInto(out *KafkaClusterBackupSpec) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterBackupSpec.
+func (in *KafkaClusterBackupSpec) DeepCopy() *KafkaClusterBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaClusterBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaClusterBackupStatus) DeepCopyInto(out *KafkaClusterBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make(map[string]BrokerBackupStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterBackupStatus.
+func (in *KafkaClusterBackupStatus) DeepCopy() *KafkaClusterBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaClusterBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaTopic) DeepCopyInto(out *KafkaTopic) {
 	*out = *in
@@ -259,6 +524,11 @@ func (in *KafkaTopicSpec) DeepCopyInto(out *KafkaTopicSpec) {
 		}
 	}
 	out.ClusterRef = in.ClusterRef
+	if in.RemoteStorage != nil {
+		in, out := &in.RemoteStorage, &out.RemoteStorage
+		*out = new(TopicRemoteStorage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTopicSpec.
@@ -376,11 +646,21 @@ func (in *KafkaUserSpec) DeepCopyInto(out *KafkaUserSpec) {
 		*out = new(PKIBackendSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KeystoreFormats != nil {
+		in, out := &in.KeystoreFormats, &out.KeystoreFormats
+		*out = make([]v1beta1.KeystoreFormat, len(*in))
+		copy(*out, *in)
+	}
 	if in.ExpirationSeconds != nil {
 		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RenewalPercentage != nil {
+		in, out := &in.RenewalPercentage, &out.RenewalPercentage
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaUserSpec.
@@ -401,6 +681,10 @@ func (in *KafkaUserStatus) DeepCopyInto(out *KafkaUserStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CertificateNotAfter != nil {
+		in, out := &in.CertificateNotAfter, &out.CertificateNotAfter
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaUserStatus.
@@ -433,6 +717,31 @@ func (in *PKIBackendSpec) DeepCopy() *PKIBackendSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicRemoteStorage) DeepCopyInto(out *TopicRemoteStorage) {
+	*out = *in
+	if in.LocalRetentionMs != nil {
+		in, out := &in.LocalRetentionMs, &out.LocalRetentionMs
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LocalRetentionBytes != nil {
+		in, out := &in.LocalRetentionBytes, &out.LocalRetentionBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicRemoteStorage.
+func (in *TopicRemoteStorage) DeepCopy() *TopicRemoteStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicRemoteStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserTopicGrant) DeepCopyInto(out *UserTopicGrant) {
 	*out = *in
@@ -447,3 +756,18 @@ func (in *UserTopicGrant) DeepCopy() *UserTopicGrant {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSnapshotStatus) DeepCopyInto(out *VolumeSnapshotStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSnapshotStatus.
+func (in *VolumeSnapshotStatus) DeepCopy() *VolumeSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
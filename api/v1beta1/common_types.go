@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // RackAwarenessState stores info about rack awareness status
@@ -49,6 +50,9 @@ type SSLClientAuthentication string
 // PerBrokerConfigurationState holds info about the per-broker configuration state
 type PerBrokerConfigurationState string
 
+// ConfigChangeType describes how a configuration change gets applied on the broker
+type ConfigChangeType string
+
 // ExternalListenerConfigNames type describes a collection of external listener names
 type ExternalListenerConfigNames []string
 
@@ -63,6 +67,14 @@ type KafkaVersion struct {
 // PKIBackend represents an interface implementing the PKIManager
 type PKIBackend string
 
+// KeystoreFormat is the format a certificate and its private key are stored in besides the PEM encoded files
+// +kubebuilder:validation:Enum={"jks","pkcs12","pem-bundle"}
+type KeystoreFormat string
+
+// SASLMechanism is a SASL mechanism enabled on a listener
+// +kubebuilder:validation:Enum={"PLAIN","SCRAM-SHA-256","SCRAM-SHA-512","OAUTHBEARER","GSSAPI"}
+type SASLMechanism string
+
 // CruiseControlVolumeState holds information about the state of volume rebalance
 type CruiseControlVolumeState string
 
@@ -72,18 +84,42 @@ func (s CruiseControlVolumeState) IsRunningState() bool {
 	return s == GracefulDiskRebalanceRunning ||
 		s == GracefulDiskRebalanceCompletedWithError ||
 		s == GracefulDiskRebalancePaused ||
-		s == GracefulDiskRebalanceScheduled
+		s == GracefulDiskRebalanceScheduled ||
+		s == GracefulDiskRemovalRunning ||
+		s == GracefulDiskRemovalCompletedWithError ||
+		s == GracefulDiskRemovalPaused ||
+		s == GracefulDiskRemovalScheduled
 }
 
-// IsRequiredState returns true if CruiseControlVolumeState is in GracefulDiskRebalanceRequired state
+// IsRequiredState returns true if CruiseControlVolumeState is in GracefulDiskRebalanceRequired or
+// GracefulDiskRemovalRequired state
 func (s CruiseControlVolumeState) IsRequiredState() bool {
-	return s == GracefulDiskRebalanceRequired
+	return s == GracefulDiskRebalanceRequired ||
+		s == GracefulDiskRemovalRequired
+}
+
+// IsDiskRemoval returns true if CruiseControlVolumeState is in GracefulDiskRemoval* state.
+func (s CruiseControlVolumeState) IsDiskRemoval() bool {
+	return s == GracefulDiskRemovalRequired ||
+		s == GracefulDiskRemovalRunning ||
+		s == GracefulDiskRemovalCompletedWithError ||
+		s == GracefulDiskRemovalPaused ||
+		s == GracefulDiskRemovalScheduled ||
+		s == GracefulDiskRemovalSucceeded
+}
+
+// IsDiskRemovalRevocable returns true if Cruise Control has not started to drain the disk being removed or the
+// removal has been stopped, so the disk can be added back to the broker.
+func (s CruiseControlVolumeState) IsDiskRemovalRevocable() bool {
+	return s == GracefulDiskRemovalRequired ||
+		s == GracefulDiskRemovalScheduled ||
+		s == GracefulDiskRemovalPaused
 }
 
 // IsActive returns true if CruiseControlVolumeState is in active state
 // the controller needs to take care of.
 func (s CruiseControlVolumeState) IsActive() bool {
-	return s.IsRunningState() || s == GracefulDiskRebalanceRequired
+	return s.IsRunningState() || s.IsRequiredState()
 }
 
 // IsUpscale returns true if CruiseControlState in GracefulUpscale* state.
@@ -140,7 +176,8 @@ func (r CruiseControlState) IsSucceeded() bool {
 
 // IsSucceeded returns true if CruiseControlVolumeState is succeeded
 func (r CruiseControlVolumeState) IsSucceeded() bool {
-	return r == GracefulDiskRebalanceSucceeded
+	return r == GracefulDiskRebalanceSucceeded ||
+		r == GracefulDiskRemovalSucceeded
 }
 
 // IsSSL determines if the receiver is using SSL
@@ -163,6 +200,16 @@ func (r SecurityProtocol) ToUpperString() string {
 	return strings.ToUpper(string(r))
 }
 
+// ConfigPrefix returns the prefix of the listener configurations specific to the SASL mechanism
+func (m SASLMechanism) ConfigPrefix() string {
+	return strings.ToLower(string(m))
+}
+
+// IsScram determines if the receiver is one of the SCRAM mechanisms
+func (m SASLMechanism) IsScram() bool {
+	return m == SASLMechanismScramSHA256 || m == SASLMechanismScramSHA512
+}
+
 // Equal checks the equality between two SecurityProtocols
 func (r SecurityProtocol) Equal(s SecurityProtocol) bool {
 	return r.ToUpperString() == s.ToUpperString()
@@ -176,6 +223,30 @@ const (
 	PKIBackendProvided PKIBackend = "pki-backend-provided"
 	// PKIBackendK8sCSR invokes kubernetes csr API for user certificate management
 	PKIBackendK8sCSR PKIBackend = "k8s-csr"
+	// PKIBackendVault invokes the PKI secrets engine of Vault for certificate management
+	PKIBackendVault PKIBackend = "vault"
+)
+
+const (
+	// KeystoreFormatJKS stores the certificate in a Java KeyStore
+	KeystoreFormatJKS KeystoreFormat = "jks"
+	// KeystoreFormatPKCS12 stores the certificate in a PKCS#12 keystore
+	KeystoreFormatPKCS12 KeystoreFormat = "pkcs12"
+	// KeystoreFormatPEMBundle stores the certificate chain together with the encrypted PKCS#8 private key in a PEM file
+	KeystoreFormatPEMBundle KeystoreFormat = "pem-bundle"
+)
+
+const (
+	// SASLMechanismPlain authenticates the users with the passwords set in the JAAS configuration of the listener
+	SASLMechanismPlain SASLMechanism = "PLAIN"
+	// SASLMechanismScramSHA256 authenticates the users with the SCRAM credentials stored in ZooKeeper
+	SASLMechanismScramSHA256 SASLMechanism = "SCRAM-SHA-256"
+	// SASLMechanismScramSHA512 authenticates the users with the SCRAM credentials stored in ZooKeeper
+	SASLMechanismScramSHA512 SASLMechanism = "SCRAM-SHA-512"
+	// SASLMechanismOAuthBearer authenticates the users with JSON Web Tokens issued by an OAuth 2 authorization server
+	SASLMechanismOAuthBearer SASLMechanism = "OAUTHBEARER"
+	// SASLMechanismGSSAPI authenticates the users with Kerberos
+	SASLMechanismGSSAPI SASLMechanism = "GSSAPI"
 )
 
 // IstioControlPlaneReference is a reference to the IstioControlPlane resource.
@@ -219,6 +290,66 @@ type BrokerState struct {
 	Image string `json:"image,omitempty"`
 	// Compressed data from broker configuration to restore broker pod in specific cases
 	ConfigurationBackup string `json:"configurationBackup,omitempty"`
+	// Load holds the latest resource utilization of the broker as reported by Cruise Control
+	// +optional
+	Load *BrokerLoad `json:"load,omitempty"`
+	// PendingConfigChanges holds the configuration changes which are not applied on the broker yet
+	// +optional
+	PendingConfigChanges []PendingConfigChange `json:"pendingConfigChanges,omitempty"`
+	// ListenerCertificates holds the state of the certificates served by the SSL listeners of the broker
+	// keyed by the name of the listener
+	// +optional
+	ListenerCertificates ListenerCertificateStates `json:"listenerCertificates,omitempty"`
+}
+
+// ListenerCertificateStates holds the state of the certificates served by the SSL listeners of a broker keyed by the name of the listener
+type ListenerCertificateStates map[string]ListenerCertificateState
+
+// ListenerCertificateState describes the certificate served by an SSL listener of a broker
+type ListenerCertificateState struct {
+	// NotAfter is the expiration time of the certificate served by the listener
+	NotAfter metav1.Time `json:"notAfter"`
+	// SecretResourceVersion is the resource version of the server certificate secret the served certificate was
+	// checked against. The served certificate is only checked again when the secret changes.
+	// +optional
+	SecretResourceVersion string `json:"secretResourceVersion,omitempty"`
+	// ReloadRequestedAt is the time the broker was first requested to reload the renewed certificate of the listener.
+	// It is cleared once the broker serves the renewed certificate.
+	// +optional
+	ReloadRequestedAt *metav1.Time `json:"reloadRequestedAt,omitempty"`
+}
+
+// PendingConfigChange describes a configuration change which is not applied on the broker yet
+type PendingConfigChange struct {
+	// Key is the name of the configuration
+	Key string `json:"key"`
+	// OldValue is the value currently applied on the broker, empty when the configuration is added
+	// +optional
+	OldValue string `json:"oldValue,omitempty"`
+	// NewValue is the desired value, empty when the configuration is removed
+	// +optional
+	NewValue string `json:"newValue,omitempty"`
+	// Type describes how the change gets applied on the broker
+	// +kubebuilder:validation:Enum=DynamicClusterWide;DynamicPerBroker;RestartRequired
+	Type ConfigChangeType `json:"type"`
+}
+
+// BrokerLoad holds information about the resource utilization of a broker reported by Cruise Control
+type BrokerLoad struct {
+	// CPUPercentage is the CPU utilization of the broker in percentage
+	CPUPercentage string `json:"cpuPercentage,omitempty"`
+	// DiskMB is the disk space used by the broker in megabytes
+	DiskMB string `json:"diskMB,omitempty"`
+	// DiskPercentage is the disk utilization of the broker in percentage
+	DiskPercentage string `json:"diskPercentage,omitempty"`
+	// NetworkInRate is the incoming network throughput of the broker in kB/s
+	NetworkInRate string `json:"networkInRate,omitempty"`
+	// NetworkOutRate is the outgoing network throughput of the broker in kB/s
+	NetworkOutRate string `json:"networkOutRate,omitempty"`
+	// Leaders is the number of partition leader replicas hosted by the broker
+	Leaders int32 `json:"leaders"`
+	// Replicas is the number of partition replicas hosted by the broker
+	Replicas int32 `json:"replicas"`
 }
 
 const (
@@ -269,6 +400,21 @@ const (
 	// GracefulDiskRebalancePaused states that the broker volume rebalance task is completed with an error and it will not be retried, it is paused
 	GracefulDiskRebalancePaused CruiseControlVolumeState = "GracefulDiskRebalancePaused"
 
+	// Disk removal cruise control states
+	// GracefulDiskRemovalRequired states that the broker volume has been removed from the spec and it needs to be emptied by CC
+	GracefulDiskRemovalRequired CruiseControlVolumeState = "GracefulDiskRemovalRequired"
+	// GracefulDiskRemovalScheduled states that the broker volume removal CCOperation is created and the task is waiting for execution
+	GracefulDiskRemovalScheduled CruiseControlVolumeState = "GracefulDiskRemovalScheduled"
+	// GracefulDiskRemovalRunning states that CC is moving the replicas off the broker volume
+	GracefulDiskRemovalRunning CruiseControlVolumeState = "GracefulDiskRemovalRunning"
+	// GracefulDiskRemovalSucceeded states that the broker volume is emptied and it can be dropped from the broker
+	GracefulDiskRemovalSucceeded CruiseControlVolumeState = "GracefulDiskRemovalSucceeded"
+	// GracefulDiskRemovalCompletedWithError states that the broker volume removal task completed with an error
+	GracefulDiskRemovalCompletedWithError CruiseControlVolumeState = "GracefulDiskRemovalCompletedWithError"
+	// GracefulDiskRemovalPaused states that the broker volume removal task is completed with an error or it has been cancelled,
+	// and it will not be retried, it is paused. The removal can be revoked by adding the volume back to the broker.
+	GracefulDiskRemovalPaused CruiseControlVolumeState = "GracefulDiskRemovalPaused"
+
 	// CruiseControlTopicNotReady states the CC required topic is not yet created
 	CruiseControlTopicNotReady CruiseControlTopicStatus = "CruiseControlTopicNotReady"
 	// CruiseControlTopicReady states the CC required topic is created
@@ -281,6 +427,8 @@ const (
 	CruiseControlTaskCompleted CruiseControlUserTaskState = "Completed"
 	// CruiseControlTaskCompletedWithError states the CC task completed with error
 	CruiseControlTaskCompletedWithError CruiseControlUserTaskState = "CompletedWithError"
+	// CruiseControlTaskCancelled states the CC task was cancelled by the user
+	CruiseControlTaskCancelled CruiseControlUserTaskState = "Cancelled"
 	// KafkaClusterReconciling states that the cluster is still in reconciling stage
 	KafkaClusterReconciling ClusterState = "ClusterReconciling"
 	// KafkaClusterRollingUpgrading states that the cluster is rolling upgrading
@@ -299,6 +447,13 @@ const (
 	// PerBrokerConfigError states that the generated per-broker brokerConfig can not be set in the Broker
 	PerBrokerConfigError PerBrokerConfigurationState = "PerBrokerConfigError"
 
+	// ConfigChangeDynamicClusterWide states that the configuration change is applied dynamically on every broker
+	ConfigChangeDynamicClusterWide ConfigChangeType = "DynamicClusterWide"
+	// ConfigChangeDynamicPerBroker states that the configuration change is applied dynamically on the broker
+	ConfigChangeDynamicPerBroker ConfigChangeType = "DynamicPerBroker"
+	// ConfigChangeRestartRequired states that the configuration change is applied by restarting the broker
+	ConfigChangeRestartRequired ConfigChangeType = "RestartRequired"
+
 	// SecurityProtocolSSL
 	SecurityProtocolSSL SecurityProtocol = "ssl"
 	// SecurityProtocolPlaintext
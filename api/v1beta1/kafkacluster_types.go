@@ -16,6 +16,7 @@ package v1beta1
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"emperror.dev/errors"
@@ -41,6 +42,10 @@ const (
 	KafkaCRLabelKey  = "kafka_cr"
 	BrokerIdLabelKey = "brokerId"
 
+	// ReplicasInSyncReadinessGate is the readiness gate of the broker pods which becomes true once the broker
+	// has rejoined the in-sync replica set of all its partitions
+	ReplicasInSyncReadinessGate corev1.PodConditionType = "kafka.banzaicloud.io/replicas-in-sync"
+
 	// These are default values for API keys
 
 	/* General Config */
@@ -104,6 +109,9 @@ const (
 	defaultKafkaClusterIngressController = "envoy"
 	defaultKafkaClusterK8sClusterDomain  = "cluster.local"
 
+	// Kerberos service name of the brokers on GSSAPI listeners
+	defaultKerberosServiceName = "kafka"
+
 	// KafkaBroker.spec.container["kafka"].image
 	defaultKafkaImage = "ghcr.io/banzaicloud/kafka:2.13-3.4.1"
 
@@ -129,6 +137,13 @@ const (
 	// KafkaBrokerPod.spec.initContainer["jmx-exporter"].command
 	// kafkaClusterDeployment.spec.template.spec.initContainer["jmx-exporter"].command
 	defaultMonitorPathToJar = "/jmx_prometheus_javaagent.jar"
+
+	/* Tiered Storage Config */
+
+	// KafkaBrokerPod.spec.initContainer["tiered-storage-plugins"].command
+	defaultTieredStoragePluginPath = "/plugins"
+	// topic based RemoteLogMetadataManager shipped with Kafka
+	defaultRemoteLogMetadataManagerClassName = "org.apache.kafka.server.log.remote.metadata.storage.TopicBasedRemoteLogMetadataManager"
 )
 
 // KafkaClusterSpec defines the desired state of KafkaCluster
@@ -183,6 +198,69 @@ type KafkaClusterSpec struct {
 	// The secret must contain the keystore, truststore jks files and the password for them in base64 encoded format
 	// under the keystore.jks, truststore.jks, password data fields.
 	ClientSSLCertSecret *corev1.LocalObjectReference `json:"clientSSLCertSecret,omitempty"`
+	// ClientSASL configures the credentials koperator authenticates with on sasl_plaintext and sasl_ssl internal listeners.
+	// It is required when none of the internal listeners can be reached without SASL.
+	// +optional
+	ClientSASL *ClientSASLConfig `json:"clientSASL,omitempty"`
+	// TieredStorage configures the remote log storage (KIP-405) of the brokers.
+	// It can be overridden per broker in the BrokerConfig.
+	// +optional
+	TieredStorage *TieredStorageConfig `json:"tieredStorage,omitempty"`
+	// RestoreFromBackup provisions the persistent volume claims of the brokers from the volume snapshots
+	// of a KafkaClusterBackup. It only affects the persistent volume claims created after it is set.
+	// +optional
+	RestoreFromBackup *RestoreFromBackup `json:"restoreFromBackup,omitempty"`
+	// ConfigSecretRefs sets broker configurations from secret keys, so their values are not stored in the KafkaCluster.
+	// The values are resolved by the brokers through the FileConfigProvider of Kafka.
+	// It can be extended or overridden per broker in the BrokerConfig.
+	// +optional
+	ConfigSecretRefs []ConfigSecretRef `json:"configSecretRefs,omitempty"`
+}
+
+// ConfigSecretRef sets the value of a broker configuration from a secret key
+type ConfigSecretRef struct {
+	// Config is the name of the broker configuration, e.g. listener.name.sasl_ssl.plain.sasl.jaas.config
+	// +kubebuilder:validation:MinLength=1
+	Config string `json:"config"`
+	// SecretKeyRef selects the key of the secret in the namespace of the KafkaCluster
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
+// TieredStorageConfig defines the remote log storage (KIP-405) configuration of the brokers
+type TieredStorageConfig struct {
+	// RemoteStorageManagerClassName is the fully qualified class name of the RemoteStorageManager implementation
+	RemoteStorageManagerClassName string `json:"remoteStorageManagerClassName"`
+	// RemoteLogMetadataManagerClassName is the fully qualified class name of the RemoteLogMetadataManager implementation,
+	// the topic based RemoteLogMetadataManager shipped with Kafka is used by default
+	// +optional
+	RemoteLogMetadataManagerClassName string `json:"remoteLogMetadataManagerClassName,omitempty"`
+	// PluginImage is the image containing the jars of the remote storage plugin
+	PluginImage string `json:"pluginImage"`
+	// PluginPath is the directory in the plugin image the plugin jars are copied from, defaults to /plugins
+	// +optional
+	PluginPath string `json:"pluginPath,omitempty"`
+	// Config holds the configuration of the RemoteStorageManager implementation. The keys are
+	// rendered into the broker configuration with the rsm.config. prefix
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+	// Credentials are exposed to the brokers as environment variables sourced from secrets,
+	// so they are not stored in the broker configuration
+	// +optional
+	Credentials []TieredStorageCredential `json:"credentials,omitempty"`
+}
+
+// TieredStorageCredential defines an environment variable of the brokers sourced from a secret key
+type TieredStorageCredential struct {
+	// EnvName is the name of the environment variable the credential is exposed as
+	EnvName string `json:"envName"`
+	// SecretKeyRef selects the key of the secret in the namespace of the KafkaCluster
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
+// RestoreFromBackup references the KafkaClusterBackup the broker volumes are restored from
+type RestoreFromBackup struct {
+	// Name of the KafkaClusterBackup in the namespace of the KafkaCluster
+	Name string `json:"name"`
 }
 
 // KafkaClusterStatus defines the observed state of KafkaCluster
@@ -193,6 +271,34 @@ type KafkaClusterStatus struct {
 	RollingUpgrade           RollingUpgradeStatus     `json:"rollingUpgradeStatus,omitempty"`
 	AlertCount               int                      `json:"alertCount"`
 	ListenerStatuses         ListenerStatuses         `json:"listenerStatuses,omitempty"`
+	// CruiseControl holds the latest observed readiness and executor state of Cruise Control
+	// +optional
+	CruiseControl *CruiseControlStatus `json:"cruiseControl,omitempty"`
+	// AdminClientListener is the name of the internal listener koperator connects to the brokers through
+	// +optional
+	AdminClientListener string `json:"adminClientListener,omitempty"`
+}
+
+// CruiseControlStatus holds information about the internal state of Cruise Control
+type CruiseControlStatus struct {
+	// Ready states that both the Monitor and the Analyzer components of Cruise Control are ready,
+	// thus Cruise Control is able to serve operations (e.g. add_broker, remove_broker)
+	Ready bool `json:"ready"`
+	// MonitorReady states that the load monitor of Cruise Control is running
+	MonitorReady bool `json:"monitorReady"`
+	// MonitorState is the state of the load monitor as reported by Cruise Control
+	MonitorState string `json:"monitorState,omitempty"`
+	// AnalyzerReady states that proposals are ready and the goals of Cruise Control are ready
+	AnalyzerReady bool `json:"analyzerReady"`
+	// ExecutorReady states that there is no task in progress in Cruise Control
+	ExecutorReady bool `json:"executorReady"`
+	// ExecutorState is the state of the executor as reported by Cruise Control
+	ExecutorState string `json:"executorState,omitempty"`
+	// NumValidWindows is the number of valid metric sample windows collected by Cruise Control.
+	// Until enough valid windows are collected Cruise Control fails with NotEnoughValidWindowsException.
+	NumValidWindows int32 `json:"numValidWindows"`
+	// MonitoringCoveragePercentage is the percentage of the partitions covered by the valid windows
+	MonitoringCoveragePercentage string `json:"monitoringCoveragePercentage,omitempty"`
 }
 
 // RollingUpgradeStatus defines status of rolling upgrade
@@ -222,6 +328,15 @@ type RollingUpgradeConfig struct {
 	// +kubebuilder:default=1
 	// +optional
 	ConcurrentBrokerRestartCountPerRack int `json:"concurrentBrokerRestartCountPerRack,omitempty"`
+
+	// ReplicasInSyncReadinessGate adds the "kafka.banzaicloud.io/replicas-in-sync" readiness gate to the broker pods.
+	// The operator sets the gate only after the broker has rejoined the in-sync replica set of all its partitions,
+	// so the broker pod is not ready (e.g. for PodDisruptionBudgets) while it is catching up, and the rolling upgrade
+	// waits for the gate before restarting the next broker. The broker services publish the addresses of the not ready
+	// brokers while the gate is enabled, so the brokers catching up stay reachable by the other brokers and the operator.
+	// Since readiness gates can not be changed on running pods, enabling or disabling it restarts the brokers.
+	// +optional
+	ReplicasInSyncReadinessGate bool `json:"replicasInSyncReadinessGate,omitempty"`
 }
 
 // DisruptionBudget defines the configuration for PodDisruptionBudget where the workload is managed by the kafka-operator
@@ -326,6 +441,17 @@ type BrokerConfig struct {
 	// If not specified, the broker pods' priority is default to zero.
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// TieredStorage overrides the remote log storage configuration of the KafkaCluster for the broker(s)
+	// +optional
+	TieredStorage *TieredStorageConfig `json:"tieredStorage,omitempty"`
+	// TopologySpreadConstraints controls how the broker pods are spread across the topology domains of the cluster.
+	// Constraints set on the broker override the ones set on its brokerConfigGroup.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// ConfigSecretRefs extends the configSecretRefs of the KafkaCluster for the broker(s).
+	// References set on the broker override the ones set on its brokerConfigGroup for the same configuration.
+	// +optional
+	ConfigSecretRefs []ConfigSecretRef `json:"configSecretRefs,omitempty"`
 }
 
 type NetworkConfig struct {
@@ -336,6 +462,23 @@ type NetworkConfig struct {
 // RackAwareness defines the required fields to enable kafka's rack aware feature
 type RackAwareness struct {
 	Labels []string `json:"labels"`
+	// ZonePinning pins every broker to a single zone. The zone label of the rack of the broker is the zone
+	// the broker is pinned to, the other rack awareness labels are read from the node it was scheduled to
+	// +optional
+	ZonePinning *ZonePinning `json:"zonePinning,omitempty"`
+}
+
+// ZonePinning defines how the brokers are distributed across the zones of the Kubernetes cluster.
+// Broker IDs are assigned to the zones in round-robin, so the zone and the rack of a broker never change
+// as long as the list of zones is not modified.
+type ZonePinning struct {
+	// Zones lists the values of the zone node label the brokers are distributed across
+	// +kubebuilder:validation:MinItems=1
+	Zones []string `json:"zones"`
+	// Label is the node label holding the zone of the nodes, it must be one of the rack awareness labels.
+	// Defaults to the first rack awareness label.
+	// +optional
+	Label string `json:"label,omitempty"`
 }
 
 // CruiseControlConfig defines the config for Cruise Control
@@ -397,6 +540,27 @@ func (c *CruiseControlOperationSpec) GetTTLSecondsAfterFinished() *int {
 type CruiseControlTaskSpec struct {
 	// RetryDurationMinutes describes the amount of time the Operator waits for the task
 	RetryDurationMinutes int `json:"RetryDurationMinutes"`
+	// ReplicationThrottle is the upper bound in bytes/sec on the inter-broker replication traffic
+	// of partition movements initiated by the operator (e.g. add_broker, remove_broker, rebalance).
+	// When not set, replication is not throttled.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicationThrottle *int64 `json:"replicationThrottle,omitempty"`
+	// ConcurrentPartitionMovementsPerBroker is the maximum number of partition movements
+	// in progress per broker. When not set, the Cruise Control default is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ConcurrentPartitionMovementsPerBroker *int32 `json:"concurrentPartitionMovementsPerBroker,omitempty"`
+	// ConcurrentLeaderMovements is the maximum number of leadership movements in progress in the cluster.
+	// When not set, the Cruise Control default is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ConcurrentLeaderMovements *int32 `json:"concurrentLeaderMovements,omitempty"`
+	// ExecutionProgressCheckIntervalMs is the interval in milliseconds at which Cruise Control checks
+	// the progress of the execution. When not set, the Cruise Control default is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ExecutionProgressCheckIntervalMs *int64 `json:"executionProgressCheckIntervalMs,omitempty"`
 }
 
 // TopicConfig holds info for topic configuration regarding partitions and replicationFactor
@@ -584,8 +748,53 @@ type SSLSecrets struct {
 	JKSPasswordName string                  `json:"jksPasswordName,omitempty"`
 	Create          bool                    `json:"create,omitempty"`
 	IssuerRef       *cmmeta.ObjectReference `json:"issuerRef,omitempty"`
-	// +kubebuilder:validation:Enum={"cert-manager"}
+	// +kubebuilder:validation:Enum={"cert-manager","vault"}
 	PKIBackend PKIBackend `json:"pkiBackend,omitempty"`
+	// VaultConfig configures the Vault PKI backend, it is required when the pkiBackend is vault
+	// +optional
+	VaultConfig *VaultConfig `json:"vaultConfig,omitempty"`
+	// ListenerKeystoreFormat is the keystore format the brokers load the generated listener server certificate from,
+	// defaults to jks. The pem-bundle format makes the brokers use ssl.keystore.type=PEM.
+	// +optional
+	ListenerKeystoreFormat KeystoreFormat `json:"listenerKeystoreFormat,omitempty"`
+}
+
+// GetListenerKeystoreFormat returns the keystore format of the generated listener server certificate, defaults to jks
+func (s *SSLSecrets) GetListenerKeystoreFormat() KeystoreFormat {
+	if s.ListenerKeystoreFormat == "" {
+		return KeystoreFormatJKS
+	}
+	return s.ListenerKeystoreFormat
+}
+
+// VaultConfig defines how the certificates are issued from the PKI secrets engine of Vault
+type VaultConfig struct {
+	// Address of the Vault server, the VAULT_ADDR environment variable of the operator is used when it is not set
+	// +optional
+	Address string `json:"address,omitempty"`
+	// AuthRole is the role of the Kubernetes auth method the operator logs in to Vault with.
+	// The operator uses the token in the VAULT_TOKEN environment variable instead when it is set.
+	AuthRole string `json:"authRole"`
+	// AuthPath is the mount path of the Kubernetes auth method, defaults to kubernetes
+	// +optional
+	AuthPath string `json:"authPath,omitempty"`
+	// PKIPath is the mount path of the PKI secrets engine the certificates are issued from.
+	// The PKI secrets engine has to have a CA configured, the operator does not generate one.
+	// +kubebuilder:validation:MinLength=1
+	PKIPath string `json:"pkiPath"`
+	// IssueRole is the role of the PKI secrets engine the certificates are issued with.
+	// The role has to allow any common name, non-hostname subject alternative names and SPIFFE URI SANs,
+	// and both the client and server key usages.
+	// +kubebuilder:validation:MinLength=1
+	IssueRole string `json:"issueRole"`
+}
+
+// GetAuthPath returns the mount path of the Kubernetes auth method, defaults to kubernetes
+func (c *VaultConfig) GetAuthPath() string {
+	if c.AuthPath == "" {
+		return "kubernetes"
+	}
+	return c.AuthPath
 }
 
 // TODO (tinyzimmer): The above are all optional now in one way or another.
@@ -593,6 +802,23 @@ type SSLSecrets struct {
 // E.g. TLSSecretName and JKSPasswordName are only required if Create is false
 // Or heck, do we even want to bother supporting an imported PKI?
 
+// DownScaleStrategy defines a rule the broker removed by the downScale alert command is selected by
+// +kubebuilder:validation:Enum=LeastReplicas;LeastLeaders;RackBalanced;AvoidController;NewestFirst
+type DownScaleStrategy string
+
+const (
+	// DownScaleLeastReplicas keeps the brokers hosting the least partition replicas
+	DownScaleLeastReplicas DownScaleStrategy = "LeastReplicas"
+	// DownScaleLeastLeaders keeps the brokers leading the least partitions
+	DownScaleLeastLeaders DownScaleStrategy = "LeastLeaders"
+	// DownScaleRackBalanced keeps the brokers of the racks with the most brokers
+	DownScaleRackBalanced DownScaleStrategy = "RackBalanced"
+	// DownScaleAvoidController drops the active controller
+	DownScaleAvoidController DownScaleStrategy = "AvoidController"
+	// DownScaleNewestFirst keeps the broker with the highest ID
+	DownScaleNewestFirst DownScaleStrategy = "NewestFirst"
+)
+
 // AlertManagerConfig defines configuration for alert manager
 type AlertManagerConfig struct {
 	// DownScaleLimit the limit for auto-downscaling the Kafka cluster.
@@ -603,6 +829,37 @@ type AlertManagerConfig struct {
 	// Once the size of the cluster (number of brokers) reaches or exceeds this limit the auto-upscaling triggered by alerts is disabled until the cluster size falls below this limit.
 	// This limit is not enforced if this field is omitted or is <= 0.
 	UpScaleLimit int `json:"upScaleLimit,omitempty"`
+	// DownScaleStrategies defines how the broker removed by the downScale alert command is selected when the alert
+	// does not have a brokerId label. The strategies are applied in order, each one narrowing down the brokers
+	// kept by the previous ones:
+	// "LeastReplicas" keeps the brokers hosting the least partition replicas,
+	// "LeastLeaders" keeps the brokers leading the least partitions,
+	// "RackBalanced" keeps the brokers of the racks with the most brokers, so the racks stay balanced,
+	// "AvoidController" drops the active controller,
+	// "NewestFirst" keeps the broker with the highest ID, which is the one added last.
+	// The broker with the highest ID is selected when more than one broker is left. Defaults to LeastReplicas.
+	// +optional
+	DownScaleStrategies []DownScaleStrategy `json:"downScaleStrategies,omitempty"`
+	// DryRun when true the commands triggered by alerts do not modify the Kafka cluster.
+	// The mutation a command would make is recorded in the status of the KafkaAlertAction belonging to the alert instead.
+	// Dry-run can also be enabled for a single alert by setting its "dryRun" annotation to "true".
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+	// CommandCooldownMinutes overrides the minimum time in minutes between two executions of an alert command
+	// for the Kafka cluster, keyed by the name of the command. Zero disables the cooldown of the command.
+	// The defaults are 30 minutes for rebalance, 10 minutes for restartBroker and demoteBroker,
+	// 1 minute for throttleReplication and no cooldown for the rest of the commands.
+	// +optional
+	CommandCooldownMinutes map[string]int `json:"commandCooldownMinutes,omitempty"`
+}
+
+// GetDownScaleStrategies returns the strategies the broker removed by the downScale alert command is selected by,
+// LeastReplicas when they are not specified
+func (c *AlertManagerConfig) GetDownScaleStrategies() []DownScaleStrategy {
+	if c == nil || len(c.DownScaleStrategies) == 0 {
+		return []DownScaleStrategy{DownScaleLeastReplicas}
+	}
+	return c.DownScaleStrategies
 }
 
 type IngressServiceSettings struct {
@@ -698,6 +955,10 @@ type CommonListenerSpec struct {
 	// +kubebuilder:validation:ExclusiveMinimum=true
 	// +kubebuilder:validation:Maximum=65535
 	ContainerPort int32 `json:"containerPort"`
+	// SASL configures the SASL mechanisms of sasl_plaintext and sasl_ssl listeners.
+	// When it is omitted the mechanisms have to be configured through the readOnlyConfig.
+	// +optional
+	SASL *SASLConfig `json:"sasl,omitempty"`
 }
 
 func (c *CommonListenerSpec) GetServerSSLCertSecretName() string {
@@ -707,6 +968,116 @@ func (c *CommonListenerSpec) GetServerSSLCertSecretName() string {
 	return c.ServerSSLCertSecret.Name
 }
 
+// SASLConfig defines the SASL mechanisms enabled on a listener
+type SASLConfig struct {
+	// Mechanisms enabled on the listener
+	// +kubebuilder:validation:MinItems=1
+	Mechanisms []SASLMechanism `json:"mechanisms"`
+	// InterBrokerMechanism is the mechanism the brokers authenticate with when the listener is used for inter broker
+	// or controller communication, defaults to the first mechanism
+	// +optional
+	InterBrokerMechanism SASLMechanism `json:"interBrokerMechanism,omitempty"`
+	// Plain configures the PLAIN mechanism
+	// +optional
+	Plain *PlainSASLConfig `json:"plain,omitempty"`
+	// Scram configures the SCRAM-SHA-256 and SCRAM-SHA-512 mechanisms
+	// +optional
+	Scram *ScramSASLConfig `json:"scram,omitempty"`
+	// OAuthBearer configures the OAUTHBEARER mechanism
+	// +optional
+	OAuthBearer *OAuthBearerSASLConfig `json:"oauthBearer,omitempty"`
+	// GSSAPI configures the GSSAPI mechanism
+	// +optional
+	GSSAPI *GSSAPISASLConfig `json:"gssapi,omitempty"`
+}
+
+// PlainSASLConfig defines the users of the PLAIN mechanism
+type PlainSASLConfig struct {
+	// UsersSecret is the name of the secret holding the passwords of the users keyed by their username
+	UsersSecret string `json:"usersSecret"`
+	// BrokerUser is the user the brokers authenticate with, it is required when PLAIN is the inter broker mechanism
+	// +optional
+	BrokerUser string `json:"brokerUser,omitempty"`
+}
+
+// ScramSASLConfig defines the credentials the brokers authenticate with using SCRAM. The credentials of the users
+// are stored in ZooKeeper.
+type ScramSASLConfig struct {
+	// BrokerCredentialsSecret is the name of the secret holding the username and the password of the brokers under
+	// the username and password keys, it is required when SCRAM is the inter broker mechanism
+	// +optional
+	BrokerCredentialsSecret string `json:"brokerCredentialsSecret,omitempty"`
+}
+
+// OAuthBearerSASLConfig defines how the JSON Web Tokens presented by the clients are validated
+type OAuthBearerSASLConfig struct {
+	// JWKSEndpointURL is the URL of the JSON Web Key Set the signature of the tokens is verified with
+	// +kubebuilder:validation:Pattern=`^https?://`
+	JWKSEndpointURL string `json:"jwksEndpointURL"`
+	// ExpectedIssuer is the issuer the iss claim of the tokens must match
+	// +optional
+	ExpectedIssuer string `json:"expectedIssuer,omitempty"`
+	// ExpectedAudience lists the audiences the aud claim of the tokens must contain one of
+	// +optional
+	ExpectedAudience []string `json:"expectedAudience,omitempty"`
+}
+
+// GSSAPISASLConfig defines the Kerberos principal of the brokers
+type GSSAPISASLConfig struct {
+	// ServiceName is the Kerberos service name of the brokers, defaults to kafka
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// Principal the brokers log in with, e.g. kafka/kafka.example.com@EXAMPLE.COM
+	Principal string `json:"principal"`
+	// KeytabSecret is the name of the secret holding the keytab of the principal under the keytab key.
+	// The keytab is readable only by the owner and the group of the mounted files, so the podSecurityContext
+	// of the brokers must set an fsGroup which the broker process is a member of.
+	KeytabSecret string `json:"keytabSecret"`
+}
+
+// HasMechanism returns true if the mechanism is enabled on the listener
+func (c *SASLConfig) HasMechanism(mechanism SASLMechanism) bool {
+	for _, m := range c.Mechanisms {
+		if m == mechanism {
+			return true
+		}
+	}
+	return false
+}
+
+// GetInterBrokerMechanism returns the mechanism the brokers authenticate with on the listener
+func (c *SASLConfig) GetInterBrokerMechanism() SASLMechanism {
+	if c.InterBrokerMechanism != "" || len(c.Mechanisms) == 0 {
+		return c.InterBrokerMechanism
+	}
+	return c.Mechanisms[0]
+}
+
+// GetServiceName returns the Kerberos service name of the brokers
+func (c *GSSAPISASLConfig) GetServiceName() string {
+	if c.ServiceName == "" {
+		return defaultKerberosServiceName
+	}
+	return c.ServiceName
+}
+
+// ClientSASLConfig defines how koperator authenticates with the brokers on SASL listeners
+type ClientSASLConfig struct {
+	// Mechanism koperator authenticates with, one of SCRAM-SHA-256, SCRAM-SHA-512 and OAUTHBEARER
+	Mechanism SASLMechanism `json:"mechanism"`
+	// CredentialsSecret is the name of the secret holding the credentials of koperator. SCRAM credentials are read
+	// from the username and password keys, OAUTHBEARER client credentials from the clientId and clientSecret keys.
+	CredentialsSecret string `json:"credentialsSecret"`
+	// TokenEndpointURL is the URL of the OAuth 2 token endpoint the access tokens are requested from using the
+	// client credentials grant, it is required for OAUTHBEARER
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	TokenEndpointURL string `json:"tokenEndpointURL,omitempty"`
+	// Scopes requested with the access tokens
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+}
+
 // ListenerStatuses holds information about the statuses of the configured listeners.
 // The internal and external listeners are stored in separate maps, and each listener can be looked up by name.
 type ListenerStatuses struct {
@@ -987,6 +1358,105 @@ func (bConfig *BrokerConfig) GetPriorityClassName() string {
 	return bConfig.PriorityClassName
 }
 
+// GetTieredStorageConfig returns the remote log storage configuration of the broker,
+// the configuration of the BrokerConfig takes precedence over the one of the KafkaCluster
+func (kSpec *KafkaClusterSpec) GetTieredStorageConfig(bConfig *BrokerConfig) *TieredStorageConfig {
+	if bConfig != nil && bConfig.TieredStorage != nil {
+		return bConfig.TieredStorage
+	}
+	return kSpec.TieredStorage
+}
+
+// GetConfigSecretRefs returns the config secret references of the broker sorted by the name of the configuration.
+// The references of the BrokerConfig take precedence over the ones of the KafkaCluster for the same configuration.
+func (kSpec *KafkaClusterSpec) GetConfigSecretRefs(bConfig *BrokerConfig) []ConfigSecretRef {
+	refs := make(map[string]ConfigSecretRef)
+	for _, ref := range kSpec.ConfigSecretRefs {
+		refs[ref.Config] = ref
+	}
+	if bConfig != nil {
+		// the references of the broker precede the ones of its brokerConfigGroup after merging the BrokerConfigs
+		overridden := make(map[string]bool)
+		for _, ref := range bConfig.ConfigSecretRefs {
+			if !overridden[ref.Config] {
+				refs[ref.Config] = ref
+				overridden[ref.Config] = true
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	configSecretRefs := make([]ConfigSecretRef, 0, len(refs))
+	for _, ref := range refs {
+		configSecretRefs = append(configSecretRefs, ref)
+	}
+	sort.Slice(configSecretRefs, func(i, j int) bool {
+		return configSecretRefs[i].Config < configSecretRefs[j].Config
+	})
+	return configSecretRefs
+}
+
+// IsTieredStorageEnabled returns true when the remote log storage is configured for all the brokers
+func (kSpec *KafkaClusterSpec) IsTieredStorageEnabled() bool {
+	if len(kSpec.Brokers) == 0 {
+		return false
+	}
+	for i := range kSpec.Brokers {
+		bConfig, err := kSpec.Brokers[i].GetBrokerConfig(*kSpec)
+		if err != nil || kSpec.GetTieredStorageConfig(bConfig) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// GetPluginPath returns the directory of the plugin image the remote storage plugin jars are copied from
+func (t *TieredStorageConfig) GetPluginPath() string {
+	if t.PluginPath != "" {
+		return t.PluginPath
+	}
+	return defaultTieredStoragePluginPath
+}
+
+// GetRemoteLogMetadataManagerClassName returns the class name of the RemoteLogMetadataManager implementation
+func (t *TieredStorageConfig) GetRemoteLogMetadataManagerClassName() string {
+	if t.RemoteLogMetadataManagerClassName != "" {
+		return t.RemoteLogMetadataManagerClassName
+	}
+	return defaultRemoteLogMetadataManagerClassName
+}
+
+// GetZoneLabel returns the node label holding the zone the brokers are pinned to
+func (z *ZonePinning) GetZoneLabel(rackAwareness *RackAwareness) string {
+	if z.Label != "" {
+		return z.Label
+	}
+	if len(rackAwareness.Labels) > 0 {
+		return rackAwareness.Labels[0]
+	}
+	return ""
+}
+
+// GetBrokerZone returns the zone the broker is pinned to when zone pinning is enabled
+func (kSpec *KafkaClusterSpec) GetBrokerZone(brokerId int32) (string, bool) {
+	if kSpec.RackAwareness == nil || kSpec.RackAwareness.ZonePinning == nil || len(kSpec.RackAwareness.ZonePinning.Zones) == 0 {
+		return "", false
+	}
+	zones := kSpec.RackAwareness.ZonePinning.Zones
+	index := int(brokerId) % len(zones)
+	if index < 0 {
+		index += len(zones)
+	}
+	return zones[index], true
+}
+
+// GetTopologySpreadConstraints returns the topology spread constraints of the broker pod
+func (bConfig *BrokerConfig) GetTopologySpreadConstraints() []corev1.TopologySpreadConstraint {
+	return bConfig.TopologySpreadConstraints
+}
+
 // GetImagePullSecrets returns the list of Secrets needed to pull Containers images from private repositories
 func (bConfig *BrokerConfig) GetImagePullSecrets() []corev1.LocalObjectReference {
 	return bConfig.ImagePullSecrets
@@ -1196,6 +1666,11 @@ func (b *Broker) GetBrokerConfig(kafkaClusterSpec KafkaClusterSpec) (*BrokerConf
 		return nil, errors.NewWithDetails("missing brokerConfigGroup", "key", b.BrokerConfigGroup)
 	}
 
+	topologySpreadConstraints := groupConfig.TopologySpreadConstraints
+	if len(bConfig.TopologySpreadConstraints) > 0 {
+		topologySpreadConstraints = bConfig.TopologySpreadConstraints
+	}
+
 	dstAffinity, err := mergeAffinity(groupConfig, bConfig)
 	if err != nil {
 		return nil, errors.WrapIf(err, "could not merge brokerConfig.Affinity with ConfigGroup.Affinity")
@@ -1212,6 +1687,7 @@ func (b *Broker) GetBrokerConfig(kafkaClusterSpec KafkaClusterSpec) (*BrokerConf
 		bConfig.Affinity = dstAffinity
 	}
 	bConfig.Envs = envs
+	bConfig.TopologySpreadConstraints = topologySpreadConstraints
 
 	return bConfig, nil
 }
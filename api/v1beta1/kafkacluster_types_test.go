@@ -458,3 +458,147 @@ func TestGetBrokerLabels(t *testing.T) {
 		t.Error("Expected:", expected, "Got:", result)
 	}
 }
+
+func TestIsTieredStorageEnabled(t *testing.T) {
+	tieredStorage := &TieredStorageConfig{
+		RemoteStorageManagerClassName: "io.aiven.kafka.tieredstorage.RemoteStorageManager",
+		PluginImage:                   "tiered-storage-plugin:latest",
+	}
+	testCases := []struct {
+		testName string
+		spec     KafkaClusterSpec
+		expected bool
+	}{
+		{
+			testName: "no brokers",
+			spec:     KafkaClusterSpec{TieredStorage: tieredStorage},
+			expected: false,
+		},
+		{
+			testName: "cluster wide tiered storage",
+			spec: KafkaClusterSpec{
+				TieredStorage: tieredStorage,
+				Brokers:       []Broker{{Id: 0}, {Id: 1}},
+			},
+			expected: true,
+		},
+		{
+			testName: "tiered storage only on the broker config group",
+			spec: KafkaClusterSpec{
+				BrokerConfigGroups: map[string]BrokerConfig{
+					"default": {TieredStorage: tieredStorage},
+				},
+				Brokers: []Broker{{Id: 0, BrokerConfigGroup: "default"}, {Id: 1, BrokerConfigGroup: "default"}},
+			},
+			expected: true,
+		},
+		{
+			testName: "tiered storage missing from a broker",
+			spec: KafkaClusterSpec{
+				Brokers: []Broker{{Id: 0, BrokerConfig: &BrokerConfig{TieredStorage: tieredStorage}}, {Id: 1}},
+			},
+			expected: false,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			assert.Equal(t, test.spec.IsTieredStorageEnabled(), test.expected)
+		})
+	}
+}
+
+func TestGetBrokerZone(t *testing.T) {
+	spec := KafkaClusterSpec{
+		RackAwareness: &RackAwareness{
+			Labels: []string{"topology.kubernetes.io/zone"},
+		},
+	}
+	_, ok := spec.GetBrokerZone(0)
+	assert.Equal(t, ok, false)
+
+	spec.RackAwareness.ZonePinning = &ZonePinning{Zones: []string{"a", "b", "c"}}
+	for brokerId, expected := range map[int32]string{0: "a", 1: "b", 2: "c", 3: "a", 101: "c"} {
+		zone, ok := spec.GetBrokerZone(brokerId)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, zone, expected)
+	}
+	assert.Equal(t, spec.RackAwareness.ZonePinning.GetZoneLabel(spec.RackAwareness), "topology.kubernetes.io/zone")
+}
+
+func TestGetBrokerConfigTopologySpreadConstraints(t *testing.T) {
+	groupConstraints := []corev1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+	}
+	brokerConstraints := []corev1.TopologySpreadConstraint{
+		{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway},
+	}
+	spec := KafkaClusterSpec{
+		BrokerConfigGroups: map[string]BrokerConfig{
+			"default": {TopologySpreadConstraints: groupConstraints},
+		},
+	}
+
+	broker := Broker{Id: 0, BrokerConfigGroup: "default"}
+	bConfig, err := broker.GetBrokerConfig(spec)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, bConfig.GetTopologySpreadConstraints(), groupConstraints)
+
+	broker.BrokerConfig = &BrokerConfig{TopologySpreadConstraints: brokerConstraints}
+	bConfig, err = broker.GetBrokerConfig(spec)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, bConfig.GetTopologySpreadConstraints(), brokerConstraints)
+}
+
+func TestGetConfigSecretRefs(t *testing.T) {
+	secretKeyRef := func(name, key string) corev1.SecretKeySelector {
+		return corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key}
+	}
+	spec := KafkaClusterSpec{
+		ConfigSecretRefs: []ConfigSecretRef{
+			{Config: "ssl.truststore.password", SecretKeyRef: secretKeyRef("cluster", "truststore")},
+			{Config: "rsm.config.access.key", SecretKeyRef: secretKeyRef("cluster", "access-key")},
+		},
+		BrokerConfigGroups: map[string]BrokerConfig{
+			"default": {
+				ConfigSecretRefs: []ConfigSecretRef{
+					{Config: "ssl.truststore.password", SecretKeyRef: secretKeyRef("group", "truststore")},
+					{Config: "sasl.jaas.config", SecretKeyRef: secretKeyRef("group", "jaas")},
+				},
+			},
+		},
+	}
+	assert.Assert(t, spec.GetConfigSecretRefs(nil) != nil)
+	assert.Equal(t, len((&KafkaClusterSpec{}).GetConfigSecretRefs(&BrokerConfig{})), 0)
+
+	broker := Broker{
+		Id:                0,
+		BrokerConfigGroup: "default",
+		BrokerConfig: &BrokerConfig{
+			ConfigSecretRefs: []ConfigSecretRef{
+				{Config: "sasl.jaas.config", SecretKeyRef: secretKeyRef("broker", "jaas")},
+			},
+		},
+	}
+	bConfig, err := broker.GetBrokerConfig(spec)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, spec.GetConfigSecretRefs(bConfig), []ConfigSecretRef{
+		{Config: "rsm.config.access.key", SecretKeyRef: secretKeyRef("cluster", "access-key")},
+		{Config: "sasl.jaas.config", SecretKeyRef: secretKeyRef("broker", "jaas")},
+		{Config: "ssl.truststore.password", SecretKeyRef: secretKeyRef("group", "truststore")},
+	})
+}
+
+func TestSASLConfig(t *testing.T) {
+	sasl := &SASLConfig{Mechanisms: []SASLMechanism{SASLMechanismScramSHA512, SASLMechanismPlain}}
+	assert.Equal(t, sasl.GetInterBrokerMechanism(), SASLMechanismScramSHA512)
+	assert.Assert(t, sasl.HasMechanism(SASLMechanismPlain))
+	assert.Assert(t, !sasl.HasMechanism(SASLMechanismGSSAPI))
+	assert.Equal(t, SASLMechanismScramSHA512.ConfigPrefix(), "scram-sha-512")
+	assert.Assert(t, SASLMechanismScramSHA256.IsScram())
+
+	sasl.InterBrokerMechanism = SASLMechanismPlain
+	assert.Equal(t, sasl.GetInterBrokerMechanism(), SASLMechanismPlain)
+
+	assert.Equal(t, (&GSSAPISASLConfig{}).GetServiceName(), "kafka")
+	assert.Equal(t, (&GSSAPISASLConfig{ServiceName: "broker"}).GetServiceName(), "broker")
+}
@@ -31,6 +31,18 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertManagerConfig) DeepCopyInto(out *AlertManagerConfig) {
 	*out = *in
+	if in.DownScaleStrategies != nil {
+		in, out := &in.DownScaleStrategies, &out.DownScaleStrategies
+		*out = make([]DownScaleStrategy, len(*in))
+		copy(*out, *in)
+	}
+	if in.CommandCooldownMinutes != nil {
+		in, out := &in.CommandCooldownMinutes, &out.CommandCooldownMinutes
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertManagerConfig.
@@ -183,6 +195,25 @@ func (in *BrokerConfig) DeepCopyInto(out *BrokerConfig) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.TieredStorage != nil {
+		in, out := &in.TieredStorage, &out.TieredStorage
+		*out = new(TieredStorageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigSecretRefs != nil {
+		in, out := &in.ConfigSecretRefs, &out.ConfigSecretRefs
+		*out = make([]ConfigSecretRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerConfig.
@@ -195,6 +226,21 @@ func (in *BrokerConfig) DeepCopy() *BrokerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerLoad) DeepCopyInto(out *BrokerLoad) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerLoad.
+func (in *BrokerLoad) DeepCopy() *BrokerLoad {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerLoad)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BrokerState) DeepCopyInto(out *BrokerState) {
 	*out = *in
@@ -204,6 +250,23 @@ func (in *BrokerState) DeepCopyInto(out *BrokerState) {
 		*out = make(ExternalListenerConfigNames, len(*in))
 		copy(*out, *in)
 	}
+	if in.Load != nil {
+		in, out := &in.Load, &out.Load
+		*out = new(BrokerLoad)
+		**out = **in
+	}
+	if in.PendingConfigChanges != nil {
+		in, out := &in.PendingConfigChanges, &out.PendingConfigChanges
+		*out = make([]PendingConfigChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.ListenerCertificates != nil {
+		in, out := &in.ListenerCertificates, &out.ListenerCertificates
+		*out = make(ListenerCertificateStates, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerState.
@@ -216,6 +279,26 @@ func (in *BrokerState) DeepCopy() *BrokerState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientSASLConfig) DeepCopyInto(out *ClientSASLConfig) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientSASLConfig.
+func (in *ClientSASLConfig) DeepCopy() *ClientSASLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientSASLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonListenerSpec) DeepCopyInto(out *CommonListenerSpec) {
 	*out = *in
@@ -224,6 +307,11 @@ func (in *CommonListenerSpec) DeepCopyInto(out *CommonListenerSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.SASL != nil {
+		in, out := &in.SASL, &out.SASL
+		*out = new(SASLConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonListenerSpec.
@@ -258,10 +346,26 @@ func (in *Config) DeepCopy() *Config {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSecretRef) DeepCopyInto(out *ConfigSecretRef) {
+	*out = *in
+	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSecretRef.
+func (in *ConfigSecretRef) DeepCopy() *ConfigSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CruiseControlConfig) DeepCopyInto(out *CruiseControlConfig) {
 	*out = *in
-	out.CruiseControlTaskSpec = in.CruiseControlTaskSpec
+	in.CruiseControlTaskSpec.DeepCopyInto(&out.CruiseControlTaskSpec)
 	if in.CruiseControlOperationSpec != nil {
 		in, out := &in.CruiseControlOperationSpec, &out.CruiseControlOperationSpec
 		*out = new(CruiseControlOperationSpec)
@@ -371,9 +475,44 @@ func (in *CruiseControlOperationSpec) DeepCopy() *CruiseControlOperationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CruiseControlStatus) DeepCopyInto(out *CruiseControlStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CruiseControlStatus.
+func (in *CruiseControlStatus) DeepCopy() *CruiseControlStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CruiseControlStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CruiseControlTaskSpec) DeepCopyInto(out *CruiseControlTaskSpec) {
 	*out = *in
+	if in.ReplicationThrottle != nil {
+		in, out := &in.ReplicationThrottle, &out.ReplicationThrottle
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ConcurrentPartitionMovementsPerBroker != nil {
+		in, out := &in.ConcurrentPartitionMovementsPerBroker, &out.ConcurrentPartitionMovementsPerBroker
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConcurrentLeaderMovements != nil {
+		in, out := &in.ConcurrentLeaderMovements, &out.ConcurrentLeaderMovements
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExecutionProgressCheckIntervalMs != nil {
+		in, out := &in.ExecutionProgressCheckIntervalMs, &out.ExecutionProgressCheckIntervalMs
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CruiseControlTaskSpec.
@@ -571,6 +710,21 @@ func (in ExternalListenerConfigNames) DeepCopy() ExternalListenerConfigNames {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GSSAPISASLConfig) DeepCopyInto(out *GSSAPISASLConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GSSAPISASLConfig.
+func (in *GSSAPISASLConfig) DeepCopy() *GSSAPISASLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GSSAPISASLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GracefulActionState) DeepCopyInto(out *GracefulActionState) {
 	*out = *in
@@ -855,7 +1009,7 @@ func (in *KafkaClusterSpec) DeepCopyInto(out *KafkaClusterSpec) {
 	if in.AlertManagerConfig != nil {
 		in, out := &in.AlertManagerConfig, &out.AlertManagerConfig
 		*out = new(AlertManagerConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	in.IstioIngressConfig.DeepCopyInto(&out.IstioIngressConfig)
 	if in.Envs != nil {
@@ -870,6 +1024,28 @@ func (in *KafkaClusterSpec) DeepCopyInto(out *KafkaClusterSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.ClientSASL != nil {
+		in, out := &in.ClientSASL, &out.ClientSASL
+		*out = new(ClientSASLConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TieredStorage != nil {
+		in, out := &in.TieredStorage, &out.TieredStorage
+		*out = new(TieredStorageConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestoreFromBackup != nil {
+		in, out := &in.RestoreFromBackup, &out.RestoreFromBackup
+		*out = new(RestoreFromBackup)
+		**out = **in
+	}
+	if in.ConfigSecretRefs != nil {
+		in, out := &in.ConfigSecretRefs, &out.ConfigSecretRefs
+		*out = make([]ConfigSecretRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterSpec.
@@ -894,6 +1070,11 @@ func (in *KafkaClusterStatus) DeepCopyInto(out *KafkaClusterStatus) {
 	}
 	out.RollingUpgrade = in.RollingUpgrade
 	in.ListenerStatuses.DeepCopyInto(&out.ListenerStatuses)
+	if in.CruiseControl != nil {
+		in, out := &in.CruiseControl, &out.CruiseControl
+		*out = new(CruiseControlStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaClusterStatus.
@@ -921,6 +1102,47 @@ func (in *KafkaVersion) DeepCopy() *KafkaVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListenerCertificateState) DeepCopyInto(out *ListenerCertificateState) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	if in.ReloadRequestedAt != nil {
+		in, out := &in.ReloadRequestedAt, &out.ReloadRequestedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerCertificateState.
+func (in *ListenerCertificateState) DeepCopy() *ListenerCertificateState {
+	if in == nil {
+		return nil
+	}
+	out := new(ListenerCertificateState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ListenerCertificateStates) DeepCopyInto(out *ListenerCertificateStates) {
+	{
+		in := &in
+		*out = make(ListenerCertificateStates, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListenerCertificateStates.
+func (in ListenerCertificateStates) DeepCopy() ListenerCertificateStates {
+	if in == nil {
+		return nil
+	}
+	out := new(ListenerCertificateStates)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenerStatus) DeepCopyInto(out *ListenerStatus) {
 	*out = *in
@@ -1071,6 +1293,56 @@ func (in *NetworkConfig) DeepCopy() *NetworkConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuthBearerSASLConfig) DeepCopyInto(out *OAuthBearerSASLConfig) {
+	*out = *in
+	if in.ExpectedAudience != nil {
+		in, out := &in.ExpectedAudience, &out.ExpectedAudience
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthBearerSASLConfig.
+func (in *OAuthBearerSASLConfig) DeepCopy() *OAuthBearerSASLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuthBearerSASLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingConfigChange) DeepCopyInto(out *PendingConfigChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingConfigChange.
+func (in *PendingConfigChange) DeepCopy() *PendingConfigChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingConfigChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlainSASLConfig) DeepCopyInto(out *PlainSASLConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlainSASLConfig.
+func (in *PlainSASLConfig) DeepCopy() *PlainSASLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PlainSASLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RackAwareness) DeepCopyInto(out *RackAwareness) {
 	*out = *in
@@ -1079,6 +1351,11 @@ func (in *RackAwareness) DeepCopyInto(out *RackAwareness) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ZonePinning != nil {
+		in, out := &in.ZonePinning, &out.ZonePinning
+		*out = new(ZonePinning)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RackAwareness.
@@ -1091,6 +1368,21 @@ func (in *RackAwareness) DeepCopy() *RackAwareness {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreFromBackup) DeepCopyInto(out *RestoreFromBackup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreFromBackup.
+func (in *RestoreFromBackup) DeepCopy() *RestoreFromBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreFromBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpgradeConfig) DeepCopyInto(out *RollingUpgradeConfig) {
 	*out = *in
@@ -1121,6 +1413,46 @@ func (in *RollingUpgradeStatus) DeepCopy() *RollingUpgradeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SASLConfig) DeepCopyInto(out *SASLConfig) {
+	*out = *in
+	if in.Mechanisms != nil {
+		in, out := &in.Mechanisms, &out.Mechanisms
+		*out = make([]SASLMechanism, len(*in))
+		copy(*out, *in)
+	}
+	if in.Plain != nil {
+		in, out := &in.Plain, &out.Plain
+		*out = new(PlainSASLConfig)
+		**out = **in
+	}
+	if in.Scram != nil {
+		in, out := &in.Scram, &out.Scram
+		*out = new(ScramSASLConfig)
+		**out = **in
+	}
+	if in.OAuthBearer != nil {
+		in, out := &in.OAuthBearer, &out.OAuthBearer
+		*out = new(OAuthBearerSASLConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GSSAPI != nil {
+		in, out := &in.GSSAPI, &out.GSSAPI
+		*out = new(GSSAPISASLConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SASLConfig.
+func (in *SASLConfig) DeepCopy() *SASLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SASLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSLSecrets) DeepCopyInto(out *SSLSecrets) {
 	*out = *in
@@ -1129,6 +1461,11 @@ func (in *SSLSecrets) DeepCopyInto(out *SSLSecrets) {
 		*out = new(metav1.ObjectReference)
 		**out = **in
 	}
+	if in.VaultConfig != nil {
+		in, out := &in.VaultConfig, &out.VaultConfig
+		*out = new(VaultConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSLSecrets.
@@ -1141,6 +1478,21 @@ func (in *SSLSecrets) DeepCopy() *SSLSecrets {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScramSASLConfig) DeepCopyInto(out *ScramSASLConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScramSASLConfig.
+func (in *ScramSASLConfig) DeepCopy() *ScramSASLConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ScramSASLConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
 	*out = *in
@@ -1166,6 +1518,51 @@ func (in *StorageConfig) DeepCopy() *StorageConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TieredStorageConfig) DeepCopyInto(out *TieredStorageConfig) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]TieredStorageCredential, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TieredStorageConfig.
+func (in *TieredStorageConfig) DeepCopy() *TieredStorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TieredStorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TieredStorageCredential) DeepCopyInto(out *TieredStorageCredential) {
+	*out = *in
+	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TieredStorageCredential.
+func (in *TieredStorageCredential) DeepCopy() *TieredStorageCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(TieredStorageCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopicConfig) DeepCopyInto(out *TopicConfig) {
 	*out = *in
@@ -1181,6 +1578,21 @@ func (in *TopicConfig) DeepCopy() *TopicConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultConfig) DeepCopyInto(out *VaultConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConfig.
+func (in *VaultConfig) DeepCopy() *VaultConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeState) DeepCopyInto(out *VolumeState) {
 	*out = *in
@@ -1200,3 +1612,23 @@ func (in *VolumeState) DeepCopy() *VolumeState {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZonePinning) DeepCopyInto(out *ZonePinning) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZonePinning.
+func (in *ZonePinning) DeepCopy() *ZonePinning {
+	if in == nil {
+		return nil
+	}
+	out := new(ZonePinning)
+	in.DeepCopyInto(out)
+	return out
+}
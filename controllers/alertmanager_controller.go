@@ -16,38 +16,120 @@ package controllers
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/banzaicloud/koperator/internal/alertmanager"
+	"github.com/banzaicloud/koperator/internal/alertmanager/currentalert"
+	"github.com/banzaicloud/koperator/internal/alertmanager/receiver"
 	"github.com/banzaicloud/koperator/pkg/util"
 )
 
 const (
 	receiverAddr = ":9001"
+	// alertActionGCInterval is the time between two garbage collections of the expired KafkaAlertActions
+	alertActionGCInterval = 10 * time.Minute
 )
 
+// AlertManagerConfig configures the receiver of the Prometheus Alertmanager webhook
+type AlertManagerConfig struct {
+	receiver.Options
+	// TLSCertDir is the directory with a tls.crt and tls.key the receiver serves HTTPS with. When the directory
+	// contains a ca.crt as well, clients have to present a certificate signed by it.
+	TLSCertDir string
+}
+
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaalertactions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaalertactions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 // AController implements Runnable
 type AController struct {
-	Client client.Client
+	Client   client.Client
+	Recorder record.EventRecorder
+	Config   AlertManagerConfig
 }
 
 // SetAlertManagerWithManager creates a new Alertmanager Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-func SetAlertManagerWithManager(mgr manager.Manager) error {
-	return mgr.Add(AController{Client: mgr.GetClient()})
+func SetAlertManagerWithManager(mgr manager.Manager, config AlertManagerConfig) error {
+	// the credentials secret may be in a namespace which is not watched by the manager
+	config.SecretReader = mgr.GetAPIReader()
+	return mgr.Add(AController{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("alertmanager"),
+		Config:   config,
+	})
 }
 
 // Start initiates the alertmanager controller
 func (c AController) Start(ctx context.Context) error {
 	logf.SetLogger(util.CreateLogger(false, false))
 	log := logf.Log.WithName("alertmanager")
+	currentalert.GetCurrentAlerts().SetEventRecorder(c.Recorder)
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		currentalert.GetCurrentAlerts().CollectAlertActions(ctx, c.Client, log)
+	}, alertActionGCInterval)
+
+	ln, err := net.Listen("tcp", receiverAddr)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not listen for alerts", "address", receiverAddr)
+	}
+
+	if c.Config.TLSCertDir != "" {
+		tlsConfig, err := c.tlsConfig(ctx)
+		if err != nil {
+			return err
+		}
+		ln = tls.NewListener(ln, tlsConfig)
+	}
 
-	ln, _ := net.Listen("tcp", receiverAddr)
-	httpServer := &http.Server{Handler: alertmanager.NewApp(log, c.Client)}
+	httpServer := &http.Server{Handler: alertmanager.NewApp(log, c.Client, c.Config.Options)}
 	return httpServer.Serve(ln)
 }
+
+// tlsConfig returns the TLS configuration of the receiver. The serving certificate is reloaded when it changes on disk.
+func (c AController) tlsConfig(ctx context.Context) (*tls.Config, error) {
+	watcher, err := certwatcher.New(filepath.Join(c.Config.TLSCertDir, "tls.crt"), filepath.Join(c.Config.TLSCertDir, "tls.key"))
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not load alert receiver certificate", "directory", c.Config.TLSCertDir)
+	}
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			logf.Log.WithName("alertmanager").Error(err, "alert receiver certificate watcher stopped")
+		}
+	}()
+
+	tlsConfig := &tls.Config{
+		GetCertificate: watcher.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	caCert, err := os.ReadFile(filepath.Join(c.Config.TLSCertDir, "ca.crt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return tlsConfig, nil
+	}
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not read alert receiver client CA", "directory", c.Config.TLSCertDir)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caCert) {
+		return nil, errors.NewWithDetails("alert receiver client CA does not contain any valid certificate", "directory", c.Config.TLSCertDir)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
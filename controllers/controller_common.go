@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"fmt"
+	"reflect"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -142,3 +143,23 @@ func (p SkipClusterRegistryOwnedResourcePredicate) Update(e event.UpdateEvent) b
 func (p SkipClusterRegistryOwnedResourcePredicate) Generic(e event.GenericEvent) bool {
 	return !util.ObjectManagedByClusterRegistry(e.Object)
 }
+
+// brokersStateChanged reports whether the broker states differ, ignoring the periodically refreshed broker load
+// information so that load updates alone do not trigger reconciliations.
+func brokersStateChanged(oldStates, newStates map[string]v1beta1.BrokerState) bool {
+	if len(oldStates) != len(newStates) {
+		return true
+	}
+	for brokerID, oldState := range oldStates {
+		newState, ok := newStates[brokerID]
+		if !ok {
+			return true
+		}
+		oldState.Load = nil
+		newState.Load = nil
+		if !reflect.DeepEqual(oldState, newState) {
+			return true
+		}
+	}
+	return false
+}
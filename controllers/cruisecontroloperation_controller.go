@@ -57,7 +57,9 @@ var (
 	executionPriorityMap            = map[banzaiv1alpha1.CruiseControlTaskOperation]int{
 		banzaiv1alpha1.OperationAddBroker:    2,
 		banzaiv1alpha1.OperationRemoveBroker: 1,
+		banzaiv1alpha1.OperationRemoveDisks:  1,
 		banzaiv1alpha1.OperationRebalance:    0,
+		banzaiv1alpha1.OperationDemoteBroker: 0,
 	}
 	missingCCResErr = errors.New("missing Cruise Control user task result")
 )
@@ -159,11 +161,6 @@ func (r *CruiseControlOperationReconciler) Reconcile(ctx context.Context, reques
 		return requeueAfter(defaultRequeueIntervalInSeconds)
 	}
 
-	if !status.IsReady() {
-		log.Info("requeue event as Cruise Control is not ready (yet)", "status", status)
-		return requeueAfter(defaultRequeueIntervalInSeconds)
-	}
-
 	// Filtering out CruiseControlOperation by kafka cluster ref and state
 	var ccOperationsKafkaClusterFiltered []*banzaiv1alpha1.CruiseControlOperation
 	for i := range ccOperationListClusterWide.Items {
@@ -187,6 +184,21 @@ func (r *CruiseControlOperationReconciler) Reconcile(ctx context.Context, reques
 		return requeueAfter(defaultRequeueIntervalInSeconds)
 	}
 
+	// Cancelling the operations of the cluster whose cancellation is requested
+	cancelInProgress, err := r.cancelOperations(ctx, ccOperationsKafkaClusterFiltered)
+	if err != nil {
+		return requeueWithError(log, "could not cancel CruiseControlOperation", err)
+	}
+	if cancelInProgress {
+		// Waiting for the executor of Cruise Control to stop the cancelled task
+		return requeueAfter(defaultRequeueIntervalInSeconds)
+	}
+
+	if !status.IsReady() {
+		log.Info("requeue event as Cruise Control is not ready (yet)", "status", status)
+		return requeueAfter(defaultRequeueIntervalInSeconds)
+	}
+
 	// When the task is not in execution we can remove the finalizer
 	if isFinalizerNeeded(currentCCOperation) && !currentCCOperation.IsCurrentTaskRunning() {
 		controllerutil.RemoveFinalizer(currentCCOperation, ccOperationFinalizerGroup)
@@ -207,13 +219,34 @@ func (r *CruiseControlOperationReconciler) Reconcile(ctx context.Context, reques
 	}
 
 	ccOperationExecution := selectOperationForExecution(ccOperationQueueMap)
+	// Check if CruiseControl is ready as we cannot perform any operation until it is in ready state unless it is a stop execution operation
+	waitsForCruiseControl := ccOperationExecution != nil && (status.InExecution() || len(ccOperationQueueMap[ccOperationInProgress]) > 0) &&
+		ccOperationExecution.CurrentTaskOperation() != banzaiv1alpha1.OperationStopExecution
+
+	// The queue position of the executed operation is cleared together with its result
+	executed := ccOperationExecution
+	if waitsForCruiseControl {
+		executed = nil
+	}
+	if err := r.updateQueuePositions(ctx, ccOperationsKafkaClusterFiltered, ccOperationQueueMap, executed); err != nil {
+		log.Error(err, "could not update the queue position of CruiseControlOperations")
+	}
+
 	// There is nothing to be executed for now, requeue
 	if ccOperationExecution == nil {
 		return requeueAfter(defaultRequeueIntervalInSeconds)
 	}
 
-	// Check if CruiseControl is ready as we cannot perform any operation until it is in ready state unless it is a stop execution operation
-	if (status.InExecution() || len(ccOperationQueueMap[ccOperationInProgress]) > 0) && ccOperationExecution.CurrentTaskOperation() != banzaiv1alpha1.OperationStopExecution {
+	if waitsForCruiseControl {
+		// The selected operation can stop the running one when it has higher priority
+		if len(ccOperationQueueMap[ccOperationInProgress]) > 0 && ccOperationExecution.CanPreempt(ccOperationQueueMap[ccOperationInProgress][0]) {
+			preempted := ccOperationQueueMap[ccOperationInProgress][0]
+			log.Info("preempting running Cruise Control task", "name", preempted.GetName(), "priority", preempted.GetPriority(),
+				"preemptedBy", ccOperationExecution.GetName(), "preemptorPriority", ccOperationExecution.GetPriority())
+			if err := r.preemptOperation(ctx, preempted, ccOperationExecution); err != nil {
+				return requeueWithError(log, "could not preempt the running CruiseControlOperation", err)
+			}
+		}
 		// Requeue because we can't do more
 		return requeueAfter(defaultRequeueIntervalInSeconds)
 	}
@@ -274,6 +307,10 @@ func (r *CruiseControlOperationReconciler) executeOperation(ctx context.Context,
 		cruseControlTaskResult, err = r.scaler.RemoveBrokersWithParams(ctx, ccOperationExecution.CurrentTaskParameters())
 	case banzaiv1alpha1.OperationRebalance:
 		cruseControlTaskResult, err = r.scaler.RebalanceWithParams(ctx, ccOperationExecution.CurrentTaskParameters())
+	case banzaiv1alpha1.OperationRemoveDisks:
+		cruseControlTaskResult, err = r.scaler.RemoveDisksWithParams(ctx, ccOperationExecution.CurrentTaskParameters())
+	case banzaiv1alpha1.OperationDemoteBroker:
+		cruseControlTaskResult, err = r.scaler.DemoteBrokersWithParams(ctx, ccOperationExecution.CurrentTaskParameters())
 	case banzaiv1alpha1.OperationStopExecution:
 		cruseControlTaskResult, err = r.scaler.StopExecution(ctx)
 	case banzaiv1alpha1.OperationStatus:
@@ -288,6 +325,9 @@ func sortOperations(ccOperations []*banzaiv1alpha1.CruiseControlOperation) map[s
 	ccOperationQueueMap := make(map[string][]*banzaiv1alpha1.CruiseControlOperation)
 	for _, ccOperation := range ccOperations {
 		switch {
+		// Operations being cancelled are not executed, stopped or preempted through the queues
+		case ccOperation.IsCancelRequested():
+			continue
 		case isWaitingForFinalization(ccOperation):
 			ccOperationQueueMap[ccOperationForStopExecution] = append(ccOperationQueueMap[ccOperationForStopExecution], ccOperation)
 		case ccOperation.IsWaitingForFirstExecution():
@@ -299,43 +339,228 @@ func sortOperations(ccOperations []*banzaiv1alpha1.CruiseControlOperation) map[s
 		}
 	}
 
-	// Sorting by operation type and by the k8s object creation time
+	// Sorting by priority, operation type and by the k8s object creation time
 	for key := range ccOperationQueueMap {
 		ccOperationQueue := ccOperationQueueMap[key]
 		sort.SliceStable(ccOperationQueue, func(i, j int) bool {
-			return executionPriorityMap[ccOperationQueue[i].CurrentTaskOperation()] > executionPriorityMap[ccOperationQueue[j].CurrentTaskOperation()] ||
-				(executionPriorityMap[ccOperationQueue[i].CurrentTaskOperation()] == executionPriorityMap[ccOperationQueue[j].CurrentTaskOperation()] &&
-					ccOperationQueue[i].CreationTimestamp.Unix() < ccOperationQueue[j].CreationTimestamp.Unix())
+			return isExecutedBefore(ccOperationQueue[i], ccOperationQueue[j])
 		})
 	}
 	return ccOperationQueueMap
 }
 
+// isExecutedBefore returns true if operation a precedes operation b in the same queue.
+func isExecutedBefore(a, b *banzaiv1alpha1.CruiseControlOperation) bool {
+	if a.GetPriority() != b.GetPriority() {
+		return a.GetPriority() > b.GetPriority()
+	}
+	return executionPriorityMap[a.CurrentTaskOperation()] > executionPriorityMap[b.CurrentTaskOperation()] ||
+		(executionPriorityMap[a.CurrentTaskOperation()] == executionPriorityMap[b.CurrentTaskOperation()] &&
+			a.CreationTimestamp.Unix() < b.CreationTimestamp.Unix())
+}
+
+// queueRank returns the rank of the operation in the execution order when the priorities are equal:
+// add_broker operations come first, then the failed tasks waiting for retry and then the rest of the operations.
+func queueRank(ccOperation *banzaiv1alpha1.CruiseControlOperation) int {
+	switch {
+	case ccOperation.IsWaitingForRetryExecution():
+		return 1
+	case ccOperation.CurrentTaskOperation() == banzaiv1alpha1.OperationAddBroker:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// waitingOperations returns the operations waiting for their (first or retry) execution in the order
+// they are going to be executed.
+func waitingOperations(ccOperationQueueMap map[string][]*banzaiv1alpha1.CruiseControlOperation) []*banzaiv1alpha1.CruiseControlOperation {
+	waiting := make([]*banzaiv1alpha1.CruiseControlOperation, 0,
+		len(ccOperationQueueMap[ccOperationFirstExecution])+len(ccOperationQueueMap[ccOperationRetryExecution]))
+	waiting = append(waiting, ccOperationQueueMap[ccOperationFirstExecution]...)
+	waiting = append(waiting, ccOperationQueueMap[ccOperationRetryExecution]...)
+
+	sort.SliceStable(waiting, func(i, j int) bool {
+		if waiting[i].GetPriority() != waiting[j].GetPriority() {
+			return waiting[i].GetPriority() > waiting[j].GetPriority()
+		}
+		if queueRank(waiting[i]) != queueRank(waiting[j]) {
+			return queueRank(waiting[i]) > queueRank(waiting[j])
+		}
+		return isExecutedBefore(waiting[i], waiting[j])
+	})
+	return waiting
+}
+
 func selectOperationForExecution(ccOperationQueueMap map[string][]*banzaiv1alpha1.CruiseControlOperation) *banzaiv1alpha1.CruiseControlOperation {
 	// SELECTING OPERATION FOR EXECUTION
-	var ccOperationExecution *banzaiv1alpha1.CruiseControlOperation
 	// First prio: execute the finalize task
-	switch {
-	case len(ccOperationQueueMap[ccOperationForStopExecution]) > 0:
-		ccOperationExecution = ccOperationQueueMap[ccOperationForStopExecution][0]
+	if len(ccOperationQueueMap[ccOperationForStopExecution]) > 0 {
+		ccOperationExecution := ccOperationQueueMap[ccOperationForStopExecution][0]
 		ccOperationExecution.CurrentTask().Operation = banzaiv1alpha1.OperationStopExecution
-	// Second prio: execute add_broker operation
-	case len(ccOperationQueueMap[ccOperationFirstExecution]) > 0 && ccOperationQueueMap[ccOperationFirstExecution][0].CurrentTaskOperation() == banzaiv1alpha1.OperationAddBroker:
-		ccOperationExecution = ccOperationQueueMap[ccOperationFirstExecution][0]
-	// Third prio: execute failed task
-	case len(ccOperationQueueMap[ccOperationRetryExecution]) > 0:
-		// When the default backoff duration elapsed we retry
-		if ccOperationQueueMap[ccOperationRetryExecution][0].IsReadyForRetryExecution() {
-			ccOperationExecution = ccOperationQueueMap[ccOperationRetryExecution][0]
-		}
-	// Forth prio: execute the first element in the FirstExecutionQueue which is ordered by operation type and k8s creation timestamp
-	case len(ccOperationQueueMap[ccOperationFirstExecution]) > 0:
-		ccOperationExecution = ccOperationQueueMap[ccOperationFirstExecution][0]
+		return ccOperationExecution
 	}
 
+	// Then the operation with the highest priority. With equal priorities add_broker operations go first,
+	// then the failed tasks and then the rest ordered by operation type and k8s creation timestamp.
+	waiting := waitingOperations(ccOperationQueueMap)
+	if len(waiting) == 0 {
+		return nil
+	}
+	ccOperationExecution := waiting[0]
+	// When the default backoff duration has not elapsed yet we wait with the retry
+	if ccOperationExecution.IsWaitingForRetryExecution() && !ccOperationExecution.IsReadyForRetryExecution() {
+		return nil
+	}
 	return ccOperationExecution
 }
 
+// cancelOperations cancels the operations whose cancellation is requested. It returns true while the execution
+// of a cancelled task is being stopped in Cruise Control.
+func (r *CruiseControlOperationReconciler) cancelOperations(ctx context.Context, ccOperations []*banzaiv1alpha1.CruiseControlOperation) (bool, error) {
+	cancelInProgress := false
+	for _, ccOperation := range ccOperations {
+		if !ccOperation.IsCancelRequested() {
+			continue
+		}
+		stopping, err := r.cancelOperation(ctx, ccOperation)
+		if err != nil {
+			return false, err
+		}
+		cancelInProgress = cancelInProgress || stopping
+	}
+	return cancelInProgress, nil
+}
+
+// cancelOperation stops the execution of the operation in Cruise Control when its own task is the one being executed
+// and marks the operation as cancelled once its task is not running anymore. It returns true while the task is running.
+// Operations whose task has already completed successfully are left as they are.
+func (r *CruiseControlOperationReconciler) cancelOperation(ctx context.Context, ccOperation *banzaiv1alpha1.CruiseControlOperation) (bool, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	switch ccOperation.CurrentTaskState() {
+	case banzaiv1beta1.CruiseControlTaskCompleted:
+		return false, nil
+	case banzaiv1beta1.CruiseControlTaskInExecution:
+		log.Info("stopping the execution of the cancelled Cruise Control task", "name", ccOperation.GetName(), "task ID", ccOperation.CurrentTaskID())
+		if _, err := r.scaler.StopExecution(ctx); err != nil {
+			return false, errors.WrapIfWithDetails(err, "could not stop the execution of the cancelled Cruise Control task",
+				"name", ccOperation.GetName(), "namespace", ccOperation.GetNamespace())
+		}
+		return true, nil
+	case banzaiv1beta1.CruiseControlTaskActive:
+		// The proposal of the task is still being computed, it is stopped once its execution started
+		return true, nil
+	}
+
+	conflictRetryFunction := func() error {
+		markCancelled(ccOperation)
+		err := r.Status().Update(ctx, ccOperation)
+		if apiErrors.IsConflict(err) {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(ccOperation), ccOperation); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+	if err := util.RetryOnConflict(util.DefaultBackOffForConflict, conflictRetryFunction); err != nil {
+		return false, errors.WrapIfWithDetails(err, "could not update the status of the cancelled CruiseControlOperation",
+			"name", ccOperation.GetName(), "namespace", ccOperation.GetNamespace())
+	}
+	log.Info("CruiseControlOperation has been cancelled", "name", ccOperation.GetName())
+	return false, nil
+}
+
+// markCancelled sets the state of the current task of the operation to cancelled.
+func markCancelled(operation *banzaiv1alpha1.CruiseControlOperation) {
+	if operation.Status.CurrentTask == nil {
+		operation.Status.CurrentTask = &banzaiv1alpha1.CruiseControlTask{}
+	}
+	task := operation.CurrentTask()
+	task.State = banzaiv1beta1.CruiseControlTaskCancelled
+	task.Finished = &v1.Time{Time: time.Now()}
+	operation.Status.QueuePosition = nil
+}
+
+// updateQueuePositions sets the position of the waiting operations in their status and clears it
+// for the rest of the operations. Only the operations whose position changed are patched and the executed
+// operation is skipped as its status is updated with the result of the execution.
+func (r *CruiseControlOperationReconciler) updateQueuePositions(ctx context.Context, ccOperations []*banzaiv1alpha1.CruiseControlOperation,
+	ccOperationQueueMap map[string][]*banzaiv1alpha1.CruiseControlOperation, executed *banzaiv1alpha1.CruiseControlOperation) error {
+	positions := queuePositions(ccOperationQueueMap, executed)
+	for _, ccOperation := range ccOperations {
+		if executed != nil && ccOperation.GetName() == executed.GetName() {
+			continue
+		}
+		position := positions[ccOperation.GetName()]
+		if reflect.DeepEqual(ccOperation.Status.QueuePosition, position) {
+			continue
+		}
+		patch := client.MergeFrom(ccOperation.DeepCopy())
+		ccOperation.Status.QueuePosition = position
+		if err := r.Status().Patch(ctx, ccOperation, patch); err != nil {
+			return errors.WrapIfWithDetails(err, "could not update CruiseControlOperation status", "name", ccOperation.GetName(), "namespace", ccOperation.GetNamespace())
+		}
+	}
+	return nil
+}
+
+// queuePositions returns the positions of the operations waiting for execution starting from 1, keyed by their name.
+func queuePositions(ccOperationQueueMap map[string][]*banzaiv1alpha1.CruiseControlOperation,
+	executed *banzaiv1alpha1.CruiseControlOperation) map[string]*int {
+	positions := make(map[string]*int)
+	position := 0
+	for _, ccOperation := range waitingOperations(ccOperationQueueMap) {
+		if executed != nil && ccOperation.GetName() == executed.GetName() {
+			continue
+		}
+		position++
+		p := position
+		positions[ccOperation.GetName()] = &p
+	}
+	return positions
+}
+
+// preemptOperation stops the execution of the running operation in Cruise Control and requeues it for execution.
+func (r *CruiseControlOperationReconciler) preemptOperation(ctx context.Context, preempted, preemptor *banzaiv1alpha1.CruiseControlOperation) error {
+	if _, err := r.scaler.StopExecution(ctx); err != nil {
+		return errors.WrapIfWithDetails(err, "could not stop the execution of the running Cruise Control task", "name", preempted.GetName(), "namespace", preempted.GetNamespace())
+	}
+
+	conflictRetryFunction := func() error {
+		markPreempted(preempted, preemptor.GetName())
+		err := r.Status().Update(ctx, preempted)
+		if apiErrors.IsConflict(err) {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(preempted), preempted); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+	return util.RetryOnConflict(util.DefaultBackOffForConflict, conflictRetryFunction)
+}
+
+// markPreempted records the preempted task into the failed tasks and resets the current task so the operation
+// waits for execution again.
+func markPreempted(operation *banzaiv1alpha1.CruiseControlOperation, preemptorName string) {
+	task := operation.CurrentTask()
+	if task == nil || task.ID == "" {
+		return
+	}
+
+	preemptedTask := task.DeepCopy()
+	preemptedTask.State = banzaiv1beta1.CruiseControlTaskCompletedWithError
+	preemptedTask.Finished = &v1.Time{Time: time.Now()}
+	preemptedTask.ErrorMessage = fmt.Sprintf("preempted by CruiseControlOperation %s with higher priority", preemptorName)
+	if len(operation.Status.FailedTasks) >= defaultFailedTasksHistoryMaxLength {
+		operation.Status.FailedTasks = operation.Status.FailedTasks[1:]
+	}
+	operation.Status.FailedTasks = append(operation.Status.FailedTasks, *preemptedTask)
+	operation.Status.PreemptedBy = preemptorName
+
+	task.SetDefaults()
+}
+
 // SetupCruiseControlWithManager registers cruise control controller to the manager
 func SetupCruiseControlOperationWithManager(mgr ctrl.Manager) *ctrl.Builder {
 	builder := ctrl.NewControllerManagedBy(mgr).
@@ -397,6 +622,10 @@ func updateResult(log logr.Logger, res *scale.Result, operation *banzaiv1alpha1.
 	}
 
 	operation.Status.ErrorPolicy = operation.Spec.ErrorPolicy
+	if isAfterExecution {
+		operation.Status.QueuePosition = nil
+		operation.Status.PreemptedBy = ""
+	}
 	task := operation.CurrentTask()
 
 	if (res.State == banzaiv1beta1.CruiseControlTaskCompleted || res.State == banzaiv1beta1.CruiseControlTaskCompletedWithError) && task.Finished == nil {
@@ -15,16 +15,33 @@
 package controllers
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/banzaicloud/koperator/api/v1alpha1"
 	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/scale"
 )
 
+// stopExecutionScaler counts the StopExecution calls of the CruiseControlOperationReconciler
+type stopExecutionScaler struct {
+	scale.CruiseControlScaler
+	stopExecutions int
+}
+
+func (s *stopExecutionScaler) StopExecution(_ context.Context) (*scale.Result, error) {
+	s.stopExecutions++
+	return &scale.Result{}, nil
+}
+
 func createCCRetryExecutionOperation(createTime time.Time, id string, operation v1alpha1.CruiseControlTaskOperation) *v1alpha1.CruiseControlOperation {
 	return &v1alpha1.CruiseControlOperation{
 		ObjectMeta: v1.ObjectMeta{
@@ -91,3 +108,236 @@ func TestSortOperations(t *testing.T) {
 		assert.Equal(t, sortedRetryOutput, testCase.expectedOutput, "test", testCase.testName)
 	}
 }
+
+func createCCFirstExecutionOperation(createTime time.Time, name string, operation v1alpha1.CruiseControlTaskOperation, priority int32) *v1alpha1.CruiseControlOperation {
+	return &v1alpha1.CruiseControlOperation{
+		ObjectMeta: v1.ObjectMeta{
+			Name: name,
+			CreationTimestamp: v1.Time{
+				Time: createTime,
+			},
+		},
+		Spec: v1alpha1.CruiseControlOperationSpec{
+			ErrorPolicy: v1alpha1.ErrorPolicyRetry,
+			Priority:    priority,
+		},
+		Status: v1alpha1.CruiseControlOperationStatus{
+			CurrentTask: &v1alpha1.CruiseControlTask{
+				Operation: operation,
+			},
+		},
+	}
+}
+
+func TestSelectOperationForExecution(t *testing.T) {
+	timeNow := time.Now()
+	retryReady := createCCRetryExecutionOperation(timeNow, "retry", v1alpha1.OperationRebalance)
+	retryReady.Name = "retry"
+	retryReady.Status.CurrentTask.Finished = &v1.Time{Time: timeNow.Add(-time.Minute)}
+	retryNotReady := retryReady.DeepCopy()
+	retryNotReady.Status.CurrentTask.Finished = &v1.Time{Time: timeNow}
+
+	testCases := []struct {
+		testName          string
+		ccOperations      []*v1alpha1.CruiseControlOperation
+		expectedOperation string
+		expectedPositions []string
+	}{
+		{
+			testName: "same priority is ordered by operation type",
+			ccOperations: []*v1alpha1.CruiseControlOperation{
+				createCCFirstExecutionOperation(timeNow, "rebalance", v1alpha1.OperationRebalance, 0),
+				createCCFirstExecutionOperation(timeNow.Add(time.Second), "remove", v1alpha1.OperationRemoveBroker, 0),
+				createCCFirstExecutionOperation(timeNow.Add(2*time.Second), "add", v1alpha1.OperationAddBroker, 0),
+			},
+			expectedOperation: "add",
+			expectedPositions: []string{"add", "remove", "rebalance"},
+		},
+		{
+			testName: "higher priority jumps ahead",
+			ccOperations: []*v1alpha1.CruiseControlOperation{
+				createCCFirstExecutionOperation(timeNow, "add", v1alpha1.OperationAddBroker, 0),
+				createCCFirstExecutionOperation(timeNow.Add(time.Second), "remove", v1alpha1.OperationRemoveBroker, 10),
+				createCCFirstExecutionOperation(timeNow.Add(2*time.Second), "rebalance", v1alpha1.OperationRebalance, 0),
+			},
+			expectedOperation: "remove",
+			expectedPositions: []string{"remove", "add", "rebalance"},
+		},
+		{
+			testName: "retry goes before first execution with the same priority",
+			ccOperations: []*v1alpha1.CruiseControlOperation{
+				createCCFirstExecutionOperation(timeNow, "remove", v1alpha1.OperationRemoveBroker, 0),
+				retryReady,
+			},
+			expectedOperation: "retry",
+			expectedPositions: []string{"retry", "remove"},
+		},
+		{
+			testName: "retry waiting for backoff blocks first executions with the same priority",
+			ccOperations: []*v1alpha1.CruiseControlOperation{
+				createCCFirstExecutionOperation(timeNow, "remove", v1alpha1.OperationRemoveBroker, 0),
+				retryNotReady,
+			},
+			expectedOperation: "",
+			expectedPositions: []string{"retry", "remove"},
+		},
+		{
+			testName: "higher priority goes before retry waiting for backoff",
+			ccOperations: []*v1alpha1.CruiseControlOperation{
+				createCCFirstExecutionOperation(timeNow, "remove", v1alpha1.OperationRemoveBroker, 1),
+				retryNotReady,
+			},
+			expectedOperation: "remove",
+			expectedPositions: []string{"remove", "retry"},
+		},
+	}
+	for _, testCase := range testCases {
+		queueMap := sortOperations(testCase.ccOperations)
+		var positions []string
+		for _, operation := range waitingOperations(queueMap) {
+			positions = append(positions, operation.GetName())
+		}
+		assert.Equal(t, testCase.expectedPositions, positions, "testName", testCase.testName)
+
+		selected := selectOperationForExecution(queueMap)
+		if testCase.expectedOperation == "" {
+			assert.Nil(t, selected, "testName", testCase.testName)
+			continue
+		}
+		if assert.NotNil(t, selected, "testName", testCase.testName) {
+			assert.Equal(t, testCase.expectedOperation, selected.GetName(), "testName", testCase.testName)
+		}
+	}
+}
+
+func TestMarkPreempted(t *testing.T) {
+	running := createCCFirstExecutionOperation(time.Now(), "rebalance", v1alpha1.OperationRebalance, 0)
+	running.Status.CurrentTask.ID = "task-id"
+	running.Status.CurrentTask.State = v1beta1.CruiseControlTaskInExecution
+	running.Status.CurrentTask.Parameters = map[string]string{"destination_broker_ids": "1"}
+
+	preemptor := createCCFirstExecutionOperation(time.Now(), "remove", v1alpha1.OperationRemoveBroker, 10)
+	preemptor.Spec.PreemptionPolicy = v1alpha1.PreemptionPolicyPreemptLowerPriority
+	assert.True(t, preemptor.CanPreempt(running))
+	assert.False(t, running.CanPreempt(preemptor))
+
+	running.Status.RetryCount = 1
+	markPreempted(running, preemptor.GetName())
+
+	assert.True(t, running.IsPreempted())
+	assert.True(t, running.IsWaitingForFirstExecution())
+	assert.Equal(t, map[string]string{"destination_broker_ids": "1"}, running.CurrentTaskParameters())
+	if assert.Len(t, running.Status.FailedTasks, 1) {
+		assert.Equal(t, "task-id", running.Status.FailedTasks[0].ID)
+		assert.Equal(t, v1beta1.CruiseControlTaskCompletedWithError, running.Status.FailedTasks[0].State)
+		assert.Contains(t, running.Status.FailedTasks[0].ErrorMessage, "remove")
+	}
+}
+
+func TestIsWaitingForFirstExecutionAfterRetry(t *testing.T) {
+	operation := createCCFirstExecutionOperation(time.Now(), "remove", v1alpha1.OperationRemoveBroker, 0)
+	assert.True(t, operation.IsWaitingForFirstExecution())
+
+	// A failed task is reset after it is recorded, the operation must not jump the queue as a first execution
+	operation.Status.RetryCount = 1
+	assert.False(t, operation.IsWaitingForFirstExecution())
+}
+
+func TestQueuePositions(t *testing.T) {
+	timeNow := time.Now()
+	queueMap := sortOperations([]*v1alpha1.CruiseControlOperation{
+		createCCFirstExecutionOperation(timeNow, "rebalance", v1alpha1.OperationRebalance, 0),
+		createCCFirstExecutionOperation(timeNow.Add(time.Second), "remove", v1alpha1.OperationRemoveBroker, 0),
+		createCCFirstExecutionOperation(timeNow.Add(2*time.Second), "add", v1alpha1.OperationAddBroker, 0),
+	})
+	position := func(p int) *int { return &p }
+
+	assert.Equal(t, map[string]*int{"add": position(1), "remove": position(2), "rebalance": position(3)}, queuePositions(queueMap, nil))
+
+	executed := selectOperationForExecution(queueMap)
+	assert.Equal(t, map[string]*int{"remove": position(1), "rebalance": position(2)}, queuePositions(queueMap, executed))
+}
+
+func TestMarkCancelled(t *testing.T) {
+	operation := createCCFirstExecutionOperation(time.Now(), "remove", v1alpha1.OperationRemoveBroker, 0)
+	operation.Spec.Cancel = true
+	operation.Status.CurrentTask.ID = "task-id"
+	operation.Status.CurrentTask.State = v1beta1.CruiseControlTaskInExecution
+	assert.True(t, operation.IsCancelRequested())
+
+	markCancelled(operation)
+
+	assert.False(t, operation.IsCancelRequested())
+	assert.True(t, operation.IsCancelled())
+	assert.True(t, operation.IsDone())
+	assert.False(t, operation.IsFinished())
+	assert.NotNil(t, operation.CurrentTaskFinished())
+
+	task := &CruiseControlTask{
+		Operation:   v1alpha1.OperationRemoveBroker,
+		BrokerState: v1beta1.GracefulDownscaleRunning,
+	}
+	task.FromResult(operation)
+	assert.Equal(t, v1beta1.GracefulDownscaleRequired, task.BrokerState)
+}
+
+func TestSortOperationsSkipsCancelRequested(t *testing.T) {
+	timeNow := time.Now()
+	waiting := createCCFirstExecutionOperation(timeNow, "waiting", v1alpha1.OperationRebalance, 0)
+	retry := createCCRetryExecutionOperation(timeNow, "1", v1alpha1.OperationRemoveBroker)
+	retry.Spec.Cancel = true
+	inProgress := createCCFirstExecutionOperation(timeNow, "in-progress", v1alpha1.OperationAddBroker, 0)
+	inProgress.Status.CurrentTask.ID = "2"
+	inProgress.Status.CurrentTask.State = v1beta1.CruiseControlTaskInExecution
+	inProgress.Spec.Cancel = true
+	cancelled := createCCFirstExecutionOperation(timeNow, "cancelled", v1alpha1.OperationAddBroker, 10)
+	cancelled.Spec.Cancel = true
+
+	queueMap := sortOperations([]*v1alpha1.CruiseControlOperation{waiting, retry, inProgress, cancelled})
+
+	assert.Equal(t, []*v1alpha1.CruiseControlOperation{waiting}, queueMap[ccOperationFirstExecution])
+	assert.Empty(t, queueMap[ccOperationRetryExecution])
+	assert.Empty(t, queueMap[ccOperationInProgress])
+	assert.Equal(t, waiting, selectOperationForExecution(queueMap))
+}
+
+func TestCancelOperations(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	newOperation := func(name, taskID string, state v1beta1.CruiseControlUserTaskState, cancel bool) *v1alpha1.CruiseControlOperation {
+		operation := createCCFirstExecutionOperation(time.Now(), name, v1alpha1.OperationRemoveBroker, 0)
+		operation.Namespace = "kafka"
+		operation.Spec.Cancel = cancel
+		operation.Status.CurrentTask.ID = taskID
+		operation.Status.CurrentTask.State = state
+		return operation
+	}
+	running := newOperation("running", "1", v1beta1.CruiseControlTaskInExecution, false)
+	waiting := newOperation("waiting", "", "", true)
+	stopped := newOperation("stopped", "2", v1beta1.CruiseControlTaskCompletedWithError, true)
+	operations := []*v1alpha1.CruiseControlOperation{running, waiting, stopped}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(running, waiting, stopped).Build()
+	scaler := &stopExecutionScaler{}
+	r := &CruiseControlOperationReconciler{Client: c, scaler: scaler}
+
+	// the task of another operation is running, it is not stopped
+	cancelInProgress, err := r.cancelOperations(context.Background(), operations)
+	require.NoError(t, err)
+	assert.False(t, cancelInProgress)
+	assert.Zero(t, scaler.stopExecutions)
+	for _, operation := range []*v1alpha1.CruiseControlOperation{waiting, stopped} {
+		current := &v1alpha1.CruiseControlOperation{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(operation), current))
+		assert.True(t, current.IsCancelled(), operation.Name)
+	}
+
+	// the task of the cancelled operation is running, its execution is stopped
+	running.Spec.Cancel = true
+	cancelInProgress, err = r.cancelOperations(context.Background(), []*v1alpha1.CruiseControlOperation{running})
+	require.NoError(t, err)
+	assert.True(t, cancelInProgress)
+	assert.Equal(t, 1, scaler.stopExecutions)
+	assert.False(t, running.IsCancelled())
+}
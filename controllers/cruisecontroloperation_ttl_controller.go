@@ -77,14 +77,14 @@ func SetupCruiseControlOperationTTLWithManager(mgr ctrl.Manager) *ctrl.Builder {
 	cruiseControlOperationTTLPredicate := predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			obj := e.Object.(*banzaiv1alpha1.CruiseControlOperation)
-			if obj.IsFinished() && obj.GetTTLSecondsAfterFinished() != nil && obj.GetDeletionTimestamp().IsZero() {
+			if (obj.IsFinished() || obj.IsCancelled()) && obj.GetTTLSecondsAfterFinished() != nil && obj.GetDeletionTimestamp().IsZero() {
 				return true
 			}
 			return false
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			newObj := e.ObjectNew.(*banzaiv1alpha1.CruiseControlOperation)
-			if newObj.IsFinished() && newObj.GetTTLSecondsAfterFinished() != nil && newObj.GetDeletionTimestamp().IsZero() {
+			if (newObj.IsFinished() || newObj.IsCancelled()) && newObj.GetTTLSecondsAfterFinished() != nil && newObj.GetDeletionTimestamp().IsZero() {
 				return true
 			}
 			return false
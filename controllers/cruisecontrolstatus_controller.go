@@ -0,0 +1,190 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/banzaicloud/go-cruise-control/pkg/types"
+	"github.com/go-logr/logr"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/scale"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+const (
+	// CruiseControlStatusRefreshIntervalInSec is the interval between two consecutive polls of the Cruise Control
+	// state and cluster load.
+	CruiseControlStatusRefreshIntervalInSec = 60
+)
+
+// CruiseControlStatusReconciler periodically reflects the readiness of Cruise Control and the load of the brokers
+// reported by Cruise Control in the status of the KafkaCluster custom resource.
+type CruiseControlStatusReconciler struct {
+	client.Client
+	ScaleFactory func(ctx context.Context, kafkaCluster *banzaiv1beta1.KafkaCluster) (scale.CruiseControlScaler, error)
+}
+
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusters/status,verbs=get;update;patch
+
+func (r *CruiseControlStatusReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	instance := &banzaiv1beta1.KafkaCluster{}
+	if err := r.Get(ctx, request.NamespacedName, instance); err != nil {
+		if apiErrors.IsNotFound(err) {
+			return reconciled()
+		}
+		return requeueWithError(log, err.Error(), err)
+	}
+
+	if !instance.GetDeletionTimestamp().IsZero() {
+		return reconciled()
+	}
+
+	// Cruise Control cannot serve requests until its topic is created
+	if instance.Status.CruiseControlTopicStatus != banzaiv1beta1.CruiseControlTopicReady {
+		log.V(1).Info("Cruise Control topic is not ready yet, skip collecting Cruise Control status")
+		return requeueAfter(CruiseControlStatusRefreshIntervalInSec)
+	}
+
+	scaler, err := r.ScaleFactory(ctx, instance)
+	if err != nil {
+		return requeueWithError(log, "failed to create Cruise Control Scaler instance", err)
+	}
+
+	statusResult, err := scaler.Status(ctx)
+	if err != nil {
+		log.Error(err, "could not get Cruise Control status")
+		return requeueAfter(DefaultRequeueAfterTimeInSec)
+	}
+	// Cruise Control turned the request into an async one, try it again later
+	if statusResult.Status == nil {
+		return requeueAfter(DefaultRequeueAfterTimeInSec)
+	}
+
+	var brokerStats []types.BrokerLoadStats
+	// The cluster load is not available until Cruise Control collects enough valid windows
+	if statusResult.Status.IsReady() {
+		loadResp, err := scaler.KafkaClusterLoad(ctx)
+		if err != nil {
+			log.Info("could not get cluster load from Cruise Control", "error", err.Error())
+		} else if loadResp != nil && loadResp.Result != nil {
+			brokerStats = loadResp.Result.Brokers
+		}
+	}
+
+	if err = r.updateStatus(ctx, instance, statusResult.Status, brokerStats); err != nil {
+		return requeueWithError(log, "failed to update Cruise Control status of Kafka Cluster", err)
+	}
+
+	return requeueAfter(CruiseControlStatusRefreshIntervalInSec)
+}
+
+func (r *CruiseControlStatusReconciler) updateStatus(ctx context.Context, instance *banzaiv1beta1.KafkaCluster,
+	status *scale.CruiseControlStatus, brokerStats []types.BrokerLoadStats) error {
+	currentStatus := instance.Status.DeepCopy()
+	applyCruiseControlStatus(instance, status, brokerStats)
+	if reflect.DeepEqual(*currentStatus, instance.Status) {
+		return nil
+	}
+
+	conflictRetryFunction := func() error {
+		err := r.Status().Update(ctx, instance)
+		if apiErrors.IsConflict(err) {
+			err := r.Get(ctx, client.ObjectKeyFromObject(instance), instance)
+			if err != nil {
+				return errors.WithMessage(err, "failed to get updated Kafka Cluster CR before updating its status")
+			}
+			applyCruiseControlStatus(instance, status, brokerStats)
+		}
+		return err
+	}
+
+	return util.RetryOnConflict(util.DefaultBackOffForConflict, conflictRetryFunction)
+}
+
+// applyCruiseControlStatus sets the Cruise Control related fields in the status of the provided KafkaCluster.
+// The load of the brokers is only updated when brokerStats is not empty, so the last known load is kept when
+// Cruise Control is not able to provide it.
+func applyCruiseControlStatus(instance *banzaiv1beta1.KafkaCluster, status *scale.CruiseControlStatus,
+	brokerStats []types.BrokerLoadStats) {
+	if instance == nil || status == nil {
+		return
+	}
+
+	instance.Status.CruiseControl = &banzaiv1beta1.CruiseControlStatus{
+		Ready:                        status.IsReady(),
+		MonitorReady:                 status.MonitorReady,
+		MonitorState:                 status.MonitorState,
+		AnalyzerReady:                status.AnalyzerReady,
+		ExecutorReady:                status.ExecutorReady,
+		ExecutorState:                status.ExecutorState,
+		NumValidWindows:              int32(status.MonitoredWindows),
+		MonitoringCoveragePercentage: formatFloat(status.MonitoringCoverage),
+	}
+
+	for _, stats := range brokerStats {
+		brokerID := strconv.Itoa(int(stats.Broker))
+		brokerState, ok := instance.Status.BrokersState[brokerID]
+		if !ok {
+			continue
+		}
+		brokerState.Load = &banzaiv1beta1.BrokerLoad{
+			CPUPercentage:  formatFloat(stats.CPUPct),
+			DiskMB:         formatFloat(stats.DiskMB),
+			DiskPercentage: formatFloat(stats.DiskPct),
+			NetworkInRate:  formatFloat(stats.LeaderNwInRate + stats.FollowerNwInRate),
+			NetworkOutRate: formatFloat(stats.NwOutRate),
+			Leaders:        stats.Leaders,
+			Replicas:       stats.Replicas,
+		}
+		instance.Status.BrokersState[brokerID] = brokerState
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+// SetupCruiseControlStatusWithManager registers the Cruise Control status controller to the manager
+func SetupCruiseControlStatusWithManager(mgr ctrl.Manager) *ctrl.Builder {
+	kafkaClusterPredicate := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj := e.ObjectOld.(*banzaiv1beta1.KafkaCluster)
+			newObj := e.ObjectNew.(*banzaiv1beta1.KafkaCluster)
+			return oldObj.Status.CruiseControlTopicStatus != newObj.Status.CruiseControlTopicStatus ||
+				oldObj.GetGeneration() != newObj.GetGeneration()
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&banzaiv1beta1.KafkaCluster{}).
+		WithEventFilter(SkipClusterRegistryOwnedResourcePredicate{}).
+		WithEventFilter(kafkaClusterPredicate).
+		Named("CruiseControlStatus")
+}
@@ -0,0 +1,166 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/banzaicloud/go-cruise-control/pkg/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/scale"
+)
+
+func TestApplyCruiseControlStatus(t *testing.T) {
+	testCases := []struct {
+		testName        string
+		brokersState    map[string]v1beta1.BrokerState
+		status          *scale.CruiseControlStatus
+		brokerStats     []types.BrokerLoadStats
+		expectedCCState *v1beta1.CruiseControlStatus
+		expectedLoads   map[string]*v1beta1.BrokerLoad
+	}{
+		{
+			testName: "not ready Cruise Control without load",
+			brokersState: map[string]v1beta1.BrokerState{
+				"0": {},
+			},
+			status: &scale.CruiseControlStatus{
+				MonitorState:       "LOADING",
+				ExecutorReady:      true,
+				ExecutorState:      "NO_TASK_IN_PROGRESS",
+				MonitoredWindows:   2,
+				MonitoringCoverage: 12.5,
+			},
+			expectedCCState: &v1beta1.CruiseControlStatus{
+				MonitorState:                 "LOADING",
+				ExecutorReady:                true,
+				ExecutorState:                "NO_TASK_IN_PROGRESS",
+				NumValidWindows:              2,
+				MonitoringCoveragePercentage: "12.50",
+			},
+			expectedLoads: map[string]*v1beta1.BrokerLoad{
+				"0": nil,
+			},
+		},
+		{
+			testName: "ready Cruise Control with load",
+			brokersState: map[string]v1beta1.BrokerState{
+				"0": {},
+				"1": {Load: &v1beta1.BrokerLoad{Leaders: 1}},
+			},
+			status: &scale.CruiseControlStatus{
+				MonitorReady:       true,
+				MonitorState:       "RUNNING",
+				AnalyzerReady:      true,
+				ExecutorReady:      true,
+				ExecutorState:      "NO_TASK_IN_PROGRESS",
+				MonitoredWindows:   5,
+				MonitoringCoverage: 100,
+			},
+			brokerStats: []types.BrokerLoadStats{
+				{
+					Broker:           0,
+					CPUPct:           10.123,
+					DiskMB:           1024,
+					DiskPct:          5.5,
+					LeaderNwInRate:   1.5,
+					FollowerNwInRate: 2,
+					NwOutRate:        4,
+					Leaders:          10,
+					Replicas:         30,
+				},
+				{
+					// unknown broker is ignored
+					Broker: 5,
+				},
+			},
+			expectedCCState: &v1beta1.CruiseControlStatus{
+				Ready:                        true,
+				MonitorReady:                 true,
+				MonitorState:                 "RUNNING",
+				AnalyzerReady:                true,
+				ExecutorReady:                true,
+				ExecutorState:                "NO_TASK_IN_PROGRESS",
+				NumValidWindows:              5,
+				MonitoringCoveragePercentage: "100.00",
+			},
+			expectedLoads: map[string]*v1beta1.BrokerLoad{
+				"0": {
+					CPUPercentage:  "10.12",
+					DiskMB:         "1024.00",
+					DiskPercentage: "5.50",
+					NetworkInRate:  "3.50",
+					NetworkOutRate: "4.00",
+					Leaders:        10,
+					Replicas:       30,
+				},
+				// last known load is kept
+				"1": {Leaders: 1},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		instance := &v1beta1.KafkaCluster{
+			Status: v1beta1.KafkaClusterStatus{
+				BrokersState: testCase.brokersState,
+			},
+		}
+		applyCruiseControlStatus(instance, testCase.status, testCase.brokerStats)
+		assert.Equal(t, testCase.expectedCCState, instance.Status.CruiseControl, "testName", testCase.testName)
+		assert.Len(t, instance.Status.BrokersState, len(testCase.expectedLoads), "testName", testCase.testName)
+		for brokerID, expectedLoad := range testCase.expectedLoads {
+			assert.Equal(t, expectedLoad, instance.Status.BrokersState[brokerID].Load, "testName", testCase.testName)
+		}
+	}
+}
+
+func TestBrokersStateChanged(t *testing.T) {
+	testCases := []struct {
+		testName  string
+		oldStates map[string]v1beta1.BrokerState
+		newStates map[string]v1beta1.BrokerState
+		expected  bool
+	}{
+		{
+			testName:  "only load changed",
+			oldStates: map[string]v1beta1.BrokerState{"0": {Version: "3.4.1"}},
+			newStates: map[string]v1beta1.BrokerState{"0": {Version: "3.4.1", Load: &v1beta1.BrokerLoad{Leaders: 3}}},
+			expected:  false,
+		},
+		{
+			testName:  "broker state changed",
+			oldStates: map[string]v1beta1.BrokerState{"0": {Version: "3.4.1"}},
+			newStates: map[string]v1beta1.BrokerState{"0": {Version: "3.4.0"}},
+			expected:  true,
+		},
+		{
+			testName:  "broker added",
+			oldStates: map[string]v1beta1.BrokerState{"0": {}},
+			newStates: map[string]v1beta1.BrokerState{"0": {}, "1": {}},
+			expected:  true,
+		},
+		{
+			testName:  "broker replaced",
+			oldStates: map[string]v1beta1.BrokerState{"0": {}},
+			newStates: map[string]v1beta1.BrokerState{"1": {}},
+			expected:  true,
+		},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, brokersStateChanged(testCase.oldStates, testCase.newStates), "testName", testCase.testName)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"emperror.dev/errors"
@@ -169,6 +170,27 @@ func (r *CruiseControlTaskReconciler) Reconcile(ctx context.Context, request ctr
 		removeTask.SetCruiseControlOperationRef(cruiseControlOpRef)
 		removeTask.SetStateScheduled()
 
+	case tasksAndStates.NumActiveTasksByOp(banzaiv1alpha1.OperationRemoveDisks) > 0:
+		brokerIDsAndLogDirs := make([]string, 0)
+		for _, task := range tasksAndStates.GetActiveTasksByOp(banzaiv1alpha1.OperationRemoveDisks) {
+			brokerIDsAndLogDirs = append(brokerIDsAndLogDirs,
+				fmt.Sprintf("%s-%s", task.BrokerID, util.StorageConfigKafkaMountPath(task.Volume)))
+		}
+		sort.Strings(brokerIDsAndLogDirs)
+
+		cruiseControlOpRef, err := r.removeDisks(ctx, instance, operationTTLSecondsAfterFinished, brokerIDsAndLogDirs)
+		if err != nil {
+			return requeueWithError(log, fmt.Sprintf("creating CruiseControlOperation for disk removal has failed, log dirs: %s", brokerIDsAndLogDirs), err)
+		}
+
+		for _, task := range tasksAndStates.GetActiveTasksByOp(banzaiv1alpha1.OperationRemoveDisks) {
+			if task == nil {
+				continue
+			}
+			task.SetCruiseControlOperationRef(cruiseControlOpRef)
+			task.SetStateScheduled()
+		}
+
 	case tasksAndStates.NumActiveTasksByOp(banzaiv1alpha1.OperationRebalance) > 0:
 		brokerIDs := make([]string, 0)
 		for _, task := range tasksAndStates.GetActiveTasksByOp(banzaiv1alpha1.OperationRebalance) {
@@ -291,6 +313,10 @@ func (r *CruiseControlTaskReconciler) rebalanceDisks(ctx context.Context, kafkaC
 	return r.createCCOperation(ctx, kafkaCluster, banzaiv1alpha1.ErrorPolicyRetry, ttlSecondsAfterFinished, banzaiv1alpha1.OperationRebalance, bokerIDs, isJBOD)
 }
 
+func (r *CruiseControlTaskReconciler) removeDisks(ctx context.Context, kafkaCluster *banzaiv1beta1.KafkaCluster, ttlSecondsAfterFinished *int, brokerIDsAndLogDirs []string) (corev1.LocalObjectReference, error) {
+	return r.createCCOperation(ctx, kafkaCluster, banzaiv1alpha1.ErrorPolicyRetry, ttlSecondsAfterFinished, banzaiv1alpha1.OperationRemoveDisks, brokerIDsAndLogDirs, false)
+}
+
 func (r *CruiseControlTaskReconciler) createCCOperation(
 	ctx context.Context,
 	kafkaCluster *banzaiv1beta1.KafkaCluster,
@@ -323,28 +349,45 @@ func (r *CruiseControlTaskReconciler) createCCOperation(
 	}
 
 	operation.Status.CurrentTask = &banzaiv1alpha1.CruiseControlTask{
-		Operation: operationType,
-		Parameters: map[string]string{
-			"exclude_recently_demoted_brokers": "true",
-			"exclude_recently_removed_brokers": "true",
-		},
+		Operation:  operationType,
+		Parameters: ccOperationParameters(operationType, bokerIDs, isJBOD, kafkaCluster.Spec.CruiseControlConfig.CruiseControlTaskSpec),
 	}
 
+	if err := r.Status().Update(ctx, operation); err != nil {
+		return corev1.LocalObjectReference{}, err
+	}
+	return corev1.LocalObjectReference{
+		Name: operation.Name,
+	}, nil
+}
+
+// ccOperationParameters returns the Cruise Control parameters of the operation. The remove_disks operation accepts
+// neither the exclude_recently_* nor the execution parameters, so only its log dirs are set.
+func ccOperationParameters(operationType banzaiv1alpha1.CruiseControlTaskOperation, brokerIDs []string, isJBOD bool,
+	taskSpec banzaiv1beta1.CruiseControlTaskSpec) map[string]string {
+	if operationType == banzaiv1alpha1.OperationRemoveDisks {
+		return map[string]string{
+			"brokerid_and_logdirs": strings.Join(brokerIDs, ","),
+		}
+	}
+
+	params := map[string]string{
+		"exclude_recently_demoted_brokers": "true",
+		"exclude_recently_removed_brokers": "true",
+	}
 	if operationType == banzaiv1alpha1.OperationRebalance {
-		operation.Status.CurrentTask.Parameters["destination_broker_ids"] = strings.Join(bokerIDs, ",")
+		params["destination_broker_ids"] = strings.Join(brokerIDs, ",")
 		if isJBOD {
-			operation.Status.CurrentTask.Parameters["rebalance_disk"] = "true"
+			params["rebalance_disk"] = "true"
 		}
 	} else {
-		operation.Status.CurrentTask.Parameters["brokerid"] = strings.Join(bokerIDs, ",")
+		params["brokerid"] = strings.Join(brokerIDs, ",")
 	}
 
-	if err := r.Status().Update(ctx, operation); err != nil {
-		return corev1.LocalObjectReference{}, err
+	for param, value := range scale.ExecutionParameters(taskSpec) {
+		params[param] = value
 	}
-	return corev1.LocalObjectReference{
-		Name: operation.Name,
-	}, nil
+	return params
 }
 
 // brokersJBODSelector filters out the JBOD and not JBOD brokers from a broker list based on the capacityConfig
@@ -469,7 +512,7 @@ func SetupCruiseControlWithManager(mgr ctrl.Manager) *ctrl.Builder {
 			if _, ok := e.ObjectNew.(*banzaiv1beta1.KafkaCluster); ok {
 				oldObj := e.ObjectOld.(*banzaiv1beta1.KafkaCluster)
 				newObj := e.ObjectNew.(*banzaiv1beta1.KafkaCluster)
-				if !reflect.DeepEqual(oldObj.Status.BrokersState, newObj.Status.BrokersState) ||
+				if brokersStateChanged(oldObj.Status.BrokersState, newObj.Status.BrokersState) ||
 					oldObj.GetDeletionTimestamp() != newObj.GetDeletionTimestamp() ||
 					oldObj.GetGeneration() != newObj.GetGeneration() {
 					return true
@@ -518,11 +561,15 @@ func getActiveTasksFromCluster(instance *banzaiv1beta1.KafkaCluster) *CruiseCont
 
 		for mountPath, volumeState := range brokerStatus.GracefulActionState.VolumeStates {
 			if volumeState.CruiseControlVolumeState.IsActive() {
+				operation := banzaiv1alpha1.OperationRebalance
+				if volumeState.CruiseControlVolumeState.IsDiskRemoval() {
+					operation = banzaiv1alpha1.OperationRemoveDisks
+				}
 				t := &CruiseControlTask{
 					BrokerID:                        brokerId,
 					Volume:                          mountPath,
 					VolumeState:                     volumeState.CruiseControlVolumeState,
-					Operation:                       banzaiv1alpha1.OperationRebalance,
+					Operation:                       operation,
 					CruiseControlOperationReference: volumeState.CruiseControlOperationReference,
 				}
 				tasksAndStates.Add(t)
@@ -18,6 +18,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	banzaiv1alpha1 "github.com/banzaicloud/koperator/api/v1alpha1"
+	banzaiv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/util"
 )
 
 func TestBrokersJBODSelector(t *testing.T) {
@@ -295,3 +300,151 @@ func TestBrokersJBODSelector(t *testing.T) {
 		assert.ElementsMatch(t, testCase.expectedBrokersNotJBOD, brokersNotJBOD, "testName", testCase.testName)
 	}
 }
+
+func TestGetActiveTasksFromClusterWithDiskRemoval(t *testing.T) {
+	instance := &banzaiv1beta1.KafkaCluster{
+		Status: banzaiv1beta1.KafkaClusterStatus{
+			BrokersState: map[string]banzaiv1beta1.BrokerState{
+				"1": {
+					GracefulActionState: banzaiv1beta1.GracefulActionState{
+						VolumeStates: map[string]banzaiv1beta1.VolumeState{
+							"/kafka-logs1": {CruiseControlVolumeState: banzaiv1beta1.GracefulDiskRebalanceRequired},
+							"/kafka-logs2": {CruiseControlVolumeState: banzaiv1beta1.GracefulDiskRemovalRequired},
+							"/kafka-logs3": {CruiseControlVolumeState: banzaiv1beta1.GracefulDiskRemovalSucceeded},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tasksAndStates := getActiveTasksFromCluster(instance)
+
+	rebalanceTasks := tasksAndStates.GetActiveTasksByOp(banzaiv1alpha1.OperationRebalance)
+	assert.Len(t, rebalanceTasks, 1)
+	assert.Equal(t, "/kafka-logs1", rebalanceTasks[0].Volume)
+
+	removeDisksTasks := tasksAndStates.GetActiveTasksByOp(banzaiv1alpha1.OperationRemoveDisks)
+	assert.Len(t, removeDisksTasks, 1)
+	assert.Equal(t, "/kafka-logs2", removeDisksTasks[0].Volume)
+
+	removeDisksTasks[0].SetCruiseControlOperationRef(corev1.LocalObjectReference{Name: "kafka-removedisks-abcde"})
+	removeDisksTasks[0].SetStateScheduled()
+	removeDisksTasks[0].Apply(instance)
+	assert.Equal(t, banzaiv1beta1.VolumeState{
+		CruiseControlVolumeState:        banzaiv1beta1.GracefulDiskRemovalScheduled,
+		CruiseControlOperationReference: &corev1.LocalObjectReference{Name: "kafka-removedisks-abcde"},
+	}, instance.Status.BrokersState["1"].GracefulActionState.VolumeStates["/kafka-logs2"])
+}
+
+func TestCruiseControlTaskFromResultWithDiskRemoval(t *testing.T) {
+	testCases := []struct {
+		testName      string
+		operation     *banzaiv1alpha1.CruiseControlOperation
+		expectedState banzaiv1beta1.CruiseControlVolumeState
+	}{
+		{
+			testName:      "missing operation",
+			expectedState: banzaiv1beta1.GracefulDiskRemovalRequired,
+		},
+		{
+			testName:      "waiting for execution",
+			operation:     newCruiseControlOperationWithTaskState(""),
+			expectedState: banzaiv1beta1.GracefulDiskRemovalScheduled,
+		},
+		{
+			testName:      "in execution",
+			operation:     newCruiseControlOperationWithTaskState(banzaiv1beta1.CruiseControlTaskInExecution),
+			expectedState: banzaiv1beta1.GracefulDiskRemovalRunning,
+		},
+		{
+			testName:      "completed",
+			operation:     newCruiseControlOperationWithTaskState(banzaiv1beta1.CruiseControlTaskCompleted),
+			expectedState: banzaiv1beta1.GracefulDiskRemovalSucceeded,
+		},
+		{
+			testName:      "completed with error",
+			operation:     newCruiseControlOperationWithTaskState(banzaiv1beta1.CruiseControlTaskCompletedWithError),
+			expectedState: banzaiv1beta1.GracefulDiskRemovalCompletedWithError,
+		},
+		{
+			testName:      "cancelled",
+			operation:     newCruiseControlOperationWithTaskState(banzaiv1beta1.CruiseControlTaskCancelled),
+			expectedState: banzaiv1beta1.GracefulDiskRemovalPaused,
+		},
+	}
+
+	for _, testCase := range testCases {
+		task := &CruiseControlTask{
+			Operation:   banzaiv1alpha1.OperationRemoveDisks,
+			VolumeState: banzaiv1beta1.GracefulDiskRemovalRunning,
+		}
+		task.FromResult(testCase.operation)
+		assert.Equal(t, testCase.expectedState, task.VolumeState, "testName", testCase.testName)
+	}
+}
+
+func newCruiseControlOperationWithTaskState(state banzaiv1beta1.CruiseControlUserTaskState) *banzaiv1alpha1.CruiseControlOperation {
+	return &banzaiv1alpha1.CruiseControlOperation{
+		Spec: banzaiv1alpha1.CruiseControlOperationSpec{ErrorPolicy: banzaiv1alpha1.ErrorPolicyRetry},
+		Status: banzaiv1alpha1.CruiseControlOperationStatus{
+			CurrentTask: &banzaiv1alpha1.CruiseControlTask{
+				Operation: banzaiv1alpha1.OperationRemoveDisks,
+				State:     state,
+			},
+		},
+	}
+}
+
+func TestCCOperationParameters(t *testing.T) {
+	taskSpec := banzaiv1beta1.CruiseControlTaskSpec{
+		ReplicationThrottle:       util.Int64Pointer(10485760),
+		ConcurrentLeaderMovements: util.Int32Pointer(100),
+	}
+	testCases := []struct {
+		testName       string
+		operationType  banzaiv1alpha1.CruiseControlTaskOperation
+		brokerIDs      []string
+		isJBOD         bool
+		expectedParams map[string]string
+	}{
+		{
+			testName:      "add broker",
+			operationType: banzaiv1alpha1.OperationAddBroker,
+			brokerIDs:     []string{"1", "2"},
+			expectedParams: map[string]string{
+				"exclude_recently_demoted_brokers": "true",
+				"exclude_recently_removed_brokers": "true",
+				"brokerid":                         "1,2",
+				"replication_throttle":             "10485760",
+				"concurrent_leader_movements":      "100",
+			},
+		},
+		{
+			testName:      "JBOD rebalance",
+			operationType: banzaiv1alpha1.OperationRebalance,
+			brokerIDs:     []string{"1"},
+			isJBOD:        true,
+			expectedParams: map[string]string{
+				"exclude_recently_demoted_brokers": "true",
+				"exclude_recently_removed_brokers": "true",
+				"destination_broker_ids":           "1",
+				"rebalance_disk":                   "true",
+				"replication_throttle":             "10485760",
+				"concurrent_leader_movements":      "100",
+			},
+		},
+		{
+			testName:      "remove disks",
+			operationType: banzaiv1alpha1.OperationRemoveDisks,
+			brokerIDs:     []string{"1-/kafka-logs2"},
+			expectedParams: map[string]string{
+				"brokerid_and_logdirs": "1-/kafka-logs2",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		params := ccOperationParameters(testCase.operationType, testCase.brokerIDs, testCase.isJBOD, taskSpec)
+		assert.Equal(t, testCase.expectedParams, params, "testName", testCase.testName)
+	}
+}
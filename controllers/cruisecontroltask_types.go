@@ -41,7 +41,7 @@ func (t *CruiseControlTask) IsRequired() bool {
 	switch t.Operation {
 	case koperatorv1alpha1.OperationAddBroker, koperatorv1alpha1.OperationRemoveBroker:
 		return t.BrokerState.IsRequiredState()
-	case koperatorv1alpha1.OperationRebalance:
+	case koperatorv1alpha1.OperationRebalance, koperatorv1alpha1.OperationRemoveDisks:
 		return t.VolumeState.IsRequiredState()
 	}
 	return false
@@ -61,7 +61,7 @@ func (t *CruiseControlTask) Apply(instance *koperatorv1beta1.KafkaCluster) {
 			state.GracefulActionState.CruiseControlOperationReference = t.CruiseControlOperationReference
 			instance.Status.BrokersState[t.BrokerID] = state
 		}
-	case koperatorv1alpha1.OperationRebalance:
+	case koperatorv1alpha1.OperationRebalance, koperatorv1alpha1.OperationRemoveDisks:
 		if state, ok := instance.Status.BrokersState[t.BrokerID]; ok {
 			if volState, ok := state.GracefulActionState.VolumeStates[t.Volume]; ok {
 				volState.CruiseControlVolumeState = t.VolumeState
@@ -88,6 +88,8 @@ func (t *CruiseControlTask) SetStateScheduled() {
 		t.BrokerState = koperatorv1beta1.GracefulDownscaleScheduled
 	case koperatorv1alpha1.OperationRebalance:
 		t.VolumeState = koperatorv1beta1.GracefulDiskRebalanceScheduled
+	case koperatorv1alpha1.OperationRemoveDisks:
+		t.VolumeState = koperatorv1beta1.GracefulDiskRemovalScheduled
 	}
 }
 
@@ -104,6 +106,9 @@ func (t *CruiseControlTask) FromResult(operation *koperatorv1alpha1.CruiseContro
 		// When CruiseControlOperation is missing
 		case operation == nil:
 			t.BrokerState = koperatorv1beta1.GracefulUpscaleSucceeded
+		// Cancelled operations are requeued by the controller
+		case operation.IsCancelled():
+			t.BrokerState = koperatorv1beta1.GracefulUpscaleRequired
 		case operation.IsErrorPolicyIgnore() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
 			t.BrokerState = koperatorv1beta1.GracefulUpscaleSucceeded
 		case operation.IsPaused() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
@@ -121,6 +126,9 @@ func (t *CruiseControlTask) FromResult(operation *koperatorv1alpha1.CruiseContro
 		switch {
 		case operation == nil:
 			t.BrokerState = koperatorv1beta1.GracefulDownscaleSucceeded
+		// Cancelled operations are requeued by the controller
+		case operation.IsCancelled():
+			t.BrokerState = koperatorv1beta1.GracefulDownscaleRequired
 		case operation.IsErrorPolicyIgnore() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
 			t.BrokerState = koperatorv1beta1.GracefulDownscaleSucceeded
 		case operation.IsPaused() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
@@ -139,6 +147,9 @@ func (t *CruiseControlTask) FromResult(operation *koperatorv1alpha1.CruiseContro
 		switch {
 		case operation == nil:
 			t.VolumeState = koperatorv1beta1.GracefulDiskRebalanceSucceeded
+		// Cancelled operations are requeued by the controller
+		case operation.IsCancelled():
+			t.VolumeState = koperatorv1beta1.GracefulDiskRebalanceRequired
 		case operation.IsErrorPolicyIgnore() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
 			t.VolumeState = koperatorv1beta1.GracefulDiskRebalanceSucceeded
 		case operation.IsPaused() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
@@ -152,6 +163,26 @@ func (t *CruiseControlTask) FromResult(operation *koperatorv1alpha1.CruiseContro
 		case operation.CurrentTaskState() == "":
 			t.VolumeState = koperatorv1beta1.GracefulDiskRebalanceScheduled
 		}
+
+	case koperatorv1alpha1.OperationRemoveDisks:
+		switch {
+		// The removal can not be considered succeeded without the CruiseControlOperation as the disk may not be empty
+		case operation == nil:
+			t.VolumeState = koperatorv1beta1.GracefulDiskRemovalRequired
+		// Cancelled removals are not requeued, the removal is revoked by adding the disk back to the broker
+		case operation.IsCancelled():
+			t.VolumeState = koperatorv1beta1.GracefulDiskRemovalPaused
+		case operation.IsPaused() && operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
+			t.VolumeState = koperatorv1beta1.GracefulDiskRemovalPaused
+		case operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskActive, operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskInExecution:
+			t.VolumeState = koperatorv1beta1.GracefulDiskRemovalRunning
+		case operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompleted:
+			t.VolumeState = koperatorv1beta1.GracefulDiskRemovalSucceeded
+		case operation.CurrentTaskState() == koperatorv1beta1.CruiseControlTaskCompletedWithError:
+			t.VolumeState = koperatorv1beta1.GracefulDiskRemovalCompletedWithError
+		case operation.CurrentTaskState() == "":
+			t.VolumeState = koperatorv1beta1.GracefulDiskRemovalScheduled
+		}
 	}
 }
 
@@ -28,9 +28,13 @@ import (
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/banzaicloud/k8s-objectmatcher/patch"
 
@@ -73,6 +77,7 @@ type KafkaClusterReconciler struct {
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
@@ -361,6 +366,7 @@ func SetupKafkaClusterWithManager(mgr ctrl.Manager) *ctrl.Builder {
 		Named("KafkaCluster")
 
 	kafkaWatches(builder)
+	configSecretWatches(builder, mgr.GetClient(), log)
 	envoyWatches(builder)
 	cruiseControlWatches(builder)
 
@@ -386,7 +392,7 @@ func SetupKafkaClusterWithManager(mgr ctrl.Manager) *ctrl.Builder {
 					if !reflect.DeepEqual(oldObj.Spec, newObj.Spec) ||
 						oldObj.GetDeletionTimestamp() != newObj.GetDeletionTimestamp() ||
 						oldObj.GetGeneration() != newObj.GetGeneration() ||
-						!reflect.DeepEqual(oldObj.Status.BrokersState, newObj.Status.BrokersState) {
+						brokersStateChanged(oldObj.Status.BrokersState, newObj.Status.BrokersState) {
 						return true
 					}
 					return false
@@ -407,6 +413,96 @@ func kafkaWatches(builder *ctrl.Builder) *ctrl.Builder {
 		Owns(&corev1.Pod{})
 }
 
+// referencedSecretsField is the field the KafkaClusters are indexed by with the names of the secrets referenced by
+// their configSecretRefs or holding the server certificate of their listeners
+const referencedSecretsField = "referencedSecrets"
+
+// AddKafkaClusterIndexers adds the indexers of the KafkaClusters the watches of the KafkaCluster controller rely on
+func AddKafkaClusterIndexers(ctx context.Context, cache cache.Cache) error {
+	err := cache.IndexField(ctx, &v1beta1.KafkaCluster{}, referencedSecretsField, referencedSecretNames)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not setup indexer for field", "field", referencedSecretsField)
+	}
+	return nil
+}
+
+// referencedSecretNames returns the names of the secrets referenced by the configSecretRefs of the KafkaCluster
+// or holding the server certificate of its listeners
+func referencedSecretNames(obj client.Object) []string {
+	cluster := obj.(*v1beta1.KafkaCluster)
+	return append(configSecretNames(cluster.Spec), listenerServerCertSecretNames(cluster)...)
+}
+
+// configSecretWatches triggers the reconciliation of the KafkaClusters when a secret referenced by their configSecretRefs
+// or holding the server certificate of their listeners changes, only the metadata of the secrets is watched
+func configSecretWatches(builder *ctrl.Builder, client client.Reader, log logr.Logger) *ctrl.Builder {
+	mapper := configSecretMapper{
+		client: client,
+		log:    log,
+	}
+	return builder.Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(mapper.mapToKafkaClusters),
+		ctrlbuilder.OnlyMetadata)
+}
+
+type configSecretMapper struct {
+	client client.Reader
+	log    logr.Logger
+}
+
+// mapToKafkaClusters maps Secret events to the reconcile events of the KafkaClusters referencing the secret in their
+// configSecretRefs or listeners
+func (m *configSecretMapper) mapToKafkaClusters(obj client.Object) []ctrl.Request {
+	var clusters v1beta1.KafkaClusterList
+	if err := m.client.List(context.Background(), &clusters, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{referencedSecretsField: obj.GetName()}); err != nil {
+		m.log.Error(err, "could not list KafkaClusters", "namespace", obj.GetNamespace(), "secret", obj.GetName())
+		return nil
+	}
+
+	requests := make([]ctrl.Request, 0, len(clusters.Items))
+	for i := range clusters.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&clusters.Items[i])})
+	}
+	return requests
+}
+
+// configSecretNames returns the names of the secrets referenced by the configSecretRefs of the KafkaCluster
+func configSecretNames(spec v1beta1.KafkaClusterSpec) []string {
+	refs := append([]v1beta1.ConfigSecretRef{}, spec.ConfigSecretRefs...)
+	for _, brokerConfigGroup := range spec.BrokerConfigGroups {
+		refs = append(refs, brokerConfigGroup.ConfigSecretRefs...)
+	}
+	for _, broker := range spec.Brokers {
+		if broker.BrokerConfig != nil {
+			refs = append(refs, broker.BrokerConfig.ConfigSecretRefs...)
+		}
+	}
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.SecretKeyRef.Name)
+	}
+	return names
+}
+
+// listenerServerCertSecretNames returns the names of the secrets holding the server certificate of the SSL listeners
+// of the KafkaCluster, so the renewed certificates can be reloaded by the brokers
+func listenerServerCertSecretNames(cluster *v1beta1.KafkaCluster) []string {
+	var listeners []v1beta1.CommonListenerSpec
+	for _, iListener := range cluster.Spec.ListenersConfig.InternalListeners {
+		listeners = append(listeners, iListener.CommonListenerSpec)
+	}
+	for _, eListener := range cluster.Spec.ListenersConfig.ExternalListeners {
+		listeners = append(listeners, eListener.CommonListenerSpec)
+	}
+	var names []string
+	for _, listener := range listeners {
+		if listener.Type.IsSSL() {
+			names = append(names, kafka.ListenerServerCertSecretName(listener, cluster.Name))
+		}
+	}
+	return names
+}
+
 func envoyWatches(builder *ctrl.Builder) *ctrl.Builder {
 	return builder.
 		Owns(&corev1.Service{}).
@@ -0,0 +1,107 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func TestConfigSecretMapper(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	secretRef := func(name string) []v1beta1.ConfigSecretRef {
+		return []v1beta1.ConfigSecretRef{{
+			Config:       "sasl.jaas.config",
+			SecretKeyRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "jaas"},
+		}}
+	}
+	clusters := []*v1beta1.KafkaCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-ref", Namespace: testNamespace},
+			Spec:       v1beta1.KafkaClusterSpec{ConfigSecretRefs: secretRef("credentials")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "group-ref", Namespace: testNamespace},
+			Spec: v1beta1.KafkaClusterSpec{BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+				"default": {ConfigSecretRefs: secretRef("credentials")},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "broker-ref", Namespace: testNamespace},
+			Spec: v1beta1.KafkaClusterSpec{Brokers: []v1beta1.Broker{
+				{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{ConfigSecretRefs: secretRef("credentials")}},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-ref", Namespace: testNamespace},
+			Spec:       v1beta1.KafkaClusterSpec{ConfigSecretRefs: secretRef("other")},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "other"},
+			Spec:       v1beta1.KafkaClusterSpec{ConfigSecretRefs: secretRef("credentials")},
+		},
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithIndex(&v1beta1.KafkaCluster{}, referencedSecretsField, referencedSecretNames)
+	for _, cluster := range clusters {
+		builder.WithObjects(cluster)
+	}
+	mapper := configSecretMapper{client: builder.Build(), log: log}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: testNamespace}}
+	require.ElementsMatch(t, []ctrl.Request{
+		{NamespacedName: types.NamespacedName{Name: "cluster-ref", Namespace: testNamespace}},
+		{NamespacedName: types.NamespacedName{Name: "group-ref", Namespace: testNamespace}},
+		{NamespacedName: types.NamespacedName{Name: "broker-ref", Namespace: testNamespace}},
+	}, mapper.mapToKafkaClusters(secret))
+
+	secret.Name = "unreferenced"
+	require.Empty(t, mapper.mapToKafkaClusters(secret))
+}
+
+func TestListenerServerCertSecretNames(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: testNamespace},
+		Spec: v1beta1.KafkaClusterSpec{
+			ListenersConfig: v1beta1.ListenersConfig{
+				InternalListeners: []v1beta1.InternalListenerConfig{
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "internal", Type: v1beta1.SecurityProtocolSSL}},
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "plaintext", Type: v1beta1.SecurityProtocolPlaintext,
+						ServerSSLCertSecret: &corev1.LocalObjectReference{Name: "unused"}}},
+				},
+				ExternalListeners: []v1beta1.ExternalListenerConfig{
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "external", Type: v1beta1.SecurityProtocolSSL,
+						ServerSSLCertSecret: &corev1.LocalObjectReference{Name: "external-server-cert"}}},
+					{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "sasl", Type: v1beta1.SecurityProtocolSaslSSL,
+						ServerSSLCertSecret: &corev1.LocalObjectReference{Name: "sasl-server-cert"}}},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, []string{"kafka-server-certificate", "external-server-cert", "sasl-server-cert"}, listenerServerCertSecretNames(cluster))
+}
@@ -0,0 +1,382 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/scale"
+)
+
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   v1alpha1.VolumeSnapshotAPIGroup,
+	Version: v1alpha1.VolumeSnapshotAPIVersion,
+	Kind:    v1alpha1.VolumeSnapshotKind,
+}
+
+// SetupKafkaClusterBackupWithManager registers kafka cluster backup controller with manager
+func SetupKafkaClusterBackupWithManager(mgr ctrl.Manager) *ctrl.Builder {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.KafkaClusterBackup{}).
+		Owns(&v1alpha1.CruiseControlOperation{}).
+		WithEventFilter(SkipClusterRegistryOwnedResourcePredicate{}).
+		Named("KafkaClusterBackup")
+}
+
+// blank assignment to verify that KafkaClusterBackupReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &KafkaClusterBackupReconciler{}
+
+// KafkaClusterBackupReconciler reconciles a KafkaClusterBackup object
+type KafkaClusterBackupReconciler struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusterbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkaclusterbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=cruisecontroloperations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=cruisecontroloperations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+
+// Reconcile takes the volume snapshots of the brokers one broker at a time
+func (r *KafkaClusterBackupReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := logr.FromContextOrDiscard(ctx)
+	log.V(1).Info("Reconciling KafkaClusterBackup")
+
+	backup := &v1alpha1.KafkaClusterBackup{}
+	if err := r.Client.Get(ctx, request.NamespacedName, backup); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconciled()
+		}
+		return requeueWithError(log, err.Error(), err)
+	}
+
+	if backup.IsDone() {
+		return reconciled()
+	}
+
+	cluster := &v1beta1.KafkaCluster{}
+	clusterRef := types.NamespacedName{
+		Name:      backup.Spec.ClusterRef.Name,
+		Namespace: getClusterRefNamespace(backup.Namespace, backup.Spec.ClusterRef),
+	}
+	if err := r.Client.Get(ctx, clusterRef, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.updateBackupStatus(ctx, log, backup, v1alpha1.KafkaClusterBackupFailed, fmt.Sprintf("KafkaCluster %s not found", clusterRef))
+		}
+		return requeueWithError(log, "failed to lookup referenced cluster", err)
+	}
+	if cluster.Namespace != backup.Namespace {
+		return r.updateBackupStatus(ctx, log, backup, v1alpha1.KafkaClusterBackupFailed,
+			"the KafkaClusterBackup must be in the namespace of the KafkaCluster as volume snapshots are namespaced")
+	}
+
+	if backup.Status.State == "" {
+		now := metav1.Now()
+		backup.Status.StartTime = &now
+		return r.updateBackupStatus(ctx, log, backup, v1alpha1.KafkaClusterBackupRunning, "")
+	}
+
+	brokerIDs := make([]int, 0, len(cluster.Spec.Brokers))
+	for _, broker := range cluster.Spec.Brokers {
+		brokerIDs = append(brokerIDs, int(broker.Id))
+	}
+	sort.Ints(brokerIDs)
+
+	for _, id := range brokerIDs {
+		brokerID := strconv.Itoa(id)
+		brokerStatus := backup.Status.Brokers[brokerID]
+		if brokerStatus.State == v1alpha1.BrokerBackupSucceeded {
+			continue
+		}
+
+		brokerStatus, err := r.backupBroker(ctx, log, backup, cluster, brokerID, brokerStatus)
+		if err != nil {
+			return requeueWithError(log, "failed to back up broker "+brokerID, err)
+		}
+		if backup.Status.Brokers == nil {
+			backup.Status.Brokers = make(map[string]v1alpha1.BrokerBackupStatus)
+		}
+		backup.Status.Brokers[brokerID] = brokerStatus
+
+		switch brokerStatus.State {
+		case v1alpha1.BrokerBackupFailed:
+			return r.updateBackupStatus(ctx, log, backup, v1alpha1.KafkaClusterBackupFailed, fmt.Sprintf("backup of broker %s failed", brokerID))
+		case v1alpha1.BrokerBackupSucceeded:
+			log.Info("volume snapshots of the broker are ready", v1beta1.BrokerIdLabelKey, brokerID)
+			continue
+		}
+
+		// the brokers are backed up one by one
+		return r.updateBackupStatus(ctx, log, backup, v1alpha1.KafkaClusterBackupRunning, "")
+	}
+
+	now := metav1.Now()
+	backup.Status.CompletionTime = &now
+	return r.updateBackupStatus(ctx, log, backup, v1alpha1.KafkaClusterBackupSucceeded, "")
+}
+
+// backupBroker advances the backup of a single broker and returns its new state
+func (r *KafkaClusterBackupReconciler) backupBroker(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup,
+	cluster *v1beta1.KafkaCluster, brokerID string, brokerStatus v1alpha1.BrokerBackupStatus) (v1alpha1.BrokerBackupStatus, error) {
+	if backup.Spec.DrainLeadership && (brokerStatus.State == "" || brokerStatus.State == v1alpha1.BrokerBackupDraining) {
+		brokerStatus.State = v1alpha1.BrokerBackupDraining
+		done, err := r.ensureLeadershipOperation(ctx, log, backup, cluster, brokerID, v1alpha1.OperationDemoteBroker, &brokerStatus)
+		if err != nil {
+			return brokerStatus, err
+		}
+		if brokerStatus.State == v1alpha1.BrokerBackupFailed {
+			// some of the leaderships may have been moved off the broker before the demotion was cancelled
+			brokerStatus.BackupFailed = true
+			brokerStatus.State = v1alpha1.BrokerBackupRestoringLeadership
+			return brokerStatus, nil
+		}
+		if !done {
+			return brokerStatus, nil
+		}
+	}
+	if brokerStatus.State == v1alpha1.BrokerBackupRestoringLeadership {
+		done, err := r.ensureLeadershipOperation(ctx, log, backup, cluster, brokerID, v1alpha1.OperationRebalance, &brokerStatus)
+		if err == nil && done {
+			brokerStatus.State = v1alpha1.BrokerBackupSucceeded
+			if brokerStatus.BackupFailed {
+				brokerStatus.State = v1alpha1.BrokerBackupFailed
+			}
+		}
+		return brokerStatus, err
+	}
+	brokerStatus.State = v1alpha1.BrokerBackupSnapshotting
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	matchingLabels := client.MatchingLabels(
+		apiutil.MergeLabels(
+			apiutil.LabelsForKafka(cluster.Name),
+			map[string]string{v1beta1.BrokerIdLabelKey: brokerID},
+		),
+	)
+	if err := r.Client.List(ctx, pvcList, client.InNamespace(cluster.Namespace), matchingLabels); err != nil {
+		return brokerStatus, errors.WrapIfWithDetails(err, "failed to list persistent volume claims", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	sort.Slice(pvcList.Items, func(i, j int) bool {
+		return pvcList.Items[i].Name < pvcList.Items[j].Name
+	})
+
+	snapshots := make([]v1alpha1.VolumeSnapshotStatus, 0, len(pvcList.Items))
+	allReady := true
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		snapshot, err := r.ensureVolumeSnapshot(ctx, log, backup, cluster, brokerID, pvc)
+		if err != nil {
+			return brokerStatus, err
+		}
+		ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found && message != "" {
+			log.Info("volume snapshot failed", v1beta1.BrokerIdLabelKey, brokerID, "name", snapshot.GetName(), "message", message)
+			brokerStatus.State = v1alpha1.BrokerBackupFailed
+		}
+		allReady = allReady && ready
+		snapshots = append(snapshots, v1alpha1.VolumeSnapshotStatus{
+			MountPath:                 pvc.Annotations["mountPath"],
+			PersistentVolumeClaimName: pvc.Name,
+			VolumeSnapshotName:        snapshot.GetName(),
+			ReadyToUse:                ready,
+		})
+	}
+	brokerStatus.Snapshots = snapshots
+
+	switch {
+	case brokerStatus.State == v1alpha1.BrokerBackupFailed && backup.Spec.DrainLeadership:
+		// the leaderships are moved back before the backup fails, so the broker does not stay demoted
+		brokerStatus.BackupFailed = true
+		brokerStatus.State = v1alpha1.BrokerBackupRestoringLeadership
+	case brokerStatus.State == v1alpha1.BrokerBackupFailed || !allReady:
+	case backup.Spec.DrainLeadership:
+		// the leaderships are moved back before the next broker is drained, so the demotions do not pile up
+		brokerStatus.State = v1alpha1.BrokerBackupRestoringLeadership
+	default:
+		brokerStatus.State = v1alpha1.BrokerBackupSucceeded
+	}
+	return brokerStatus, nil
+}
+
+// ensureLeadershipOperation submits the CruiseControlOperation which either drains the partition leaderships off the
+// broker (demote_broker) or moves them back (rebalance) unless it has been submitted already.
+// It returns true when the operation has been finished.
+func (r *KafkaClusterBackupReconciler) ensureLeadershipOperation(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup,
+	cluster *v1beta1.KafkaCluster, brokerID string, operationType v1alpha1.CruiseControlTaskOperation, brokerStatus *v1alpha1.BrokerBackupStatus) (bool, error) {
+	operationRef := &brokerStatus.DemoteOperationReference
+	if operationType == v1alpha1.OperationRebalance {
+		operationRef = &brokerStatus.RestoreLeadershipOperationReference
+	}
+
+	if *operationRef != nil {
+		operation := &v1alpha1.CruiseControlOperation{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: (*operationRef).Name, Namespace: cluster.Namespace}, operation)
+		switch {
+		case apierrors.IsNotFound(err):
+			log.Info("CruiseControlOperation not found, submitting it again", v1beta1.BrokerIdLabelKey, brokerID, "name", (*operationRef).Name)
+		case err != nil:
+			return false, errors.WrapIfWithDetails(err, "failed to get CruiseControlOperation", "name", (*operationRef).Name)
+		case operation.IsCancelled():
+			log.Info("CruiseControlOperation has been cancelled", v1beta1.BrokerIdLabelKey, brokerID, "name", operation.Name)
+			brokerStatus.State = v1alpha1.BrokerBackupFailed
+			return false, nil
+		default:
+			return operation.IsFinished(), nil
+		}
+	}
+
+	operation, err := r.createLeadershipOperation(ctx, backup, cluster, brokerID, operationType)
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to create CruiseControlOperation", v1beta1.BrokerIdLabelKey, brokerID, "operation", operationType)
+	}
+	log.Info("CruiseControlOperation created", v1beta1.BrokerIdLabelKey, brokerID, "name", operation.Name, "operation", operationType)
+	*operationRef = &corev1.LocalObjectReference{Name: operation.Name}
+	return false, nil
+}
+
+// createLeadershipOperation creates a CruiseControlOperation owned by the backup. The rebalance operation only
+// balances the leader replicas to move the leaderships back to the demoted broker. Preferred leader election can not
+// be used for that, as demote_broker moves the broker to the end of the replica lists. Replicas may still be moved
+// when moving the leaderships alone can not balance the leader replicas.
+func (r *KafkaClusterBackupReconciler) createLeadershipOperation(ctx context.Context, backup *v1alpha1.KafkaClusterBackup,
+	cluster *v1beta1.KafkaCluster, brokerID string, operationType v1alpha1.CruiseControlTaskOperation) (*v1alpha1.CruiseControlOperation, error) {
+	params := map[string]string{
+		"brokerid": brokerID,
+	}
+	if operationType == v1alpha1.OperationRebalance {
+		params = scale.ExecutionParameters(cluster.Spec.CruiseControlConfig.CruiseControlTaskSpec)
+		params["goals"] = "LeaderReplicaDistributionGoal"
+		params["skip_hard_goal_check"] = "true"
+	}
+
+	operation := &v1alpha1.CruiseControlOperation{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-%s-", backup.Name, strings.ReplaceAll(string(operationType), "_", "")),
+			Namespace:    cluster.Namespace,
+			Labels:       apiutil.LabelsForKafka(cluster.Name),
+		},
+		Spec: v1alpha1.CruiseControlOperationSpec{
+			ErrorPolicy: v1alpha1.ErrorPolicyRetry,
+		},
+	}
+	if err := controllerutil.SetControllerReference(backup, operation, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Client.Create(ctx, operation); err != nil {
+		return nil, err
+	}
+
+	operation.Status.CurrentTask = &v1alpha1.CruiseControlTask{
+		Operation:  operationType,
+		Parameters: params,
+	}
+	if err := r.Client.Status().Update(ctx, operation); err != nil {
+		return nil, err
+	}
+	return operation, nil
+}
+
+// ensureVolumeSnapshot creates the volume snapshot of the persistent volume claim unless it exists already
+func (r *KafkaClusterBackupReconciler) ensureVolumeSnapshot(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup,
+	cluster *v1beta1.KafkaCluster, brokerID string, pvc *corev1.PersistentVolumeClaim) (*unstructured.Unstructured, error) {
+	desired := newVolumeSnapshot(backup, cluster, brokerID, pvc)
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(volumeSnapshotGVK)
+	err := r.Client.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	if err == nil {
+		return current, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, errors.WrapIfWithDetails(err, "failed to get volume snapshot", "name", desired.GetName())
+	}
+
+	if err := controllerutil.SetControllerReference(backup, desired, r.Scheme); err != nil {
+		return nil, errors.WrapIf(err, "failed to set controller reference on volume snapshot")
+	}
+	if err := r.Client.Create(ctx, desired); err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to create volume snapshot", "name", desired.GetName())
+	}
+	log.Info("volume snapshot created", v1beta1.BrokerIdLabelKey, brokerID, "name", desired.GetName(), "persistentVolumeClaim", pvc.Name)
+	return desired, nil
+}
+
+// newVolumeSnapshot generates the volume snapshot of a broker persistent volume claim. The snapshot carries the same
+// labels and mountPath annotation as the persistent volume claim, so it can be matched with the broker volume on restore.
+func newVolumeSnapshot(backup *v1alpha1.KafkaClusterBackup, cluster *v1beta1.KafkaCluster, brokerID string,
+	pvc *corev1.PersistentVolumeClaim) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(fmt.Sprintf("%s-%s", backup.Name, pvc.Name))
+	snapshot.SetNamespace(pvc.Namespace)
+	snapshot.SetLabels(apiutil.MergeLabels(
+		apiutil.LabelsForKafka(cluster.Name),
+		map[string]string{
+			v1beta1.BrokerIdLabelKey:            brokerID,
+			v1alpha1.KafkaClusterBackupLabelKey: backup.Name,
+		},
+	))
+	snapshot.SetAnnotations(map[string]string{"mountPath": pvc.Annotations["mountPath"]})
+
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": pvc.Name,
+		},
+	}
+	if backup.Spec.VolumeSnapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = backup.Spec.VolumeSnapshotClassName
+	}
+	snapshot.Object["spec"] = spec
+	return snapshot
+}
+
+func (r *KafkaClusterBackupReconciler) updateBackupStatus(ctx context.Context, log logr.Logger, backup *v1alpha1.KafkaClusterBackup,
+	state v1alpha1.KafkaClusterBackupState, message string) (reconcile.Result, error) {
+	backup.Status.State = state
+	backup.Status.Message = message
+	if err := r.Client.Status().Update(ctx, backup); err != nil {
+		return requeueWithError(log, "failed to update KafkaClusterBackup status", err)
+	}
+	if state == v1alpha1.KafkaClusterBackupRunning {
+		return requeueAfter(defaultRequeueIntervalInSeconds)
+	}
+	log.Info("KafkaClusterBackup finished", "state", state, "message", message)
+	return reconciled()
+}
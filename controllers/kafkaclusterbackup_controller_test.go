@@ -0,0 +1,285 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func newBrokerPvc(name string, brokerID string, mountPath string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "kafka",
+			Labels:      map[string]string{"app": "kafka", "kafka_cr": "kafka", v1beta1.BrokerIdLabelKey: brokerID},
+			Annotations: map[string]string{"mountPath": mountPath},
+		},
+	}
+}
+
+func setVolumeSnapshotsReady(t *testing.T, c client.Client, names ...string) {
+	for _, name := range names {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "kafka"}, snapshot))
+		require.NoError(t, unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"))
+		require.NoError(t, c.Update(context.Background(), snapshot))
+	}
+}
+
+func TestKafkaClusterBackupReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{Id: 1}, {Id: 0}},
+		},
+	}
+	backup := &v1alpha1.KafkaClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kafka"},
+		Spec: v1alpha1.KafkaClusterBackupSpec{
+			ClusterRef:              v1alpha1.ClusterReference{Name: "kafka"},
+			VolumeSnapshotClassName: "csi-snapclass",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		cluster,
+		backup,
+		newBrokerPvc("kafka-0-storage-0", "0", "/kafka-logs"),
+		newBrokerPvc("kafka-0-storage-1", "0", "/kafka-logs2"),
+		newBrokerPvc("kafka-1-storage-0", "1", "/kafka-logs"),
+	).Build()
+	r := &KafkaClusterBackupReconciler{Client: c, Scheme: scheme}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "backup", Namespace: "kafka"}}
+	reconcileAndGet := func() *v1alpha1.KafkaClusterBackup {
+		_, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		current := &v1alpha1.KafkaClusterBackup{}
+		require.NoError(t, c.Get(context.Background(), request.NamespacedName, current))
+		return current
+	}
+
+	current := reconcileAndGet()
+	require.Equal(t, v1alpha1.KafkaClusterBackupRunning, current.Status.State)
+	require.NotNil(t, current.Status.StartTime)
+
+	// the snapshots of the broker with the lowest ID are taken first
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupSnapshotting, current.Status.Brokers["0"].State)
+	require.Len(t, current.Status.Brokers["0"].Snapshots, 2)
+	require.NotContains(t, current.Status.Brokers, "1")
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "backup-kafka-0-storage-1", Namespace: "kafka"}, snapshot))
+	require.Equal(t, "0", snapshot.GetLabels()[v1beta1.BrokerIdLabelKey])
+	require.Equal(t, "backup", snapshot.GetLabels()[v1alpha1.KafkaClusterBackupLabelKey])
+	require.Equal(t, "/kafka-logs2", snapshot.GetAnnotations()["mountPath"])
+	pvcName, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	require.Equal(t, "kafka-0-storage-1", pvcName)
+	className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	require.Equal(t, "csi-snapclass", className)
+
+	setVolumeSnapshotsReady(t, c, "backup-kafka-0-storage-0", "backup-kafka-0-storage-1")
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupSucceeded, current.Status.Brokers["0"].State)
+	require.Equal(t, v1alpha1.BrokerBackupSnapshotting, current.Status.Brokers["1"].State)
+
+	setVolumeSnapshotsReady(t, c, "backup-kafka-1-storage-0")
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.KafkaClusterBackupSucceeded, current.Status.State)
+	require.NotNil(t, current.Status.CompletionTime)
+
+	snapshotName, ok := current.Status.VolumeSnapshotName("0", "/kafka-logs2")
+	require.True(t, ok)
+	require.Equal(t, "backup-kafka-0-storage-1", snapshotName)
+}
+
+func TestKafkaClusterBackupDrainLeadership(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1}},
+		},
+	}
+	backup := &v1alpha1.KafkaClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kafka"},
+		Spec: v1alpha1.KafkaClusterBackupSpec{
+			ClusterRef:      v1alpha1.ClusterReference{Name: "kafka"},
+			DrainLeadership: true,
+		},
+		Status: v1alpha1.KafkaClusterBackupStatus{State: v1alpha1.KafkaClusterBackupRunning},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		cluster,
+		backup,
+		newBrokerPvc("kafka-0-storage-0", "0", "/kafka-logs"),
+	).Build()
+
+	r := &KafkaClusterBackupReconciler{Client: c, Scheme: scheme}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "backup", Namespace: "kafka"}}
+	reconcileAndGet := func() *v1alpha1.KafkaClusterBackup {
+		_, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		current := &v1alpha1.KafkaClusterBackup{}
+		require.NoError(t, c.Get(context.Background(), request.NamespacedName, current))
+		return current
+	}
+	getOperation := func(ref *corev1.LocalObjectReference) *v1alpha1.CruiseControlOperation {
+		require.NotNil(t, ref)
+		operation := &v1alpha1.CruiseControlOperation{}
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: ref.Name, Namespace: "kafka"}, operation))
+		return operation
+	}
+	setOperationState := func(operation *v1alpha1.CruiseControlOperation, state v1beta1.CruiseControlUserTaskState) {
+		operation.Status.CurrentTask.State = state
+		require.NoError(t, c.Status().Update(context.Background(), operation))
+	}
+
+	// the leaderships are drained with a demote_broker CruiseControlOperation
+	current := reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupDraining, current.Status.Brokers["0"].State)
+	demote := getOperation(current.Status.Brokers["0"].DemoteOperationReference)
+	require.Equal(t, v1alpha1.OperationDemoteBroker, demote.CurrentTaskOperation())
+	require.Equal(t, map[string]string{"brokerid": "0"}, demote.CurrentTaskParameters())
+	require.Equal(t, "kafka", demote.GetClusterRef())
+	require.Equal(t, "backup", demote.OwnerReferences[0].Name)
+
+	// no snapshot is taken until the leaderships are drained
+	setOperationState(demote, v1beta1.CruiseControlTaskInExecution)
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupDraining, current.Status.Brokers["0"].State)
+	require.Empty(t, current.Status.Brokers["0"].Snapshots)
+
+	setOperationState(demote, v1beta1.CruiseControlTaskCompleted)
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupSnapshotting, current.Status.Brokers["0"].State)
+	require.Len(t, current.Status.Brokers["0"].Snapshots, 1)
+
+	// the leaderships are moved back once the snapshots are ready
+	setVolumeSnapshotsReady(t, c, "backup-kafka-0-storage-0")
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupRestoringLeadership, current.Status.Brokers["0"].State)
+	require.NotContains(t, current.Status.Brokers, "1")
+
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupRestoringLeadership, current.Status.Brokers["0"].State)
+	restore := getOperation(current.Status.Brokers["0"].RestoreLeadershipOperationReference)
+	require.Equal(t, v1alpha1.OperationRebalance, restore.CurrentTaskOperation())
+	require.Equal(t, "LeaderReplicaDistributionGoal", restore.CurrentTaskParameters()["goals"])
+
+	setOperationState(restore, v1beta1.CruiseControlTaskCompleted)
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupSucceeded, current.Status.Brokers["0"].State)
+
+	// the next broker is drained only after the leaderships of the previous one are restored
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupDraining, current.Status.Brokers["1"].State)
+	require.NotNil(t, current.Status.Brokers["1"].DemoteOperationReference)
+}
+
+func TestKafkaClusterBackupDrainLeadershipSnapshotFailed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{Id: 0}},
+		},
+	}
+	backup := &v1alpha1.KafkaClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kafka"},
+		Spec: v1alpha1.KafkaClusterBackupSpec{
+			ClusterRef:      v1alpha1.ClusterReference{Name: "kafka"},
+			DrainLeadership: true,
+		},
+		Status: v1alpha1.KafkaClusterBackupStatus{State: v1alpha1.KafkaClusterBackupRunning},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		cluster,
+		backup,
+		newBrokerPvc("kafka-0-storage-0", "0", "/kafka-logs"),
+	).Build()
+
+	r := &KafkaClusterBackupReconciler{Client: c, Scheme: scheme}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "backup", Namespace: "kafka"}}
+	reconcileAndGet := func() *v1alpha1.KafkaClusterBackup {
+		_, err := r.Reconcile(context.Background(), request)
+		require.NoError(t, err)
+		current := &v1alpha1.KafkaClusterBackup{}
+		require.NoError(t, c.Get(context.Background(), request.NamespacedName, current))
+		return current
+	}
+	completeOperation := func(ref *corev1.LocalObjectReference) *v1alpha1.CruiseControlOperation {
+		require.NotNil(t, ref)
+		operation := &v1alpha1.CruiseControlOperation{}
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: ref.Name, Namespace: "kafka"}, operation))
+		operation.Status.CurrentTask.State = v1beta1.CruiseControlTaskCompleted
+		require.NoError(t, c.Status().Update(context.Background(), operation))
+		return operation
+	}
+
+	current := reconcileAndGet()
+	completeOperation(current.Status.Brokers["0"].DemoteOperationReference)
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupSnapshotting, current.Status.Brokers["0"].State)
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "backup-kafka-0-storage-0", Namespace: "kafka"}, snapshot))
+	require.NoError(t, unstructured.SetNestedField(snapshot.Object, "snapshot failed", "status", "error", "message"))
+	require.NoError(t, c.Update(context.Background(), snapshot))
+
+	// the leaderships are moved back before the backup fails
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupRestoringLeadership, current.Status.Brokers["0"].State)
+	require.True(t, current.Status.Brokers["0"].BackupFailed)
+	require.Equal(t, v1alpha1.KafkaClusterBackupRunning, current.Status.State)
+
+	current = reconcileAndGet()
+	restore := completeOperation(current.Status.Brokers["0"].RestoreLeadershipOperationReference)
+	require.Equal(t, v1alpha1.OperationRebalance, restore.CurrentTaskOperation())
+
+	current = reconcileAndGet()
+	require.Equal(t, v1alpha1.BrokerBackupFailed, current.Status.Brokers["0"].State)
+	require.Equal(t, v1alpha1.KafkaClusterBackupFailed, current.Status.State)
+}
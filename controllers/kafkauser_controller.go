@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"reflect"
 	"strings"
@@ -28,9 +29,12 @@ import (
 	certv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/go-logr/logr"
 	certsigningreqv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlBuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,6 +50,7 @@ import (
 	"github.com/banzaicloud/koperator/pkg/k8sutil"
 	"github.com/banzaicloud/koperator/pkg/pki"
 	"github.com/banzaicloud/koperator/pkg/util"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
 	kafkautil "github.com/banzaicloud/koperator/pkg/util/kafka"
 	pkicommon "github.com/banzaicloud/koperator/pkg/util/pki"
 
@@ -54,6 +59,14 @@ import (
 
 var userFinalizer = "finalizer.kafkausers.kafka.banzaicloud.io"
 
+const (
+	userCertificateRenewedReason  = "CertificateRenewed"
+	userCertificateExpiringReason = "CertificateExpiring"
+	userCertificateExpiredReason  = "CertificateExpired"
+	// userCertificateExpiryCheckInterval is how often an expiring or expired user certificate is reported
+	userCertificateExpiryCheckInterval = time.Hour
+)
+
 // SetupKafkaUserWithManager registers KafkaUser controller to the manager
 func SetupKafkaUserWithManager(mgr ctrl.Manager, certSigningEnabled bool, certManagerEnabled bool) *ctrl.Builder {
 	log := mgr.GetLogger()
@@ -150,6 +163,8 @@ type KafkaUserReconciler struct {
 	// that reads objects from the cache and writes to the apiserver
 	Client client.Client
 	Scheme *runtime.Scheme
+	// Recorder is used to emit events about the expiration of user certificates, events are not emitted when it is not set
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=kafka.banzaicloud.io,resources=kafkausers,verbs=get;list;watch;create;update;patch;delete;deletecollection
@@ -161,6 +176,7 @@ type KafkaUserReconciler struct {
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/approval,verbs=update
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,verbs=approve
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile reads that state of the cluster for a KafkaUser object and makes changes based on the state read
 // and what is in the KafkaUser.Spec
@@ -196,6 +212,7 @@ func (r *KafkaUserReconciler) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	var kafkaUser string
+	var userCert *x509.Certificate
 
 	if instance.Spec.GetIfCertShouldBeCreated() {
 		// Validate the KafkaUser instance annotations before creating a certificate request
@@ -254,6 +271,9 @@ func (r *KafkaUserReconciler) Reconcile(ctx context.Context, request reconcile.R
 				Requeue: false,
 			}, err
 		}
+		if userCert, err = certutil.DecodeCertificate(user.Certificate); err != nil {
+			return requeueWithError(reqLogger, "could not decode the user certificate", err)
+		}
 		// check if marked for deletion and remove created certs
 		if k8sutil.IsMarkedForDeletion(instance.ObjectMeta) {
 			reqLogger.Info("Kafka user is marked for deletion, revoking certificates")
@@ -302,17 +322,64 @@ func (r *KafkaUserReconciler) Reconcile(ctx context.Context, request reconcile.R
 	}
 
 	// set user status
+	previousNotAfter := instance.Status.CertificateNotAfter
 	instance.Status = v1alpha1.KafkaUserStatus{
 		State: v1alpha1.UserStateCreated,
 	}
 	if len(instance.Spec.TopicGrants) > 0 {
 		instance.Status.ACLs = kafkautil.GrantsToACLStrings(kafkaUser, instance.Spec.TopicGrants)
 	}
+	if userCert != nil {
+		instance.Status.CertificateNotAfter = &metav1.Time{Time: userCert.NotAfter}
+	}
 	if err := r.Client.Status().Update(ctx, instance); err != nil {
 		return requeueWithError(reqLogger, "failed to update kafkauser status", err)
 	}
 
-	return reconciled()
+	if userCert == nil {
+		return reconciled()
+	}
+	return ctrl.Result{
+		RequeueAfter: r.checkUserCertificateExpiry(instance, previousNotAfter, userCert, time.Now()),
+	}, nil
+}
+
+// checkUserCertificateExpiry emits events about the renewal and the upcoming expiration of the user certificate
+// and returns the duration after which the certificate should be checked again
+func (r *KafkaUserReconciler) checkUserCertificateExpiry(user *v1alpha1.KafkaUser, previousNotAfter *metav1.Time,
+	cert *x509.Certificate, now time.Time) time.Duration {
+	if previousNotAfter != nil && !previousNotAfter.Time.Equal(cert.NotAfter) {
+		r.recordUserEvent(user, corev1.EventTypeNormal, userCertificateRenewedReason,
+			fmt.Sprintf("User certificate renewed, it expires at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	renewalPercentage := user.Spec.GetRenewalPercentage()
+	// expiry is reported halfway between the renewal of the certificate and its expiration
+	warningTime := certutil.RenewalTime(cert, (100+renewalPercentage)/2)
+	switch {
+	case !now.Before(cert.NotAfter):
+		r.recordUserEvent(user, corev1.EventTypeWarning, userCertificateExpiredReason,
+			fmt.Sprintf("User certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+		return userCertificateExpiryCheckInterval
+	case !now.Before(warningTime):
+		r.recordUserEvent(user, corev1.EventTypeWarning, userCertificateExpiringReason,
+			fmt.Sprintf("User certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+		if untilExpiry := cert.NotAfter.Sub(now); untilExpiry < userCertificateExpiryCheckInterval {
+			return untilExpiry
+		}
+		return userCertificateExpiryCheckInterval
+	}
+
+	if renewalTime := certutil.RenewalTime(cert, renewalPercentage); now.Before(renewalTime) {
+		return renewalTime.Sub(now)
+	}
+	return warningTime.Sub(now)
+}
+
+func (r *KafkaUserReconciler) recordUserEvent(user *v1alpha1.KafkaUser, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(user, eventType, reason, message)
+	}
 }
 
 func (r *KafkaUserReconciler) ensureClusterLabel(ctx context.Context, cluster *v1beta1.KafkaCluster, user *v1alpha1.KafkaUser) (*v1alpha1.KafkaUser, error) {
@@ -0,0 +1,126 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+)
+
+func TestCheckUserCertificateExpiry(t *testing.T) {
+	notBefore := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(100 * time.Hour),
+	}
+	previousNotAfter := &metav1.Time{Time: cert.NotAfter}
+
+	tests := []struct {
+		name             string
+		previousNotAfter *metav1.Time
+		now              time.Time
+		expectedRequeue  time.Duration
+		expectedEvents   []string
+	}{
+		{
+			name:             "certificate before renewal",
+			previousNotAfter: previousNotAfter,
+			now:              notBefore.Add(10 * time.Hour),
+			expectedRequeue:  57 * time.Hour,
+		},
+		{
+			name:             "certificate being renewed",
+			previousNotAfter: previousNotAfter,
+			now:              notBefore.Add(70 * time.Hour),
+			expectedRequeue:  13 * time.Hour,
+		},
+		{
+			name:             "certificate expiring",
+			previousNotAfter: previousNotAfter,
+			now:              notBefore.Add(90 * time.Hour),
+			expectedRequeue:  userCertificateExpiryCheckInterval,
+			expectedEvents:   []string{"Warning " + userCertificateExpiringReason},
+		},
+		{
+			name:             "certificate expiring within the check interval",
+			previousNotAfter: previousNotAfter,
+			now:              notBefore.Add(100*time.Hour - time.Minute),
+			expectedRequeue:  time.Minute,
+			expectedEvents:   []string{"Warning " + userCertificateExpiringReason},
+		},
+		{
+			name:             "certificate expired",
+			previousNotAfter: previousNotAfter,
+			now:              notBefore.Add(101 * time.Hour),
+			expectedRequeue:  userCertificateExpiryCheckInterval,
+			expectedEvents:   []string{"Warning " + userCertificateExpiredReason},
+		},
+		{
+			name:             "certificate renewed",
+			previousNotAfter: &metav1.Time{Time: notBefore.Add(-time.Hour)},
+			now:              notBefore,
+			expectedRequeue:  67 * time.Hour,
+			expectedEvents:   []string{"Normal " + userCertificateRenewedReason},
+		},
+		{
+			name:            "certificate created",
+			now:             notBefore,
+			expectedRequeue: 67 * time.Hour,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := KafkaUserReconciler{Recorder: recorder}
+			requeue := r.checkUserCertificateExpiry(&v1alpha1.KafkaUser{}, test.previousNotAfter, cert, test.now)
+			if requeue != test.expectedRequeue {
+				t.Errorf("expected requeue after %s, got %s", test.expectedRequeue, requeue)
+			}
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if len(events) != len(test.expectedEvents) {
+				t.Fatalf("expected events %v, got %v", test.expectedEvents, events)
+			}
+			for i := range events {
+				if !strings.HasPrefix(events[i], test.expectedEvents[i]) {
+					t.Errorf("expected event %q, got %q", test.expectedEvents[i], events[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckUserCertificateExpiryWithoutRecorder(t *testing.T) {
+	cert := &x509.Certificate{
+		NotBefore: time.Now().Add(-2 * time.Hour),
+		NotAfter:  time.Now().Add(-time.Hour),
+	}
+	r := KafkaUserReconciler{}
+	if requeue := r.checkUserCertificateExpiry(&v1alpha1.KafkaUser{}, nil, cert, time.Now()); requeue != userCertificateExpiryCheckInterval {
+		t.Errorf("expected requeue after %s, got %s", userCertificateExpiryCheckInterval, requeue)
+	}
+}
@@ -122,6 +122,41 @@ func (mr *MockCruiseControlScalerMockRecorder) BrokersWithState(ctx interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BrokersWithState", reflect.TypeOf((*MockCruiseControlScaler)(nil).BrokersWithState), varargs...)
 }
 
+// DemoteBrokers mocks base method.
+func (m *MockCruiseControlScaler) DemoteBrokers(ctx context.Context, brokerIDs ...string) (*scale.Result, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range brokerIDs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DemoteBrokers", varargs...)
+	ret0, _ := ret[0].(*scale.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DemoteBrokers indicates an expected call of DemoteBrokers.
+func (mr *MockCruiseControlScalerMockRecorder) DemoteBrokers(ctx interface{}, brokerIDs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, brokerIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DemoteBrokers", reflect.TypeOf((*MockCruiseControlScaler)(nil).DemoteBrokers), varargs...)
+}
+
+// DemoteBrokersWithParams mocks base method.
+func (m *MockCruiseControlScaler) DemoteBrokersWithParams(ctx context.Context, params map[string]string) (*scale.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DemoteBrokersWithParams", ctx, params)
+	ret0, _ := ret[0].(*scale.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DemoteBrokersWithParams indicates an expected call of DemoteBrokersWithParams.
+func (mr *MockCruiseControlScalerMockRecorder) DemoteBrokersWithParams(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DemoteBrokersWithParams", reflect.TypeOf((*MockCruiseControlScaler)(nil).DemoteBrokersWithParams), ctx, params)
+}
+
 // IsReady mocks base method.
 func (m *MockCruiseControlScaler) IsReady(ctx context.Context) bool {
 	m.ctrl.T.Helper()
@@ -280,6 +315,21 @@ func (mr *MockCruiseControlScalerMockRecorder) RemoveBrokersWithParams(ctx, para
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBrokersWithParams", reflect.TypeOf((*MockCruiseControlScaler)(nil).RemoveBrokersWithParams), ctx, params)
 }
 
+// RemoveDisksWithParams mocks base method.
+func (m *MockCruiseControlScaler) RemoveDisksWithParams(ctx context.Context, params map[string]string) (*scale.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDisksWithParams", ctx, params)
+	ret0, _ := ret[0].(*scale.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveDisksWithParams indicates an expected call of RemoveDisksWithParams.
+func (mr *MockCruiseControlScalerMockRecorder) RemoveDisksWithParams(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDisksWithParams", reflect.TypeOf((*MockCruiseControlScaler)(nil).RemoveDisksWithParams), ctx, params)
+}
+
 // Status mocks base method.
 func (m *MockCruiseControlScaler) Status(ctx context.Context) (scale.StatusTaskResult, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +157,7 @@ var _ = BeforeSuite(func(ctx SpecContext) {
 		KafkaClientProvider: kafkaclient.NewMockProvider(),
 	}
 
+	Expect(controllers.AddKafkaClusterIndexers(context.Background(), mgr.GetCache())).To(Succeed())
 	err = controllers.SetupKafkaClusterWithManager(mgr).Complete(&kafkaClusterReconciler)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -24,8 +24,8 @@ import (
 )
 
 // NewApp returns HTTPHandler
-func NewApp(log logr.Logger, client client.Client) http.Handler {
+func NewApp(log logr.Logger, client client.Client, options receiver.Options) http.Handler {
 	mux := http.NewServeMux()
-	mux.Handle(receiver.APIEndPoint, receiver.NewHTTPHandler(log, client))
+	mux.Handle(receiver.APIEndPoint, receiver.NewHTTPHandler(log, client, options))
 	return mux
 }
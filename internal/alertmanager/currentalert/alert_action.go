@@ -0,0 +1,196 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+// alertActionRetention is the time a KafkaAlertAction is kept for after its last update when the alert is not
+// received any more, it is longer than the default repeat interval of Alertmanager
+const alertActionRetention = 24 * time.Hour
+
+// alertActionName returns the name of the KafkaAlertAction which belongs to the alert with the given fingerprint
+func alertActionName(fingerprint model.Fingerprint) string {
+	return "alert-" + fingerprint.String()
+}
+
+// alertActionNamespace returns the namespace the KafkaAlertAction of the alert is stored in
+func alertActionNamespace(alert *currentAlertStruct) string {
+	return string(alert.Labels["namespace"])
+}
+
+// getAlertAction returns the stored KafkaAlertAction of the alert, or nil when it does not exist
+func getAlertAction(ctx context.Context, c client.Reader, fingerprint model.Fingerprint, alert *currentAlertStruct) (*v1alpha1.KafkaAlertAction, error) {
+	namespace := alertActionNamespace(alert)
+	if namespace == "" {
+		return nil, nil
+	}
+
+	action := &v1alpha1.KafkaAlertAction{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: alertActionName(fingerprint)}, action)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not get KafkaAlertAction", "fingerprint", fingerprint.String())
+	}
+	return action, nil
+}
+
+func newAlertAction(fingerprint model.Fingerprint, alert *currentAlertStruct, cluster *v1beta1.KafkaCluster) *v1alpha1.KafkaAlertAction {
+	labels := make(map[string]string, len(alert.Labels))
+	for name, value := range alert.Labels {
+		labels[string(name)] = string(value)
+	}
+
+	return &v1alpha1.KafkaAlertAction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      alertActionName(fingerprint),
+			Namespace: cluster.GetNamespace(),
+			Labels:    map[string]string{v1beta1.KafkaCRLabelKey: cluster.GetName()},
+		},
+		Spec: v1alpha1.KafkaAlertActionSpec{
+			Fingerprint: fingerprint.String(),
+			Command:     string(alert.Annotations["command"]),
+			ClusterName: cluster.GetName(),
+			Labels:      labels,
+		},
+	}
+}
+
+// recordAlertAction stores the processing state of the alert in its KafkaAlertAction. The resource is created
+// when it does not exist and the KafkaCluster of the alert is known, it is owned by the KafkaCluster so it is
+// deleted together with the cluster. The status is only updated when the update function changed it.
+func recordAlertAction(ctx context.Context, c client.Client, fingerprint model.Fingerprint, alert *currentAlertStruct,
+	cluster *v1beta1.KafkaCluster, update func(action *v1alpha1.KafkaAlertAction)) error {
+	action, err := getAlertAction(ctx, c, fingerprint, alert)
+	if err != nil {
+		return err
+	}
+	if action == nil {
+		if cluster == nil || alertActionNamespace(alert) != cluster.GetNamespace() {
+			return nil
+		}
+		action = newAlertAction(fingerprint, alert, cluster)
+		if err := controllerutil.SetControllerReference(cluster, action, c.Scheme()); err != nil {
+			return errors.WrapIfWithDetails(err, "could not set the owner of KafkaAlertAction", "fingerprint", fingerprint.String())
+		}
+		if err := c.Create(ctx, action); err != nil {
+			return errors.WrapIfWithDetails(err, "could not create KafkaAlertAction", "fingerprint", fingerprint.String())
+		}
+	}
+
+	currentStatus := action.Status.DeepCopy()
+	update(action)
+	if reflect.DeepEqual(*currentStatus, action.Status) {
+		return nil
+	}
+
+	conflictRetryFunction := func() error {
+		err := c.Status().Update(ctx, action)
+		if apierrors.IsConflict(err) {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(action), action); err != nil {
+				return errors.WrapIf(err, "failed to get updated KafkaAlertAction before updating its status")
+			}
+			update(action)
+		}
+		return err
+	}
+	return util.RetryOnConflict(util.DefaultBackOffForConflict, conflictRetryFunction)
+}
+
+// isAlertActionExpired returns true when the KafkaAlertAction is not needed any more. The cooldown of the command
+// is kept in the KafkaAlertActions, so they are only expired after the cooldown is over and the alert has been
+// resolved, or it has not been updated for longer than alertActionRetention.
+func isAlertActionExpired(action *v1alpha1.KafkaAlertAction, cooldown time.Duration, now time.Time) bool {
+	if action.Status.LastExecuted != nil && now.Before(action.Status.LastExecuted.Add(cooldown)) {
+		return false
+	}
+	if action.Status.AlertStatus == string(model.AlertResolved) {
+		return true
+	}
+	lastUpdate := action.GetCreationTimestamp().Time
+	if n := len(action.Status.History); n > 0 {
+		lastUpdate = action.Status.History[n-1].Time.Time
+	}
+	return now.Sub(lastUpdate) > alertActionRetention
+}
+
+// alertActionCooldown returns the cooldown of the command of the KafkaAlertAction
+func alertActionCooldown(ctx context.Context, c client.Reader, action *v1alpha1.KafkaAlertAction) (time.Duration, error) {
+	command, ok := DefaultCommandRegistry().Get(action.Spec.Command)
+	if !ok {
+		return 0, nil
+	}
+	cluster := &v1beta1.KafkaCluster{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: action.GetNamespace(), Name: action.Spec.ClusterName}, cluster)
+	if apierrors.IsNotFound(err) {
+		return CommandCooldown(command, nil), nil
+	}
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "could not get KafkaCluster of KafkaAlertAction", "name", action.GetName())
+	}
+	return CommandCooldown(command, cluster), nil
+}
+
+// deleteAlertActionIfExpired deletes the KafkaAlertAction when it is expired
+func deleteAlertActionIfExpired(ctx context.Context, c client.Client, action *v1alpha1.KafkaAlertAction, now time.Time) (bool, error) {
+	cooldown, err := alertActionCooldown(ctx, c, action)
+	if err != nil {
+		return false, err
+	}
+	if !isAlertActionExpired(action, cooldown, now) {
+		return false, nil
+	}
+	if err := c.Delete(ctx, action); client.IgnoreNotFound(err) != nil {
+		return false, errors.WrapIfWithDetails(err, "could not delete KafkaAlertAction", "name", action.GetName(), "namespace", action.GetNamespace())
+	}
+	return true, nil
+}
+
+// CollectAlertActions deletes the expired KafkaAlertActions, except the ones belonging to alerts which are still firing
+func (a *currentAlerts) CollectAlertActions(ctx context.Context, c client.Client, log logr.Logger) {
+	actions := &v1alpha1.KafkaAlertActionList{}
+	if err := c.List(ctx, actions); err != nil {
+		log.Error(err, "could not list KafkaAlertActions")
+		return
+	}
+	now := time.Now()
+	for i := range actions.Items {
+		action := &actions.Items[i]
+		if fingerprint, err := model.ParseFingerprint(action.Spec.Fingerprint); err == nil && a.isFiring(fingerprint) {
+			continue
+		}
+		if _, err := deleteAlertActionIfExpired(ctx, c, action, now); err != nil {
+			log.Error(err, "could not garbage collect KafkaAlertAction", "name", action.GetName(), "namespace", action.GetNamespace())
+		}
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func newAlertActionTestClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestRecordAlertAction(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka", UID: "cluster-uid"}}
+	c := newAlertActionTestClient(t, cluster)
+	ctx := context.Background()
+
+	fingerprint := model.Fingerprint(1111)
+	alerts := &currentAlerts{alerts: map[model.Fingerprint]*currentAlertStruct{
+		fingerprint: {
+			Status: model.AlertFiring,
+			Labels: model.LabelSet{
+				v1beta1.KafkaCRLabelKey: "kafka",
+				"namespace":             "kafka",
+			},
+			Annotations: model.LabelSet{"command": RebalanceCommand},
+		},
+	}}
+
+	// nothing is stored until the alert is processed
+	action, err := getAlertAction(ctx, c, fingerprint, alerts.alerts[fingerprint])
+	require.NoError(t, err)
+	assert.Nil(t, action)
+
+	require.NoError(t, recordProcessing(ctx, c, fingerprint, alerts.alerts[fingerprint], cluster, true, nil, nil))
+
+	action, err = getAlertAction(ctx, c, fingerprint, alerts.alerts[fingerprint])
+	require.NoError(t, err)
+	require.NotNil(t, action)
+	assert.Equal(t, alertActionName(fingerprint), action.Name)
+	assert.Equal(t, "kafka", action.Labels[v1beta1.KafkaCRLabelKey])
+	assert.Equal(t, RebalanceCommand, action.Spec.Command)
+	assert.Equal(t, "kafka", action.Spec.ClusterName)
+	if assert.Len(t, action.OwnerReferences, 1) {
+		assert.Equal(t, cluster.UID, action.OwnerReferences[0].UID)
+		assert.True(t, *action.OwnerReferences[0].Controller)
+	}
+	assert.True(t, action.Status.Processed)
+	assert.Equal(t, v1alpha1.AlertActionSucceeded, action.Status.LastResult)
+	// the execution time starts the cooldown of the command
+	assert.NotNil(t, action.Status.LastExecuted)
+
+	resolved := AlertState{
+		FingerPrint: fingerprint,
+		Status:      model.AlertResolved,
+		Labels:      alerts.alerts[fingerprint].Labels,
+		Annotations: alerts.alerts[fingerprint].Annotations,
+	}
+	require.NoError(t, alerts.ResolveAlert(ctx, resolved, c))
+
+	action, err = getAlertAction(ctx, c, fingerprint, alerts.alerts[fingerprint])
+	require.NoError(t, err)
+	assert.False(t, action.Status.Processed)
+	assert.Equal(t, string(model.AlertResolved), action.Status.AlertStatus)
+	require.Len(t, action.Status.History, 2)
+	assert.Equal(t, v1alpha1.AlertActionSucceeded, action.Status.History[0].Result)
+	assert.Equal(t, v1alpha1.AlertActionResolved, action.Status.History[1].Result)
+
+	// the resolved alert is deleted once the cooldown of its command is over
+	action.Status.LastExecuted = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	require.NoError(t, c.Status().Update(ctx, action))
+	require.NoError(t, alerts.ResolveAlert(ctx, resolved, c))
+
+	action, err = getAlertAction(ctx, c, fingerprint, alerts.alerts[fingerprint])
+	require.NoError(t, err)
+	assert.Nil(t, action)
+}
+
+func TestRecordAlertActionWithoutCluster(t *testing.T) {
+	c := newAlertActionTestClient(t)
+	ctx := context.Background()
+
+	fingerprint := model.Fingerprint(2222)
+	alert := &currentAlertStruct{
+		Status:      model.AlertFiring,
+		Labels:      model.LabelSet{"namespace": "kafka"},
+		Annotations: model.LabelSet{"command": UpScaleCommand},
+	}
+
+	require.NoError(t, recordProcessing(ctx, c, fingerprint, alert, nil, true, nil, nil))
+
+	actions := &v1alpha1.KafkaAlertActionList{}
+	require.NoError(t, c.List(ctx, actions))
+	assert.Empty(t, actions.Items)
+}
+
+func TestIsAlertActionExpired(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		testName    string
+		alertStatus model.AlertStatus
+		executed    time.Time
+		lastUpdate  time.Time
+		expired     bool
+	}{
+		{
+			testName:    "resolved alert",
+			alertStatus: model.AlertResolved,
+			executed:    now.Add(-time.Hour),
+			lastUpdate:  now,
+			expired:     true,
+		},
+		{
+			testName:    "resolved alert in cooldown",
+			alertStatus: model.AlertResolved,
+			executed:    now.Add(-time.Minute),
+			lastUpdate:  now,
+		},
+		{
+			testName:    "firing alert",
+			alertStatus: model.AlertFiring,
+			executed:    now.Add(-time.Hour),
+			lastUpdate:  now.Add(-time.Hour),
+		},
+		{
+			testName:    "firing alert which is not received any more",
+			alertStatus: model.AlertFiring,
+			executed:    now.Add(-2 * alertActionRetention),
+			lastUpdate:  now.Add(-2 * alertActionRetention),
+			expired:     true,
+		},
+	}
+	for _, testCase := range testCases {
+		action := &v1alpha1.KafkaAlertAction{
+			Status: v1alpha1.KafkaAlertActionStatus{
+				AlertStatus:  string(testCase.alertStatus),
+				LastExecuted: &metav1.Time{Time: testCase.executed},
+				History:      []v1alpha1.KafkaAlertActionHistoryEntry{{Time: metav1.Time{Time: testCase.lastUpdate}}},
+			},
+		}
+		assert.Equal(t, testCase.expired, isAlertActionExpired(action, 30*time.Minute, now), "testName", testCase.testName)
+	}
+}
+
+func TestCollectAlertActions(t *testing.T) {
+	newAction := func(fingerprint model.Fingerprint, alertStatus model.AlertStatus) *v1alpha1.KafkaAlertAction {
+		return &v1alpha1.KafkaAlertAction{
+			ObjectMeta: metav1.ObjectMeta{Name: alertActionName(fingerprint), Namespace: "kafka"},
+			Spec:       v1alpha1.KafkaAlertActionSpec{Fingerprint: fingerprint.String(), Command: UpScaleCommand, ClusterName: "kafka"},
+			Status: v1alpha1.KafkaAlertActionStatus{
+				AlertStatus: string(alertStatus),
+				History:     []v1alpha1.KafkaAlertActionHistoryEntry{{Time: metav1.Time{Time: time.Now().Add(-2 * alertActionRetention)}}},
+			},
+		}
+	}
+	c := newAlertActionTestClient(t,
+		newAction(1111, model.AlertResolved),
+		newAction(2222, model.AlertFiring),
+		newAction(3333, model.AlertFiring),
+	)
+	ctx := context.Background()
+
+	alerts := &currentAlerts{alerts: map[model.Fingerprint]*currentAlertStruct{
+		3333: {Status: model.AlertFiring},
+	}}
+	alerts.CollectAlertActions(ctx, c, logr.Discard())
+
+	actions := &v1alpha1.KafkaAlertActionList{}
+	require.NoError(t, c.List(ctx, actions))
+	if assert.Len(t, actions.Items, 1) {
+		assert.Equal(t, alertActionName(3333), actions.Items[0].Name)
+	}
+}
+
+// alertActionFailingClient fails the requests of the KafkaAlertActions with the configured errors
+type alertActionFailingClient struct {
+	client.Client
+	getErr    error
+	createErr error
+}
+
+func (c *alertActionFailingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*v1alpha1.KafkaAlertAction); ok && c.getErr != nil {
+		return c.getErr
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *alertActionFailingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*v1alpha1.KafkaAlertAction); ok && c.createErr != nil {
+		return c.createErr
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func newHandledAlert(fingerprint model.Fingerprint) *currentAlerts {
+	return &currentAlerts{alerts: map[model.Fingerprint]*currentAlertStruct{
+		fingerprint: {
+			Status: model.AlertFiring,
+			Labels: model.LabelSet{
+				v1beta1.KafkaCRLabelKey: "kafka",
+				"namespace":             "kafka",
+			},
+			Annotations: model.LabelSet{"command": "testing"},
+		},
+	}}
+}
+
+func TestHandleAlertStoredStateNotReadable(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Status:     v1beta1.KafkaClusterStatus{State: v1beta1.KafkaClusterRunning},
+	}
+	c := &alertActionFailingClient{
+		Client: newAlertActionTestClient(t, cluster),
+		getErr: errors.New("unavailable"),
+	}
+	fingerprint := model.Fingerprint(3333)
+	alerts := newHandledAlert(fingerprint)
+
+	_, err := alerts.HandleAlert(context.Background(), fingerprint, c, 0, logr.Discard())
+	require.Error(t, err)
+	// the alert is handled again on the next notification
+	assert.False(t, alerts.alerts[fingerprint].Processed)
+	assert.False(t, alerts.alerts[fingerprint].processing)
+}
+
+func TestHandleAlertExecutionNotRecorded(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Status:     v1beta1.KafkaClusterStatus{State: v1beta1.KafkaClusterRunning},
+	}
+	c := &alertActionFailingClient{
+		Client:    newAlertActionTestClient(t, cluster),
+		createErr: errors.New("unavailable"),
+	}
+	recorder := record.NewFakeRecorder(10)
+	fingerprint := model.Fingerprint(4444)
+	alerts := newHandledAlert(fingerprint)
+	alerts.Recorder = recorder
+
+	alert, err := alerts.HandleAlert(context.Background(), fingerprint, c, 0, logr.Discard())
+	require.Error(t, err)
+	require.NotNil(t, alert)
+	// the command has been executed, it is not executed again by this operator
+	assert.True(t, alerts.alerts[fingerprint].Processed)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "AlertActionNotRecorded")
+}
@@ -0,0 +1,333 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+// Command is a remediation which can be triggered by a Prometheus alert through its "command" annotation
+type Command interface {
+	// Name returns the value of the "command" alert annotation the command is triggered by
+	Name() string
+	// Validate checks that the alert has every label and annotation required by the command
+	Validate(alert Alert) error
+	// Execute performs the command. It returns false without an error when the execution is skipped
+	// so the alert is going to be processed again.
+	Execute(ctx context.Context, req CommandRequest) (bool, error)
+	// Cooldown returns the default minimum time between two executions of the command for the same Kafka cluster,
+	// it can be overridden by the alertManagerConfig of the KafkaCluster
+	Cooldown() time.Duration
+}
+
+// Alert is the Prometheus alert a command is triggered by
+type Alert struct {
+	Labels      model.LabelSet
+	Annotations model.LabelSet
+}
+
+// currentAlert returns the alert in the form the built-in alert validators expect
+func (a Alert) currentAlert() *currentAlertStruct {
+	return &currentAlertStruct{Labels: a.Labels, Annotations: a.Annotations}
+}
+
+// CommandRequest holds everything a command needs for its execution
+type CommandRequest struct {
+	Alert        Alert
+	KafkaCluster *v1beta1.KafkaCluster
+	Client       client.Client
+	Log          logr.Logger
+	// Recorder is used to emit events about the decisions of the command, it may be nil
+	Recorder record.EventRecorder
+	// DryRun collects the mutations the command would make instead of applying them. It is nil when
+	// the command is not executed in dry-run mode.
+	DryRun *DryRunPlan
+}
+
+// CommandRegistry stores the commands alerts can be routed to
+type CommandRegistry struct {
+	lock     sync.Mutex
+	commands map[string]Command
+}
+
+// NewCommandRegistry returns a CommandRegistry with the provided commands registered
+func NewCommandRegistry(commands ...Command) (*CommandRegistry, error) {
+	r := &CommandRegistry{
+		commands: make(map[string]Command),
+	}
+	for _, command := range commands {
+		if err := r.Register(command); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds the command to the registry
+func (r *CommandRegistry) Register(command Command) error {
+	if command == nil || command.Name() == "" {
+		return errors.New("command must have a name")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.commands[command.Name()]; ok {
+		return errors.NewWithDetails("command is already registered", "command", command.Name())
+	}
+	r.commands[command.Name()] = command
+	return nil
+}
+
+// Get returns the command registered with the given name
+func (r *CommandRegistry) Get(name string) (Command, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	command, ok := r.commands[name]
+	return command, ok
+}
+
+// Names returns the sorted list of the registered command names
+func (r *CommandRegistry) Names() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	names := make([]string, 0, len(r.commands))
+	for name := range r.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CommandCooldown returns the minimum time between two executions of the command for the given Kafka cluster
+func CommandCooldown(command Command, cluster *v1beta1.KafkaCluster) time.Duration {
+	if cluster != nil && cluster.Spec.AlertManagerConfig != nil {
+		if minutes, ok := cluster.Spec.AlertManagerConfig.CommandCooldownMinutes[command.Name()]; ok {
+			return time.Duration(minutes) * time.Minute
+		}
+	}
+	return command.Cooldown()
+}
+
+// cooldownRemaining returns the time left until the command can be executed again for the given Kafka cluster.
+// The last execution is taken from the KafkaAlertActions of the cluster, so the cooldown survives operator restarts.
+func cooldownRemaining(ctx context.Context, c client.Reader, command Command, cluster *v1beta1.KafkaCluster, now time.Time) (time.Duration, error) {
+	cooldown := CommandCooldown(command, cluster)
+	if cooldown <= 0 {
+		return 0, nil
+	}
+
+	actions := &v1alpha1.KafkaAlertActionList{}
+	if err := c.List(ctx, actions, client.InNamespace(cluster.GetNamespace()),
+		client.MatchingLabels{v1beta1.KafkaCRLabelKey: cluster.GetName()}); err != nil {
+		return 0, errors.WrapIfWithDetails(err, "could not list KafkaAlertActions", "cluster", cluster.GetName())
+	}
+
+	var lastExecution time.Time
+	for _, action := range actions.Items {
+		if action.Spec.Command != command.Name() || action.Status.LastExecuted == nil {
+			continue
+		}
+		if action.Status.LastExecuted.After(lastExecution) {
+			lastExecution = action.Status.LastExecuted.Time
+		}
+	}
+	if lastExecution.IsZero() {
+		return 0, nil
+	}
+	remaining := lastExecution.Add(cooldown).Sub(now)
+	if remaining < 0 {
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+var defaultCommandRegistry *CommandRegistry
+
+func init() {
+	var err error
+	defaultCommandRegistry, err = NewCommandRegistry(
+		addPvcCmd{},
+		resizePvcCmd{},
+		downScaleCmd{},
+		upScaleCmd{},
+		rebalanceCmd{},
+		restartBrokerCmd{},
+		demoteBrokerCmd{},
+		throttleReplicationCmd{},
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DefaultCommandRegistry returns the registry alerts are routed through
+func DefaultCommandRegistry() *CommandRegistry {
+	return defaultCommandRegistry
+}
+
+// RegisterCommand adds a command to the default registry, so alerts can be routed to it
+func RegisterCommand(command Command) error {
+	return defaultCommandRegistry.Register(command)
+}
+
+type addPvcCmd struct{}
+
+func (addPvcCmd) Name() string { return AddPvcCommand }
+
+func (addPvcCmd) Validate(alert Alert) error {
+	return AlertValidators{newAddPvcValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (addPvcCmd) Execute(_ context.Context, req CommandRequest) (bool, error) {
+	if err := addPvc(req.Log, req.Alert.Labels, req.Alert.Annotations, req.Client, req.DryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (addPvcCmd) Cooldown() time.Duration { return 0 }
+
+type resizePvcCmd struct{}
+
+func (resizePvcCmd) Name() string { return ResizePvcCommand }
+
+func (resizePvcCmd) Validate(alert Alert) error {
+	return AlertValidators{newResizePvcValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (resizePvcCmd) Execute(_ context.Context, req CommandRequest) (bool, error) {
+	if err := resizePvc(req.Log, req.Alert.Labels, req.Alert.Annotations, req.Client, req.DryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (resizePvcCmd) Cooldown() time.Duration { return 0 }
+
+type downScaleCmd struct{}
+
+func (downScaleCmd) Name() string { return DownScaleCommand }
+
+func (downScaleCmd) Validate(alert Alert) error {
+	return AlertValidators{newDownScaleValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (downScaleCmd) Execute(ctx context.Context, req CommandRequest) (bool, error) {
+	if getDisableScaling(req.KafkaCluster).Down {
+		req.Log.Info("downscale is skipped due to downscale limit")
+		return false, nil
+	}
+	if err := downScale(ctx, req.Log, req.Alert.Labels, req.Client, req.Recorder, req.DryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (downScaleCmd) Cooldown() time.Duration { return 0 }
+
+type upScaleCmd struct{}
+
+func (upScaleCmd) Name() string { return UpScaleCommand }
+
+func (upScaleCmd) Validate(alert Alert) error {
+	return AlertValidators{newUpScaleValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (upScaleCmd) Execute(ctx context.Context, req CommandRequest) (bool, error) {
+	if getDisableScaling(req.KafkaCluster).Up {
+		req.Log.Info("upscale is skipped due to upscale limit")
+		return false, nil
+	}
+	if err := upScale(ctx, req.Log, req.Alert.Labels, req.Alert.Annotations, req.Client, req.Recorder, req.DryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (upScaleCmd) Cooldown() time.Duration { return 0 }
+
+type rebalanceCmd struct{}
+
+func (rebalanceCmd) Name() string { return RebalanceCommand }
+
+func (rebalanceCmd) Validate(alert Alert) error {
+	return AlertValidators{newRebalanceValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (rebalanceCmd) Execute(ctx context.Context, req CommandRequest) (bool, error) {
+	return rebalance(ctx, req.Log, req.KafkaCluster, req.Alert.Annotations, req.Client, req.DryRun)
+}
+
+func (rebalanceCmd) Cooldown() time.Duration { return 30 * time.Minute }
+
+type restartBrokerCmd struct{}
+
+func (restartBrokerCmd) Name() string { return RestartBrokerCommand }
+
+func (restartBrokerCmd) Validate(alert Alert) error {
+	return AlertValidators{newRestartBrokerValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (restartBrokerCmd) Execute(ctx context.Context, req CommandRequest) (bool, error) {
+	if err := restartBroker(ctx, req.Log, req.KafkaCluster, req.Alert.Labels, req.Client, req.DryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (restartBrokerCmd) Cooldown() time.Duration { return 10 * time.Minute }
+
+type demoteBrokerCmd struct{}
+
+func (demoteBrokerCmd) Name() string { return DemoteBrokerCommand }
+
+func (demoteBrokerCmd) Validate(alert Alert) error {
+	return AlertValidators{newDemoteBrokerValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (demoteBrokerCmd) Execute(ctx context.Context, req CommandRequest) (bool, error) {
+	return demoteBroker(ctx, req.Log, req.KafkaCluster, req.Alert.Labels, req.DryRun)
+}
+
+func (demoteBrokerCmd) Cooldown() time.Duration { return 10 * time.Minute }
+
+type throttleReplicationCmd struct{}
+
+func (throttleReplicationCmd) Name() string { return ThrottleReplicationCommand }
+
+func (throttleReplicationCmd) Validate(alert Alert) error {
+	return AlertValidators{newThrottleReplicationValidator(alert.currentAlert())}.ValidateAlert()
+}
+
+func (throttleReplicationCmd) Execute(_ context.Context, req CommandRequest) (bool, error) {
+	if err := throttleReplication(req.Log, req.KafkaCluster, req.Alert.Annotations, req.Client, req.DryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (throttleReplicationCmd) Cooldown() time.Duration { return time.Minute }
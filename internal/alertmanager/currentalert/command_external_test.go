@@ -0,0 +1,65 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/koperator/internal/alertmanager/currentalert"
+)
+
+// externalCmd is a command implemented outside of the currentalert package
+type externalCmd struct{}
+
+func (externalCmd) Name() string { return "external_test_command" }
+
+func (externalCmd) Validate(alert currentalert.Alert) error {
+	if _, ok := alert.Labels["topic"]; !ok {
+		return errors.New("topic label doesn't exist")
+	}
+	return nil
+}
+
+func (externalCmd) Execute(_ context.Context, req currentalert.CommandRequest) (bool, error) {
+	return req.Alert.Annotations["command"] == "external_test_command", nil
+}
+
+func (externalCmd) Cooldown() time.Duration { return time.Minute }
+
+func TestRegisterExternalCommand(t *testing.T) {
+	require.NoError(t, currentalert.RegisterCommand(externalCmd{}))
+	assert.Error(t, currentalert.RegisterCommand(externalCmd{}))
+
+	command, ok := currentalert.DefaultCommandRegistry().Get("external_test_command")
+	require.True(t, ok)
+
+	alert := currentalert.Alert{
+		Labels:      model.LabelSet{"topic": "test"},
+		Annotations: model.LabelSet{"command": "external_test_command"},
+	}
+	require.NoError(t, command.Validate(alert))
+	assert.Error(t, command.Validate(currentalert.Alert{}))
+
+	executed, err := command.Execute(context.Background(), currentalert.CommandRequest{Alert: alert})
+	require.NoError(t, err)
+	assert.True(t, executed)
+}
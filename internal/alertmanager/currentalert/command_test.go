@@ -0,0 +1,125 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+type fakeCmd struct {
+	name     string
+	cooldown time.Duration
+}
+
+func (c fakeCmd) Name() string { return c.name }
+
+func (c fakeCmd) Validate(_ Alert) error { return nil }
+
+func (c fakeCmd) Execute(_ context.Context, _ CommandRequest) (bool, error) { return true, nil }
+
+func (c fakeCmd) Cooldown() time.Duration { return c.cooldown }
+
+func TestCommandRegistry(t *testing.T) {
+	registry, err := NewCommandRegistry(fakeCmd{name: "b", cooldown: time.Minute}, fakeCmd{name: "a"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, registry.Names())
+	assert.Error(t, registry.Register(fakeCmd{name: "a"}))
+	assert.Error(t, registry.Register(fakeCmd{}))
+
+	_, ok := registry.Get("c")
+	assert.False(t, ok)
+}
+
+func TestCommandCooldown(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"}}
+	assert.Equal(t, 30*time.Minute, CommandCooldown(rebalanceCmd{}, cluster))
+
+	cluster.Spec.AlertManagerConfig = &v1beta1.AlertManagerConfig{
+		CommandCooldownMinutes: map[string]int{RebalanceCommand: 60, RestartBrokerCommand: 0},
+	}
+	assert.Equal(t, time.Hour, CommandCooldown(rebalanceCmd{}, cluster))
+	assert.Equal(t, time.Duration(0), CommandCooldown(restartBrokerCmd{}, cluster))
+	assert.Equal(t, 10*time.Minute, CommandCooldown(demoteBrokerCmd{}, cluster))
+}
+
+func TestCooldownRemaining(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	newAction := func(name, namespace, command string, lastExecuted time.Time) *v1alpha1.KafkaAlertAction {
+		return &v1alpha1.KafkaAlertAction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{v1beta1.KafkaCRLabelKey: "kafka"},
+			},
+			Spec:   v1alpha1.KafkaAlertActionSpec{Command: command, ClusterName: "kafka"},
+			Status: v1alpha1.KafkaAlertActionStatus{LastExecuted: &metav1.Time{Time: lastExecuted}},
+		}
+	}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newAction("alert-1", "kafka", "b", now.Add(-40*time.Second)),
+		newAction("alert-2", "kafka", "b", now.Add(-time.Hour)),
+		newAction("alert-3", "other", "a", now),
+	).Build()
+	ctx := context.Background()
+
+	cluster := &v1beta1.KafkaCluster{ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"}}
+	otherCluster := &v1beta1.KafkaCluster{ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "other"}}
+	withCooldown := fakeCmd{name: "b", cooldown: time.Minute}
+	withoutCooldown := fakeCmd{name: "a"}
+
+	remaining, err := cooldownRemaining(ctx, c, withCooldown, cluster, now)
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Second, remaining)
+
+	remaining, err = cooldownRemaining(ctx, c, withCooldown, otherCluster, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), remaining)
+
+	remaining, err = cooldownRemaining(ctx, c, withoutCooldown, otherCluster, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), remaining)
+
+	remaining, err = cooldownRemaining(ctx, c, withCooldown, cluster, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), remaining)
+}
+
+func TestDefaultCommandRegistry(t *testing.T) {
+	assert.Equal(t, []string{
+		AddPvcCommand,
+		DemoteBrokerCommand,
+		DownScaleCommand,
+		RebalanceCommand,
+		ResizePvcCommand,
+		RestartBrokerCommand,
+		ThrottleReplicationCommand,
+		UpScaleCommand,
+	}, GetCommandList())
+}
@@ -18,21 +18,31 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
 )
 
 // CurrentAlerts interface
 type CurrentAlerts interface {
 	AddAlert(AlertState) *currentAlertStruct
 	AlertGC(AlertState) error
+	ResolveAlert(context.Context, AlertState, client.Client) error
 	DeleteAlert(model.Fingerprint) error
 	ListAlerts() map[model.Fingerprint]*currentAlertStruct
 	HandleAlert(context.Context, model.Fingerprint, client.Client, int, logr.Logger) (*currentAlertStruct, error)
 	GetRollingUpgradeAlertCount() int
 	IgnoreCCStatusCheck(bool)
+	SetEventRecorder(record.EventRecorder)
+	CollectAlertActions(context.Context, client.Client, logr.Logger)
 }
 
 // AlertState current alert state
@@ -47,6 +57,7 @@ type currentAlerts struct {
 	lock           sync.Mutex
 	alerts         map[model.Fingerprint]*currentAlertStruct
 	IgnoreCCStatus bool
+	Recorder       record.EventRecorder
 }
 
 type currentAlertStruct struct {
@@ -54,6 +65,8 @@ type currentAlertStruct struct {
 	Labels      model.LabelSet
 	Annotations model.LabelSet
 	Processed   bool
+	// processing is true while the command of the alert is being processed
+	processing bool
 }
 
 type examiner struct {
@@ -61,6 +74,14 @@ type examiner struct {
 	Client         client.Client
 	IgnoreCCStatus bool
 	Log            logr.Logger
+	// Recorder is used to emit events about the decisions of the commands, events are not emitted when it is not set
+	Recorder record.EventRecorder
+	// Registry holds the commands alerts are routed to, the default registry is used when it is not set
+	Registry *CommandRegistry
+	// DryRunPlan holds the mutations the command of the alert would have made when it was executed in dry-run mode
+	DryRunPlan *DryRunPlan
+	// KafkaCluster is the cluster the alert belongs to, it is set when the alert is examined
+	KafkaCluster *v1beta1.KafkaCluster
 }
 
 var currAlert *currentAlerts
@@ -93,13 +114,31 @@ func (a *currentAlerts) AddAlert(alert AlertState) *currentAlertStruct {
 }
 
 func (a *currentAlerts) ListAlerts() map[model.Fingerprint]*currentAlertStruct {
-	return a.alerts
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	alerts := make(map[model.Fingerprint]*currentAlertStruct, len(a.alerts))
+	for fingerprint, alert := range a.alerts {
+		alerts[fingerprint] = alert
+	}
+	return alerts
+}
+
+// isFiring returns true when the alert with the given fingerprint is stored and it has not been resolved
+func (a *currentAlerts) isFiring(alertFp model.Fingerprint) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	alert, ok := a.alerts[alertFp]
+	return ok && alert.Status == model.AlertFiring
 }
 
 func (a *currentAlerts) IgnoreCCStatusCheck(c bool) {
 	a.IgnoreCCStatus = c
 }
 
+func (a *currentAlerts) SetEventRecorder(recorder record.EventRecorder) {
+	a.Recorder = recorder
+}
+
 func (a *currentAlerts) DeleteAlert(alertFp model.Fingerprint) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
@@ -117,34 +156,135 @@ func (a *currentAlerts) AlertGC(alert AlertState) error {
 	return nil
 }
 
+// ResolveAlert resets the stored processing state of the resolved alert, so its command is executed again
+// when the alert fires next time. The state is deleted unless the command of the alert is in cooldown.
+func (a *currentAlerts) ResolveAlert(ctx context.Context, alert AlertState, client client.Client) error {
+	stored := &currentAlertStruct{
+		Status:      alert.Status,
+		Labels:      alert.Labels,
+		Annotations: alert.Annotations,
+	}
+	now := time.Now()
+	action, err := getAlertAction(ctx, client, alert.FingerPrint, stored)
+	if err != nil || action == nil {
+		return err
+	}
+	action.Status.AlertStatus = string(alert.Status)
+	if deleted, err := deleteAlertActionIfExpired(ctx, client, action, now); err != nil || deleted {
+		return err
+	}
+	return recordAlertAction(ctx, client, alert.FingerPrint, stored, nil, func(action *v1alpha1.KafkaAlertAction) {
+		action.Status.AlertStatus = string(alert.Status)
+		action.Status.Processed = false
+		action.AddHistoryEntry(now, v1alpha1.AlertActionResolved, "")
+	})
+}
+
+// HandleAlert executes the command of the alert unless it has already been processed. The lock is only held while
+// the stored alert is accessed, so the API calls of the processing of an alert do not block the other alerts.
 func (a *currentAlerts) HandleAlert(ctx context.Context, alertFp model.Fingerprint, client client.Client, rollingUpgradeAlertCount int, log logr.Logger) (*currentAlertStruct, error) {
-	a.lock.Lock()
-	defer a.lock.Unlock()
-	if _, ok := a.alerts[alertFp]; !ok {
-		return &currentAlertStruct{}, errors.New("alert doesn't exist")
-	}
-	if !a.alerts[alertFp].Processed {
-		e := &examiner{
-			Alert:          a.alerts[alertFp],
-			Client:         client,
-			IgnoreCCStatus: a.IgnoreCCStatus,
-			Log:            log,
+	alert, started, err := a.startProcessing(alertFp)
+	if err != nil || !started {
+		return alert, err
+	}
+	processed := false
+	defer func() {
+		a.lock.Lock()
+		defer a.lock.Unlock()
+		alert.processing = false
+		alert.Processed = alert.Processed || processed
+	}()
+
+	// The processing state kept in memory is lost when the operator restarts, the stored one
+	// prevents executing the command of the same firing alert again. When it can not be read the alert
+	// is left unprocessed and it is handled again on the next notification.
+	action, err := getAlertAction(ctx, client, alertFp, alert)
+	if err != nil {
+		return nil, err
+	}
+	if action != nil && action.Status.Processed {
+		processed = true
+		return alert, nil
+	}
+
+	e := &examiner{
+		Alert:          alert,
+		Client:         client,
+		IgnoreCCStatus: a.IgnoreCCStatus,
+		Log:            log,
+		Recorder:       a.Recorder,
+	}
+	// if alertProcessed is false without an error the alert is skipped because
+	// - cluster is not ready
+	// - alert has to be skipped because of broker upscale/downscale limits
+	// - unknown command is presented
+	// on every other case examineAlert will throw an error
+	alertProcessed, err := e.examineAlert(ctx, rollingUpgradeAlertCount)
+	recordErr := recordProcessing(ctx, client, alertFp, alert, e.KafkaCluster, alertProcessed, e.DryRunPlan, err)
+	if err != nil {
+		if recordErr != nil {
+			log.Error(recordErr, "could not store the state of the alert", "fingerprint", alertFp)
 		}
-		// if alertProcessed is false without an error the alert is skipped because
-		// - cluster is not ready
-		// - alert has to be skipped because of broker upscale/downscale limits
-		// - unknown command is presented
-		// on every other case examineAlert will throw an error
-		alertProcessed, err := e.examineAlert(ctx, rollingUpgradeAlertCount)
-		if err != nil {
-			return nil, err
+		return nil, err
+	}
+	processed = alertProcessed
+	if recordErr != nil && alertProcessed {
+		// The command has been executed, but its last execution and so its cooldown is not stored
+		if a.Recorder != nil && e.KafkaCluster != nil {
+			a.Recorder.Eventf(e.KafkaCluster, corev1.EventTypeWarning, "AlertActionNotRecorded",
+				"the execution of the %s command for alert %s could not be recorded, its cooldown is not applied: %v",
+				alert.Annotations["command"], alertFp, recordErr)
 		}
-		a.alerts[alertFp].Processed = alertProcessed
+		return alert, recordErr
+	}
+	if recordErr != nil {
+		log.Error(recordErr, "could not store the state of the alert", "fingerprint", alertFp)
 	}
-	return a.alerts[alertFp], nil
+	return alert, nil
+}
+
+// startProcessing marks the stored alert as being processed. It returns false when the alert has already been
+// processed or its processing is in progress.
+func (a *currentAlerts) startProcessing(alertFp model.Fingerprint) (*currentAlertStruct, bool, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	alert, ok := a.alerts[alertFp]
+	if !ok {
+		return &currentAlertStruct{}, false, errors.New("alert doesn't exist")
+	}
+	if alert.Processed || alert.processing {
+		return alert, false, nil
+	}
+	alert.processing = true
+	return alert, true, nil
+}
+
+// recordProcessing stores the result of the processing of the alert in its KafkaAlertAction
+func recordProcessing(ctx context.Context, client client.Client, alertFp model.Fingerprint, alert *currentAlertStruct,
+	cluster *v1beta1.KafkaCluster, processed bool, dryRunPlan *DryRunPlan, processErr error) error {
+	now := time.Now()
+	return recordAlertAction(ctx, client, alertFp, alert, cluster, func(action *v1alpha1.KafkaAlertAction) {
+		action.Status.AlertStatus = string(alert.Status)
+		switch {
+		case processErr != nil:
+			action.AddHistoryEntry(now, v1alpha1.AlertActionFailed, processErr.Error())
+		case processed && dryRunPlan != nil:
+			// the alert is handled as processed until it is resolved, so the dry-run is not repeated
+			action.Status.Processed = true
+			action.AddHistoryEntry(now, v1alpha1.AlertActionDryRun, dryRunPlan.String())
+		case processed:
+			action.Status.Processed = true
+			action.Status.LastExecuted = &metav1.Time{Time: now}
+			action.AddHistoryEntry(now, v1alpha1.AlertActionSucceeded, "")
+		default:
+			action.AddHistoryEntry(now, v1alpha1.AlertActionSkipped, "")
+		}
+	})
 }
 
 func (a *currentAlerts) GetRollingUpgradeAlertCount() int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
 	alertCount := 0
 	for _, alert := range a.alerts {
 		for key := range alert.Labels {
@@ -0,0 +1,45 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	emperror "emperror.dev/errors"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+type demoteBrokerValidator struct {
+	Alert *currentAlertStruct
+}
+
+func newDemoteBrokerValidator(currentAlert *currentAlertStruct) demoteBrokerValidator {
+	return demoteBrokerValidator{
+		Alert: currentAlert,
+	}
+}
+
+func (a demoteBrokerValidator) validateAlert() error {
+	if !checkLabelExists(a.Alert.Labels, v1beta1.KafkaCRLabelKey) {
+		return emperror.New("kafka_cr label doesn't exist")
+	}
+	if !checkLabelExists(a.Alert.Labels, v1beta1.BrokerIdLabelKey) {
+		return emperror.New("brokerId label doesn't exist")
+	}
+	if a.Alert.Annotations["command"] != DemoteBrokerCommand {
+		return emperror.NewWithDetails("unsupported command", "comand", a.Alert.Annotations["command"])
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/scale"
+)
+
+// brokerCandidate holds the data of a broker the downscale strategies are evaluated on
+type brokerCandidate struct {
+	ID           int32
+	Replicas     int32
+	Leaders      int32
+	Rack         string
+	IsController bool
+}
+
+// downScaleCandidates returns the brokers of the Kafka cluster with their partition counts and racks reported by Cruise Control
+func downScaleCandidates(ctx context.Context, cc scale.CruiseControlScaler, cr *v1beta1.KafkaCluster,
+	strategies []v1beta1.DownScaleStrategy) ([]brokerCandidate, error) {
+	state, err := cc.KafkaClusterState(ctx)
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not get Kafka cluster state from Cruise Control")
+	}
+	if state == nil {
+		return nil, errors.New("Kafka cluster state is not available in Cruise Control")
+	}
+
+	racks := make(map[int32]string)
+	// the rack of the brokers is only available from the cluster load which requires enough valid windows in Cruise Control
+	if containsDownScaleStrategy(strategies, v1beta1.DownScaleRackBalanced) {
+		load, err := cc.KafkaClusterLoad(ctx)
+		if err != nil {
+			return nil, errors.WrapIf(err, "could not get Kafka cluster load from Cruise Control")
+		}
+		if load != nil && load.Result != nil {
+			for _, stats := range load.Result.Brokers {
+				racks[stats.Broker] = stats.Rack
+			}
+		}
+	}
+
+	candidates := make([]brokerCandidate, 0, len(cr.Spec.Brokers))
+	for _, broker := range cr.Spec.Brokers {
+		brokerID := strconv.Itoa(int(broker.Id))
+		candidates = append(candidates, brokerCandidate{
+			ID:           broker.Id,
+			Replicas:     state.KafkaBrokerState.ReplicaCountByBrokerID[brokerID],
+			Leaders:      state.KafkaBrokerState.LeaderCountByBrokerID[brokerID],
+			Rack:         racks[broker.Id],
+			IsController: state.KafkaBrokerState.IsController[brokerID],
+		})
+	}
+	return candidates, nil
+}
+
+// selectBrokerToRemove applies the strategies in order on the candidates and returns the selected broker with
+// the reason of the selection
+func selectBrokerToRemove(candidates []brokerCandidate, strategies []v1beta1.DownScaleStrategy) (brokerCandidate, string) {
+	var reasons []string
+	for _, strategy := range strategies {
+		var reason string
+		candidates, reason = applyDownScaleStrategy(candidates, strategy)
+		reasons = append(reasons, reason)
+	}
+
+	selected := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.ID > selected.ID {
+			selected = candidate
+		}
+	}
+	if len(candidates) > 1 {
+		reasons = append(reasons, fmt.Sprintf("highest broker ID among %d candidates", len(candidates)))
+	}
+	return selected, strings.Join(reasons, ", ")
+}
+
+func applyDownScaleStrategy(candidates []brokerCandidate, strategy v1beta1.DownScaleStrategy) ([]brokerCandidate, string) {
+	switch strategy {
+	case v1beta1.DownScaleLeastReplicas:
+		kept, replicas := keepMinimum(candidates, func(c brokerCandidate) int32 { return c.Replicas })
+		return kept, fmt.Sprintf("%s (%d replicas)", strategy, replicas)
+	case v1beta1.DownScaleLeastLeaders:
+		kept, leaders := keepMinimum(candidates, func(c brokerCandidate) int32 { return c.Leaders })
+		return kept, fmt.Sprintf("%s (%d leaders)", strategy, leaders)
+	case v1beta1.DownScaleNewestFirst:
+		kept, negatedID := keepMinimum(candidates, func(c brokerCandidate) int32 { return -c.ID })
+		return kept, fmt.Sprintf("%s (broker %d)", strategy, -negatedID)
+	case v1beta1.DownScaleAvoidController:
+		var kept []brokerCandidate
+		for _, candidate := range candidates {
+			if !candidate.IsController {
+				kept = append(kept, candidate)
+			}
+		}
+		if len(kept) == 0 {
+			return candidates, fmt.Sprintf("%s (skipped, only the controller is left)", strategy)
+		}
+		return kept, string(strategy)
+	case v1beta1.DownScaleRackBalanced:
+		brokersByRack := make(map[string]int32)
+		for _, candidate := range candidates {
+			brokersByRack[candidate.Rack]++
+		}
+		kept, negatedCount := keepMinimum(candidates, func(c brokerCandidate) int32 { return -brokersByRack[c.Rack] })
+		return kept, fmt.Sprintf("%s (rack %q with %d brokers)", strategy, kept[0].Rack, -negatedCount)
+	default:
+		return candidates, fmt.Sprintf("%s (unknown strategy, ignored)", strategy)
+	}
+}
+
+// keepMinimum returns the candidates with the lowest value and the value itself
+func keepMinimum(candidates []brokerCandidate, value func(brokerCandidate) int32) ([]brokerCandidate, int32) {
+	var kept []brokerCandidate
+	var minimum int32
+	for _, candidate := range candidates {
+		v := value(candidate)
+		switch {
+		case len(kept) == 0 || v < minimum:
+			kept = []brokerCandidate{candidate}
+			minimum = v
+		case v == minimum:
+			kept = append(kept, candidate)
+		}
+	}
+	return kept, minimum
+}
+
+func containsDownScaleStrategy(strategies []v1beta1.DownScaleStrategy, strategy v1beta1.DownScaleStrategy) bool {
+	for _, s := range strategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/banzaicloud/go-cruise-control/pkg/api"
+	"github.com/banzaicloud/go-cruise-control/pkg/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/controllers/tests/mocks"
+)
+
+func TestSelectBrokerToRemove(t *testing.T) {
+	candidates := []brokerCandidate{
+		{ID: 0, Replicas: 10, Leaders: 5, Rack: "a", IsController: false},
+		{ID: 1, Replicas: 3, Leaders: 4, Rack: "a", IsController: true},
+		{ID: 2, Replicas: 4, Leaders: 1, Rack: "b", IsController: false},
+		{ID: 3, Replicas: 8, Leaders: 2, Rack: "a", IsController: false},
+		{ID: 4, Replicas: 12, Leaders: 6, Rack: "b", IsController: false},
+	}
+
+	tests := []struct {
+		testName   string
+		strategies []v1beta1.DownScaleStrategy
+		expectedID int32
+	}{
+		{
+			testName:   "least replicas",
+			strategies: []v1beta1.DownScaleStrategy{v1beta1.DownScaleLeastReplicas},
+			expectedID: 1,
+		},
+		{
+			testName:   "least leaders",
+			strategies: []v1beta1.DownScaleStrategy{v1beta1.DownScaleLeastLeaders},
+			expectedID: 2,
+		},
+		{
+			testName:   "newest first",
+			strategies: []v1beta1.DownScaleStrategy{v1beta1.DownScaleNewestFirst},
+			expectedID: 4,
+		},
+		{
+			testName:   "rack balanced removes from the most populated rack",
+			strategies: []v1beta1.DownScaleStrategy{v1beta1.DownScaleRackBalanced, v1beta1.DownScaleLeastReplicas},
+			expectedID: 1,
+		},
+		{
+			testName: "avoid controller",
+			strategies: []v1beta1.DownScaleStrategy{v1beta1.DownScaleAvoidController, v1beta1.DownScaleRackBalanced,
+				v1beta1.DownScaleLeastReplicas},
+			expectedID: 2,
+		},
+		{
+			testName:   "unknown strategy is ignored and the highest ID is selected",
+			strategies: []v1beta1.DownScaleStrategy{"Unknown"},
+			expectedID: 4,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.testName, func(t *testing.T) {
+			selected, reason := selectBrokerToRemove(candidates, test.strategies)
+			require.Equal(t, test.expectedID, selected.ID)
+			require.NotEmpty(t, reason)
+		})
+	}
+}
+
+func TestSelectBrokerToRemoveKeepsController(t *testing.T) {
+	candidates := []brokerCandidate{{ID: 1, IsController: true}}
+
+	selected, reason := selectBrokerToRemove(candidates, []v1beta1.DownScaleStrategy{v1beta1.DownScaleAvoidController})
+	require.Equal(t, int32(1), selected.ID)
+	require.Contains(t, reason, "skipped")
+}
+
+func TestDownScaleCandidates(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	cc := mocks.NewMockCruiseControlScaler(mockCtrl)
+
+	cr := &v1beta1.KafkaCluster{
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{Id: 1}, {Id: 2}},
+		},
+	}
+	cc.EXPECT().KafkaClusterState(ctx).Return(&types.KafkaClusterState{
+		KafkaBrokerState: types.KafkaBrokerState{
+			ReplicaCountByBrokerID: map[string]int32{"1": 3, "2": 5, "3": 1},
+			LeaderCountByBrokerID:  map[string]int32{"1": 2, "2": 1, "3": 0},
+			IsController:           map[string]bool{"1": true},
+		},
+	}, nil)
+	cc.EXPECT().KafkaClusterLoad(ctx).Return(&api.KafkaClusterLoadResponse{
+		Result: &types.BrokerStats{
+			Brokers: []types.BrokerLoadStats{{Broker: 1, Rack: "a"}, {Broker: 2, Rack: "b"}},
+		},
+	}, nil)
+
+	candidates, err := downScaleCandidates(ctx, cc, cr,
+		[]v1beta1.DownScaleStrategy{v1beta1.DownScaleRackBalanced})
+	require.NoError(t, err)
+	require.Equal(t, []brokerCandidate{
+		{ID: 1, Replicas: 3, Leaders: 2, Rack: "a", IsController: true},
+		{ID: 2, Replicas: 5, Leaders: 1, Rack: "b"},
+	}, candidates)
+}
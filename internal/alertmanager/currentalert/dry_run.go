@@ -0,0 +1,57 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+// DryRunAnnotation enables dry-run mode for a single alert when its value is "true"
+const DryRunAnnotation = "dryRun"
+
+// DryRunPlan collects the mutations the commands would make when they are executed in dry-run mode
+type DryRunPlan struct {
+	Mutations []string
+}
+
+// isDryRun returns whether the command of the alert has to be executed in dry-run mode
+func isDryRun(cr *v1beta1.KafkaCluster, alert *currentAlertStruct) bool {
+	if cr != nil && cr.Spec.AlertManagerConfig != nil && cr.Spec.AlertManagerConfig.DryRun {
+		return true
+	}
+	return alert != nil && strings.EqualFold(string(alert.Annotations[DryRunAnnotation]), "true")
+}
+
+// add records the mutation instead of applying it
+func (p *DryRunPlan) add(log logr.Logger, description string, mutation interface{}) {
+	entry := description
+	if raw, err := json.Marshal(mutation); err == nil {
+		entry += ": " + string(raw)
+	}
+	p.Mutations = append(p.Mutations, entry)
+	log.Info("dry-run mode, the Kafka cluster is not modified", "mutation", entry)
+}
+
+func (p *DryRunPlan) String() string {
+	if p == nil {
+		return ""
+	}
+	return strings.Join(p.Mutations, "; ")
+}
@@ -0,0 +1,107 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func TestIsDryRun(t *testing.T) {
+	testCases := []struct {
+		testName string
+		config   *v1beta1.AlertManagerConfig
+		alert    *currentAlertStruct
+		expected bool
+	}{
+		{
+			testName: "dry-run is disabled by default",
+			alert:    &currentAlertStruct{},
+			expected: false,
+		},
+		{
+			testName: "dry-run is enabled for the cluster",
+			config:   &v1beta1.AlertManagerConfig{DryRun: true},
+			alert:    &currentAlertStruct{},
+			expected: true,
+		},
+		{
+			testName: "dry-run is enabled for the alert",
+			config:   &v1beta1.AlertManagerConfig{},
+			alert:    &currentAlertStruct{Annotations: model.LabelSet{DryRunAnnotation: "true"}},
+			expected: true,
+		},
+		{
+			testName: "dry-run annotation with invalid value",
+			alert:    &currentAlertStruct{Annotations: model.LabelSet{DryRunAnnotation: "yes"}},
+			expected: false,
+		},
+	}
+	for _, testCase := range testCases {
+		cr := &v1beta1.KafkaCluster{Spec: v1beta1.KafkaClusterSpec{AlertManagerConfig: testCase.config}}
+		assert.Equal(t, testCase.expected, isDryRun(cr, testCase.alert), "testName", testCase.testName)
+	}
+}
+
+func TestScalingDryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	cluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1}},
+			BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+				"default": {},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+	ctx := context.Background()
+	labels := model.LabelSet{
+		v1beta1.KafkaCRLabelKey:  "kafka",
+		v1beta1.BrokerIdLabelKey: "1",
+		"namespace":              "kafka",
+	}
+
+	plan := &DryRunPlan{}
+	require.NoError(t, upScale(ctx, logr.Discard(), labels, model.LabelSet{"brokerConfigGroup": "default"}, c, nil, plan))
+	require.NoError(t, downScale(ctx, logr.Discard(), labels, c, nil, plan))
+	cr := &v1beta1.KafkaCluster{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cluster), cr))
+	require.NoError(t, throttleReplication(logr.Discard(), cr, model.LabelSet{"throttleRate": "10Mi"}, c, plan))
+
+	assert.Equal(t, []string{
+		`add broker: {"id":2,"brokerConfigGroup":"default"}`,
+		`remove broker: {"brokerId":"1"}`,
+		`set Cruise Control replication throttle: {"replicationThrottle":10485760}`,
+	}, plan.Mutations)
+
+	// the cluster is not modified
+	cr = &v1beta1.KafkaCluster{}
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(cluster), cr))
+	assert.Equal(t, cluster.Spec.Brokers, cr.Spec.Brokers)
+	assert.Nil(t, cr.Spec.CruiseControlConfig.CruiseControlTaskSpec.ReplicationThrottle)
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
@@ -26,9 +27,14 @@ import (
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	banzaiv1alpha1 "github.com/banzaicloud/koperator/api/v1alpha1"
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/k8sutil"
 	"github.com/banzaicloud/koperator/pkg/resources/kafka"
@@ -50,17 +56,21 @@ const (
 	UpScaleCommand = "upScale"
 	// ResizePvcCommand command name for resizePvc
 	ResizePvcCommand = "resizePvc"
+	// RebalanceCommand command name for rebalance
+	RebalanceCommand = "rebalance"
+	// RestartBrokerCommand command name for restartBroker
+	RestartBrokerCommand = "restartBroker"
+	// DemoteBrokerCommand command name for demoteBroker
+	DemoteBrokerCommand = "demoteBroker"
+	// ThrottleReplicationCommand command name for throttleReplication
+	ThrottleReplicationCommand = "throttleReplication"
 )
 
 // GetCommandList returns list of supported commands
 func GetCommandList() []string {
-	return []string{
-		AddPvcCommand,
-		DownScaleCommand,
-		UpScaleCommand,
-		ResizePvcCommand,
-	}
+	return DefaultCommandRegistry().Names()
 }
+
 func (e *examiner) getKafkaCr() (*v1beta1.KafkaCluster, error) {
 	var cr *v1beta1.KafkaCluster
 	if kafkaCr, ok := e.Alert.Labels[v1beta1.KafkaCRLabelKey]; ok {
@@ -97,6 +107,7 @@ func (e *examiner) examineAlert(ctx context.Context, rollingUpgradeAlertCount in
 	if cr == nil {
 		return false, errors.New("kafkaCR is nil")
 	}
+	e.KafkaCluster = cr
 
 	if err := k8sutil.UpdateCrWithRollingUpgrade(rollingUpgradeAlertCount, cr, e.Client, e.Log); err != nil {
 		return false, err
@@ -106,8 +117,13 @@ func (e *examiner) examineAlert(ctx context.Context, rollingUpgradeAlertCount in
 		return false, nil
 	}
 
+	return e.processAlert(ctx, cr)
+}
+
+// getDisableScaling returns whether up and downscaling is disabled because of the limits set in the KafkaCluster
+func getDisableScaling(cr *v1beta1.KafkaCluster) disableScaling {
 	ds := disableScaling{}
-	if cr.Spec.AlertManagerConfig != nil {
+	if cr != nil && cr.Spec.AlertManagerConfig != nil {
 		if len(cr.Spec.Brokers) <= cr.Spec.AlertManagerConfig.DownScaleLimit {
 			ds.Down = true
 		}
@@ -115,72 +131,62 @@ func (e *examiner) examineAlert(ctx context.Context, rollingUpgradeAlertCount in
 			ds.Up = true
 		}
 	}
-
-	return e.processAlert(ctx, ds)
+	return ds
 }
 
-func (e *examiner) processAlert(ctx context.Context, ds disableScaling) (bool, error) {
-	switch e.Alert.Annotations["command"] {
-	case AddPvcCommand:
-		validators := AlertValidators{newAddPvcValidator(e.Alert)}
-		if err := validators.ValidateAlert(); err != nil {
-			return false, err
-		}
-		err := addPvc(e.Log, e.Alert.Labels, e.Alert.Annotations, e.Client)
-		if err != nil {
-			return false, err
-		}
-
+func (e *examiner) processAlert(ctx context.Context, cr *v1beta1.KafkaCluster) (bool, error) {
+	commandName := string(e.Alert.Annotations["command"])
+	// Used only for testing purposes
+	if commandName == "testing" {
 		return true, nil
-	case ResizePvcCommand:
-		validators := AlertValidators{newResizePvcValidator(e.Alert)}
-		if err := validators.ValidateAlert(); err != nil {
-			return false, err
-		}
-		err := resizePvc(e.Log, e.Alert.Labels, e.Alert.Annotations, e.Client)
-		if err != nil {
-			return false, err
-		}
+	}
 
-		return true, nil
-	case DownScaleCommand:
-		validators := AlertValidators{newDownScaleValidator(e.Alert)}
-		if err := validators.ValidateAlert(); err != nil {
-			return false, err
-		}
-		if ds.Down {
-			e.Log.Info("downscale is skipped due to downscale limit")
-			return false, nil
-		}
-		err := downScale(ctx, e.Log, e.Alert.Labels, e.Client)
-		if err != nil {
-			return false, err
-		}
+	registry := e.Registry
+	if registry == nil {
+		registry = DefaultCommandRegistry()
+	}
+	command, ok := registry.Get(commandName)
+	if !ok {
+		return false, nil
+	}
 
-		return true, nil
-	case UpScaleCommand:
-		validators := AlertValidators{newUpScaleValidator(e.Alert)}
-		if err := validators.ValidateAlert(); err != nil {
-			return false, err
-		}
-		if ds.Up {
-			e.Log.Info("upscale is skipped due to upscale limit")
-			return false, nil
-		}
-		err := upScale(e.Log, e.Alert.Labels, e.Alert.Annotations, e.Client)
-		if err != nil {
-			return false, err
-		}
+	alert := Alert{Labels: e.Alert.Labels, Annotations: e.Alert.Annotations}
+	if err := command.Validate(alert); err != nil {
+		return false, err
+	}
 
-		return true, nil
-	// Used only for testing purposes
-	case "testing":
-		return true, nil
+	remaining, err := cooldownRemaining(ctx, e.Client, command, cr, time.Now())
+	if err != nil {
+		return false, err
 	}
-	return false, nil
+	if remaining > 0 {
+		e.Log.Info("command is skipped due to cooldown", "command", commandName, "remaining", remaining.String())
+		return false, nil
+	}
+
+	req := CommandRequest{
+		Alert:        alert,
+		KafkaCluster: cr,
+		Client:       e.Client,
+		Log:          e.Log,
+		Recorder:     e.Recorder,
+	}
+	if isDryRun(cr, e.Alert) {
+		req.DryRun = &DryRunPlan{}
+	}
+	processed, err := command.Execute(ctx, req)
+	if err != nil {
+		return false, err
+	}
+	// the cooldown is started by the execution time stored in the KafkaAlertAction of the alert,
+	// it is not recorded for dry-runs as the cluster has not been modified
+	if processed && req.DryRun != nil {
+		e.DryRunPlan = req.DryRun
+	}
+	return processed, nil
 }
 
-func addPvc(log logr.Logger, alertLabels model.LabelSet, alertAnnotations model.LabelSet, client client.Client) error {
+func addPvc(log logr.Logger, alertLabels model.LabelSet, alertAnnotations model.LabelSet, client client.Client, dryRun *DryRunPlan) error {
 	var storageClassName *string
 
 	if alertAnnotations["storageClass"] != "" {
@@ -229,6 +235,11 @@ func addPvc(log logr.Logger, alertLabels model.LabelSet, alertAnnotations model.
 			},
 		}}
 
+	if dryRun != nil {
+		dryRun.add(log, fmt.Sprintf("add storage to broker %s", pvc.Labels[v1beta1.BrokerIdLabelKey]), storageConfig)
+		return nil
+	}
+
 	err = k8sutil.AddPvToSpecificBroker(pvc.Labels[v1beta1.BrokerIdLabelKey], pvc.Labels[v1beta1.KafkaCRLabelKey], string(alertLabels["namespace"]), &storageConfig, client)
 	if err != nil {
 		return err
@@ -239,7 +250,7 @@ func addPvc(log logr.Logger, alertLabels model.LabelSet, alertAnnotations model.
 	return nil
 }
 
-func resizePvc(log logr.Logger, labels model.LabelSet, annotiations model.LabelSet, client client.Client) error {
+func resizePvc(log logr.Logger, labels model.LabelSet, annotiations model.LabelSet, client client.Client, dryRun *DryRunPlan) error {
 	pvc, err := getPvc(string(labels["persistentvolumeclaim"]), string(labels["namespace"]), client)
 	if err != nil {
 		return err
@@ -270,6 +281,9 @@ func resizePvc(log logr.Logger, labels model.LabelSet, annotiations model.LabelS
 					size.Add(incrementBy)
 
 					modifiableConfig.PvcSpec.Resources.Requests["storage"] = size
+					if dryRun != nil {
+						dryRun.add(log, fmt.Sprintf("resize storage of broker %d", broker.Id), modifiableConfig)
+					}
 
 					// When the storage is in a brokerConfigGroup we don't resize the storage there because in that case
 					// all of the brokers that are using this brokerConfigGroup would have their storages resized.
@@ -289,6 +303,10 @@ func resizePvc(log logr.Logger, labels model.LabelSet, annotiations model.LabelS
 		}
 	}
 
+	if dryRun != nil {
+		return nil
+	}
+
 	err = k8sutil.UpdateCr(cr, client)
 	if err != nil {
 		return err
@@ -299,7 +317,8 @@ func resizePvc(log logr.Logger, labels model.LabelSet, annotiations model.LabelS
 	return nil
 }
 
-func downScale(ctx context.Context, log logr.Logger, labels model.LabelSet, client client.Client) error {
+func downScale(ctx context.Context, log logr.Logger, labels model.LabelSet, client client.Client, recorder record.EventRecorder,
+	dryRun *DryRunPlan) error {
 	cr, err := k8sutil.GetCr(string(labels[v1beta1.KafkaCRLabelKey]), string(labels["namespace"]), client)
 	if err != nil {
 		return err
@@ -327,10 +346,27 @@ func downScale(ctx context.Context, log logr.Logger, labels model.LabelSet, clie
 			return errors.WrapIfWithDetails(err, "failed to initialize Cruise Control Scaler",
 				"cruise control url", cruiseControlURL)
 		}
-		brokerID, err = cc.BrokerWithLeastPartitionReplicas(ctx)
+		strategies := cr.Spec.AlertManagerConfig.GetDownScaleStrategies()
+		candidates, err := downScaleCandidates(ctx, cc, cr, strategies)
 		if err != nil {
 			return err
 		}
+		if len(candidates) == 0 {
+			log.Info("downscale is skipped as there are no brokers to select from")
+			return nil
+		}
+		selected, reason := selectBrokerToRemove(candidates, strategies)
+		brokerID = strconv.Itoa(int(selected.ID))
+		log.Info("broker is selected for removal", "brokerId", brokerID, "reason", reason)
+		if recorder != nil {
+			recorder.Eventf(cr, corev1.EventTypeNormal, "DownScaleBrokerSelected",
+				"broker %s is selected for removal: %s", brokerID, reason)
+		}
+	}
+
+	if dryRun != nil {
+		dryRun.add(log, "remove broker", map[string]string{"brokerId": brokerID})
+		return nil
 	}
 
 	err = k8sutil.RemoveBrokerFromCr(brokerID, string(labels[v1beta1.KafkaCRLabelKey]), string(labels["namespace"]), client)
@@ -340,7 +376,8 @@ func downScale(ctx context.Context, log logr.Logger, labels model.LabelSet, clie
 	return nil
 }
 
-func upScale(log logr.Logger, labels model.LabelSet, annotations model.LabelSet, client client.Client) error {
+func upScale(ctx context.Context, log logr.Logger, labels model.LabelSet, annotations model.LabelSet, client client.Client,
+	recorder record.EventRecorder, dryRun *DryRunPlan) error {
 	cr, err := k8sutil.GetCr(string(labels[v1beta1.KafkaCRLabelKey]), string(labels["namespace"]), client)
 	if err != nil {
 		return err
@@ -412,6 +449,22 @@ func upScale(log logr.Logger, labels model.LabelSet, annotations model.LabelSet,
 		}
 	}
 
+	if pinnedZone, ok := cr.Spec.GetBrokerZone(broker.Id); ok {
+		// the zone of the broker is dictated by its ID, the broker pod is pinned to it when it is created
+		log.Info("broker is placed in the zone dictated by zone pinning", "brokerId", broker.Id, "zone", pinnedZone)
+		if recorder != nil {
+			recorder.Eventf(cr, corev1.EventTypeNormal, "UpScaleZoneSelected",
+				"broker %d is placed in zone %q by zone pinning", broker.Id, pinnedZone)
+		}
+	} else if err := placeBrokerInLeastPopulatedZone(ctx, log, client, recorder, cr, &broker); err != nil {
+		return err
+	}
+
+	if dryRun != nil {
+		dryRun.add(log, "add broker", broker)
+		return nil
+	}
+
 	err = k8sutil.AddNewBrokerToCr(broker, string(labels[v1beta1.KafkaCRLabelKey]), string(labels["namespace"]), client)
 	if err != nil {
 		return err
@@ -419,6 +472,199 @@ func upScale(log logr.Logger, labels model.LabelSet, annotations model.LabelSet,
 	return nil
 }
 
+// placeBrokerInLeastPopulatedZone pins the broker to the zone with the least brokers when the Kafka cluster is rack aware
+func placeBrokerInLeastPopulatedZone(ctx context.Context, log logr.Logger, client client.Client, recorder record.EventRecorder,
+	cr *v1beta1.KafkaCluster, broker *v1beta1.Broker) error {
+	z, err := selectUpScaleZone(ctx, log, client, cr)
+	if err != nil {
+		return err
+	}
+	if z != nil {
+		if err := placeBrokerInZone(cr, broker, z); err != nil {
+			return err
+		}
+		log.Info("broker is placed in the zone with the least brokers", "brokerId", broker.Id, "zone", z.Name,
+			"brokers", z.Brokers, "brokerConfigGroup", broker.BrokerConfigGroup)
+		if recorder != nil {
+			recorder.Eventf(cr, corev1.EventTypeNormal, "UpScaleZoneSelected",
+				"broker %d is placed in zone %q which has %d brokers", broker.Id, z.Name, z.Brokers)
+		}
+	}
+	return nil
+}
+
+// rebalance creates a CruiseControlOperation which rebalances the partitions of the Kafka cluster. The operation
+// is not created while there is a Cruise Control task for the brokers or another operation which is not finished yet.
+func rebalance(ctx context.Context, log logr.Logger, cr *v1beta1.KafkaCluster, annotations model.LabelSet, c client.Client, dryRun *DryRunPlan) (bool, error) {
+	if ids := kafka.GetBrokersWithPendingOrRunningCCTask(cr); len(ids) > 0 {
+		var keyVals []interface{}
+		for _, id := range ids {
+			brokerId := strconv.Itoa(int(id))
+			keyVals = append(keyVals, brokerId, cr.Status.BrokersState[brokerId].GracefulActionState.CruiseControlState)
+		}
+		log.Info("rebalance is skipped as there are brokers which are pending task to be initiated in CC "+
+			"or already have a running CC task", keyVals...)
+		return false, nil
+	}
+
+	operations := &banzaiv1alpha1.CruiseControlOperationList{}
+	if err := c.List(ctx, operations, client.InNamespace(cr.Namespace), client.MatchingLabels(apiutil.LabelsForKafka(cr.Name))); err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to list CruiseControlOperations", "namespace", cr.Namespace)
+	}
+	for i := range operations.Items {
+		if !operations.Items[i].IsDone() {
+			log.Info("rebalance is skipped as there is a CruiseControlOperation which is not finished yet",
+				"name", operations.Items[i].Name)
+			return false, nil
+		}
+	}
+
+	currentTask := &banzaiv1alpha1.CruiseControlTask{
+		Operation: banzaiv1alpha1.OperationRebalance,
+		Parameters: map[string]string{
+			"exclude_recently_demoted_brokers": "true",
+			"exclude_recently_removed_brokers": "true",
+		},
+	}
+	if destinationBrokerIDs := string(annotations["destinationBrokerIds"]); destinationBrokerIDs != "" {
+		currentTask.Parameters["destination_broker_ids"] = destinationBrokerIDs
+	}
+	for param, value := range scale.ExecutionParameters(cr.Spec.CruiseControlConfig.CruiseControlTaskSpec) {
+		currentTask.Parameters[param] = value
+	}
+
+	if dryRun != nil {
+		dryRun.add(log, "create CruiseControlOperation", currentTask)
+		return true, nil
+	}
+
+	operation := &banzaiv1alpha1.CruiseControlOperation{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-%s-", cr.Name, RebalanceCommand),
+			Namespace:    cr.Namespace,
+			Labels:       apiutil.LabelsForKafka(cr.Name),
+		},
+		Spec: banzaiv1alpha1.CruiseControlOperationSpec{
+			ErrorPolicy:             banzaiv1alpha1.ErrorPolicyRetry,
+			TTLSecondsAfterFinished: cr.Spec.CruiseControlConfig.CruiseControlOperationSpec.GetTTLSecondsAfterFinished(),
+		},
+	}
+	if err := controllerutil.SetControllerReference(cr, operation, c.Scheme()); err != nil {
+		return false, err
+	}
+	if err := c.Create(ctx, operation); err != nil {
+		return false, errors.WrapIf(err, "failed to create CruiseControlOperation for rebalance")
+	}
+
+	operation.Status.CurrentTask = currentTask
+	if err := c.Status().Update(ctx, operation); err != nil {
+		return false, errors.WrapIf(err, "failed to update the status of CruiseControlOperation for rebalance")
+	}
+
+	log.Info("rebalance is initiated", "cruiseControlOperation", operation.Name)
+
+	return true, nil
+}
+
+// restartBroker deletes the pod of the broker so it is recreated by the KafkaCluster controller
+func restartBroker(ctx context.Context, log logr.Logger, cr *v1beta1.KafkaCluster, labels model.LabelSet, c client.Client, dryRun *DryRunPlan) error {
+	brokerID := string(labels[v1beta1.BrokerIdLabelKey])
+	pods := &corev1.PodList{}
+	matchingLabels := client.MatchingLabels(
+		apiutil.MergeLabels(
+			apiutil.LabelsForKafka(cr.Name),
+			map[string]string{v1beta1.BrokerIdLabelKey: brokerID},
+		),
+	)
+	if err := c.List(ctx, pods, client.InNamespace(cr.Namespace), matchingLabels); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to list broker pods", "brokerId", brokerID)
+	}
+	if len(pods.Items) == 0 {
+		return errors.NewWithDetails("broker pod not found", "brokerId", brokerID)
+	}
+
+	if dryRun != nil {
+		podNames := make([]string, 0, len(pods.Items))
+		for i := range pods.Items {
+			podNames = append(podNames, pods.Items[i].Name)
+		}
+		dryRun.add(log, fmt.Sprintf("delete pods of broker %s", brokerID), podNames)
+		return nil
+	}
+
+	for i := range pods.Items {
+		if err := c.Delete(ctx, &pods.Items[i]); client.IgnoreNotFound(err) != nil {
+			return errors.WrapIfWithDetails(err, "failed to delete broker pod", "brokerId", brokerID, "pod", pods.Items[i].Name)
+		}
+	}
+
+	log.Info("broker is restarted", "brokerId", brokerID)
+
+	return nil
+}
+
+// demoteBroker moves the partition leaderships off the broker using Cruise Control
+func demoteBroker(ctx context.Context, log logr.Logger, cr *v1beta1.KafkaCluster, labels model.LabelSet, dryRun *DryRunPlan) (bool, error) {
+	if ids := kafka.GetBrokersWithPendingOrRunningCCTask(cr); len(ids) > 0 {
+		var keyVals []interface{}
+		for _, id := range ids {
+			brokerId := strconv.Itoa(int(id))
+			keyVals = append(keyVals, brokerId, cr.Status.BrokersState[brokerId].GracefulActionState.CruiseControlState)
+		}
+		log.Info("demoteBroker is skipped as there are brokers which are pending task to be initiated in CC "+
+			"or already have a running CC task", keyVals...)
+		return false, nil
+	}
+
+	brokerID := string(labels[v1beta1.BrokerIdLabelKey])
+	if dryRun != nil {
+		dryRun.add(log, "demote broker", map[string]string{"brokerId": brokerID})
+		return true, nil
+	}
+
+	cruiseControlURL := scale.CruiseControlURLFromKafkaCluster(cr)
+	cc, err := scale.NewCruiseControlScaler(ctx, cruiseControlURL)
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to initialize Cruise Control Scaler",
+			"cruise control url", cruiseControlURL)
+	}
+	if _, err = cc.DemoteBrokers(ctx, brokerID); err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to demote broker", "brokerId", brokerID)
+	}
+
+	log.Info("broker is demoted", "brokerId", brokerID)
+
+	return true, nil
+}
+
+// throttleReplication sets the replication throttle used by the Cruise Control operations of the Kafka cluster
+func throttleReplication(log logr.Logger, cr *v1beta1.KafkaCluster, annotations model.LabelSet, c client.Client, dryRun *DryRunPlan) error {
+	throttleRate, err := resource.ParseQuantity(string(annotations["throttleRate"]))
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to parse throttleRate", "throttleRate", annotations["throttleRate"])
+	}
+
+	rate := throttleRate.Value()
+	current := cr.Spec.CruiseControlConfig.CruiseControlTaskSpec.ReplicationThrottle
+	if current != nil && *current == rate {
+		return nil
+	}
+
+	if dryRun != nil {
+		dryRun.add(log, "set Cruise Control replication throttle", map[string]int64{"replicationThrottle": rate})
+		return nil
+	}
+
+	cr.Spec.CruiseControlConfig.CruiseControlTaskSpec.ReplicationThrottle = util.Int64Pointer(rate)
+	if err := k8sutil.UpdateCr(cr, c); err != nil {
+		return err
+	}
+
+	log.Info("replication throttle is updated", "throttleRate", rate)
+
+	return nil
+}
+
 // getPvc returns the given PVC object
 func getPvc(name, namespace string, client client.Client) (*corev1.PersistentVolumeClaim, error) {
 	pvc := &corev1.PersistentVolumeClaim{}
@@ -218,7 +218,7 @@ func Test_resizePvc(t *testing.T) {
 			}
 
 			for _, alert := range tt.alertList {
-				err := resizePvc(logr.Discard(), alert.Labels, alert.Annotations, testClient)
+				err := resizePvc(logr.Discard(), alert.Labels, alert.Annotations, testClient, nil)
 				if err != nil {
 					t.Errorf("process.resizePvc() error = %v", err)
 				}
@@ -395,7 +395,7 @@ func Test_addPvc(t *testing.T) {
 			}
 
 			for _, alert := range tt.alertList {
-				err := addPvc(logr.Discard(), alert.Labels, alert.Annotations, testClient)
+				err := addPvc(logr.Discard(), alert.Labels, alert.Annotations, testClient, nil)
 				if err != nil {
 					t.Errorf("process.addPvc() error = %v", err)
 				}
@@ -444,7 +444,8 @@ func Test_addPvc(t *testing.T) {
 }
 
 func Test_upScale(t *testing.T) {
-	testClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	// the nodes are only considered for the placement of the brokers of rack aware clusters
+	testClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(zoneNode("node-0", "a"), zoneNode("node-1", "b")).Build()
 
 	testCases := []struct {
 		testName        string
@@ -558,6 +559,40 @@ func Test_upScale(t *testing.T) {
 					},
 				}}},
 		},
+		{
+			testName: "upScale with zone pinning",
+			kafkaCluster: v1beta1.KafkaCluster{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: v1beta1.KafkaClusterSpec{
+					RackAwareness: &v1beta1.RackAwareness{
+						Labels:      []string{zoneLabel},
+						ZonePinning: &v1beta1.ZonePinning{Zones: []string{"a", "b"}},
+					},
+					BrokerConfigGroups: map[string]v1beta1.BrokerConfig{"default": {}},
+					Brokers:            []v1beta1.Broker{{Id: 0, BrokerConfigGroup: "default", ReadOnlyConfig: "broker.rack=a"}},
+				},
+			},
+			alert: model.Alert{
+				Labels: model.LabelSet{
+					v1beta1.KafkaCRLabelKey: "test-cluster",
+					"namespace":             "test-namespace",
+					"severity":              "critical",
+					"alertGroup":            "test",
+				},
+				Annotations: map[model.LabelName]model.LabelValue{
+					"command":           "upScale",
+					"brokerConfigGroup": "default",
+				},
+			},
+			// broker 1 is pinned to zone "b" by its ID, node affinity is not added to it
+			expectedBrokers: []v1beta1.Broker{
+				{Id: 0, BrokerConfigGroup: "default", ReadOnlyConfig: "broker.rack=a"},
+				{Id: 1, BrokerConfigGroup: "default"},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -574,7 +609,7 @@ func Test_upScale(t *testing.T) {
 				}
 			}()
 
-			if err := upScale(logr.Discard(), test.alert.Labels, test.alert.Annotations, testClient); err != nil {
+			if err := upScale(context.Background(), logr.Discard(), test.alert.Labels, test.alert.Annotations, testClient, nil, nil); err != nil {
 				t.Error(err)
 				return
 			}
@@ -667,7 +702,7 @@ func Test_downScale(t *testing.T) {
 				}
 			}()
 
-			if err := downScale(ctx, logr.Discard(), test.alert.Labels, testClient); err != nil {
+			if err := downScale(ctx, logr.Discard(), test.alert.Labels, testClient, nil, nil); err != nil {
 				t.Error(err)
 				return
 			}
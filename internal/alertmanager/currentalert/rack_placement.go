@@ -0,0 +1,204 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka"
+)
+
+// zone is a rack of the Kafka cluster as it is configured in the broker.rack read-only config of the brokers
+type zone struct {
+	// Name is the comma separated list of the node label values the zone is identified by
+	Name string
+	// NodeLabels holds the values of the rack awareness labels of the nodes in the zone
+	NodeLabels map[string]string
+	Brokers    int
+}
+
+// selectUpScaleZone returns the zone with the least brokers among the zones of the nodes. It returns nil when the
+// Kafka cluster is not rack aware or the zone of the existing brokers is not known yet.
+func selectUpScaleZone(ctx context.Context, log logr.Logger, c client.Reader, cr *v1beta1.KafkaCluster) (*zone, error) {
+	if cr.Spec.RackAwareness == nil || len(cr.Spec.RackAwareness.Labels) == 0 {
+		return nil, nil
+	}
+	rackLabels := cr.Spec.RackAwareness.Labels
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes, client.HasLabels(rackLabels)); err != nil {
+		return nil, errors.WrapIf(err, "could not list nodes to determine the zones of the Kafka cluster")
+	}
+
+	zones := make(map[string]*zone)
+	for _, node := range nodes.Items {
+		nodeLabels := make(map[string]string, len(rackLabels))
+		values := make([]string, 0, len(rackLabels))
+		for _, label := range rackLabels {
+			nodeLabels[label] = node.Labels[label]
+			values = append(values, node.Labels[label])
+		}
+		name := strings.Join(values, ",")
+		if _, ok := zones[name]; !ok {
+			zones[name] = &zone{Name: name, NodeLabels: nodeLabels}
+		}
+	}
+	if len(zones) == 0 {
+		log.Info("rack aware placement is skipped as there are no nodes with the rack awareness labels",
+			"labels", rackLabels)
+		return nil, nil
+	}
+
+	for brokerID, brokerZone := range kafka.GetBrokerAzMap(cr) {
+		z, ok := zones[brokerZone]
+		if !ok {
+			log.Info("rack aware placement is skipped as the zone of a broker is unknown", "brokerId", brokerID)
+			return nil, nil
+		}
+		z.Brokers++
+	}
+
+	names := make([]string, 0, len(zones))
+	for name := range zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	selected := zones[names[0]]
+	for _, name := range names[1:] {
+		if zones[name].Brokers < selected.Brokers {
+			selected = zones[name]
+		}
+	}
+	return selected, nil
+}
+
+// zoneOfBrokerConfigGroup returns the zone the broker config group is pinned to by a required node affinity on
+// every rack awareness label
+func zoneOfBrokerConfigGroup(group v1beta1.BrokerConfig, rackLabels []string) (string, bool) {
+	if group.Affinity == nil || group.Affinity.NodeAffinity == nil ||
+		group.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return "", false
+	}
+	// node selector terms are ORed, so the group is pinned to a single zone only when it has one term
+	terms := group.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 {
+		return "", false
+	}
+
+	values := make([]string, 0, len(rackLabels))
+	for _, label := range rackLabels {
+		found := false
+		for _, expression := range terms[0].MatchExpressions {
+			if expression.Key == label && expression.Operator == corev1.NodeSelectorOpIn && len(expression.Values) == 1 {
+				values = append(values, expression.Values[0])
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", false
+		}
+	}
+	return strings.Join(values, ","), true
+}
+
+// brokerConfigGroupForZone returns the name of a broker config group pinned to the zone. Only zone specific
+// replacements of a group which is pinned to a zone itself are considered.
+func brokerConfigGroupForZone(cr *v1beta1.KafkaCluster, groupName string, z *zone) (string, bool) {
+	rackLabels := cr.Spec.RackAwareness.Labels
+	group, ok := cr.Spec.BrokerConfigGroups[groupName]
+	if !ok {
+		return "", false
+	}
+	if _, pinned := zoneOfBrokerConfigGroup(group, rackLabels); !pinned {
+		return "", false
+	}
+
+	names := make([]string, 0, len(cr.Spec.BrokerConfigGroups))
+	for name := range cr.Spec.BrokerConfigGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if groupZone, pinned := zoneOfBrokerConfigGroup(cr.Spec.BrokerConfigGroups[name], rackLabels); pinned && groupZone == z.Name {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// placeBrokerInZone pins the broker to the zone either by switching it to the broker config group of the zone or
+// by adding the zone to the required node affinity of the broker. The node affinity of the broker overrides the
+// one of its broker config group, so the existing node constraints of the group are copied to the broker.
+func placeBrokerInZone(cr *v1beta1.KafkaCluster, broker *v1beta1.Broker, z *zone) error {
+	if groupName, ok := brokerConfigGroupForZone(cr, broker.BrokerConfigGroup, z); ok {
+		broker.BrokerConfigGroup = groupName
+		return nil
+	}
+
+	brokerConfig, err := broker.GetBrokerConfig(cr.Spec)
+	if err != nil {
+		return err
+	}
+	if brokerConfig == nil {
+		brokerConfig = &v1beta1.BrokerConfig{}
+	}
+	hasAffinity := brokerConfig.Affinity != nil
+	affinity := kafka.GetAffinity(brokerConfig, cr).DeepCopy()
+
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	// node selector terms are ORed, so the zone is required by each of them
+	for i := range nodeSelector.NodeSelectorTerms {
+		for _, label := range cr.Spec.RackAwareness.Labels {
+			nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions,
+				corev1.NodeSelectorRequirement{
+					Key:      label,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{z.NodeLabels[label]},
+				})
+		}
+	}
+
+	if broker.BrokerConfig == nil {
+		broker.BrokerConfig = &v1beta1.BrokerConfig{}
+	}
+	if broker.BrokerConfig.Affinity == nil {
+		broker.BrokerConfig.Affinity = &corev1.Affinity{}
+	}
+	broker.BrokerConfig.Affinity.NodeAffinity = affinity.NodeAffinity
+	if !hasAffinity {
+		// the default pod anti-affinity is only generated for brokers without affinity
+		broker.BrokerConfig.Affinity.PodAntiAffinity = affinity.PodAntiAffinity
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka"
+)
+
+const zoneLabel = "topology.kubernetes.io/zone"
+
+func zoneNode(name, zone string) client.Object {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{zoneLabel: zone}}}
+}
+
+func zonePinnedGroup(zone string) v1beta1.BrokerConfig {
+	return v1beta1.BrokerConfig{
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      zoneLabel,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{zone},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestSelectUpScaleZone(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	nodes := []client.Object{zoneNode("node-0", "a"), zoneNode("node-1", "b"), zoneNode("node-2", "c"),
+		zoneNode("node-3", "a")}
+
+	tests := []struct {
+		testName      string
+		rackAwareness *v1beta1.RackAwareness
+		brokers       []v1beta1.Broker
+		expectedZone  string
+		expectedCount int
+	}{
+		{
+			testName: "cluster is not rack aware",
+			brokers:  []v1beta1.Broker{{Id: 0, ReadOnlyConfig: "broker.rack=a\n"}},
+		},
+		{
+			testName:      "zone without brokers is selected",
+			rackAwareness: &v1beta1.RackAwareness{Labels: []string{zoneLabel}},
+			brokers: []v1beta1.Broker{
+				{Id: 0, ReadOnlyConfig: "broker.rack=a\n"},
+				{Id: 1, ReadOnlyConfig: "broker.rack=b\n"},
+				{Id: 2, ReadOnlyConfig: "broker.rack=a\n"},
+			},
+			expectedZone: "c",
+		},
+		{
+			testName:      "zone with the least brokers is selected",
+			rackAwareness: &v1beta1.RackAwareness{Labels: []string{zoneLabel}},
+			brokers: []v1beta1.Broker{
+				{Id: 0, ReadOnlyConfig: "broker.rack=a\n"},
+				{Id: 1, ReadOnlyConfig: "broker.rack=b\n"},
+				{Id: 2, ReadOnlyConfig: "broker.rack=c\n"},
+				{Id: 3, ReadOnlyConfig: "broker.rack=a\n"},
+				{Id: 4, ReadOnlyConfig: "broker.rack=c\n"},
+			},
+			expectedZone:  "b",
+			expectedCount: 1,
+		},
+		{
+			testName:      "zone of a broker is unknown",
+			rackAwareness: &v1beta1.RackAwareness{Labels: []string{zoneLabel}},
+			brokers: []v1beta1.Broker{
+				{Id: 0, ReadOnlyConfig: "broker.rack=a\n"},
+				{Id: 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.testName, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodes...).Build()
+			cr := &v1beta1.KafkaCluster{
+				Spec: v1beta1.KafkaClusterSpec{RackAwareness: test.rackAwareness, Brokers: test.brokers},
+			}
+
+			z, err := selectUpScaleZone(context.Background(), logr.Discard(), c, cr)
+			require.NoError(t, err)
+			if test.expectedZone == "" {
+				require.Nil(t, z)
+				return
+			}
+			require.NotNil(t, z)
+			require.Equal(t, test.expectedZone, z.Name)
+			require.Equal(t, test.expectedCount, z.Brokers)
+			require.Equal(t, map[string]string{zoneLabel: test.expectedZone}, z.NodeLabels)
+		})
+	}
+}
+
+func TestPlaceBrokerInZone(t *testing.T) {
+	hostLabel := "kubernetes.io/hostname"
+	constrainedGroup := v1beta1.BrokerConfig{
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      hostLabel,
+							Operator: corev1.NodeSelectorOpNotIn,
+							Values:   []string{"node-0"},
+						}},
+					}},
+				},
+			},
+		},
+	}
+	cr := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			RackAwareness: &v1beta1.RackAwareness{Labels: []string{zoneLabel}},
+			BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+				"default":     {},
+				"constrained": constrainedGroup,
+				"zone-a":      zonePinnedGroup("a"),
+				"zone-b":      zonePinnedGroup("b"),
+			},
+		},
+	}
+	z := &zone{Name: "b", NodeLabels: map[string]string{zoneLabel: "b"}}
+	defaultPodAntiAffinity := kafka.GetAffinity(&v1beta1.BrokerConfig{}, cr).PodAntiAffinity
+
+	t.Run("broker config group of the zone is selected", func(t *testing.T) {
+		broker := v1beta1.Broker{Id: 1, BrokerConfigGroup: "zone-a"}
+		require.NoError(t, placeBrokerInZone(cr, &broker, z))
+		require.Equal(t, "zone-b", broker.BrokerConfigGroup)
+		require.Nil(t, broker.BrokerConfig)
+	})
+
+	t.Run("broker is pinned with node affinity", func(t *testing.T) {
+		broker := v1beta1.Broker{Id: 1, BrokerConfigGroup: "default"}
+		require.NoError(t, placeBrokerInZone(cr, &broker, z))
+		require.Equal(t, "default", broker.BrokerConfigGroup)
+		require.Equal(t, zonePinnedGroup("b").Affinity.NodeAffinity, broker.BrokerConfig.Affinity.NodeAffinity)
+		require.Equal(t, defaultPodAntiAffinity, broker.BrokerConfig.Affinity.PodAntiAffinity)
+	})
+
+	t.Run("broker without config group is pinned with node affinity", func(t *testing.T) {
+		broker := v1beta1.Broker{Id: 1, BrokerConfig: &v1beta1.BrokerConfig{Image: "kafka"}}
+		require.NoError(t, placeBrokerInZone(cr, &broker, z))
+		require.Equal(t, "kafka", broker.BrokerConfig.Image)
+		require.Equal(t, zonePinnedGroup("b").Affinity.NodeAffinity, broker.BrokerConfig.Affinity.NodeAffinity)
+		require.Equal(t, defaultPodAntiAffinity, broker.BrokerConfig.Affinity.PodAntiAffinity)
+	})
+
+	t.Run("node constraints of the broker config group are kept", func(t *testing.T) {
+		broker := v1beta1.Broker{Id: 1, BrokerConfigGroup: "constrained"}
+		require.NoError(t, placeBrokerInZone(cr, &broker, z))
+
+		brokerConfig, err := broker.GetBrokerConfig(cr.Spec)
+		require.NoError(t, err)
+		terms := brokerConfig.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		require.Equal(t, []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: hostLabel, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"node-0"}},
+				{Key: zoneLabel, Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}},
+			},
+		}}, terms)
+		require.Nil(t, brokerConfig.Affinity.PodAntiAffinity)
+		// the group is not modified
+		require.Len(t, cr.Spec.BrokerConfigGroups["constrained"].Affinity.NodeAffinity.
+			RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentalert
+
+import (
+	emperror "emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+type throttleReplicationValidator struct {
+	Alert *currentAlertStruct
+}
+
+func newThrottleReplicationValidator(currentAlert *currentAlertStruct) throttleReplicationValidator {
+	return throttleReplicationValidator{
+		Alert: currentAlert,
+	}
+}
+
+func (a throttleReplicationValidator) validateAlert() error {
+	if !checkLabelExists(a.Alert.Labels, v1beta1.KafkaCRLabelKey) {
+		return emperror.New("kafka_cr label doesn't exist")
+	}
+	throttleRate, err := resource.ParseQuantity(string(a.Alert.Annotations["throttleRate"]))
+	if err != nil {
+		return emperror.WrapIf(err, "throttleRate annotation is not a valid quantity")
+	}
+	if throttleRate.Value() <= 0 {
+		return emperror.NewWithDetails("throttleRate annotation must be positive", "throttleRate", a.Alert.Annotations["throttleRate"])
+	}
+	if a.Alert.Annotations["command"] != ThrottleReplicationCommand {
+		return emperror.NewWithDetails("unsupported command", "comand", a.Alert.Annotations["command"])
+	}
+
+	return nil
+}
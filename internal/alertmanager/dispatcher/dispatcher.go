@@ -35,6 +35,11 @@ func Dispatcher(ctx context.Context, promAlerts []model.Alert, log logr.Logger,
 			Annotations: promAlert.Annotations,
 		}
 		storedAlerts.AddAlert(store)
+		if store.Status == model.AlertResolved {
+			if err := storedAlerts.ResolveAlert(ctx, store, client); err != nil {
+				log.Error(err, "could not store the state of the resolved alert", "fingerprint", store.FingerPrint)
+			}
+		}
 		err := storedAlerts.AlertGC(store)
 		if err != nil {
 			log.Error(err, "alerts garbage collection failed")
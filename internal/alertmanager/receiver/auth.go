@@ -0,0 +1,100 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TokenSecretKey is the key of the bearer token in the secret referenced by AuthConfig
+	TokenSecretKey = "token"
+	// UsernameSecretKey is the key of the HTTP basic auth username in the secret referenced by AuthConfig
+	UsernameSecretKey = "username"
+	// PasswordSecretKey is the key of the HTTP basic auth password in the secret referenced by AuthConfig
+	PasswordSecretKey = "password"
+)
+
+var errUnauthenticated = errors.New("request is not authenticated")
+
+// AuthConfig configures the authentication of the requests sent by Prometheus Alertmanager
+type AuthConfig struct {
+	// SecretRef references the secret holding the credentials. The secret contains either a "token" key
+	// for bearer token authentication or "username" and "password" keys for HTTP basic authentication.
+	// Requests are not authenticated when it is nil.
+	SecretRef *types.NamespacedName
+}
+
+// ParseSecretRef parses a secret reference in the <namespace>/<name> format
+func ParseSecretRef(ref string) (*types.NamespacedName, error) {
+	if ref == "" {
+		return nil, nil
+	}
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found || namespace == "" || name == "" {
+		return nil, errors.NewWithDetails("secret reference must be in the <namespace>/<name> format", "secret", ref)
+	}
+	return &types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// authenticate checks the credentials of the request against the referenced secret. The secret is read on every
+// request so the credentials can be rotated without restarting the operator.
+func (c AuthConfig) authenticate(ctx context.Context, reader client.Reader, r *http.Request) error {
+	if c.SecretRef == nil {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, *c.SecretRef, secret); err != nil {
+		return errors.WrapIfWithDetails(err, "could not get alert receiver credentials",
+			"secret", c.SecretRef.String())
+	}
+
+	if token, ok := secret.Data[TokenSecretKey]; ok {
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "Bearer ") ||
+			!secureCompare(strings.TrimPrefix(authorization, "Bearer "), string(token)) {
+			return errUnauthenticated
+		}
+		return nil
+	}
+
+	username, usernameOk := secret.Data[UsernameSecretKey]
+	password, passwordOk := secret.Data[PasswordSecretKey]
+	if !usernameOk || !passwordOk {
+		return errors.NewWithDetails("alert receiver credentials secret must contain either a token or a username and a password",
+			"secret", c.SecretRef.String())
+	}
+	requestUsername, requestPassword, ok := r.BasicAuth()
+	// both of the values are compared to keep the time of the check independent of which one is wrong
+	usernameMatches := secureCompare(requestUsername, string(username))
+	passwordMatches := secureCompare(requestPassword, string(password))
+	if !ok || !usernameMatches || !passwordMatches {
+		return errUnauthenticated
+	}
+	return nil
+}
+
+func secureCompare(given, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
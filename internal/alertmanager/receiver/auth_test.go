@@ -0,0 +1,166 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAuthenticate(t *testing.T) {
+	secretRef := &types.NamespacedName{Namespace: "kafka", Name: "alertmanager-credentials"}
+
+	testCases := []struct {
+		testName   string
+		secretData map[string][]byte
+		secretRef  *types.NamespacedName
+		setAuth    func(r *http.Request)
+		wantErr    bool
+		wantUnauth bool
+	}{
+		{
+			testName: "authentication is disabled",
+			setAuth:  func(r *http.Request) {},
+		},
+		{
+			testName:   "valid bearer token",
+			secretRef:  secretRef,
+			secretData: map[string][]byte{TokenSecretKey: []byte("secret-token")},
+			setAuth:    func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret-token") },
+		},
+		{
+			testName:   "invalid bearer token",
+			secretRef:  secretRef,
+			secretData: map[string][]byte{TokenSecretKey: []byte("secret-token")},
+			setAuth:    func(r *http.Request) { r.Header.Set("Authorization", "Bearer forged-token") },
+			wantErr:    true,
+			wantUnauth: true,
+		},
+		{
+			testName:   "missing bearer token",
+			secretRef:  secretRef,
+			secretData: map[string][]byte{TokenSecretKey: []byte("secret-token")},
+			setAuth:    func(r *http.Request) {},
+			wantErr:    true,
+			wantUnauth: true,
+		},
+		{
+			testName:   "valid basic auth",
+			secretRef:  secretRef,
+			secretData: map[string][]byte{UsernameSecretKey: []byte("alertmanager"), PasswordSecretKey: []byte("pass")},
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("alertmanager", "pass") },
+		},
+		{
+			testName:   "invalid basic auth password",
+			secretRef:  secretRef,
+			secretData: map[string][]byte{UsernameSecretKey: []byte("alertmanager"), PasswordSecretKey: []byte("pass")},
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("alertmanager", "wrong") },
+			wantErr:    true,
+			wantUnauth: true,
+		},
+		{
+			testName:   "secret without credentials",
+			secretRef:  secretRef,
+			secretData: map[string][]byte{UsernameSecretKey: []byte("alertmanager")},
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("alertmanager", "") },
+			wantErr:    true,
+		},
+		{
+			testName:  "missing secret",
+			secretRef: &types.NamespacedName{Namespace: "kafka", Name: "missing"},
+			setAuth:   func(r *http.Request) {},
+			wantErr:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: secretRef.Namespace, Name: secretRef.Name},
+			Data:       testCase.secretData,
+		}
+		c := fake.NewClientBuilder().WithObjects(secret).Build()
+
+		r, err := http.NewRequest(http.MethodPost, APIEndPoint, nil)
+		assert.NoError(t, err)
+		testCase.setAuth(r)
+
+		err = AuthConfig{SecretRef: testCase.secretRef}.authenticate(context.Background(), c, r)
+		assert.Equal(t, testCase.wantErr, err != nil, "testName", testCase.testName)
+		assert.Equal(t, testCase.wantUnauth, err == errUnauthenticated, "testName", testCase.testName)
+	}
+}
+
+func TestParseSecretRef(t *testing.T) {
+	ref, err := ParseSecretRef("")
+	assert.NoError(t, err)
+	assert.Nil(t, ref)
+
+	ref, err = ParseSecretRef("kafka/alertmanager-credentials")
+	assert.NoError(t, err)
+	assert.Equal(t, &types.NamespacedName{Namespace: "kafka", Name: "alertmanager-credentials"}, ref)
+
+	for _, invalid := range []string{"alertmanager-credentials", "kafka/", "/alertmanager-credentials"} {
+		_, err = ParseSecretRef(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestReceiveAlertAuthenticationFailures(t *testing.T) {
+	secretRef := &types.NamespacedName{Namespace: "kafka", Name: "alertmanager-credentials"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: secretRef.Namespace, Name: secretRef.Name},
+		Data:       map[string][]byte{TokenSecretKey: []byte("secret-token")},
+	}
+
+	testCases := []struct {
+		testName     string
+		secretReader client.Reader
+		wantStatus   int
+	}{
+		{
+			testName:     "invalid credentials",
+			secretReader: fake.NewClientBuilder().WithObjects(secret).Build(),
+			wantStatus:   http.StatusUnauthorized,
+		},
+		{
+			testName:     "credentials secret can not be read",
+			secretReader: fake.NewClientBuilder().Build(),
+			wantStatus:   http.StatusForbidden,
+		},
+	}
+
+	for _, testCase := range testCases {
+		handler := NewHTTPHandler(logr.Discard(), fake.NewClientBuilder().Build(), Options{
+			Auth:         AuthConfig{SecretRef: secretRef},
+			SecretReader: testCase.secretReader,
+		})
+
+		r := httptest.NewRequest(http.MethodPost, APIEndPoint, nil)
+		r.Header.Set("Authorization", "Bearer forged-token")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, testCase.wantStatus, w.Code, "testName", testCase.testName)
+	}
+}
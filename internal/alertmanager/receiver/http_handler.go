@@ -18,32 +18,55 @@ import (
 	"io"
 	"net/http"
 
+	"emperror.dev/errors"
 	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // APIEndPoint for token handling
 const APIEndPoint = "/"
 
+// Options configures the authentication, replay protection and rate limiting of the alert receiver
+type Options struct {
+	Auth   AuthConfig
+	Limits Limits
+	// SecretReader reads the credentials secret referenced by Auth. It should be an uncached reader, so the secret
+	// can be read from namespaces the manager does not watch. The client of the controller is used when it is nil.
+	SecretReader client.Reader
+}
+
 // HTTPController collects the greeting use cases and exposes them as HTTP handlers.
 type HTTPController struct {
-	Logger logr.Logger
-	Client client.Client
+	Logger       logr.Logger
+	Client       client.Client
+	SecretReader client.Reader
+	Auth         AuthConfig
+	auditLog     logr.Logger
+	limiter      *fingerprintLimiter
 }
 
 // NewHTTPHandler returns a new HTTP handler for the greeter.
-func NewHTTPHandler(log logr.Logger, client client.Client) http.Handler {
+func NewHTTPHandler(log logr.Logger, client client.Client, options Options) http.Handler {
 	mux := http.NewServeMux()
-	controller := NewHTTPController(log, client)
+	controller := NewHTTPController(log, client, options)
 	mux.HandleFunc(APIEndPoint, controller.reciveAlert)
 	return mux
 }
 
 // NewHTTPController returns a new HTTPController instance.
-func NewHTTPController(log logr.Logger, client client.Client) *HTTPController {
+func NewHTTPController(log logr.Logger, client client.Client, options Options) *HTTPController {
+	secretReader := options.SecretReader
+	if secretReader == nil {
+		secretReader = client
+	}
 	return &HTTPController{
-		Logger: log,
-		Client: client,
+		Logger:       log,
+		Client:       client,
+		SecretReader: secretReader,
+		Auth:         options.Auth,
+		auditLog:     log.WithName("audit"),
+		limiter:      newFingerprintLimiter(options.Limits),
 	}
 }
 
@@ -51,12 +74,32 @@ func (a *HTTPController) reciveAlert(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	switch r.Method {
 	case "POST":
+		if err := a.Auth.authenticate(r.Context(), a.SecretReader, r); err != nil {
+			a.auditRejected(r, "unauthenticated", "error", err.Error())
+			if errors.Is(err, errUnauthenticated) {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+			} else {
+				// the credentials could not be looked up, so the request can not be authorized
+				http.Error(w, "forbidden", http.StatusForbidden)
+			}
+			return
+		}
 		alert, err := io.ReadAll(r.Body)
 		if err != nil {
 			http.Error(w, "reading request body failed", http.StatusInternalServerError)
 			return
 		}
-		err = alertReciever(r.Context(), a.Logger, alert, a.Client)
+		err = alertReciever(r.Context(), a.Logger, alert, a.Client, func(promAlert model.Alert) bool {
+			accepted, reason := a.limiter.accept(promAlert)
+			if !accepted {
+				a.auditRejected(r, reason, "fingerprint", promAlert.Fingerprint().String(), "alertName", promAlert.Name())
+			}
+			return accepted
+		})
+		if errors.Is(err, errAllAlertsRejected) {
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
 		if err != nil {
 			http.Error(w, "alert receiver error", http.StatusBadRequest)
 			return
@@ -69,3 +112,12 @@ func (a *HTTPController) reciveAlert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// auditRejected records the rejected request with the identity of its sender
+func (a *HTTPController) auditRejected(r *http.Request, reason string, keysAndValues ...interface{}) {
+	keysAndValues = append([]interface{}{"reason", reason, "remoteAddr", r.RemoteAddr, "userAgent", r.UserAgent()}, keysAndValues...)
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		keysAndValues = append(keysAndValues, "clientCertificate", r.TLS.PeerCertificates[0].Subject.String())
+	}
+	a.auditLog.Info("alert request rejected", keysAndValues...)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// Limits configures the replay protection and the rate limiting of the received alerts. Alerts are tracked by
+// their fingerprint, so a noisy alert does not prevent others from being processed.
+type Limits struct {
+	// ReplayWindow is the period an alert with the same fingerprint, status and start time as an already accepted
+	// one is rejected for. Replay protection is disabled when it is zero.
+	ReplayWindow time.Duration
+	// RateLimit is the maximum number of alerts accepted with the same fingerprint within RateLimitInterval.
+	// Rate limiting is disabled when it is zero.
+	RateLimit int
+	// RateLimitInterval is the period RateLimit applies to
+	RateLimitInterval time.Duration
+}
+
+const (
+	rejectReasonReplay      = "replay"
+	rejectReasonRateLimited = "rate limited"
+)
+
+type fingerprintRecord struct {
+	lastState    string
+	lastAccepted time.Time
+	accepted     []time.Time
+}
+
+// fingerprintLimiter keeps track of the accepted alerts by fingerprint
+type fingerprintLimiter struct {
+	limits  Limits
+	lock    sync.Mutex
+	records map[model.Fingerprint]*fingerprintRecord
+	now     func() time.Time
+}
+
+func newFingerprintLimiter(limits Limits) *fingerprintLimiter {
+	return &fingerprintLimiter{
+		limits:  limits,
+		records: make(map[model.Fingerprint]*fingerprintRecord),
+		now:     time.Now,
+	}
+}
+
+// accept returns whether the alert can be processed, or the reason of its rejection
+func (l *fingerprintLimiter) accept(alert model.Alert) (bool, string) {
+	if l.limits.ReplayWindow <= 0 && l.limits.RateLimit <= 0 {
+		return true, ""
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	fingerprint := alert.Fingerprint()
+	record, ok := l.records[fingerprint]
+	if !ok {
+		record = &fingerprintRecord{}
+		l.records[fingerprint] = record
+	}
+
+	state := alertState(alert)
+	if l.limits.ReplayWindow > 0 && record.lastState == state && now.Sub(record.lastAccepted) < l.limits.ReplayWindow {
+		return false, rejectReasonReplay
+	}
+
+	if l.limits.RateLimit > 0 {
+		record.accepted = acceptedSince(record.accepted, now.Add(-l.limits.RateLimitInterval))
+		if len(record.accepted) >= l.limits.RateLimit {
+			return false, rejectReasonRateLimited
+		}
+	}
+
+	record.lastState = state
+	record.lastAccepted = now
+	record.accepted = append(record.accepted, now)
+	return true, ""
+}
+
+// prune drops the records which can not affect the acceptance of alerts anymore
+func (l *fingerprintLimiter) prune(now time.Time) {
+	retention := l.limits.ReplayWindow
+	if l.limits.RateLimit > 0 && l.limits.RateLimitInterval > retention {
+		retention = l.limits.RateLimitInterval
+	}
+	for fingerprint, record := range l.records {
+		if now.Sub(record.lastAccepted) >= retention {
+			delete(l.records, fingerprint)
+		}
+	}
+}
+
+func acceptedSince(accepted []time.Time, since time.Time) []time.Time {
+	for i, t := range accepted {
+		if t.After(since) {
+			return accepted[i:]
+		}
+	}
+	return nil
+}
+
+// alertState identifies a notification of an alert, Alertmanager sends the same state again on retries.
+// EndsAt is left out as Alertmanager moves it forward every time it resends a firing alert.
+func alertState(alert model.Alert) string {
+	return string(alert.Status()) + "/" + alert.StartsAt.UTC().String()
+}
@@ -0,0 +1,104 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprintLimiter(t *testing.T) {
+	startsAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	alert := model.Alert{
+		Labels:   model.LabelSet{"alertname": "BrokerUnderReplicated", "kafka_cr": "kafka"},
+		StartsAt: startsAt,
+	}
+	otherAlert := model.Alert{
+		Labels:   model.LabelSet{"alertname": "BrokerUnderReplicated", "kafka_cr": "other"},
+		StartsAt: startsAt,
+	}
+	resolvedAlert := alert
+	resolvedAlert.EndsAt = startsAt.Add(time.Minute)
+
+	now := startsAt
+	limiter := newFingerprintLimiter(Limits{
+		ReplayWindow:      time.Minute,
+		RateLimit:         2,
+		RateLimitInterval: 10 * time.Minute,
+	})
+	limiter.now = func() time.Time { return now }
+
+	accepted, _ := limiter.accept(alert)
+	assert.True(t, accepted)
+
+	accepted, reason := limiter.accept(alert)
+	assert.False(t, accepted)
+	assert.Equal(t, rejectReasonReplay, reason)
+
+	// alerts with other fingerprints are not affected
+	accepted, _ = limiter.accept(otherAlert)
+	assert.True(t, accepted)
+
+	// a new state of the same alert is not a replay
+	now = now.Add(10 * time.Second)
+	accepted, _ = limiter.accept(resolvedAlert)
+	assert.True(t, accepted)
+
+	now = now.Add(2 * time.Minute)
+	accepted, reason = limiter.accept(alert)
+	assert.False(t, accepted)
+	assert.Equal(t, rejectReasonRateLimited, reason)
+
+	now = now.Add(10 * time.Minute)
+	accepted, _ = limiter.accept(alert)
+	assert.True(t, accepted)
+}
+
+func TestFingerprintLimiterResentFiringAlert(t *testing.T) {
+	startsAt := time.Now().Add(-time.Hour)
+	alert := model.Alert{
+		Labels:   model.LabelSet{"alertname": "BrokerUnderReplicated", "kafka_cr": "kafka"},
+		StartsAt: startsAt,
+		EndsAt:   time.Now().Add(4 * time.Minute),
+	}
+	// Alertmanager moves EndsAt forward when it resends a firing alert
+	resentAlert := alert
+	resentAlert.EndsAt = alert.EndsAt.Add(time.Minute)
+
+	now := startsAt
+	limiter := newFingerprintLimiter(Limits{ReplayWindow: time.Minute})
+	limiter.now = func() time.Time { return now }
+
+	accepted, _ := limiter.accept(alert)
+	assert.True(t, accepted)
+
+	now = now.Add(30 * time.Second)
+	accepted, reason := limiter.accept(resentAlert)
+	assert.False(t, accepted)
+	assert.Equal(t, rejectReasonReplay, reason)
+}
+
+func TestFingerprintLimiterDisabled(t *testing.T) {
+	limiter := newFingerprintLimiter(Limits{})
+	alert := model.Alert{Labels: model.LabelSet{"alertname": "BrokerUnderReplicated"}}
+	for i := 0; i < 3; i++ {
+		accepted, _ := limiter.accept(alert)
+		assert.True(t, accepted)
+	}
+	assert.Empty(t, limiter.records)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"emperror.dev/errors"
 	"github.com/go-logr/logr"
 	"github.com/prometheus/common/model"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -25,13 +26,25 @@ import (
 	"github.com/banzaicloud/koperator/internal/alertmanager/dispatcher"
 )
 
-func alertReciever(ctx context.Context, log logr.Logger, alert []byte, client client.Client) error {
+var errAllAlertsRejected = errors.New("all of the alerts were rejected")
+
+func alertReciever(ctx context.Context, log logr.Logger, alert []byte, client client.Client, accept func(model.Alert) bool) error {
 	promAlerts := make([]model.Alert, 0)
 	err := json.Unmarshal(alert, &promAlerts)
 	if err != nil {
 		return err
 	}
 
-	dispatcher.Dispatcher(ctx, promAlerts, log, client)
+	acceptedAlerts := make([]model.Alert, 0, len(promAlerts))
+	for _, promAlert := range promAlerts {
+		if accept(promAlert) {
+			acceptedAlerts = append(acceptedAlerts, promAlert)
+		}
+	}
+	if len(promAlerts) > 0 && len(acceptedAlerts) == 0 {
+		return errAllAlertsRejected
+	}
+
+	dispatcher.Dispatcher(ctx, acceptedAlerts, log, client)
 	return nil
 }
@@ -34,9 +34,11 @@ import (
 	"flag"
 	"os"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	istioclientv1beta1 "github.com/banzaicloud/istio-client-go/pkg/networking/v1beta1"
 
@@ -51,6 +53,7 @@ import (
 	banzaicloudv1alpha1 "github.com/banzaicloud/koperator/api/v1alpha1"
 	banzaicloudv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/controllers"
+	"github.com/banzaicloud/koperator/internal/alertmanager/receiver"
 	"github.com/banzaicloud/koperator/pkg/k8sutil"
 	"github.com/banzaicloud/koperator/pkg/kafkaclient"
 	"github.com/banzaicloud/koperator/pkg/scale"
@@ -84,6 +87,7 @@ func main() {
 		enableLeaderElection              bool
 		webhookCertDir                    string
 		webhookDisabled                   bool
+		configChangeWarningsEnabled       bool
 		webhookServerPort                 int
 		developmentLogging                bool
 		verboseLogging                    bool
@@ -91,6 +95,11 @@ func main() {
 		certManagerEnabled                bool
 		maxKafkaTopicConcurrentReconciles int
 		healthProbesAddr                  string
+		alertManagerAuthSecret            string
+		alertManagerTLSCertDir            string
+		alertManagerReplayWindow          time.Duration
+		alertManagerRateLimit             int
+		alertManagerRateLimitInterval     time.Duration
 	)
 
 	flag.StringVar(&namespaces, "namespaces", "", "Comma separated list of namespaces where operator listens for resources")
@@ -98,6 +107,8 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&webhookDisabled, "disable-webhooks", false, "Disable webhooks used to validate custom resources")
+	flag.BoolVar(&configChangeWarningsEnabled, "enable-config-change-warnings", false,
+		"Warn about KafkaCluster updates which roll brokers due to configuration changes")
 	flag.StringVar(&webhookCertDir, "tls-cert-dir", "/etc/webhook/certs", "The directory with a tls.key and tls.crt for serving HTTPS requests")
 	flag.IntVar(&webhookServerPort, "webhook-server-port", 9443, "The port that the webhook server serves at")
 	flag.BoolVar(&developmentLogging, "development", false, "Enable development logging")
@@ -106,6 +117,16 @@ func main() {
 	flag.BoolVar(&certSigningDisabled, "disable-cert-signing-support", false, "Disable native certificate signing integration")
 	flag.IntVar(&maxKafkaTopicConcurrentReconciles, "max-kafka-topic-concurrent-reconciles", 10, "Define max amount of concurrent KafkaTopic reconciles")
 	flag.StringVar(&healthProbesAddr, "health-probes-addr", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&alertManagerAuthSecret, "alertmanager-auth-secret", "",
+		"The <namespace>/<name> of the secret with the bearer token or basic auth credentials the alert receiver requires")
+	flag.StringVar(&alertManagerTLSCertDir, "alertmanager-tls-cert-dir", "",
+		"The directory with a tls.key and tls.crt for serving the alert receiver over HTTPS, and an optional ca.crt to verify client certificates with")
+	flag.DurationVar(&alertManagerReplayWindow, "alertmanager-replay-window", 0,
+		"The period an already received alert is rejected for when it is sent again. Disabled when zero")
+	flag.IntVar(&alertManagerRateLimit, "alertmanager-rate-limit", 0,
+		"The maximum number of alerts accepted with the same fingerprint within the rate limit interval. Disabled when zero")
+	flag.DurationVar(&alertManagerRateLimitInterval, "alertmanager-rate-limit-interval", time.Minute,
+		"The period the alert rate limit applies to")
 	flag.Parse()
 	ctrl.SetLogger(util.CreateLogger(verboseLogging, developmentLogging))
 
@@ -157,7 +178,25 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = controllers.SetAlertManagerWithManager(mgr); err != nil {
+	alertManagerAuthSecretRef, err := receiver.ParseSecretRef(alertManagerAuthSecret)
+	if err != nil {
+		setupLog.Error(err, "invalid alertmanager-auth-secret")
+		os.Exit(1)
+	}
+
+	alertManagerConfig := controllers.AlertManagerConfig{
+		Options: receiver.Options{
+			Auth: receiver.AuthConfig{SecretRef: alertManagerAuthSecretRef},
+			Limits: receiver.Limits{
+				ReplayWindow:      alertManagerReplayWindow,
+				RateLimit:         alertManagerRateLimit,
+				RateLimitInterval: alertManagerRateLimitInterval,
+			},
+		},
+		TLSCertDir: alertManagerTLSCertDir,
+	}
+
+	if err = controllers.SetAlertManagerWithManager(mgr, alertManagerConfig); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AlertManagerForKafka")
 		os.Exit(1)
 	}
@@ -186,8 +225,9 @@ func main() {
 
 	// Create a new  kafka user reconciler
 	kafkaUserReconciler := &controllers.KafkaUserReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("kafkauser"),
 	}
 
 	if err = controllers.SetupKafkaUserWithManager(mgr, !certSigningDisabled, certManagerEnabled).Complete(kafkaUserReconciler); err != nil {
@@ -207,6 +247,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	cruiseControlStatusReconciler := &controllers.CruiseControlStatusReconciler{
+		Client:       mgr.GetClient(),
+		ScaleFactory: scale.ScaleFactoryFn(),
+	}
+
+	if err = controllers.SetupCruiseControlStatusWithManager(mgr).Complete(cruiseControlStatusReconciler); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CruiseControlStatus")
+		os.Exit(1)
+	}
+
 	cruiseControlOperationReconciler := controllers.CruiseControlOperationReconciler{
 		Client:       mgr.GetClient(),
 		DirectClient: mgr.GetAPIReader(),
@@ -229,15 +279,35 @@ func main() {
 		os.Exit(1)
 	}
 
+	kafkaClusterBackupReconciler := &controllers.KafkaClusterBackupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+
+	if err = controllers.SetupKafkaClusterBackupWithManager(mgr).Complete(kafkaClusterBackupReconciler); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafkaClusterBackup")
+		os.Exit(1)
+	}
+
 	if !webhookDisabled {
-		err = ctrl.NewWebhookManagedBy(mgr).For(&banzaicloudv1beta1.KafkaCluster{}).
-			WithValidator(webhooks.KafkaClusterValidator{
-				Log: mgr.GetLogger().WithName("webhooks").WithName("KafkaCluster"),
-			}).
-			Complete()
-		if err != nil {
-			setupLog.Error(err, "unable to create validating webhook", "Kind", "KafkaCluster")
-			os.Exit(1)
+		kafkaClusterWebhookLog := mgr.GetLogger().WithName("webhooks").WithName("KafkaCluster")
+		if configChangeWarningsEnabled {
+			// the KafkaCluster validating webhook is registered directly to warn about configuration changes rolling brokers
+			mgr.GetWebhookServer().Register("/validate-kafka-banzaicloud-io-v1beta1-kafkacluster",
+				webhooks.WithConfigChangeWarnings(admission.WithCustomValidator(&banzaicloudv1beta1.KafkaCluster{},
+					webhooks.KafkaClusterValidator{
+						Log: kafkaClusterWebhookLog,
+					}), kafkaClusterWebhookLog))
+		} else {
+			err = ctrl.NewWebhookManagedBy(mgr).For(&banzaicloudv1beta1.KafkaCluster{}).
+				WithValidator(webhooks.KafkaClusterValidator{
+					Log: kafkaClusterWebhookLog,
+				}).
+				Complete()
+			if err != nil {
+				setupLog.Error(err, "unable to create validating webhook", "Kind", "KafkaCluster")
+				os.Exit(1)
+			}
 		}
 		err = ctrl.NewWebhookManagedBy(mgr).For(&banzaicloudv1alpha1.KafkaTopic{}).
 			WithValidator(webhooks.KafkaTopicValidator{
@@ -258,6 +328,10 @@ func main() {
 		setupLog.Error(err, "unable to add indexers to manager's cache")
 		os.Exit(1)
 	}
+	if err := controllers.AddKafkaClusterIndexers(ctx, mgr.GetCache()); err != nil {
+		setupLog.Error(err, "unable to add indexers to manager's cache")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -112,6 +112,11 @@ type LoadBalancerIPNotReady struct{ error }
 
 func (e LoadBalancerIPNotReady) Unwrap() error { return e.error }
 
+// VaultAPIFailure states that something went wrong with the Vault API
+type VaultAPIFailure struct{ error }
+
+func (e VaultAPIFailure) Unwrap() error { return e.error }
+
 // New creates a new error factory error
 func New(t interface{}, err error, msg string, wrapArgs ...interface{}) error {
 	wrapped := errors.WrapIfWithDetails(err, msg, wrapArgs...)
@@ -154,6 +159,8 @@ func New(t interface{}, err error, msg string, wrapArgs ...interface{}) error {
 		return PerBrokerConfigNotReady{wrapped}
 	case LoadBalancerIPNotReady:
 		return LoadBalancerIPNotReady{wrapped}
+	case VaultAPIFailure:
+		return VaultAPIFailure{wrapped}
 	}
 	return wrapped
 }
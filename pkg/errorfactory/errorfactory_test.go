@@ -37,6 +37,7 @@ var errorTypes = []error{
 	FatalReconcileError{},
 	CruiseControlNotReady{},
 	CruiseControlTaskRunning{},
+	VaultAPIFailure{},
 }
 
 func TestNew(t *testing.T) {
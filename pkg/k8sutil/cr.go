@@ -35,19 +35,34 @@ import (
 
 // UpdateCrWithRackAwarenessConfig updates the CR with rack awareness config
 func UpdateCrWithRackAwarenessConfig(pod *corev1.Pod, cr *v1beta1.KafkaCluster, client runtimeClient.Client, directClient runtimeClient.Reader) (v1beta1.RackAwarenessState, error) {
-	rackConfigMap, err := getSpecificNodeLabels(pod.Spec.NodeName, directClient, cr.Spec.RackAwareness.Labels)
+	rackConfigValues, err := getRackConfigValues(pod, cr, directClient)
 	if err != nil {
 		return "", errorfactory.New(errorfactory.StatusUpdateError{}, err, "updating cr with rack awareness info failed")
 	}
+
+	rackAwarenessState, brokers := rackAwarenessLabelsToReadonlyConfig(pod, cr, rackConfigValues)
+	cr.Spec.Brokers = brokers
+	return rackAwarenessState, UpdateCr(cr, client)
+}
+
+// getRackConfigValues returns the rack of the broker derived from the rack awareness labels of the node the broker pod
+// is scheduled to. When zone pinning is enabled the zone label is replaced by the zone the broker is pinned to.
+func getRackConfigValues(pod *corev1.Pod, cr *v1beta1.KafkaCluster, directClient runtimeClient.Reader) ([]string, error) {
+	rackConfigMap, err := getSpecificNodeLabels(pod.Spec.NodeName, directClient, cr.Spec.RackAwareness.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if brokerID, err := strconv.ParseInt(pod.Labels[v1beta1.BrokerIdLabelKey], 10, 32); err == nil {
+		if zone, ok := cr.Spec.GetBrokerZone(int32(brokerID)); ok {
+			rackConfigMap[cr.Spec.RackAwareness.ZonePinning.GetZoneLabel(cr.Spec.RackAwareness)] = zone
+		}
+	}
 	rackConfigValues := make([]string, 0, len(rackConfigMap))
 	for _, value := range rackConfigMap {
 		rackConfigValues = append(rackConfigValues, value)
 	}
 	sort.Strings(rackConfigValues)
-
-	rackAwarenessState, brokers := rackAwarenessLabelsToReadonlyConfig(pod, cr, rackConfigValues)
-	cr.Spec.Brokers = brokers
-	return rackAwarenessState, UpdateCr(cr, client)
+	return rackConfigValues, nil
 }
 
 func rackAwarenessLabelsToReadonlyConfig(pod *corev1.Pod, cr *v1beta1.KafkaCluster, rackConfigValues []string) (v1beta1.RackAwarenessState, []v1beta1.Broker) {
@@ -20,6 +20,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/banzaicloud/koperator/api/v1beta1"
 )
@@ -225,3 +226,41 @@ func Test_rackAwarenessLabelsToReadonlyConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_getRackConfigValuesWithZonePinning(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				v1beta1.BrokerIdLabelKey: "4",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "node"},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Labels: map[string]string{
+				"topology.kubernetes.io/region": "us-east-1",
+				"topology.kubernetes.io/zone":   "us-east-1c",
+			},
+		},
+	}
+	cr := &v1beta1.KafkaCluster{
+		Spec: v1beta1.KafkaClusterSpec{
+			RackAwareness: &v1beta1.RackAwareness{
+				Labels:      []string{"topology.kubernetes.io/region", "topology.kubernetes.io/zone"},
+				ZonePinning: &v1beta1.ZonePinning{Zones: []string{"us-east-1a", "us-east-1b", "us-east-1c"}, Label: "topology.kubernetes.io/zone"},
+			},
+		},
+	}
+
+	// the zone label is replaced by the zone the broker is pinned to, the other rack awareness labels are kept
+	got, err := getRackConfigValues(pod, cr, fake.NewClientBuilder().WithObjects(node).Build())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{"us-east-1", "us-east-1b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getRackConfigValues() got = %v, want %v", got, want)
+	}
+}
@@ -143,6 +143,16 @@ func Reconcile(log logr.Logger, client runtimeClient.Client, desired runtime.Obj
 						return nil
 					}
 
+					// report the pending changes of the broker configuration, the current configmap may hold changes
+					// which are not applied yet, so the changes are merged into the already pending ones
+					pendingConfigChanges := PendingConfigChanges{
+						Changes:     kafka.GetBrokerConfigChanges(currentConfigs, desiredConfigs),
+						Incremental: true,
+					}
+					if statusErr := UpdateBrokerStatus(client, []string{id}, cr, pendingConfigChanges, log); statusErr != nil {
+						return errors.WrapIfWithDetails(statusErr, "updating pending config changes of the broker failed", v1beta1.BrokerIdLabelKey, id)
+					}
+
 					var statusErr error
 					// if only per broker configs are changed, do not trigger rolling upgrade by setting ConfigOutOfSync status
 					if kafka.ShouldRefreshOnlyPerBrokerConfigs(currentConfigs, desiredConfigs, log) {
@@ -229,6 +239,26 @@ func IsPodContainsPendingContainer(pod *corev1.Pod) bool {
 	return false
 }
 
+// HasReadinessGate returns true if the pod has a readiness gate with the given condition type
+func HasReadinessGate(pod *corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, readinessGate := range pod.Spec.ReadinessGates {
+		if readinessGate.ConditionType == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPodConditionTrue returns true if the pod has a condition with the given type and its status is true
+func IsPodConditionTrue(pod *corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func GetDefaultInitContainerResourceRequirements() corev1.ResourceRequirements {
 	return corev1.ResourceRequirements{
 		Limits: corev1.ResourceList{
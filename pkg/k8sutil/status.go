@@ -17,11 +17,13 @@ package k8sutil
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -152,8 +154,28 @@ func generateBrokerState(brokerIDs []string, cluster *banzaicloudv1beta1.KafkaCl
 			brokerState.GracefulActionState = state
 		case banzaicloudv1beta1.ConfigurationState:
 			brokerState.ConfigurationState = s
+			if s == banzaicloudv1beta1.ConfigInSync {
+				// the restart of the broker applies every change of the broker configuration
+				brokerState.PendingConfigChanges = removePendingConfigChanges(brokerState.PendingConfigChanges,
+					banzaicloudv1beta1.ConfigChangeRestartRequired, banzaicloudv1beta1.ConfigChangeDynamicPerBroker)
+			}
 		case banzaicloudv1beta1.PerBrokerConfigurationState:
 			brokerState.PerBrokerConfigurationState = s
+			if s == banzaicloudv1beta1.PerBrokerConfigInSync {
+				brokerState.PendingConfigChanges = removePendingConfigChanges(brokerState.PendingConfigChanges,
+					banzaicloudv1beta1.ConfigChangeDynamicPerBroker)
+			}
+		case PendingConfigChanges:
+			var pendingConfigChanges []banzaicloudv1beta1.PendingConfigChange
+			if s.Incremental {
+				pendingConfigChanges = mergePendingConfigChanges(brokerState.PendingConfigChanges, s.Changes)
+			} else {
+				pendingConfigChanges = append(removePendingConfigChanges(brokerState.PendingConfigChanges, s.Types...), s.Changes...)
+			}
+			sort.SliceStable(pendingConfigChanges, func(i, j int) bool {
+				return pendingConfigChanges[i].Key < pendingConfigChanges[j].Key
+			})
+			brokerState.PendingConfigChanges = pendingConfigChanges
 		case map[string]banzaicloudv1beta1.VolumeState:
 			if brokerState.GracefulActionState.VolumeStates == nil {
 				brokerState.GracefulActionState.VolumeStates = make(map[string]banzaicloudv1beta1.VolumeState)
@@ -172,12 +194,63 @@ func generateBrokerState(brokerIDs []string, cluster *banzaicloudv1beta1.KafkaCl
 		case banzaicloudv1beta1.KafkaVersion:
 			brokerState.Image = s.Image
 			brokerState.Version = s.Version
+		case banzaicloudv1beta1.ListenerCertificateStates:
+			brokerState.ListenerCertificates = s
 		}
 		brokersState[brokerID] = brokerState
 	}
 	cluster.Status.BrokersState = brokersState
 }
 
+// PendingConfigChanges replaces the pending configuration changes of the given types in the broker state.
+// Incremental changes are merged into the pending changes instead, see mergePendingConfigChanges.
+type PendingConfigChanges struct {
+	Types       []banzaicloudv1beta1.ConfigChangeType
+	Changes     []banzaicloudv1beta1.PendingConfigChange
+	Incremental bool
+}
+
+// mergePendingConfigChanges merges the changes into the pending configuration changes. A repeated change of a
+// configuration keeps the old value of the pending change, which is the value applied on the broker, and the
+// change is dropped once the configuration is set back to that value.
+func mergePendingConfigChanges(configChanges, changes []banzaicloudv1beta1.PendingConfigChange) []banzaicloudv1beta1.PendingConfigChange {
+	merged := append([]banzaicloudv1beta1.PendingConfigChange(nil), configChanges...)
+	for _, change := range changes {
+		i := slices.IndexFunc(merged, func(pending banzaicloudv1beta1.PendingConfigChange) bool {
+			return pending.Key == change.Key
+		})
+		if i < 0 {
+			merged = append(merged, change)
+			continue
+		}
+		change.OldValue = merged[i].OldValue
+		if change.NewValue == change.OldValue {
+			merged = slices.Delete(merged, i, i+1)
+			continue
+		}
+		merged[i] = change
+	}
+	return merged
+}
+
+func removePendingConfigChanges(configChanges []banzaicloudv1beta1.PendingConfigChange,
+	changeTypes ...banzaicloudv1beta1.ConfigChangeType) []banzaicloudv1beta1.PendingConfigChange {
+	var remaining []banzaicloudv1beta1.PendingConfigChange
+	for _, configChange := range configChanges {
+		removed := false
+		for _, changeType := range changeTypes {
+			if configChange.Type == changeType {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			remaining = append(remaining, configChange)
+		}
+	}
+	return remaining
+}
+
 // DeleteStatus deletes the given broker state from the CR
 func DeleteStatus(c client.Client, brokerID string, cluster *banzaicloudv1beta1.KafkaCluster, logger logr.Logger) error {
 	typeMeta := cluster.TypeMeta
@@ -223,6 +296,56 @@ func DeleteStatus(c client.Client, brokerID string, cluster *banzaicloudv1beta1.
 	return nil
 }
 
+// DeleteVolumeStatus deletes the given volume states of a broker
+func DeleteVolumeStatus(c client.Client, brokerID string, mountPaths []string, cluster *banzaicloudv1beta1.KafkaCluster, logger logr.Logger) error {
+	typeMeta := cluster.TypeMeta
+
+	deleteVolumeStates(cluster, brokerID, mountPaths)
+
+	err := c.Status().Update(context.Background(), cluster)
+	if apierrors.IsNotFound(err) {
+		err = c.Update(context.Background(), cluster)
+	}
+	if err != nil {
+		if !apierrors.IsConflict(err) {
+			return errors.WrapIff(err, "could not delete Kafka cluster broker %s volume states", brokerID)
+		}
+		err := c.Get(context.TODO(), types.NamespacedName{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name,
+		}, cluster)
+		if err != nil {
+			return errors.WrapIf(err, "could not get config for updating status")
+		}
+
+		deleteVolumeStates(cluster, brokerID, mountPaths)
+
+		err = c.Status().Update(context.Background(), cluster)
+		if apierrors.IsNotFound(err) {
+			err = c.Update(context.Background(), cluster)
+		}
+		if err != nil {
+			return errors.WrapIff(err, "could not delete Kafka cluster broker %s volume states", brokerID)
+		}
+	}
+
+	// update loses the typeMeta of the config that's used later when setting ownerrefs
+	cluster.TypeMeta = typeMeta
+	logger.Info(fmt.Sprintf("Kafka broker %s volume states deleted", brokerID), "mountPaths", mountPaths)
+	return nil
+}
+
+func deleteVolumeStates(cluster *banzaicloudv1beta1.KafkaCluster, brokerID string, mountPaths []string) {
+	brokerState, ok := cluster.Status.BrokersState[brokerID]
+	if !ok {
+		return
+	}
+	for _, mountPath := range mountPaths {
+		delete(brokerState.GracefulActionState.VolumeStates, mountPath)
+	}
+	cluster.Status.BrokersState[brokerID] = brokerState
+}
+
 // UpdateCRStatus updates the cluster state
 func UpdateCRStatus(c client.Client, cluster *banzaicloudv1beta1.KafkaCluster, state interface{}, logger logr.Logger) error {
 	typeMeta := cluster.TypeMeta
@@ -314,10 +437,17 @@ func UpdateListenerStatuses(ctx context.Context, c client.Client, cluster *banza
 
 	typeMeta := cluster.TypeMeta
 
+	// the name stays empty until koperator has the credentials for one of the internal listeners
+	var adminClientListener string
+	if listener, err := clientutil.AdminClientListener(cluster); err == nil {
+		adminClientListener = listener.Name
+	}
+
 	cluster.Status.ListenerStatuses = banzaicloudv1beta1.ListenerStatuses{
 		InternalListeners: intListenerStatuses,
 		ExternalListeners: extListenerStatuses,
 	}
+	cluster.Status.AdminClientListener = adminClientListener
 
 	err := c.Status().Update(ctx, cluster)
 	if apierrors.IsNotFound(err) {
@@ -339,6 +469,7 @@ func UpdateListenerStatuses(ctx context.Context, c client.Client, cluster *banza
 			InternalListeners: intListenerStatuses,
 			ExternalListeners: extListenerStatuses,
 		}
+		cluster.Status.AdminClientListener = adminClientListener
 
 		err = c.Status().Update(ctx, cluster)
 		if apierrors.IsNotFound(err) {
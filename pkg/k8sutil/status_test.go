@@ -0,0 +1,86 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func TestGenerateBrokerStatePendingConfigChanges(t *testing.T) {
+	restartRequired := v1beta1.PendingConfigChange{Key: "num.partitions", NewValue: "3", Type: v1beta1.ConfigChangeRestartRequired}
+	perBroker := v1beta1.PendingConfigChange{Key: "ssl.client.auth", NewValue: "required", Type: v1beta1.ConfigChangeDynamicPerBroker}
+	clusterWide := v1beta1.PendingConfigChange{Key: "background.threads", NewValue: "4", Type: v1beta1.ConfigChangeDynamicClusterWide}
+
+	cluster := &v1beta1.KafkaCluster{}
+	generateBrokerState([]string{"0"}, cluster, PendingConfigChanges{
+		Types:   []v1beta1.ConfigChangeType{v1beta1.ConfigChangeRestartRequired, v1beta1.ConfigChangeDynamicPerBroker},
+		Changes: []v1beta1.PendingConfigChange{restartRequired, perBroker},
+	})
+	generateBrokerState([]string{"0"}, cluster, PendingConfigChanges{
+		Types:   []v1beta1.ConfigChangeType{v1beta1.ConfigChangeDynamicClusterWide},
+		Changes: []v1beta1.PendingConfigChange{clusterWide},
+	})
+	expected := []v1beta1.PendingConfigChange{clusterWide, restartRequired, perBroker}
+	if got := cluster.Status.BrokersState["0"].PendingConfigChanges; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, got)
+	}
+
+	generateBrokerState([]string{"0"}, cluster, v1beta1.PerBrokerConfigInSync)
+	expected = []v1beta1.PendingConfigChange{clusterWide, restartRequired}
+	if got := cluster.Status.BrokersState["0"].PendingConfigChanges; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, got)
+	}
+
+	generateBrokerState([]string{"0"}, cluster, v1beta1.ConfigInSync)
+	expected = []v1beta1.PendingConfigChange{clusterWide}
+	if got := cluster.Status.BrokersState["0"].PendingConfigChanges; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, got)
+	}
+
+	generateBrokerState([]string{"0"}, cluster, PendingConfigChanges{
+		Types: []v1beta1.ConfigChangeType{v1beta1.ConfigChangeDynamicClusterWide},
+	})
+	if got := cluster.Status.BrokersState["0"].PendingConfigChanges; got != nil {
+		t.Errorf("expected no pending config changes, got: %+v", got)
+	}
+}
+
+func TestGenerateBrokerStateIncrementalPendingConfigChanges(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{}
+	generateBrokerState([]string{"0"}, cluster, PendingConfigChanges{
+		Changes: []v1beta1.PendingConfigChange{
+			{Key: "num.partitions", OldValue: "1", NewValue: "2", Type: v1beta1.ConfigChangeRestartRequired},
+			{Key: "ssl.client.auth", OldValue: "none", NewValue: "required", Type: v1beta1.ConfigChangeDynamicPerBroker},
+		},
+		Incremental: true,
+	})
+	// the second edit is diffed against the not yet applied configuration of the first one
+	generateBrokerState([]string{"0"}, cluster, PendingConfigChanges{
+		Changes: []v1beta1.PendingConfigChange{
+			{Key: "num.partitions", OldValue: "2", NewValue: "3", Type: v1beta1.ConfigChangeRestartRequired},
+			{Key: "ssl.client.auth", OldValue: "required", NewValue: "none", Type: v1beta1.ConfigChangeDynamicPerBroker},
+		},
+		Incremental: true,
+	})
+	expected := []v1beta1.PendingConfigChange{
+		{Key: "num.partitions", OldValue: "1", NewValue: "3", Type: v1beta1.ConfigChangeRestartRequired},
+	}
+	if got := cluster.Status.BrokersState["0"].PendingConfigChanges; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, got)
+	}
+}
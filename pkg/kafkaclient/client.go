@@ -22,6 +22,7 @@ import (
 	"github.com/banzaicloud/koperator/api/v1alpha1"
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/xdg-go/scram"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -166,6 +167,19 @@ func (k *kafkaClient) getSaramaConfig() (config *sarama.Config) {
 		config.Net.TLS.Enable = true
 		config.Net.TLS.Config = k.opts.TLSConfig
 	}
+	if k.opts.UseSASL {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.Mechanism = k.opts.SASLMechanism
+		config.Net.SASL.User = k.opts.SASLUser
+		config.Net.SASL.Password = k.opts.SASLPassword
+		config.Net.SASL.TokenProvider = k.opts.SASLTokenProvider
+		switch k.opts.SASLMechanism {
+		case sarama.SASLTypeSCRAMSHA256:
+			config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: scram.SHA256} }
+		case sarama.SASLTypeSCRAMSHA512:
+			config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &scramClient{hashGenerator: scram.SHA512} }
+		}
+	}
 	config.Version = apiVersion
 	config.ClientID = clientId
 	return
@@ -17,6 +17,8 @@ package kafkaclient
 import (
 	"crypto/tls"
 	"testing"
+
+	"github.com/Shopify/sarama"
 )
 
 func TestNew(t *testing.T) {
@@ -66,4 +68,16 @@ func TestGetSaramaConfig(t *testing.T) {
 	if conf.Net.TLS.Enable != true {
 		t.Error("Expected sarama config with TLS enabled, got false")
 	}
+
+	client.opts.UseSASL = true
+	client.opts.SASLMechanism = sarama.SASLTypeSCRAMSHA256
+	client.opts.SASLUser = "koperator"
+	client.opts.SASLPassword = "secret"
+	conf = client.getSaramaConfig()
+	if !conf.Net.SASL.Enable || conf.Net.SASL.SCRAMClientGeneratorFunc == nil {
+		t.Error("Expected sarama config with SCRAM enabled")
+	}
+	if err := conf.Validate(); err != nil {
+		t.Error("Expected valid sarama config, got:", err)
+	}
 }
@@ -16,8 +16,10 @@ package kafkaclient
 
 import (
 	"crypto/tls"
+	"fmt"
 
 	"emperror.dev/errors"
+	"github.com/Shopify/sarama"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,15 +37,25 @@ type KafkaConfig struct {
 	UseSSL    bool
 	TLSConfig *tls.Config
 
+	UseSASL           bool
+	SASLMechanism     sarama.SASLMechanism
+	SASLUser          string
+	SASLPassword      string
+	SASLTokenProvider sarama.AccessTokenProvider
+
 	OperationTimeout int64
 }
 
 // ClusterConfig creates connection options from a KafkaCluster CR
 func ClusterConfig(client client.Client, cluster *v1beta1.KafkaCluster) (*KafkaConfig, error) {
 	conf := &KafkaConfig{}
-	conf.BrokerURI = clientutil.GenerateKafkaAddress(cluster)
 	conf.OperationTimeout = kafkaDefaultTimeout
-	if clientutil.UseSSL(cluster) {
+	listener, err := clientutil.AdminClientListener(cluster)
+	if err != nil {
+		return conf, err
+	}
+	conf.BrokerURI = fmt.Sprintf("%s:%d", clientutil.GenerateKafkaAddressWithoutPort(cluster), listener.ContainerPort)
+	if listener.Type.IsSSL() {
 		var tlsConfig *tls.Config
 		var err error
 		if cluster.Spec.GetClientSSLCertSecretName() != "" {
@@ -59,5 +71,10 @@ func ClusterConfig(client client.Client, cluster *v1beta1.KafkaCluster) (*KafkaC
 		conf.UseSSL = true
 		conf.TLSConfig = tlsConfig
 	}
+	if listener.Type.IsSasl() {
+		if err := setClientSASLConfig(client, cluster, conf); err != nil {
+			return conf, err
+		}
+	}
 	return conf, nil
 }
@@ -17,7 +17,11 @@ package kafkaclient
 import (
 	"testing"
 
+	"github.com/Shopify/sarama"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/pki"
@@ -50,3 +54,77 @@ func TestClusterConfig(t *testing.T) {
 		t.Error("Expected no error got:", err)
 	}
 }
+
+func TestClusterConfigSASL(t *testing.T) {
+	cluster := newMockCluster()
+	cluster.Spec.ListenersConfig.InternalListeners = []v1beta1.InternalListenerConfig{
+		{
+			CommonListenerSpec: v1beta1.CommonListenerSpec{
+				Name:          "controller",
+				Type:          v1beta1.SecurityProtocolPlaintext,
+				ContainerPort: 29093,
+			},
+			UsedForControllerCommunication: true,
+		},
+		{
+			CommonListenerSpec: v1beta1.CommonListenerSpec{
+				Name:          "internal",
+				Type:          v1beta1.SecurityProtocolSaslPlaintext,
+				ContainerPort: 29092,
+			},
+			UsedForInnerBrokerCommunication: true,
+		},
+	}
+
+	if _, err := ClusterConfig(&mockClient{}, cluster); err == nil {
+		t.Error("Expected error for SASL listener without client SASL config, got nil")
+	}
+
+	cluster.Spec.ClientSASL = &v1beta1.ClientSASLConfig{
+		Mechanism:         v1beta1.SASLMechanismScramSHA512,
+		CredentialsSecret: "koperator-sasl",
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "koperator-sasl", Namespace: cluster.Namespace},
+		Data: map[string][]byte{
+			clientSASLUsernameKey: []byte("koperator"),
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	if _, err := ClusterConfig(k8sClient, cluster); err == nil {
+		t.Error("Expected error for incomplete credentials secret, got nil")
+	}
+
+	secret.Data[clientSASLPasswordKey] = []byte("secret")
+	k8sClient = fake.NewClientBuilder().WithObjects(secret).Build()
+	conf, err := ClusterConfig(k8sClient, cluster)
+	if err != nil {
+		t.Fatal("Expected no error got:", err)
+	}
+	if conf.BrokerURI != "test-all-broker.test.svc.cluster.local:29092" {
+		t.Error("Expected broker URI of the internal listener, got:", conf.BrokerURI)
+	}
+	if !conf.UseSASL || conf.UseSSL || conf.SASLMechanism != sarama.SASLTypeSCRAMSHA512 || conf.SASLUser != "koperator" || conf.SASLPassword != "secret" {
+		t.Error("Expected SCRAM-SHA-512 SASL config with the credentials of the secret, got:", conf)
+	}
+
+	cluster.Spec.ClientSASL = &v1beta1.ClientSASLConfig{
+		Mechanism:         v1beta1.SASLMechanismOAuthBearer,
+		CredentialsSecret: "koperator-sasl",
+		TokenEndpointURL:  "https://idp.example.com/token",
+	}
+	if _, err := ClusterConfig(k8sClient, cluster); err == nil {
+		t.Error("Expected error for missing client credentials, got nil")
+	}
+
+	secret.Data[clientSASLClientIDKey] = []byte("koperator")
+	secret.Data[clientSASLClientSecretKey] = []byte("secret")
+	k8sClient = fake.NewClientBuilder().WithObjects(secret).Build()
+	conf, err = ClusterConfig(k8sClient, cluster)
+	if err != nil {
+		t.Fatal("Expected no error got:", err)
+	}
+	if !conf.UseSASL || conf.SASLMechanism != sarama.SASLTypeOAuth || conf.SASLTokenProvider == nil {
+		t.Error("Expected OAUTHBEARER SASL config with a token provider, got:", conf)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaclient
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/Shopify/sarama"
+	"github.com/xdg-go/scram"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+)
+
+const (
+	clientSASLUsernameKey     = "username"
+	clientSASLPasswordKey     = "password"
+	clientSASLClientIDKey     = "clientId"
+	clientSASLClientSecretKey = "clientSecret"
+)
+
+// setClientSASLConfig sets the SASL options from the credentials referenced by the clientSASL field of the cluster
+func setClientSASLConfig(client client.Client, cluster *v1beta1.KafkaCluster, conf *KafkaConfig) error {
+	clientSASL := cluster.Spec.ClientSASL
+	if clientSASL == nil {
+		return errors.New("'clientSASL' must be specified as internal listener used by koperator uses SASL")
+	}
+
+	secret := &corev1.Secret{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: clientSASL.CredentialsSecret, Namespace: cluster.Namespace}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			err = errorfactory.New(errorfactory.ResourceNotReady{}, err, "client SASL credentials secret not found")
+		}
+		return err
+	}
+	credentials := func(keys ...string) ([]string, error) {
+		values := make([]string, 0, len(keys))
+		for _, key := range keys {
+			value, ok := secret.Data[key]
+			if !ok {
+				return nil, errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("secret key not found"),
+					"client SASL credentials secret is incomplete", "secret", secret.Name, "key", key)
+			}
+			values = append(values, string(value))
+		}
+		return values, nil
+	}
+
+	switch clientSASL.Mechanism {
+	case v1beta1.SASLMechanismScramSHA256, v1beta1.SASLMechanismScramSHA512:
+		values, err := credentials(clientSASLUsernameKey, clientSASLPasswordKey)
+		if err != nil {
+			return err
+		}
+		conf.SASLMechanism = sarama.SASLTypeSCRAMSHA256
+		if clientSASL.Mechanism == v1beta1.SASLMechanismScramSHA512 {
+			conf.SASLMechanism = sarama.SASLTypeSCRAMSHA512
+		}
+		conf.SASLUser, conf.SASLPassword = values[0], values[1]
+	case v1beta1.SASLMechanismOAuthBearer:
+		if clientSASL.TokenEndpointURL == "" {
+			return errors.New("'tokenEndpointURL' must be specified for the OAUTHBEARER client SASL mechanism")
+		}
+		values, err := credentials(clientSASLClientIDKey, clientSASLClientSecretKey)
+		if err != nil {
+			return err
+		}
+		conf.SASLMechanism = sarama.SASLTypeOAuth
+		conf.SASLTokenProvider = &oauthTokenProvider{
+			tokenSource: (&clientcredentials.Config{
+				ClientID:     values[0],
+				ClientSecret: values[1],
+				TokenURL:     clientSASL.TokenEndpointURL,
+				Scopes:       clientSASL.Scopes,
+			}).TokenSource(context.Background()),
+		}
+	default:
+		return errors.NewWithDetails("unsupported client SASL mechanism", "mechanism", clientSASL.Mechanism)
+	}
+	conf.UseSASL = true
+	return nil
+}
+
+// oauthTokenProvider provides sarama with the access tokens of the client credentials grant,
+// the token source caches the token until it expires
+type oauthTokenProvider struct {
+	tokenSource oauth2.TokenSource
+}
+
+func (p *oauthTokenProvider) Token() (*sarama.AccessToken, error) {
+	token, err := p.tokenSource.Token()
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not get access token for the OAUTHBEARER client SASL mechanism")
+	}
+	return &sarama.AccessToken{Token: token.AccessToken}, nil
+}
+
+// scramClient implements sarama.SCRAMClient
+type scramClient struct {
+	hashGenerator scram.HashGeneratorFcn
+	conversation  *scram.ClientConversation
+}
+
+func (c *scramClient) Begin(userName, password, authzID string) error {
+	client, err := c.hashGenerator.NewClient(userName, password, authzID)
+	if err != nil {
+		return err
+	}
+	c.conversation = client.NewConversation()
+	return nil
+}
+
+func (c *scramClient) Step(challenge string) (string, error) {
+	return c.conversation.Step(challenge)
+}
+
+func (c *scramClient) Done() bool {
+	return c.conversation.Done()
+}
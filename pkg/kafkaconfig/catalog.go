@@ -0,0 +1,237 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaconfig
+
+import (
+	_ "embed"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+// Scope is the dynamic update mode of a broker configuration
+type Scope string
+
+const (
+	// ScopeReadOnly configurations require a broker restart to be updated
+	ScopeReadOnly Scope = "read-only"
+	// ScopePerBroker configurations can be updated dynamically for each broker
+	ScopePerBroker Scope = "per-broker"
+	// ScopeClusterWide configurations can be updated dynamically as a cluster-wide default, or for each broker
+	ScopeClusterWide Scope = "cluster-wide"
+)
+
+// Type is the type of the value of a configuration
+type Type string
+
+const (
+	TypeBoolean  Type = "boolean"
+	TypeInt      Type = "int"
+	TypeShort    Type = "short"
+	TypeLong     Type = "long"
+	TypeDouble   Type = "double"
+	TypeString   Type = "string"
+	TypeList     Type = "list"
+	TypeClass    Type = "class"
+	TypePassword Type = "password"
+)
+
+// Config describes a broker or topic configuration of Kafka
+type Config struct {
+	Type  Type  `json:"type"`
+	Scope Scope `json:"scope,omitempty"`
+	// Min and Max are the bounds of the valid range of numeric configurations
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// ValidValues are the valid values of string configurations and the valid elements of list configurations
+	ValidValues []string `json:"validValues,omitempty"`
+	// Since is the first Kafka version the configuration is available in
+	Since string `json:"since,omitempty"`
+	// BrokerConfig is the broker configuration providing the default value of a topic configuration
+	BrokerConfig string `json:"brokerConfig,omitempty"`
+}
+
+type catalogData struct {
+	Broker map[string]Config `json:"broker"`
+	Topic  map[string]Config `json:"topic"`
+}
+
+var (
+	//go:embed catalog.yaml
+	catalogYaml []byte
+
+	catalog = mustLoadCatalog(catalogYaml)
+)
+
+func mustLoadCatalog(data []byte) catalogData {
+	var c catalogData
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		panic("could not load the Kafka configuration catalog: " + err.Error())
+	}
+	return c
+}
+
+// Catalog holds the broker and topic configurations available in a Kafka version
+type Catalog struct {
+	version kafkaVersion
+	broker  map[string]Config
+	topic   map[string]Config
+}
+
+// ForVersion returns the catalog of the given Kafka version. When the version is empty or can not be parsed,
+// the catalog contains every configuration known by the operator.
+func ForVersion(version string) *Catalog {
+	v, _ := parseKafkaVersion(version)
+	return &Catalog{
+		version: v,
+		broker:  catalog.Broker,
+		topic:   catalog.Topic,
+	}
+}
+
+// ForCluster returns the catalog of the lowest Kafka version running in the cluster
+func ForCluster(cluster *v1beta1.KafkaCluster) *Catalog {
+	var lowest kafkaVersion
+	for _, brokerState := range cluster.Status.BrokersState {
+		v, ok := parseKafkaVersion(brokerState.Version)
+		if !ok {
+			continue
+		}
+		if lowest.isZero() || v.less(lowest) {
+			lowest = v
+		}
+	}
+	return &Catalog{
+		version: lowest,
+		broker:  catalog.Broker,
+		topic:   catalog.Topic,
+	}
+}
+
+// BrokerConfig returns the description of the given broker configuration
+func (c *Catalog) BrokerConfig(name string) (Config, bool) {
+	config, ok := c.broker[name]
+	return config, ok
+}
+
+// TopicConfig returns the description of the given topic configuration
+func (c *Catalog) TopicConfig(name string) (Config, bool) {
+	config, ok := c.topic[name]
+	return config, ok
+}
+
+// isAvailable returns false if the configuration is not available in the Kafka version of the catalog
+func (c *Catalog) isAvailable(config Config) bool {
+	if c.version.isZero() || config.Since == "" {
+		return true
+	}
+	since, ok := parseKafkaVersion(config.Since)
+	return !ok || !c.version.less(since)
+}
+
+// kafkaVersion is the major and minor version of Kafka, the zero value stands for an unknown version
+type kafkaVersion struct {
+	major int
+	minor int
+}
+
+func (v kafkaVersion) isZero() bool {
+	return v.major == 0 && v.minor == 0
+}
+
+func (v kafkaVersion) less(o kafkaVersion) bool {
+	if v.major != o.major {
+		return v.major < o.major
+	}
+	return v.minor < o.minor
+}
+
+// parseKafkaVersion parses the major and minor version from versions like "3.4", "3.4.1" or "3.4.1-rc1"
+func parseKafkaVersion(version string) (kafkaVersion, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".", 3)
+	if len(parts) < 2 {
+		return kafkaVersion{}, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return kafkaVersion{}, false
+	}
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return kafkaVersion{}, false
+	}
+	return kafkaVersion{major: major, minor: minor}, true
+}
+
+// validateValue checks the value against the type, range and valid values of the configuration
+func validateValue(config Config, value string) string {
+	value = strings.TrimSpace(value)
+	switch config.Type {
+	case TypeBoolean:
+		if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "false") {
+			return "value must be a boolean"
+		}
+	case TypeInt, TypeShort, TypeLong:
+		bitSize := map[Type]int{TypeInt: 32, TypeShort: 16, TypeLong: 64}[config.Type]
+		number, err := strconv.ParseInt(value, 10, bitSize)
+		if err != nil {
+			return "value must be a valid " + string(config.Type)
+		}
+		return validateRange(config, float64(number))
+	case TypeDouble:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) {
+			return "value must be a valid double"
+		}
+		return validateRange(config, number)
+	case TypeList:
+		if len(config.ValidValues) == 0 || value == "" {
+			return ""
+		}
+		for _, element := range strings.Split(value, ",") {
+			if !isValidValue(config, strings.TrimSpace(element)) {
+				return "list elements must be one of " + strings.Join(config.ValidValues, ", ")
+			}
+		}
+	case TypeString:
+		if len(config.ValidValues) > 0 && !isValidValue(config, value) {
+			return "value must be one of " + strings.Join(config.ValidValues, ", ")
+		}
+	}
+	return ""
+}
+
+func validateRange(config Config, number float64) string {
+	if config.Min != nil && number < *config.Min {
+		return "value must be at least " + strconv.FormatFloat(*config.Min, 'f', -1, 64)
+	}
+	if config.Max != nil && number > *config.Max {
+		return "value must be at most " + strconv.FormatFloat(*config.Max, 'f', -1, 64)
+	}
+	return ""
+}
+
+func isValidValue(config Config, value string) bool {
+	for _, validValue := range config.ValidValues {
+		if value == validValue {
+			return true
+		}
+	}
+	return false
+}
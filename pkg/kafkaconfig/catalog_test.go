@@ -0,0 +1,115 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func TestParseKafkaVersion(t *testing.T) {
+	testCases := []struct {
+		version  string
+		expected kafkaVersion
+		ok       bool
+	}{
+		{version: "3.4", expected: kafkaVersion{major: 3, minor: 4}, ok: true},
+		{version: "3.4.1", expected: kafkaVersion{major: 3, minor: 4}, ok: true},
+		{version: "v2.8.2", expected: kafkaVersion{major: 2, minor: 8}, ok: true},
+		{version: "3.6-rc1", expected: kafkaVersion{major: 3, minor: 6}, ok: true},
+		{version: "", ok: false},
+		{version: "3", ok: false},
+		{version: "latest", ok: false},
+	}
+
+	for _, test := range testCases {
+		version, ok := parseKafkaVersion(test.version)
+		require.Equal(t, test.ok, ok, test.version)
+		require.Equal(t, test.expected, version, test.version)
+	}
+}
+
+func TestForCluster(t *testing.T) {
+	cluster := &v1beta1.KafkaCluster{
+		Status: v1beta1.KafkaClusterStatus{
+			BrokersState: map[string]v1beta1.BrokerState{
+				"0": {Version: "3.6.1"},
+				"1": {Version: "3.5.0"},
+				"2": {Version: ""},
+			},
+		},
+	}
+	require.Equal(t, kafkaVersion{major: 3, minor: 5}, ForCluster(cluster).version)
+	require.True(t, ForCluster(&v1beta1.KafkaCluster{}).version.isZero())
+}
+
+func TestCatalogLoaded(t *testing.T) {
+	catalog := ForVersion("3.6")
+
+	config, ok := catalog.BrokerConfig("log.retention.hours")
+	require.True(t, ok)
+	require.Equal(t, TypeInt, config.Type)
+	require.Equal(t, ScopeReadOnly, config.Scope)
+
+	config, ok = catalog.TopicConfig("retention.ms")
+	require.True(t, ok)
+	require.Equal(t, "log.retention.ms", config.BrokerConfig)
+
+	for name, config := range catalog.broker {
+		require.Contains(t, []Scope{ScopeReadOnly, ScopePerBroker, ScopeClusterWide}, config.Scope, name)
+	}
+	for name, config := range catalog.topic {
+		if config.BrokerConfig != "" {
+			_, ok := catalog.BrokerConfig(config.BrokerConfig)
+			require.True(t, ok, name)
+		}
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	minOne := float64(1)
+	maxTen := float64(10)
+
+	testCases := []struct {
+		testName string
+		config   Config
+		value    string
+		expected string
+	}{
+		{testName: "valid boolean", config: Config{Type: TypeBoolean}, value: "True"},
+		{testName: "invalid boolean", config: Config{Type: TypeBoolean}, value: "yes", expected: "value must be a boolean"},
+		{testName: "valid int", config: Config{Type: TypeInt, Min: &minOne, Max: &maxTen}, value: " 5 "},
+		{testName: "invalid int", config: Config{Type: TypeInt}, value: "5h", expected: "value must be a valid int"},
+		{testName: "int overflow", config: Config{Type: TypeInt}, value: "4294967296", expected: "value must be a valid int"},
+		{testName: "valid long", config: Config{Type: TypeLong}, value: "4294967296"},
+		{testName: "below min", config: Config{Type: TypeInt, Min: &minOne}, value: "0", expected: "value must be at least 1"},
+		{testName: "above max", config: Config{Type: TypeDouble, Max: &maxTen}, value: "10.5", expected: "value must be at most 10"},
+		{testName: "invalid double", config: Config{Type: TypeDouble}, value: "NaN", expected: "value must be a valid double"},
+		{testName: "valid string", config: Config{Type: TypeString, ValidValues: []string{"a", "b"}}, value: "b"},
+		{testName: "invalid string", config: Config{Type: TypeString, ValidValues: []string{"a", "b"}}, value: "c", expected: "value must be one of a, b"},
+		{testName: "valid list", config: Config{Type: TypeList, ValidValues: []string{"compact", "delete"}}, value: "compact, delete"},
+		{testName: "invalid list", config: Config{Type: TypeList, ValidValues: []string{"compact", "delete"}}, value: "compact,remove", expected: "list elements must be one of compact, delete"},
+		{testName: "free-form list", config: Config{Type: TypeList}, value: "anything"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			require.Equal(t, test.expected, validateValue(test.config, test.value))
+		})
+	}
+}
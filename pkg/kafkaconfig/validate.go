@@ -0,0 +1,235 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Section is the part of the custom resources a configuration is set in
+type Section string
+
+const (
+	// SectionReadOnly is the static broker configuration: readOnlyConfig of the cluster or of the brokers
+	SectionReadOnly Section = "readOnlyConfig"
+	// SectionClusterWide is the cluster-wide dynamic broker configuration: clusterWideConfig of the cluster
+	SectionClusterWide Section = "clusterWideConfig"
+	// SectionPerBroker is the per-broker dynamic broker configuration: config of the brokerConfig
+	SectionPerBroker Section = "config"
+	// SectionTopic is the configuration of a KafkaTopic
+	SectionTopic Section = "topic"
+)
+
+// maxSuggestionDistance is the maximum edit distance between an unknown configuration and a known one
+// which makes the unknown configuration considered as a typo
+const maxSuggestionDistance = 2
+
+// pluginConfigPrefixes are prefixes of broker configurations which are consumed by plugins, so they are not validated
+var pluginConfigPrefixes = []string{
+	"config.providers.",
+	"cruise.control.metrics.reporter.",
+	"rsm.config.",
+	"rlmm.config.",
+}
+
+// Violation describes a configuration which is invalid in the section it is set in
+type Violation struct {
+	Key     string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Key, v.Message)
+}
+
+// Validate validates the configurations set in the given section against the catalog.
+// Unknown configurations are only reported when they are likely typos of known configurations,
+// since brokers and plugins accept configurations which are not listed in the catalog.
+func (c *Catalog) Validate(section Section, config map[string]string) []Violation {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var violations []Violation
+	for _, key := range keys {
+		var message string
+		if section == SectionTopic {
+			message = c.validateTopicConfig(key, config[key])
+		} else {
+			message = c.validateBrokerConfig(section, key, config[key])
+		}
+		if message != "" {
+			violations = append(violations, Violation{Key: key, Message: message})
+		}
+	}
+	return violations
+}
+
+func (c *Catalog) validateBrokerConfig(section Section, key, value string) string {
+	for _, prefix := range pluginConfigPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return ""
+		}
+	}
+
+	config, listenerSpecific, ok := c.lookupBrokerConfig(key)
+	if !ok {
+		if listenerSpecific {
+			return ""
+		}
+		return suggest(key, c.broker)
+	}
+	if !c.isAvailable(config) {
+		return fmt.Sprintf("configuration is not available before Kafka %s", config.Since)
+	}
+
+	scope := config.Scope
+	if listenerSpecific && scope == ScopeClusterWide {
+		scope = ScopePerBroker
+	}
+	switch {
+	case scope == ScopeReadOnly && section != SectionReadOnly:
+		return "read-only configuration can not be updated dynamically, it must be set in readOnlyConfig"
+	case scope == ScopePerBroker && section == SectionClusterWide:
+		return "configuration can only be updated per broker, it must be set in the config of the brokerConfig or in readOnlyConfig"
+	}
+
+	if isConfigProviderReference(value) {
+		return ""
+	}
+	return validateValue(config, value)
+}
+
+func (c *Catalog) validateTopicConfig(key, value string) string {
+	config, ok := c.topic[key]
+	if !ok {
+		if _, isBrokerConfig := c.broker[key]; isBrokerConfig {
+			topicKey := ""
+			for name, topicConfig := range c.topic {
+				if topicConfig.BrokerConfig == key && (topicKey == "" || name < topicKey) {
+					topicKey = name
+				}
+			}
+			if topicKey != "" {
+				return fmt.Sprintf("broker configuration can not be set for a topic, use %s instead", topicKey)
+			}
+			return "broker configuration can not be set for a topic"
+		}
+		return suggest(key, c.topic)
+	}
+	if !c.isAvailable(config) {
+		return fmt.Sprintf("configuration is not available before Kafka %s", config.Since)
+	}
+	if isConfigProviderReference(value) {
+		return ""
+	}
+	return validateValue(config, value)
+}
+
+// BrokerConfigScope returns the dynamic update mode of the broker configuration. Listener-specific configurations
+// can only be updated per broker, unknown configurations are considered to be read-only.
+func (c *Catalog) BrokerConfigScope(key string) Scope {
+	config, listenerSpecific, ok := c.lookupBrokerConfig(key)
+	if !ok || config.Scope == "" {
+		return ScopeReadOnly
+	}
+	if listenerSpecific && config.Scope == ScopeClusterWide {
+		return ScopePerBroker
+	}
+	return config.Scope
+}
+
+// lookupBrokerConfig returns the description of the broker configuration and whether it is listener-specific
+func (c *Catalog) lookupBrokerConfig(key string) (Config, bool, bool) {
+	name, listenerSpecific := trimListenerPrefix(key)
+	config, ok := c.broker[name]
+	if !ok && listenerSpecific {
+		// SASL configurations of listeners are prefixed with the SASL mechanism as well
+		if i := strings.Index(name, "."); i > 0 {
+			name = name[i+1:]
+			config, ok = c.broker[name]
+		}
+	}
+	return config, listenerSpecific, ok
+}
+
+// trimListenerPrefix trims the listener.name.<listener name>. prefix of listener-specific configurations
+func trimListenerPrefix(key string) (string, bool) {
+	const listenerPrefix = "listener.name."
+	if !strings.HasPrefix(key, listenerPrefix) {
+		return key, false
+	}
+	rest := strings.TrimPrefix(key, listenerPrefix)
+	i := strings.Index(rest, ".")
+	if i <= 0 || i == len(rest)-1 {
+		return key, false
+	}
+	return rest[i+1:], true
+}
+
+// isConfigProviderReference returns true if the value is resolved by a config provider (e.g. ${file:/path:key})
+func isConfigProviderReference(value string) bool {
+	return strings.Contains(value, "${")
+}
+
+// suggest returns a violation message when the unknown key is a likely typo of a known configuration
+func suggest(key string, known map[string]Config) string {
+	closest := ""
+	closestDistance := maxSuggestionDistance + 1
+	for name := range known {
+		if distance := levenshtein(key, name); distance < closestDistance || (distance == closestDistance && name < closest) {
+			closest = name
+			closestDistance = distance
+		}
+	}
+	if closest == "" || closestDistance > maxSuggestionDistance {
+		return ""
+	}
+	return fmt.Sprintf("unknown configuration, did you mean %s?", closest)
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,153 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		testName string
+		version  string
+		section  Section
+		config   map[string]string
+		expected []Violation
+	}{
+		{
+			testName: "valid configurations",
+			section:  SectionReadOnly,
+			config: map[string]string{
+				"auto.create.topics.enable":                    "false",
+				"log.retention.hours":                          "168",
+				"listener.name.internal.ssl.keystore.location": "/var/run/secrets/keystore.jks",
+				"some.plugin.specific.configuration":           "value",
+			},
+		},
+		{
+			testName: "typo of a known configuration",
+			section:  SectionReadOnly,
+			config:   map[string]string{"log.retention.hour": "168"},
+			expected: []Violation{{Key: "log.retention.hour", Message: "unknown configuration, did you mean log.retention.hours?"}},
+		},
+		{
+			testName: "invalid value",
+			section:  SectionReadOnly,
+			config:   map[string]string{"log.retention.hours": "7d", "auto.create.topics.enable": "no"},
+			expected: []Violation{
+				{Key: "auto.create.topics.enable", Message: "value must be a boolean"},
+				{Key: "log.retention.hours", Message: "value must be a valid int"},
+			},
+		},
+		{
+			testName: "read-only configuration in clusterWideConfig",
+			section:  SectionClusterWide,
+			config:   map[string]string{"auto.create.topics.enable": "false"},
+			expected: []Violation{{Key: "auto.create.topics.enable", Message: "read-only configuration can not be updated dynamically, it must be set in readOnlyConfig"}},
+		},
+		{
+			testName: "read-only configuration in per-broker config",
+			section:  SectionPerBroker,
+			config:   map[string]string{"log.retention.hours": "168"},
+			expected: []Violation{{Key: "log.retention.hours", Message: "read-only configuration can not be updated dynamically, it must be set in readOnlyConfig"}},
+		},
+		{
+			testName: "per-broker configuration in clusterWideConfig",
+			section:  SectionClusterWide,
+			config:   map[string]string{"ssl.keystore.location": "/keystore.jks"},
+			expected: []Violation{{Key: "ssl.keystore.location", Message: "configuration can only be updated per broker, it must be set in the config of the brokerConfig or in readOnlyConfig"}},
+		},
+		{
+			testName: "listener-specific cluster-wide configuration in clusterWideConfig",
+			section:  SectionClusterWide,
+			config:   map[string]string{"listener.name.external.max.connections": "100"},
+			expected: []Violation{{Key: "listener.name.external.max.connections", Message: "configuration can only be updated per broker, it must be set in the config of the brokerConfig or in readOnlyConfig"}},
+		},
+		{
+			testName: "cluster-wide and per-broker configurations in per-broker config",
+			section:  SectionPerBroker,
+			config:   map[string]string{"min.insync.replicas": "2", "ssl.keystore.location": "/keystore.jks"},
+		},
+		{
+			testName: "config provider reference",
+			section:  SectionReadOnly,
+			config:   map[string]string{"num.partitions": "${file:/etc/kafka/secrets.properties:partitions}"},
+		},
+		{
+			testName: "plugin configurations",
+			section:  SectionReadOnly,
+			config: map[string]string{
+				"config.providers.file.class":                       "org.apache.kafka.common.config.provider.FileConfigProvider",
+				"cruise.control.metrics.reporter.bootstrap.servers": "kafka:29092",
+			},
+		},
+		{
+			testName: "configuration not available in the Kafka version",
+			version:  "3.5.1",
+			section:  SectionReadOnly,
+			config:   map[string]string{"remote.log.storage.system.enable": "true"},
+			expected: []Violation{{Key: "remote.log.storage.system.enable", Message: "configuration is not available before Kafka 3.6"}},
+		},
+		{
+			testName: "configuration available in the Kafka version",
+			version:  "3.6.0",
+			section:  SectionReadOnly,
+			config:   map[string]string{"remote.log.storage.system.enable": "true"},
+		},
+		{
+			testName: "valid topic configurations",
+			section:  SectionTopic,
+			config:   map[string]string{"cleanup.policy": "compact,delete", "retention.ms": "-1", "min.insync.replicas": "2"},
+		},
+		{
+			testName: "broker configuration set for a topic",
+			section:  SectionTopic,
+			config:   map[string]string{"log.retention.ms": "1000"},
+			expected: []Violation{{Key: "log.retention.ms", Message: "broker configuration can not be set for a topic, use retention.ms instead"}},
+		},
+		{
+			testName: "invalid topic configurations",
+			section:  SectionTopic,
+			config:   map[string]string{"cleanup.policy": "remove", "retention.m": "1000"},
+			expected: []Violation{
+				{Key: "cleanup.policy", Message: "list elements must be one of compact, delete"},
+				{Key: "retention.m", Message: "unknown configuration, did you mean retention.ms?"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.testName, func(t *testing.T) {
+			require.Equal(t, test.expected, ForVersion(test.version).Validate(test.section, test.config))
+		})
+	}
+}
+
+func TestBrokerConfigScope(t *testing.T) {
+	catalog := ForVersion("")
+	for key, expected := range map[string]Scope{
+		"broker.id":               ScopeReadOnly,
+		"ssl.truststore.password": ScopePerBroker,
+		"log.retention.ms":        ScopeClusterWide,
+		"listener.name.external.ssl.truststore.password": ScopePerBroker,
+		"listener.name.sasl_ssl.plain.sasl.jaas.config":  ScopePerBroker,
+		"rsm.config.access.key":                          ScopeReadOnly,
+	} {
+		require.Equal(t, expected, catalog.BrokerConfigScope(key), key)
+	}
+}
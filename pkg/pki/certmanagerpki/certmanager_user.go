@@ -15,13 +15,17 @@
 package certmanagerpki
 
 import (
+	"bytes"
 	"context"
+	"encoding/pem"
 	"fmt"
+	"reflect"
 	"time"
 
 	"emperror.dev/errors"
 
 	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/errorfactory"
 	"github.com/banzaicloud/koperator/pkg/resources/templates"
 
@@ -48,12 +52,12 @@ func (c *certManager) ReconcileUserCertificate(
 	var err error
 	var secret *corev1.Secret
 	// See if we have an existing certificate for this user already
-	_, err = c.getUserCertificate(ctx, user)
+	existing, err := c.getUserCertificate(ctx, user)
 
 	if err != nil && apierrors.IsNotFound(err) {
 		// the certificate does not exist, let's make one
-		// check if jks is required and create password for it
-		if user.Spec.IncludeJKS {
+		// check if keystores are required and create password for them
+		if len(user.Spec.GetKeystoreFormats()) > 0 {
 			if err := c.injectJKSPassword(ctx, user); err != nil {
 				return nil, err
 			}
@@ -65,6 +69,17 @@ func (c *certManager) ReconcileUserCertificate(
 	} else if err != nil {
 		// API failure, requeue
 		return nil, errorfactory.New(errorfactory.APIFailure{}, err, "failed looking up user certificate")
+	} else if keystores := certificateKeystoresForUser(user); !reflect.DeepEqual(existing.Spec.Keystores, keystores) {
+		// keystore formats have been added or removed since the certificate was created
+		if keystores != nil {
+			if err = c.ensureKeystorePassword(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+		existing.Spec.Keystores = keystores
+		if err = c.client.Update(ctx, existing); err != nil {
+			return nil, errorfactory.New(errorfactory.APIFailure{}, err, "could not update keystores of user certificate")
+		}
 	}
 
 	// Get the secret created from the certificate
@@ -78,6 +93,13 @@ func (c *certManager) ReconcileUserCertificate(
 		return nil, err
 	}
 
+	// cert-manager is not capable of creating PEM bundles with an encrypted private key
+	if user.Spec.HasKeystoreFormat(v1beta1.KeystoreFormatPEMBundle) {
+		if err = c.ensurePEMBundle(ctx, secret); err != nil {
+			return nil, err
+		}
+	}
+
 	return &pkicommon.UserCertificate{
 		CA:          secret.Data[v1alpha1.CoreCACertKey],
 		Certificate: secret.Data[corev1.TLSCertKey],
@@ -85,7 +107,7 @@ func (c *certManager) ReconcileUserCertificate(
 	}, nil
 }
 
-// injectJKSPassword ensures that a secret contains JKS password when requested
+// injectJKSPassword ensures that a secret contains the keystore password when keystores are requested
 func (c *certManager) injectJKSPassword(ctx context.Context, user *v1alpha1.KafkaUser) error {
 	var err error
 	secret := &corev1.Secret{
@@ -106,6 +128,70 @@ func (c *certManager) injectJKSPassword(ctx context.Context, user *v1alpha1.Kafk
 	return nil
 }
 
+// ensureKeystorePassword adds a keystore password to the secret of an existing certificate when it has none
+func (c *certManager) ensureKeystorePassword(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	secret := &corev1.Secret{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return c.injectJKSPassword(ctx, user)
+		}
+		return errorfactory.New(errorfactory.APIFailure{}, err, "failed to get user secret")
+	}
+	if len(secret.Data[v1alpha1.PasswordKey]) > 0 {
+		return nil
+	}
+	secret, err := certutil.EnsureSecretPassJKS(secret)
+	if err != nil {
+		return errorfactory.New(errorfactory.InternalError{}, err, "could not inject secret with keystore password")
+	}
+	if err = c.client.Update(ctx, secret); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not update secret with keystore password")
+	}
+	return nil
+}
+
+// ensurePEMBundle renders the PEM bundle of the certificate issued by cert-manager into the user secret
+func (c *certManager) ensurePEMBundle(ctx context.Context, secret *corev1.Secret) error {
+	certs, err := certutil.ParseCertificates(append(append(append([]byte{}, secret.Data[corev1.TLSCertKey]...), '\n'),
+		secret.Data[v1alpha1.CoreCACertKey]...))
+	if err != nil {
+		return errorfactory.New(errorfactory.InternalError{}, err, "could not parse user certificate")
+	}
+	if pemBundleHoldsCertificates(secret.Data[v1alpha1.TLSPEMKeyStore], certs) && len(secret.Data[v1alpha1.TLSPEMTrustStore]) > 0 {
+		return nil
+	}
+	keystore, truststore, err := certutil.GeneratePEMBundle(certutil.GetCertBundle(certs),
+		secret.Data[corev1.TLSPrivateKeyKey], secret.Data[v1alpha1.PasswordKey])
+	if err != nil {
+		return errorfactory.New(errorfactory.InternalError{}, err, "could not generate PEM bundle")
+	}
+	secret.Data[v1alpha1.TLSPEMKeyStore] = keystore
+	secret.Data[v1alpha1.TLSPEMTrustStore] = truststore
+	if err = c.client.Update(ctx, secret); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "could not update secret with PEM bundle")
+	}
+	return nil
+}
+
+// pemBundleHoldsCertificates returns true if the PEM bundle holds exactly the given certificate chain
+func pemBundleHoldsCertificates(bundle []byte, certs []*certutil.CertificateContainer) bool {
+	var raws [][]byte
+	for block, rest := pem.Decode(bundle); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			raws = append(raws, block.Bytes)
+		}
+	}
+	if len(raws) != len(certs) {
+		return false
+	}
+	for i := range certs {
+		if !bytes.Equal(raws[i], certs[i].Certificate.Raw) {
+			return false
+		}
+	}
+	return true
+}
+
 // getUserCertificate fetches the cert-manager Certificate for a user
 func (c *certManager) getUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) (*certv1.Certificate, error) {
 	cert := &certv1.Certificate{}
@@ -123,12 +209,15 @@ func (c *certManager) getUserSecret(ctx context.Context, user *v1alpha1.KafkaUse
 		}
 		return secret, errorfactory.New(errorfactory.APIFailure{}, err, "failed to get user secret")
 	}
-	if user.Spec.IncludeJKS {
-		if len(secret.Data) != 6 {
-			return secret, errorfactory.New(errorfactory.ResourceNotReady{}, err, "user secret not populated yet")
+	requiredFields := []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, v1alpha1.CoreCACertKey}
+	for _, format := range user.Spec.GetKeystoreFormats() {
+		// the PEM bundle is rendered by the operator once the certificate is issued
+		if format != v1beta1.KeystoreFormatPEMBundle {
+			requiredFields = append(requiredFields, certutil.KeystoreFields([]v1beta1.KeystoreFormat{format})...)
 		}
-	} else {
-		if len(secret.Data) != 3 {
+	}
+	for _, field := range requiredFields {
+		if _, ok := secret.Data[field]; !ok {
 			return secret, errorfactory.New(errorfactory.ResourceNotReady{}, err, "user secret not populated yet")
 		}
 	}
@@ -165,23 +254,40 @@ func (c *certManager) clusterCertificateForUser(
 			Duration: &metav1.Duration{Duration: time.Duration(user.Spec.GetExpirationSeconds()) * time.Second},
 		},
 	}
-	if user.Spec.IncludeJKS {
-		cert.Spec.Keystores = &certv1.CertificateKeystores{
+	cert.Spec.Keystores = certificateKeystoresForUser(user)
+	if user.Spec.DNSNames != nil && len(user.Spec.DNSNames) > 0 {
+		cert.Spec.DNSNames = user.Spec.DNSNames
+	}
+	return cert
+}
+
+// certificateKeystoresForUser returns the keystores cert-manager should create for a KafkaUser
+func certificateKeystoresForUser(user *v1alpha1.KafkaUser) *certv1.CertificateKeystores {
+	passwordSecretRef := certmeta.SecretKeySelector{
+		LocalObjectReference: certmeta.LocalObjectReference{
+			Name: user.Spec.SecretName,
+		},
+		Key: v1alpha1.PasswordKey,
+	}
+	var keystores *certv1.CertificateKeystores
+	if user.Spec.HasKeystoreFormat(v1beta1.KeystoreFormatJKS) {
+		keystores = &certv1.CertificateKeystores{
 			JKS: &certv1.JKSKeystore{
-				Create: true,
-				PasswordSecretRef: certmeta.SecretKeySelector{
-					LocalObjectReference: certmeta.LocalObjectReference{
-						Name: user.Spec.SecretName,
-					},
-					Key: v1alpha1.PasswordKey,
-				},
+				Create:            true,
+				PasswordSecretRef: passwordSecretRef,
 			},
 		}
 	}
-	if user.Spec.DNSNames != nil && len(user.Spec.DNSNames) > 0 {
-		cert.Spec.DNSNames = user.Spec.DNSNames
+	if user.Spec.HasKeystoreFormat(v1beta1.KeystoreFormatPKCS12) {
+		if keystores == nil {
+			keystores = &certv1.CertificateKeystores{}
+		}
+		keystores.PKCS12 = &certv1.PKCS12Keystore{
+			Create:            true,
+			PasswordSecretRef: passwordSecretRef,
+		}
 	}
-	return cert
+	return keystores
 }
 
 // getCA returns the CA name/kind/group for the KafkaCluster
@@ -16,15 +16,18 @@ package certmanagerpki
 
 import (
 	"context"
+	"encoding/pem"
 	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
 
 	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/errorfactory"
 	"github.com/banzaicloud/koperator/pkg/util"
 	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
@@ -128,3 +131,60 @@ func TestReconcileUserCertificate(t *testing.T) {
 		t.Error("Expected  error, got nil")
 	}
 }
+
+func TestReconcileUserCertificatePEMBundle(t *testing.T) {
+	clusterDomain := "cluster.local"
+	manager, err := newMock(newMockCluster())
+	if err != nil {
+		t.Error("Expected no error during initialization, got:", err)
+	}
+	ctx := context.Background()
+
+	user := newMockUser()
+	user.Spec.IncludeJKS = false
+	user.Spec.KeystoreFormats = []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPEMBundle}
+	if err := manager.client.Create(ctx, user); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+	if _, err := manager.ReconcileUserCertificate(ctx, user, scheme.Scheme, clusterDomain); err == nil {
+		t.Error("Expected resource not ready error, got nil")
+	}
+
+	secret := newMockUserSecret()
+	delete(secret.Data, v1alpha1.TLSJKSKeyStore)
+	delete(secret.Data, v1alpha1.TLSJKSTrustStore)
+	if err := manager.client.Delete(ctx, newMockUserSecret()); err != nil {
+		t.Error("could not delete test secret")
+	}
+	if err := manager.client.Create(ctx, secret); err != nil {
+		t.Error("could not create test secret")
+	}
+	if _, err := manager.ReconcileUserCertificate(ctx, user, scheme.Scheme, clusterDomain); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+
+	if err := manager.client.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: secret.Namespace}, secret); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+	for _, field := range []string{v1alpha1.TLSPEMKeyStore, v1alpha1.TLSPEMTrustStore} {
+		if len(secret.Data[field]) == 0 {
+			t.Errorf("Expected %s to be rendered into the user secret", field)
+		}
+	}
+	block, _ := pem.Decode(secret.Data[v1alpha1.TLSPEMKeyStore])
+	if block == nil || block.Type != certutil.EncryptedPrivateKeyType {
+		t.Error("Expected the PEM bundle to start with an encrypted private key")
+	}
+
+	// a PKCS#12 keystore is requested from cert-manager once listed
+	user.Spec.KeystoreFormats = append(user.Spec.KeystoreFormats, v1beta1.KeystoreFormatPKCS12)
+	if _, err := manager.ReconcileUserCertificate(ctx, user, scheme.Scheme, clusterDomain); err == nil {
+		t.Error("Expected resource not ready error, got nil")
+	}
+	cert, err := manager.getUserCertificate(ctx, user)
+	if err != nil {
+		t.Error("Expected no error, got:", err)
+	} else if cert.Spec.Keystores == nil || cert.Spec.Keystores.PKCS12 == nil || !cert.Spec.Keystores.PKCS12.Create {
+		t.Error("Expected the certificate to request a PKCS#12 keystore, got:", cert.Spec.Keystores)
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 const (
 	DependingCsrAnnotation     string = "banzaicloud.io/csr"
+	RenewalCsrAnnotation       string = "banzaicloud.io/renewal-csr"
 	IncludeFullChainAnnotation string = "csr.banzaicloud.io/fullchain"
 )
 
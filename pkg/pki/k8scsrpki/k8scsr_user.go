@@ -27,6 +27,7 @@ import (
 	"github.com/banzaicloud/k8s-objectmatcher/patch"
 
 	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/errorfactory"
 	"github.com/banzaicloud/koperator/pkg/util"
 
@@ -108,15 +109,16 @@ func (c *k8sCSR) ReconcileUserCertificate(
 	}
 
 	// skip handling CSR if the secret already includes all the required fields
-	kafkaUserSecretReady := isKafkaUserCertificateReady(secret, user.Spec.IncludeJKS)
+	kafkaUserSecretReady := isKafkaUserCertificateReady(secret, user.Spec.GetKeystoreFormats())
 	if kafkaUserSecretReady {
-		return &pkicommon.UserCertificate{
-			CA:          secret.Data[v1alpha1.CaChainPem],
-			Certificate: secret.Data[corev1.TLSCertKey],
-			Key:         secret.Data[corev1.TLSPrivateKeyKey],
-			JKS:         secret.Data[v1alpha1.TLSJKSKeyStore],
-			Password:    secret.Data[v1alpha1.PasswordKey],
-		}, nil
+		return c.renewUserCertificateIfDue(ctx, user, secret)
+	}
+	// the certificate has been issued already, only the keystores of newly requested formats are missing
+	if isKafkaUserCertificateReady(secret, nil) {
+		if err = c.ensureKeystores(ctx, user, secret); err != nil {
+			return nil, err
+		}
+		return c.renewUserCertificateIfDue(ctx, user, secret)
 	}
 
 	signingRequestGenName, ok := secret.Annotations[DependingCsrAnnotation]
@@ -198,19 +200,14 @@ func (c *k8sCSR) ReconcileUserCertificate(
 	secret.Data[v1alpha1.CaChainPem] = caChain
 	certBundleX509 := certutil.GetCertBundle(certs)
 
-	// Ensure a JKS if requested
-	if user.Spec.IncludeJKS {
-		// we don't have an existing one - make a new one
-		if value, ok := secret.Data[v1alpha1.TLSJKSKeyStore]; !ok || len(value) == 0 {
-			jks, jksPasswd, err := certutil.GenerateJKS(certBundleX509, secret.Data[corev1.TLSPrivateKeyKey])
-			if err != nil {
-				return nil, err
-			}
-			secret.Data[v1alpha1.TLSJKSKeyStore] = jks
-			// Adding Truststore to the secret to align with the Cert Manager generated secret
-			secret.Data[v1alpha1.TLSJKSTrustStore] = jks
-			secret.Data[v1alpha1.PasswordKey] = jksPasswd
-		}
+	// Ensure the keystores in the requested formats
+	keystores, err := certutil.GenerateKeystores(user.Spec.GetKeystoreFormats(), certBundleX509,
+		secret.Data[corev1.TLSPrivateKeyKey], secret.Data[v1alpha1.PasswordKey])
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range keystores {
+		secret.Data[field] = value
 	}
 
 	typeMeta := secret.TypeMeta
@@ -229,8 +226,41 @@ func (c *k8sCSR) ReconcileUserCertificate(
 	}, nil
 }
 
-// FinalizeUserCertificate removes/revokes a user certificate
-func (c *k8sCSR) FinalizeUserCertificate(_ context.Context, _ *v1alpha1.KafkaUser) error {
+// FinalizeUserCertificate removes the signing requests of a user certificate, the kubernetes csr api
+// is not capable of revoking certificates and the secret itself is garbage collected with the user
+func (c *k8sCSR) FinalizeUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	secret := &corev1.Secret{}
+	err := c.client.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.WrapIfWithDetails(err,
+			"failed to get user's secret from K8s", "secretName", user.Spec.SecretName,
+			"namespace", user.GetNamespace())
+	}
+	if !metav1.IsControlledBy(secret, user) {
+		return nil
+	}
+	for _, annotation := range []string{DependingCsrAnnotation, RenewalCsrAnnotation} {
+		signingRequestName, ok := secret.Annotations[annotation]
+		if !ok {
+			continue
+		}
+		if err := c.deleteSigningRequest(ctx, signingRequestName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSigningRequest deletes the signing request unless it has been deleted already
+func (c *k8sCSR) deleteSigningRequest(ctx context.Context, name string) error {
+	signingReq := &certsigningreqv1.CertificateSigningRequest{}
+	signingReq.SetName(name)
+	if err := c.client.Delete(ctx, signingReq); err != nil && !apierrors.IsNotFound(err) {
+		return errors.WrapIfWithDetails(err, "failed to delete signing request", "csrName", name)
+	}
 	return nil
 }
 
@@ -309,11 +339,8 @@ func (c *k8sCSR) secretUpdateAnnotation(ctx context.Context, secret *corev1.Secr
 	return nil
 }
 
-func isKafkaUserCertificateReady(secret *corev1.Secret, includeJKS bool) bool {
-	requiredFields := []string{corev1.TLSCertKey, v1alpha1.CaChainPem}
-	if includeJKS {
-		requiredFields = append(requiredFields, v1alpha1.TLSJKSKeyStore, v1alpha1.PasswordKey)
-	}
+func isKafkaUserCertificateReady(secret *corev1.Secret, keystoreFormats []v1beta1.KeystoreFormat) bool {
+	requiredFields := append([]string{corev1.TLSCertKey, v1alpha1.CaChainPem}, certutil.KeystoreFields(keystoreFormats)...)
 	for _, field := range requiredFields {
 		if _, ok := secret.Data[field]; !ok {
 			return false
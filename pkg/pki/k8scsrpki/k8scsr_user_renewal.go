@@ -0,0 +1,191 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8scsrpki
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+
+	certsigningreqv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/pkg/util"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+	pkicommon "github.com/banzaicloud/koperator/pkg/util/pki"
+)
+
+// renewalPrivateKeyKey holds the private key of the renewal signing request until its certificate is issued
+const renewalPrivateKeyKey = "renewal.tls.key"
+
+// renewUserCertificateIfDue returns the certificate stored in the user's secret. Once the configured percentage
+// of its lifetime has elapsed a signing request with a fresh private key is created, the secret keeps
+// the current certificate until the new one is issued and then all of its fields are swapped in a single update.
+func (c *k8sCSR) renewUserCertificateIfDue(ctx context.Context, user *v1alpha1.KafkaUser, secret *corev1.Secret) (*pkicommon.UserCertificate, error) {
+	log := logr.FromContextOrDiscard(ctx)
+	cert, err := certutil.DecodeCertificate(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not decode user certificate", "secretName", secret.GetName())
+	}
+	if time.Now().Before(certutil.RenewalTime(cert, user.Spec.GetRenewalPercentage())) {
+		return userCertificateFromSecret(secret), nil
+	}
+
+	signingRequestName, ok := secret.Annotations[RenewalCsrAnnotation]
+	if !ok || len(secret.Data[renewalPrivateKeyKey]) == 0 {
+		log.Info("Requesting renewal of the user certificate", "notAfter", cert.NotAfter)
+		if err = c.requestUserCertificateRenewal(ctx, user, secret); err != nil {
+			return nil, err
+		}
+		return userCertificateFromSecret(secret), nil
+	}
+
+	// signing requests are cluster scoped
+	signingReq, err := c.getUserSigningRequest(ctx, signingRequestName, "")
+	if apierrors.IsNotFound(err) {
+		// kubernetes removes signing requests which are not approved in time, start the renewal over
+		log.Info("Renewal signing request not found, requesting a new one", "csrName", signingRequestName)
+		if err = c.requestUserCertificateRenewal(ctx, user, secret); err != nil {
+			return nil, err
+		}
+		return userCertificateFromSecret(secret), nil
+	} else if err != nil {
+		return nil, errors.WrapIfWithDetails(err,
+			"failed to get signing request from K8s", "signingRequestName", signingRequestName)
+	}
+
+	if !isSigningRequestApproved(signingReq) {
+		if strings.Split(signingReq.Spec.SignerName, "/")[0] != v1alpha1.CertManagerSignerNamePrefix {
+			log.Info("Waiting for the renewal signing request to be approved", "csrName", signingReq.GetName())
+			return userCertificateFromSecret(secret), nil
+		}
+		if err = c.Approve(ctx, signingReq); err != nil {
+			return nil, err
+		}
+	}
+	if len(signingReq.Status.Certificate) == 0 {
+		log.Info("Waiting for the renewal signing request to be issued", "csrName", signingReq.GetName())
+		return userCertificateFromSecret(secret), nil
+	}
+
+	supersededSigningRequestName := secret.Annotations[DependingCsrAnnotation]
+	if err = c.swapUserCertificate(ctx, user, secret, signingReq); err != nil {
+		return nil, err
+	}
+	log.Info("User certificate renewed", "csrName", signingReq.GetName())
+	// the signing request of the replaced certificate is not needed anymore, its name is not stored after the swap
+	// so a failed deletion is not retried
+	if supersededSigningRequestName != "" && supersededSigningRequestName != signingReq.GetName() {
+		if err = c.deleteSigningRequest(ctx, supersededSigningRequestName); err != nil {
+			log.Error(err, "could not delete the signing request of the replaced user certificate", "csrName", supersededSigningRequestName)
+		}
+	}
+	return userCertificateFromSecret(secret), nil
+}
+
+// requestUserCertificateRenewal creates a signing request with a fresh private key and records both in the secret
+func (c *k8sCSR) requestUserCertificateRenewal(ctx context.Context, user *v1alpha1.KafkaUser, secret *corev1.Secret) error {
+	clientKey, err := certutil.GeneratePrivateKeyInPemFormat()
+	if err != nil {
+		return err
+	}
+	signingReq, err := c.generateAndCreateCSR(ctx, clientKey, user)
+	if err != nil {
+		return err
+	}
+	secret.Data[renewalPrivateKeyKey] = clientKey
+	secret.Annotations = util.MergeAnnotations(secret.Annotations, map[string]string{RenewalCsrAnnotation: signingReq.GetName()})
+	return c.updateSecret(ctx, secret)
+}
+
+// swapUserCertificate replaces the key, the certificate and the derived fields of the secret with the renewed ones
+func (c *k8sCSR) swapUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser, secret *corev1.Secret,
+	signingReq *certsigningreqv1.CertificateSigningRequest) error {
+	certs, err := certutil.ParseCertificates(signingReq.Status.Certificate)
+	if err != nil {
+		return err
+	}
+	caChain, err := c.getCAChain(ctx, signingReq, certs)
+	if err != nil {
+		return err
+	}
+
+	clientKey := secret.Data[renewalPrivateKeyKey]
+	keystores, err := certutil.GenerateKeystores(user.Spec.GetKeystoreFormats(), certutil.GetCertBundle(certs),
+		clientKey, secret.Data[v1alpha1.PasswordKey])
+	if err != nil {
+		return err
+	}
+	for field, value := range keystores {
+		secret.Data[field] = value
+	}
+	secret.Data[corev1.TLSPrivateKeyKey] = clientKey
+	secret.Data[corev1.TLSCertKey] = certs[0].ToPEM()
+	secret.Data[v1alpha1.CaChainPem] = caChain
+	delete(secret.Data, renewalPrivateKeyKey)
+	delete(secret.Annotations, RenewalCsrAnnotation)
+	secret.Annotations[DependingCsrAnnotation] = signingReq.GetName()
+	return c.updateSecret(ctx, secret)
+}
+
+// ensureKeystores adds the keystores of the requested formats to a secret holding an issued certificate
+func (c *k8sCSR) ensureKeystores(ctx context.Context, user *v1alpha1.KafkaUser, secret *corev1.Secret) error {
+	certs, err := certutil.ParseCertificates(append(append([]byte{}, secret.Data[corev1.TLSCertKey]...), secret.Data[v1alpha1.CaChainPem]...))
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not parse user certificate", "secretName", secret.GetName())
+	}
+	keystores, err := certutil.GenerateKeystores(user.Spec.GetKeystoreFormats(), certutil.GetCertBundle(certs),
+		secret.Data[corev1.TLSPrivateKeyKey], secret.Data[v1alpha1.PasswordKey])
+	if err != nil {
+		return err
+	}
+	for field, value := range keystores {
+		secret.Data[field] = value
+	}
+	return c.updateSecret(ctx, secret)
+}
+
+func (c *k8sCSR) updateSecret(ctx context.Context, secret *corev1.Secret) error {
+	typeMeta := secret.TypeMeta
+	if err := c.client.Update(ctx, secret); err != nil {
+		return err
+	}
+	secret.TypeMeta = typeMeta
+	return nil
+}
+
+func isSigningRequestApproved(signingReq *certsigningreqv1.CertificateSigningRequest) bool {
+	for _, cond := range signingReq.Status.Conditions {
+		if cond.Type == certsigningreqv1.CertificateApproved {
+			return true
+		}
+	}
+	return false
+}
+
+func userCertificateFromSecret(secret *corev1.Secret) *pkicommon.UserCertificate {
+	return &pkicommon.UserCertificate{
+		CA:          secret.Data[v1alpha1.CaChainPem],
+		Certificate: secret.Data[corev1.TLSCertKey],
+		Key:         secret.Data[corev1.TLSPrivateKeyKey],
+		JKS:         secret.Data[v1alpha1.TLSJKSKeyStore],
+		Password:    secret.Data[v1alpha1.PasswordKey],
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8scsrpki
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	certsigningreqv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+)
+
+// generateNameClient names the created objects from their generate name like the API server does
+type generateNameClient struct {
+	client.Client
+	generated int
+}
+
+func (c *generateNameClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if obj.GetName() == "" && obj.GetGenerateName() != "" {
+		c.generated++
+		obj.SetName(fmt.Sprintf("%s%d", obj.GetGenerateName(), c.generated))
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+func newTestCA(g *WithT) *testCA {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(raw)
+	g.Expect(err).NotTo(HaveOccurred())
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns the PEM encoded leaf certificate for the PEM encoded private key followed by the CA certificate
+func (ca *testCA) issue(g *WithT, keyPEM []byte, notBefore, notAfter time.Time) []byte {
+	block, _ := pem.Decode(keyPEM)
+	g.Expect(block).NotTo(BeNil())
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	g.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(notAfter.Unix()),
+		Subject:      pkix.Name{CommonName: "test-user"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	g.Expect(err).NotTo(HaveOccurred())
+	return append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})...)
+}
+
+func createUserSecret(g *WithT, ctx context.Context, c client.Client, user *v1alpha1.KafkaUser,
+	ca *testCA, notBefore, notAfter time.Time) *corev1.Secret {
+	key, err := certutil.GeneratePrivateKeyInPemFormat()
+	g.Expect(err).NotTo(HaveOccurred())
+	certs, err := certutil.ParseCertificates(ca.issue(g, key, notBefore, notAfter))
+	g.Expect(err).NotTo(HaveOccurred())
+	jks, password, err := certutil.GenerateJKS(certutil.GetCertBundle(certs), key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      user.Spec.SecretName,
+			Namespace: user.Namespace,
+			Annotations: map[string]string{
+				DependingCsrAnnotation: "test-user-initial",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       user.Kind,
+					Name:       user.Name,
+					UID:        user.UID,
+					Controller: func() *bool { b := true; return &b }(),
+				},
+			},
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey:   key,
+			corev1.TLSCertKey:         certs[0].ToPEM(),
+			v1alpha1.CaChainPem:       certs[1].ToPEM(),
+			v1alpha1.TLSJKSKeyStore:   jks,
+			v1alpha1.TLSJKSTrustStore: jks,
+			v1alpha1.PasswordKey:      password,
+		},
+	}
+	g.Expect(c.Create(ctx, secret)).To(Succeed())
+	return secret
+}
+
+func TestReconcileUserCertificateRenewal(t *testing.T) {
+	g := NewWithT(t)
+	sch, err := setupSchemeForTests()
+	g.Expect(err).NotTo(HaveOccurred())
+	ctx := context.Background()
+	ca := newTestCA(g)
+
+	user := createKafkaUser()
+	user.Spec.IncludeJKS = true
+	fakeClient := &generateNameClient{Client: fake.NewClientBuilder().WithScheme(sch).Build()}
+	pkiManager := New(fakeClient, newMockCluster())
+
+	// the certificate is past 67% of its lifetime
+	now := time.Now().Truncate(time.Second)
+	original := createUserSecret(g, ctx, fakeClient, user, ca, now.Add(-3*time.Hour), now.Add(time.Hour))
+	initialSigningReq := &certsigningreqv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "test-user-initial"}}
+	g.Expect(fakeClient.Create(ctx, initialSigningReq)).To(Succeed())
+
+	userCert, err := pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(userCert.Certificate).To(Equal(original.Data[corev1.TLSCertKey]))
+	g.Expect(userCert.Key).To(Equal(original.Data[corev1.TLSPrivateKeyKey]))
+
+	secret := &corev1.Secret{}
+	g.Expect(fakeClient.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)).To(Succeed())
+	renewalCsrName := secret.Annotations[RenewalCsrAnnotation]
+	g.Expect(renewalCsrName).NotTo(BeEmpty())
+	renewalKey := secret.Data[renewalPrivateKeyKey]
+	g.Expect(renewalKey).NotTo(BeEmpty())
+	g.Expect(renewalKey).NotTo(Equal(original.Data[corev1.TLSPrivateKeyKey]))
+
+	// the certificate is not swapped until the signing request is approved and issued
+	signingReq := &certsigningreqv1.CertificateSigningRequest{}
+	g.Expect(fakeClient.Get(ctx, types.NamespacedName{Name: renewalCsrName}, signingReq)).To(Succeed())
+	userCert, err = pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(userCert.Certificate).To(Equal(original.Data[corev1.TLSCertKey]))
+
+	signingReq.Status.Conditions = []certsigningreqv1.CertificateSigningRequestCondition{
+		{Type: certsigningreqv1.CertificateApproved, Status: corev1.ConditionTrue},
+	}
+	signingReq.Status.Certificate = ca.issue(g, renewalKey, now, now.Add(3*time.Hour))
+	g.Expect(fakeClient.Update(ctx, signingReq)).To(Succeed())
+
+	userCert, err = pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(userCert.Key).To(Equal(renewalKey))
+	renewed, err := certutil.DecodeCertificate(userCert.Certificate)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(renewed.NotAfter).To(Equal(now.Add(3 * time.Hour).UTC()))
+
+	g.Expect(fakeClient.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)).To(Succeed())
+	g.Expect(secret.Data[corev1.TLSPrivateKeyKey]).To(Equal(renewalKey))
+	g.Expect(secret.Data[corev1.TLSCertKey]).To(Equal(userCert.Certificate))
+	g.Expect(secret.Data).NotTo(HaveKey(renewalPrivateKeyKey))
+	g.Expect(secret.Annotations).NotTo(HaveKey(RenewalCsrAnnotation))
+	g.Expect(secret.Annotations[DependingCsrAnnotation]).To(Equal(renewalCsrName))
+	// the signing request of the replaced certificate is deleted
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: initialSigningReq.Name}, &certsigningreqv1.CertificateSigningRequest{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(secret.Data[v1alpha1.PasswordKey]).To(Equal(original.Data[v1alpha1.PasswordKey]))
+	tlsCert, err := certutil.ParseKeyStoreToTLSCertificate(secret.Data[v1alpha1.TLSJKSKeyStore], secret.Data[v1alpha1.PasswordKey])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tlsCert.Leaf.NotAfter).To(Equal(renewed.NotAfter))
+
+	// the renewed certificate is not due for renewal
+	userCert, err = pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(userCert.Certificate).To(Equal(secret.Data[corev1.TLSCertKey]))
+	var requestList certsigningreqv1.CertificateSigningRequestList
+	g.Expect(fakeClient.List(ctx, &requestList)).To(Succeed())
+	g.Expect(requestList.Items).To(HaveLen(1))
+}
+
+func TestReconcileUserCertificateRenewalRestartsWithoutSigningRequest(t *testing.T) {
+	g := NewWithT(t)
+	sch, err := setupSchemeForTests()
+	g.Expect(err).NotTo(HaveOccurred())
+	ctx := context.Background()
+	ca := newTestCA(g)
+
+	user := createKafkaUser()
+	fakeClient := &generateNameClient{Client: fake.NewClientBuilder().WithScheme(sch).Build()}
+	pkiManager := New(fakeClient, newMockCluster())
+
+	now := time.Now()
+	secret := createUserSecret(g, ctx, fakeClient, user, ca, now.Add(-3*time.Hour), now.Add(time.Hour))
+	secret.Annotations[RenewalCsrAnnotation] = "removed-csr"
+	secret.Data[renewalPrivateKeyKey] = []byte("stale")
+	g.Expect(fakeClient.Update(ctx, secret)).To(Succeed())
+
+	_, err = pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(fakeClient.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)).To(Succeed())
+	g.Expect(secret.Annotations[RenewalCsrAnnotation]).NotTo(Equal("removed-csr"))
+	g.Expect(secret.Data[renewalPrivateKeyKey]).NotTo(Equal([]byte("stale")))
+	signingReq := &certsigningreqv1.CertificateSigningRequest{}
+	g.Expect(fakeClient.Get(ctx, types.NamespacedName{Name: secret.Annotations[RenewalCsrAnnotation]}, signingReq)).To(Succeed())
+}
+
+func TestFinalizeUserCertificate(t *testing.T) {
+	g := NewWithT(t)
+	sch, err := setupSchemeForTests()
+	g.Expect(err).NotTo(HaveOccurred())
+	ctx := context.Background()
+	ca := newTestCA(g)
+
+	user := createKafkaUser()
+	user.UID = "test-user-uid"
+	fakeClient := fake.NewClientBuilder().WithScheme(sch).Build()
+	pkiManager := New(fakeClient, newMockCluster())
+
+	now := time.Now()
+	secret := createUserSecret(g, ctx, fakeClient, user, ca, now.Add(-3*time.Hour), now.Add(time.Hour))
+	secret.Annotations[RenewalCsrAnnotation] = "test-user-renewal"
+	g.Expect(fakeClient.Update(ctx, secret)).To(Succeed())
+	for _, name := range []string{"test-user-initial", "test-user-renewal", "other-user"} {
+		signingReq := &certsigningreqv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		g.Expect(fakeClient.Create(ctx, signingReq)).To(Succeed())
+	}
+
+	g.Expect(pkiManager.FinalizeUserCertificate(ctx, user)).To(Succeed())
+
+	var requestList certsigningreqv1.CertificateSigningRequestList
+	g.Expect(fakeClient.List(ctx, &requestList)).To(Succeed())
+	g.Expect(requestList.Items).To(HaveLen(1))
+	g.Expect(requestList.Items[0].Name).To(Equal("other-user"))
+
+	// signing requests are kept when the secret does not belong to the user
+	otherUser := createKafkaUser()
+	otherUser.UID = "other-user-uid"
+	otherUser.Spec.SecretName = secret.Name
+	g.Expect(pkiManager.FinalizeUserCertificate(ctx, otherUser)).To(Succeed())
+	g.Expect(fakeClient.List(ctx, &requestList)).To(Succeed())
+	g.Expect(requestList.Items).To(HaveLen(1))
+}
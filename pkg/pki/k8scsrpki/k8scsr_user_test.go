@@ -20,13 +20,16 @@ import (
 	"encoding/pem"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	certsigningreqv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -120,3 +123,37 @@ func TestReconcileUserCertificate(t *testing.T) {
 	Expect(certReq.Subject.CommonName).To(Equal(user.GetName()))
 	Expect(certReq.DNSNames).To(ConsistOf(testDns))
 }
+
+func TestReconcileUserCertificateAddsKeystoreFormats(t *testing.T) {
+	g := NewWithT(t)
+	sch, err := setupSchemeForTests()
+	g.Expect(err).NotTo(HaveOccurred())
+	ctx := context.Background()
+	ca := newTestCA(g)
+
+	user := createKafkaUser()
+	user.Spec.IncludeJKS = true
+	fakeClient := fake.NewClientBuilder().WithScheme(sch).Build()
+	pkiManager := New(fakeClient, newMockCluster())
+
+	now := time.Now()
+	original := createUserSecret(g, ctx, fakeClient, user, ca, now.Add(-time.Hour), now.Add(time.Hour))
+
+	user.Spec.KeystoreFormats = []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPKCS12, v1beta1.KeystoreFormatPEMBundle}
+	userCert, err := pkiManager.ReconcileUserCertificate(ctx, user, sch, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(userCert.Certificate).To(Equal(original.Data[corev1.TLSCertKey]))
+
+	secret := &corev1.Secret{}
+	g.Expect(fakeClient.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)).To(Succeed())
+	for _, field := range cert.KeystoreFields(user.Spec.GetKeystoreFormats()) {
+		g.Expect(secret.Data).To(HaveKey(field))
+	}
+	g.Expect(secret.Data[v1alpha1.PasswordKey]).To(Equal(original.Data[v1alpha1.PasswordKey]))
+	g.Expect(secret.Data[corev1.TLSPrivateKeyKey]).To(Equal(original.Data[corev1.TLSPrivateKeyKey]))
+
+	// no signing request is needed to add keystores
+	var requestList certsigningreqv1.CertificateSigningRequestList
+	g.Expect(fakeClient.List(ctx, &requestList)).To(Succeed())
+	g.Expect(requestList.Items).To(BeEmpty())
+}
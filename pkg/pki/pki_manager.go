@@ -25,6 +25,7 @@ import (
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/pki/certmanagerpki"
 	"github.com/banzaicloud/koperator/pkg/pki/k8scsrpki"
+	"github.com/banzaicloud/koperator/pkg/pki/vaultpki"
 	"github.com/banzaicloud/koperator/pkg/util/pki"
 )
 
@@ -47,6 +48,9 @@ func GetPKIManager(client client.Client, cluster *v1beta1.KafkaCluster, pkiBacke
 	// Use k8s csr api for pki backend
 	case v1beta1.PKIBackendK8sCSR:
 		return k8scsrpki.New(client, cluster)
+	// Use the PKI secrets engine of vault for pki backend
+	case v1beta1.PKIBackendVault:
+		return vaultpki.New(client, cluster)
 	// Return mock backend for testing - cannot be triggered by CR due to enum in api schema
 	case MockBackend:
 		return newMockPKIManager(client, cluster)
@@ -86,6 +86,14 @@ func TestGetPKIManager(t *testing.T) {
 		t.Error("Expected:", expected, "got:", pkiType)
 	}
 
+	cluster.Spec.ListenersConfig.SSLSecrets.PKIBackend = v1beta1.PKIBackendVault
+	vault := GetPKIManager(&mockClient{}, cluster, v1beta1.PKIBackendProvided)
+	pkiType = reflect.TypeOf(vault).String()
+	expected = "*vaultpki.vaultPKI"
+	if pkiType != expected {
+		t.Error("Expected:", expected, "got:", pkiType)
+	}
+
 	// Default should be cert-manager also
 	cluster.Spec.ListenersConfig.SSLSecrets.PKIBackend = ""
 	certmanager = GetPKIManager(&mockClient{}, cluster, v1beta1.PKIBackendProvided)
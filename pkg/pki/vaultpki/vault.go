@@ -0,0 +1,195 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultpki
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	vaultapi "github.com/hashicorp/vault/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/util/pki"
+)
+
+const (
+	spiffeIdTemplate = "spiffe://%s/ns/%s/kafkauser/%s"
+	// serviceAccountTokenPath is the path of the service account token the operator logs in to Vault with
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec
+)
+
+type VaultPKI interface {
+	pki.Manager
+}
+
+// vaultPKI implements a PKIManager using the PKI secrets engine of Vault as the backend
+type vaultPKI struct {
+	client  client.Client
+	cluster *v1beta1.KafkaCluster
+	// tokenPath is the path of the service account token used by the Kubernetes auth method
+	tokenPath string
+}
+
+func New(client client.Client, cluster *v1beta1.KafkaCluster) VaultPKI {
+	return &vaultPKI{client: client, cluster: cluster, tokenPath: serviceAccountTokenPath}
+}
+
+// getVaultConfig returns the Vault configuration of the KafkaCluster
+func (v *vaultPKI) getVaultConfig() (*v1beta1.VaultConfig, error) {
+	sslSecrets := v.cluster.Spec.ListenersConfig.SSLSecrets
+	if sslSecrets == nil || sslSecrets.VaultConfig == nil {
+		return nil, errorfactory.New(errorfactory.FatalReconcileError{}, errors.New("vault config is missing"),
+			"the vault PKI backend requires spec.listenersConfig.sslSecrets.vaultConfig to be set", "cluster", v.cluster.Name)
+	}
+	return sslSecrets.VaultConfig, nil
+}
+
+// cachedVaultClient is a Vault client shared by the reconcilers of the operator process, it is not modified
+// once it is cached
+type cachedVaultClient struct {
+	client *vaultapi.Client
+	// renewAt is the time the token of the client is renewed at, zero if the token does not expire
+	renewAt   time.Time
+	renewable bool
+}
+
+var (
+	vaultClientsMu sync.Mutex
+	// vaultClients holds the authenticated Vault clients by address, auth path and role,
+	// so the operator logs in to Vault once per process instead of once per reconciliation
+	vaultClients = map[string]*cachedVaultClient{}
+)
+
+// getVaultClient returns a Vault client authenticated by the token in the VAULT_TOKEN environment variable
+// or by logging in with the service account token of the operator through the Kubernetes auth method.
+// The clients are cached per process and their tokens are renewed after half of their lease duration,
+// the operator logs in again when the token can not be renewed. The lock of the cache is not held while
+// Vault is called, so a slow Vault does not block the reconcilers of the other clusters.
+func (v *vaultPKI) getVaultClient(vaultConfig *v1beta1.VaultConfig) (*vaultapi.Client, error) {
+	clientConfig := vaultapi.DefaultConfig()
+	if clientConfig.Error != nil {
+		return nil, errors.WrapIf(clientConfig.Error, "could not read the vault client configuration")
+	}
+	if vaultConfig.Address != "" {
+		clientConfig.Address = vaultConfig.Address
+	}
+	key := strings.Join([]string{clientConfig.Address, vaultConfig.GetAuthPath(), vaultConfig.AuthRole}, "|")
+
+	vaultClientsMu.Lock()
+	cached, ok := vaultClients[key]
+	vaultClientsMu.Unlock()
+
+	if ok {
+		if cached.renewAt.IsZero() || time.Now().Before(cached.renewAt) {
+			return cached.client, nil
+		}
+		if cached.renewable {
+			secret, err := cached.client.Auth().Token().RenewSelf(0)
+			if err == nil && secret != nil && secret.Auth != nil {
+				renewed := &cachedVaultClient{client: cached.client}
+				renewed.setLease(secret.Auth)
+				storeVaultClient(key, renewed)
+				return cached.client, nil
+			}
+		}
+	}
+
+	vaultClient, err := vaultapi.NewClient(clientConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not create vault client")
+	}
+	if vaultClient.Token() != "" {
+		storeVaultClient(key, &cachedVaultClient{client: vaultClient})
+		return vaultClient, nil
+	}
+
+	jwt, err := os.ReadFile(v.tokenPath)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not read service account token", "path", v.tokenPath)
+	}
+	secret, err := vaultClient.Logical().Write("auth/"+vaultConfig.GetAuthPath()+"/login", map[string]interface{}{
+		"role": vaultConfig.AuthRole,
+		"jwt":  string(jwt),
+	})
+	if err != nil {
+		return nil, errorfactory.New(errorfactory.VaultAPIFailure{}, err, "could not log in to vault", "role", vaultConfig.AuthRole)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, errorfactory.New(errorfactory.VaultAPIFailure{}, errors.New("no client token was returned"),
+			"could not log in to vault", "role", vaultConfig.AuthRole)
+	}
+	vaultClient.SetToken(secret.Auth.ClientToken)
+	loggedIn := &cachedVaultClient{client: vaultClient}
+	loggedIn.setLease(secret.Auth)
+	storeVaultClient(key, loggedIn)
+	return vaultClient, nil
+}
+
+// withVaultClient calls fn with the cached Vault client. When Vault denies the request, e.g. because the token
+// of the client has been revoked, the client is evicted from the cache and fn is called again after logging in.
+func (v *vaultPKI) withVaultClient(vaultConfig *v1beta1.VaultConfig, fn func(vaultClient *vaultapi.Client) error) error {
+	vaultClient, err := v.getVaultClient(vaultConfig)
+	if err != nil {
+		return err
+	}
+	err = fn(vaultClient)
+	if !isPermissionDeniedError(err) {
+		return err
+	}
+	evictVaultClient(vaultClient)
+	vaultClient, err = v.getVaultClient(vaultConfig)
+	if err != nil {
+		return err
+	}
+	return fn(vaultClient)
+}
+
+func storeVaultClient(key string, cached *cachedVaultClient) {
+	vaultClientsMu.Lock()
+	defer vaultClientsMu.Unlock()
+	vaultClients[key] = cached
+}
+
+// evictVaultClient removes the client from the cache, so the next request logs in to Vault again
+func evictVaultClient(vaultClient *vaultapi.Client) {
+	vaultClientsMu.Lock()
+	defer vaultClientsMu.Unlock()
+	for key, cached := range vaultClients {
+		if cached.client == vaultClient {
+			delete(vaultClients, key)
+		}
+	}
+}
+
+// isPermissionDeniedError returns true if Vault responded that the token is not allowed to perform the request
+func isPermissionDeniedError(err error) bool {
+	var responseErr *vaultapi.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden
+}
+
+// setLease sets when the token of the client is renewed from the lease of the token
+func (c *cachedVaultClient) setLease(auth *vaultapi.SecretAuth) {
+	c.renewable = auth.Renewable
+	c.renewAt = time.Time{}
+	if auth.LeaseDuration > 0 {
+		c.renewAt = time.Now().Add(time.Duration(auth.LeaseDuration) * time.Second / 2)
+	}
+}
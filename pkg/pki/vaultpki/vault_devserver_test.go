@@ -0,0 +1,122 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultpki
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+)
+
+// TestVaultDevServer issues and revokes certificates through a local Vault dev server started with
+//
+//	vault server -dev -dev-root-token-id=root
+//
+// It is skipped unless the VAULT_ADDR and VAULT_TOKEN environment variables point to the server.
+func TestVaultDevServer(t *testing.T) {
+	if os.Getenv(vaultapi.EnvVaultAddress) == "" || os.Getenv(vaultapi.EnvVaultToken) == "" {
+		t.Skip("VAULT_ADDR and VAULT_TOKEN are not set")
+	}
+	if err := v1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	vaultClient, err := vaultapi.NewClient(vaultapi.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mountPath := fmt.Sprintf("koperator-test-%d", time.Now().UnixNano())
+	if err := vaultClient.Sys().Mount(mountPath, &vaultapi.MountInput{
+		Type:   "pki",
+		Config: vaultapi.MountConfigInput{MaxLeaseTTL: "87600h"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = vaultClient.Sys().Unmount(mountPath)
+	})
+	if _, err := vaultClient.Logical().Write(mountPath+"/root/generate/internal", map[string]interface{}{
+		"common_name": "koperator-test-ca",
+		"ttl":         "87600h",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vaultClient.Logical().Write(mountPath+"/roles/kafka", map[string]interface{}{
+		"allow_any_name":    true,
+		"enforce_hostnames": false,
+		"allowed_uri_sans":  "spiffe://*",
+		"client_flag":       true,
+		"server_flag":       true,
+		"max_ttl":           "2160h",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := newMockCluster("")
+	cluster.Spec.ListenersConfig.SSLSecrets.VaultConfig.PKIPath = mountPath
+	manager := &vaultPKI{
+		client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		cluster: cluster,
+	}
+	ctx := context.Background()
+
+	if err := manager.ReconcilePKI(ctx, map[string]v1beta1.ListenerStatusList{}); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	user := newMockUser()
+	userCert, err := manager.ReconcileUserCertificate(ctx, user, scheme.Scheme, "cluster.local")
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	caCert, err := certutil.DecodeCertificate(userCert.CA)
+	if err != nil {
+		t.Fatal("Expected valid CA certificate, got:", err)
+	}
+	cert, err := certutil.DecodeCertificate(userCert.Certificate)
+	if err != nil {
+		t.Fatal("Expected valid certificate, got:", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "test.example.com", KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Error("Expected certificate issued by the CA, got:", err)
+	}
+
+	if err := manager.FinalizeUserCertificate(ctx, user); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	revoked, err := vaultClient.Logical().Read(mountPath + "/cert/" + vaultSerialNumber(cert))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revocationTime := fmt.Sprint(revoked.Data["revocation_time"]); revocationTime == "0" {
+		t.Error("Expected the certificate to be revoked")
+	}
+}
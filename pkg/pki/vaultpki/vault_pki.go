@@ -0,0 +1,119 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultpki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	vaultapi "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	pkicommon "github.com/banzaicloud/koperator/pkg/util/pki"
+)
+
+func (v *vaultPKI) ReconcilePKI(ctx context.Context, extListenerStatuses map[string]v1beta1.ListenerStatusList) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("Reconciling vault PKI")
+
+	vaultConfig, err := v.getVaultConfig()
+	if err != nil {
+		return err
+	}
+	if err := v.withVaultClient(vaultConfig, func(vaultClient *vaultapi.Client) error {
+		return v.ensureCA(vaultClient, vaultConfig)
+	}); err != nil {
+		return err
+	}
+
+	// the certificates of the broker and controller users are issued by ReconcileUserCertificate
+	for _, user := range []*v1alpha1.KafkaUser{
+		pkicommon.BrokerUserForCluster(v.cluster, extListenerStatuses),
+		pkicommon.ControllerUserForCluster(v.cluster),
+	} {
+		if err := v.reconcileUser(ctx, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FinalizePKI revokes the certificates of the brokers and the controller, the secrets holding them are removed
+// together with their KafkaUsers owned by the cluster
+func (v *vaultPKI) FinalizePKI(ctx context.Context) error {
+	logger := logr.FromContextOrDiscard(ctx)
+	logger.Info("Revoking vault certificates of the cluster")
+
+	for _, secretName := range []string{
+		fmt.Sprintf(pkicommon.BrokerServerCertTemplate, v.cluster.Name),
+		fmt.Sprintf(pkicommon.BrokerControllerTemplate, v.cluster.Name),
+	} {
+		secret := &corev1.Secret{}
+		if err := v.client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: v.cluster.Namespace}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errorfactory.New(errorfactory.APIFailure{}, err, "failed to get certificate secret", "secretName", secretName)
+		}
+		if err := v.revokeCertificate(ctx, secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureCA checks that the PKI secrets engine has a CA configured, the operator does not generate one
+// as the CA of the cluster has to be managed by the Vault administrators
+func (v *vaultPKI) ensureCA(vaultClient *vaultapi.Client, vaultConfig *v1beta1.VaultConfig) error {
+	ca, err := vaultClient.Logical().Read(vaultConfig.PKIPath + "/cert/ca")
+	if err != nil && !isMissingCAError(err) {
+		return errorfactory.New(errorfactory.VaultAPIFailure{}, err, "could not read the CA of the PKI secrets engine", "path", vaultConfig.PKIPath)
+	}
+	if ca != nil {
+		if certificate, _ := ca.Data["certificate"].(string); certificate != "" {
+			return nil
+		}
+	}
+	return errorfactory.New(errorfactory.VaultAPIFailure{}, errors.New("the PKI secrets engine has no CA configured"),
+		"a CA has to be generated or imported in the PKI secrets engine of vault", "path", vaultConfig.PKIPath)
+}
+
+// isMissingCAError returns true if Vault responded that the PKI secrets engine has no CA configured
+func isMissingCAError(err error) bool {
+	var responseErr *vaultapi.ResponseError
+	return errors.As(err, &responseErr) && (responseErr.StatusCode == http.StatusBadRequest || responseErr.StatusCode == http.StatusNotFound)
+}
+
+// reconcileUser ensures a v1alpha1.KafkaUser
+func (v *vaultPKI) reconcileUser(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	obj := &v1alpha1.KafkaUser{}
+	if err := v.client.Get(ctx, types.NamespacedName{Name: user.Name, Namespace: user.Namespace}, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errorfactory.New(errorfactory.APIFailure{}, err, "failed to get kafka user", "name", user.Name)
+		}
+		if err := v.client.Create(ctx, user); err != nil {
+			return errorfactory.New(errorfactory.APIFailure{}, err, "failed to create kafka user", "name", user.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultpki
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	pkicommon "github.com/banzaicloud/koperator/pkg/util/pki"
+)
+
+func TestReconcilePKI(t *testing.T) {
+	vault, server := newFakeVault(t)
+	manager := newMock(t, newMockCluster(server.URL))
+	ctx := context.Background()
+
+	// the operator does not generate a CA in the PKI secrets engine
+	if err := manager.ReconcilePKI(ctx, map[string]v1beta1.ListenerStatusList{}); err == nil {
+		t.Fatal("Expected error for missing CA, got nil")
+	}
+	if vault.caCert != nil {
+		t.Fatal("Expected no CA to be generated")
+	}
+
+	vault.generateCA("test-ca")
+	if err := manager.ReconcilePKI(ctx, map[string]v1beta1.ListenerStatusList{}); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	var users v1alpha1.KafkaUserList
+	if err := manager.client.List(ctx, &users); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if len(users.Items) != 2 {
+		t.Error("Expected the broker and controller users, got:", len(users.Items))
+	}
+}
+
+func TestFinalizePKI(t *testing.T) {
+	vault, server := newFakeVault(t)
+	manager := newMock(t, newMockCluster(server.URL))
+	ctx := context.Background()
+
+	if err := manager.FinalizePKI(ctx); err != nil {
+		t.Fatal("Expected no error without certificates, got:", err)
+	}
+
+	for _, user := range []*v1alpha1.KafkaUser{
+		pkicommon.BrokerUserForCluster(manager.cluster, nil),
+		pkicommon.ControllerUserForCluster(manager.cluster),
+	} {
+		user.UID = types.UID(user.Name)
+		if _, err := manager.ReconcileUserCertificate(ctx, user, manager.client.Scheme(), "cluster.local"); err != nil {
+			t.Fatal("Expected no error, got:", err)
+		}
+	}
+	if err := manager.FinalizePKI(ctx); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if len(vault.revoked) != 2 {
+		t.Error("Expected the broker and controller certificates to be revoked, got:", vault.revoked)
+	}
+
+	secret := &corev1.Secret{}
+	if err := manager.client.Get(ctx, types.NamespacedName{Name: "test-controller", Namespace: testNamespace}, secret); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if _, err := manager.GetControllerTLSConfig(); err != nil {
+		t.Error("Expected no error, got:", err)
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultpki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testJWT       = "test-jwt"
+	testToken     = "test-token"
+)
+
+type mockClient struct {
+	client.Client
+}
+
+// fakeVault serves the subset of the Vault API used by the PKI backend
+type fakeVault struct {
+	t  *testing.T
+	mu sync.Mutex
+
+	caCert *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+	caPEM  string
+
+	issued  []map[string]interface{}
+	revoked []string
+
+	logins   int
+	renewals int
+	// tokenRevoked makes Vault deny the requests of the token until the next login
+	tokenRevoked bool
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	vault := &fakeVault{t: t}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+	return vault, server
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var request map[string]interface{}
+	if r.Body != nil && r.Method != http.MethodGet {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeVaultResponse(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{err.Error()}})
+			return
+		}
+	}
+
+	if r.URL.Path == "/v1/auth/kubernetes/login" {
+		if request["jwt"] != testJWT || request["role"] != "koperator" {
+			writeVaultResponse(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+		f.logins++
+		f.tokenRevoked = false
+		writeVaultResponse(w, http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{
+			"client_token":   testToken,
+			"lease_duration": 3600,
+			"renewable":      true,
+		}})
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != testToken || f.tokenRevoked {
+		writeVaultResponse(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/v1/auth/token/renew-self":
+		f.renewals++
+		writeVaultResponse(w, http.StatusOK, map[string]interface{}{"auth": map[string]interface{}{
+			"client_token":   testToken,
+			"lease_duration": 3600,
+			"renewable":      true,
+		}})
+	case r.URL.Path == "/v1/pki/cert/ca" && r.Method == http.MethodGet:
+		if f.caCert == nil {
+			writeVaultResponse(w, http.StatusBadRequest, map[string]interface{}{"errors": []string{"no default issuer currently configured"}})
+			return
+		}
+		writeVaultResponse(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"certificate": f.caPEM}})
+	case r.URL.Path == "/v1/pki/issue/kafka":
+		f.issued = append(f.issued, request)
+		writeVaultResponse(w, http.StatusOK, map[string]interface{}{"data": f.issue(request)})
+	case r.URL.Path == "/v1/pki/revoke":
+		f.revoked = append(f.revoked, request["serial_number"].(string))
+		writeVaultResponse(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{}})
+	default:
+		writeVaultResponse(w, http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+	}
+}
+
+func (f *fakeVault) generateCA(commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	f.caCert, _ = x509.ParseCertificate(raw)
+	f.caKey = key
+	f.caPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}))
+}
+
+func (f *fakeVault) issue(request map[string]interface{}) map[string]interface{} {
+	if f.caCert == nil {
+		f.generateCA("test-ca")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	ttl, err := time.ParseDuration(request["ttl"].(string))
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: request["common_name"].(string)},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(ttl),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	if altNames, ok := request["alt_names"].(string); ok {
+		template.DNSNames = strings.Split(altNames, ",")
+	}
+	if uri, err := url.Parse(request["uri_sans"].(string)); err == nil {
+		template.URIs = []*url.URL{uri}
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, f.caCert, &key.PublicKey, f.caKey)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(raw)
+	return map[string]interface{}{
+		"certificate":   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw})),
+		"private_key":   string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+		"issuing_ca":    f.caPEM,
+		"ca_chain":      []string{f.caPEM},
+		"serial_number": vaultSerialNumber(cert),
+	}
+}
+
+func writeVaultResponse(w http.ResponseWriter, status int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newMockCluster(address string) *v1beta1.KafkaCluster {
+	cluster := &v1beta1.KafkaCluster{}
+	cluster.Name = "test"
+	cluster.Namespace = testNamespace
+	cluster.Spec.ListenersConfig.InternalListeners = []v1beta1.InternalListenerConfig{
+		{CommonListenerSpec: v1beta1.CommonListenerSpec{
+			ContainerPort: 9092,
+		}},
+	}
+	cluster.Spec.ListenersConfig.SSLSecrets = &v1beta1.SSLSecrets{
+		PKIBackend: v1beta1.PKIBackendVault,
+		VaultConfig: &v1beta1.VaultConfig{
+			Address:   address,
+			AuthRole:  "koperator",
+			PKIPath:   "pki",
+			IssueRole: "kafka",
+		},
+	}
+	return cluster
+}
+
+func newMock(t *testing.T, cluster *v1beta1.KafkaCluster) *vaultPKI {
+	t.Setenv("VAULT_TOKEN", "")
+	if err := v1alpha1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1beta1.AddToScheme(scheme.Scheme); err != nil {
+		t.Fatal(err)
+	}
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenPath, []byte(testJWT), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return &vaultPKI{
+		client:    fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		cluster:   cluster,
+		tokenPath: tokenPath,
+	}
+}
+
+func TestWithVaultClientRevokedToken(t *testing.T) {
+	vault, server := newFakeVault(t)
+	vault.generateCA("test-ca")
+	manager := newMock(t, newMockCluster(server.URL))
+
+	vaultConfig, err := manager.getVaultConfig()
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	readCA := func(vaultClient *vaultapi.Client) error {
+		return manager.ensureCA(vaultClient, vaultConfig)
+	}
+	if err := manager.withVaultClient(vaultConfig, readCA); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	// the cached client is evicted and the operator logs in again when its token is denied
+	vault.mu.Lock()
+	vault.tokenRevoked = true
+	vault.mu.Unlock()
+	if err := manager.withVaultClient(vaultConfig, readCA); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if vault.logins != 2 {
+		t.Error("Expected to log in again after the token was denied, got logins:", vault.logins)
+	}
+}
+
+func TestNew(t *testing.T) {
+	pkiManager := New(&mockClient{}, newMockCluster(""))
+	if reflect.TypeOf(pkiManager) != reflect.TypeOf(&vaultPKI{}) {
+		t.Error("Expected new vault PKI from New, got:", reflect.TypeOf(pkiManager))
+	}
+}
+
+func TestGetVaultClient(t *testing.T) {
+	vault, server := newFakeVault(t)
+	manager := newMock(t, newMockCluster(server.URL))
+
+	vaultConfig, err := manager.getVaultConfig()
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	vaultClient, err := manager.getVaultClient(vaultConfig)
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if vaultClient.Token() != testToken {
+		t.Error("Expected the token of the Kubernetes auth method, got:", vaultClient.Token())
+	}
+
+	// the client is cached until its token has to be renewed
+	cachedClient, err := manager.getVaultClient(vaultConfig)
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if cachedClient != vaultClient || vault.logins != 1 {
+		t.Error("Expected the cached client without logging in again, got logins:", vault.logins)
+	}
+	vaultClientsMu.Lock()
+	for _, cached := range vaultClients {
+		if cached.client == vaultClient {
+			cached.renewAt = time.Now().Add(-time.Second)
+		}
+	}
+	vaultClientsMu.Unlock()
+	if _, err := manager.getVaultClient(vaultConfig); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if vault.renewals != 1 || vault.logins != 1 {
+		t.Error("Expected the token to be renewed without logging in again, got renewals:", vault.renewals, "logins:", vault.logins)
+	}
+
+	vaultConfig.AuthRole = "unknown"
+	if _, err := manager.getVaultClient(vaultConfig); err == nil {
+		t.Error("Expected error for unknown auth role, got nil")
+	}
+
+	manager.cluster.Spec.ListenersConfig.SSLSecrets.VaultConfig = nil
+	if _, err := manager.getVaultConfig(); err == nil {
+		t.Error("Expected error for missing vault config, got nil")
+	}
+}
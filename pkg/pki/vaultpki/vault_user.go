@@ -0,0 +1,268 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultpki
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	vaultapi "github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+	pkicommon "github.com/banzaicloud/koperator/pkg/util/pki"
+)
+
+// ReconcileUserCertificate ensures a user certificate issued by Vault in the secret of the user. The certificate is
+// issued again when it reaches two thirds of its lifetime or when the DNS names of the user change.
+func (v *vaultPKI) ReconcileUserCertificate(
+	ctx context.Context, user *v1alpha1.KafkaUser, scheme *runtime.Scheme, clusterDomain string) (*pkicommon.UserCertificate, error) {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	secret := &corev1.Secret{}
+	err := v.client.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      user.Spec.SecretName,
+				Namespace: user.Namespace,
+			},
+		}
+	case err != nil:
+		return nil, errorfactory.New(errorfactory.APIFailure{}, err, "failed to get user secret")
+	case isUserCertificateUpToDate(secret, user, time.Now()):
+		return userCertificateFromSecret(secret), nil
+	}
+
+	vaultConfig, err := v.getVaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Issuing user certificate from vault", "user", user.Name, "secretName", user.Spec.SecretName)
+	var issued *issuedCertificate
+	if err := v.withVaultClient(vaultConfig, func(vaultClient *vaultapi.Client) error {
+		issued, err = issueCertificate(vaultClient, vaultConfig, user, clusterDomain)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	// keep the password of the keystores so the brokers can reload the renewed keystores
+	data, err := issued.secretData(user.Spec.GetKeystoreFormats(), secret.Data[v1alpha1.PasswordKey])
+	if err != nil {
+		return nil, errorfactory.New(errorfactory.InternalError{}, err, "could not generate user secret data")
+	}
+	secret.Data = data
+
+	if err = controllerutil.SetControllerReference(user, secret, scheme); err != nil {
+		return nil, errorfactory.New(errorfactory.InternalError{}, err, "could not set controller reference on user secret")
+	}
+	if secret.ResourceVersion == "" {
+		err = v.client.Create(ctx, secret)
+	} else {
+		err = v.client.Update(ctx, secret)
+	}
+	if err != nil {
+		return nil, errorfactory.New(errorfactory.APIFailure{}, err, "could not store user certificate in secret")
+	}
+
+	return userCertificateFromSecret(secret), nil
+}
+
+// FinalizeUserCertificate revokes the certificate of the user issued by Vault
+func (v *vaultPKI) FinalizeUserCertificate(ctx context.Context, user *v1alpha1.KafkaUser) error {
+	secret := &corev1.Secret{}
+	err := v.client.Get(ctx, types.NamespacedName{Name: user.Spec.SecretName, Namespace: user.Namespace}, secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errorfactory.New(errorfactory.APIFailure{}, err, "failed to get user secret")
+	}
+	// only the certificates issued for the user are revoked
+	if !metav1.IsControlledBy(secret, user) {
+		return nil
+	}
+	return v.revokeCertificate(ctx, secret)
+}
+
+// revokeCertificate revokes the certificate stored in the secret
+func (v *vaultPKI) revokeCertificate(ctx context.Context, secret *corev1.Secret) error {
+	logger := logr.FromContextOrDiscard(ctx)
+
+	cert, err := certutil.DecodeCertificate(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		logger.Info("Secret does not hold a certificate to revoke", "secretName", secret.Name)
+		return nil
+	}
+
+	vaultConfig, err := v.getVaultConfig()
+	if err != nil {
+		return err
+	}
+
+	serialNumber := vaultSerialNumber(cert)
+	logger.Info("Revoking certificate in vault", "secretName", secret.Name, "serialNumber", serialNumber)
+	return v.withVaultClient(vaultConfig, func(vaultClient *vaultapi.Client) error {
+		_, err := vaultClient.Logical().Write(vaultConfig.PKIPath+"/revoke", map[string]interface{}{
+			"serial_number": serialNumber,
+		})
+		if err != nil {
+			return errorfactory.New(errorfactory.VaultAPIFailure{}, err, "could not revoke certificate", "serialNumber", serialNumber)
+		}
+		return nil
+	})
+}
+
+// issuedCertificate holds a certificate issued by Vault in PEM format
+type issuedCertificate struct {
+	certificate []byte
+	privateKey  []byte
+	caChain     []byte
+}
+
+// issueCertificate issues a certificate for the user from the PKI secrets engine of Vault
+func issueCertificate(vaultClient *vaultapi.Client, vaultConfig *v1beta1.VaultConfig, user *v1alpha1.KafkaUser, clusterDomain string) (*issuedCertificate, error) {
+	request := map[string]interface{}{
+		"common_name":          user.GetName(),
+		"uri_sans":             fmt.Sprintf(spiffeIdTemplate, clusterDomain, user.GetNamespace(), user.GetName()),
+		"ttl":                  fmt.Sprintf("%ds", user.Spec.GetExpirationSeconds()),
+		"format":               "pem",
+		"private_key_format":   "pkcs8",
+		"exclude_cn_from_sans": true,
+	}
+	if len(user.Spec.DNSNames) > 0 {
+		request["alt_names"] = strings.Join(user.Spec.DNSNames, ",")
+	}
+
+	secret, err := vaultClient.Logical().Write(vaultConfig.PKIPath+"/issue/"+vaultConfig.IssueRole, request)
+	if err != nil {
+		return nil, errorfactory.New(errorfactory.VaultAPIFailure{}, err, "could not issue certificate", "user", user.GetName())
+	}
+	if secret == nil {
+		return nil, errorfactory.New(errorfactory.VaultAPIFailure{}, errors.New("empty response"), "could not issue certificate", "user", user.GetName())
+	}
+
+	certificate, _ := secret.Data["certificate"].(string)
+	privateKey, _ := secret.Data["private_key"].(string)
+	var caChain []string
+	if chain, ok := secret.Data["ca_chain"].([]interface{}); ok {
+		for _, ca := range chain {
+			if ca, ok := ca.(string); ok {
+				caChain = append(caChain, ca)
+			}
+		}
+	}
+	if issuingCA, ok := secret.Data["issuing_ca"].(string); ok && len(caChain) == 0 {
+		caChain = append(caChain, issuingCA)
+	}
+	if certificate == "" || privateKey == "" || len(caChain) == 0 {
+		return nil, errorfactory.New(errorfactory.VaultAPIFailure{}, errors.New("incomplete response"), "could not issue certificate", "user", user.GetName())
+	}
+
+	return &issuedCertificate{
+		certificate: []byte(certificate),
+		privateKey:  []byte(privateKey),
+		caChain:     []byte(strings.Join(caChain, "\n")),
+	}, nil
+}
+
+// secretData returns the content of the user secret, the keystores are protected by the given password when it is set
+func (c *issuedCertificate) secretData(keystoreFormats []v1beta1.KeystoreFormat, password []byte) (map[string][]byte, error) {
+	certs, err := certutil.ParseCertificates(append(append(append([]byte{}, c.certificate...), '\n'), c.caChain...))
+	if err != nil {
+		return nil, err
+	}
+	data, err := certutil.GenerateKeystores(keystoreFormats, certutil.GetCertBundle(certs), c.privateKey, password)
+	if err != nil {
+		return nil, err
+	}
+	data[v1alpha1.CoreCACertKey] = c.caChain
+	data[corev1.TLSCertKey] = c.certificate
+	data[corev1.TLSPrivateKeyKey] = c.privateKey
+	return data, nil
+}
+
+// isUserCertificateUpToDate returns true if the secret holds a certificate for the user which doesn't have to be renewed yet
+func isUserCertificateUpToDate(secret *corev1.Secret, user *v1alpha1.KafkaUser, now time.Time) bool {
+	requiredFields := append([]string{v1alpha1.CoreCACertKey, corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+		certutil.KeystoreFields(user.Spec.GetKeystoreFormats())...)
+	for _, field := range requiredFields {
+		if len(secret.Data[field]) == 0 {
+			return false
+		}
+	}
+
+	cert, err := certutil.DecodeCertificate(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return false
+	}
+	if cert.Subject.CommonName != user.GetName() || !sameDNSNames(cert.DNSNames, user.Spec.DNSNames) {
+		return false
+	}
+	renewBefore := cert.NotAfter.Sub(cert.NotBefore) / 3
+	return now.Before(cert.NotAfter.Add(-renewBefore))
+}
+
+func sameDNSNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// vaultSerialNumber returns the serial number of the certificate in the format used by Vault
+func vaultSerialNumber(cert *x509.Certificate) string {
+	serialBytes := cert.SerialNumber.Bytes()
+	parts := make([]string, 0, len(serialBytes))
+	for _, b := range serialBytes {
+		parts = append(parts, fmt.Sprintf("%02x", b))
+	}
+	return strings.Join(parts, ":")
+}
+
+func userCertificateFromSecret(secret *corev1.Secret) *pkicommon.UserCertificate {
+	return &pkicommon.UserCertificate{
+		CA:          secret.Data[v1alpha1.CoreCACertKey],
+		Certificate: secret.Data[corev1.TLSCertKey],
+		Key:         secret.Data[corev1.TLSPrivateKeyKey],
+		JKS:         secret.Data[v1alpha1.TLSJKSKeyStore],
+		Password:    secret.Data[v1alpha1.PasswordKey],
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vaultpki
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+)
+
+func newMockUser() *v1alpha1.KafkaUser {
+	user := &v1alpha1.KafkaUser{}
+	user.Name = "test-user"
+	user.Namespace = testNamespace
+	user.UID = "test-user-uid"
+	user.Spec = v1alpha1.KafkaUserSpec{SecretName: "test-secret", IncludeJKS: true, DNSNames: []string{"test.example.com"}}
+	return user
+}
+
+func TestReconcileUserCertificate(t *testing.T) {
+	vault, server := newFakeVault(t)
+	manager := newMock(t, newMockCluster(server.URL))
+	ctx := context.Background()
+	user := newMockUser()
+
+	userCert, err := manager.ReconcileUserCertificate(ctx, user, manager.client.Scheme(), "cluster.local")
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if len(vault.issued) != 1 {
+		t.Fatal("Expected a certificate to be issued, got:", len(vault.issued))
+	}
+	if dn, err := userCert.GetDistinguishedName(); err != nil || dn != "CN=test-user" {
+		t.Error("Expected distinguished name CN=test-user, got:", dn, err)
+	}
+	if vault.issued[0]["uri_sans"] != "spiffe://cluster.local/ns/test-namespace/kafkauser/test-user" {
+		t.Error("Expected SPIFFE ID of the user, got:", vault.issued[0]["uri_sans"])
+	}
+
+	secret := &corev1.Secret{}
+	if err := manager.client.Get(ctx, types.NamespacedName{Name: "test-secret", Namespace: testNamespace}, secret); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if !metav1.IsControlledBy(secret, user) {
+		t.Error("Expected the secret to be controlled by the user")
+	}
+	tlsCert, err := certutil.ParseKeyStoreToTLSCertificate(secret.Data[v1alpha1.TLSJKSKeyStore], secret.Data[v1alpha1.PasswordKey])
+	if err != nil {
+		t.Fatal("Expected valid keystore, got:", err)
+	}
+	if !bytes.Equal(tlsCert.Leaf.Raw, mustDecodeCertificate(t, secret.Data[corev1.TLSCertKey]).Raw) {
+		t.Error("Expected the issued certificate in the keystore")
+	}
+
+	// the certificate is up to date
+	if _, err := manager.ReconcileUserCertificate(ctx, user, manager.client.Scheme(), "cluster.local"); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if len(vault.issued) != 1 {
+		t.Error("Expected the certificate not to be issued again, got:", len(vault.issued))
+	}
+
+	// the certificate is issued again with the same keystore password when the DNS names change
+	password := secret.Data[v1alpha1.PasswordKey]
+	user.Spec.DNSNames = append(user.Spec.DNSNames, "other.example.com")
+	if _, err := manager.ReconcileUserCertificate(ctx, user, manager.client.Scheme(), "cluster.local"); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if len(vault.issued) != 2 {
+		t.Error("Expected the certificate to be issued again, got:", len(vault.issued))
+	}
+	if err := manager.client.Get(ctx, types.NamespacedName{Name: "test-secret", Namespace: testNamespace}, secret); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if !bytes.Equal(password, secret.Data[v1alpha1.PasswordKey]) {
+		t.Error("Expected the keystore password to be kept")
+	}
+}
+
+func TestFinalizeUserCertificate(t *testing.T) {
+	vault, server := newFakeVault(t)
+	manager := newMock(t, newMockCluster(server.URL))
+	ctx := context.Background()
+	user := newMockUser()
+
+	if err := manager.FinalizeUserCertificate(ctx, user); err != nil {
+		t.Fatal("Expected no error without user secret, got:", err)
+	}
+
+	if _, err := manager.ReconcileUserCertificate(ctx, user, manager.client.Scheme(), "cluster.local"); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	// the certificates of other users are not revoked
+	otherUser := newMockUser()
+	otherUser.UID = "other-user-uid"
+	if err := manager.FinalizeUserCertificate(ctx, otherUser); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if len(vault.revoked) != 0 {
+		t.Error("Expected no revoked certificates, got:", vault.revoked)
+	}
+
+	if err := manager.FinalizeUserCertificate(ctx, user); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	secret := &corev1.Secret{}
+	if err := manager.client.Get(ctx, types.NamespacedName{Name: "test-secret", Namespace: testNamespace}, secret); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	expected := vaultSerialNumber(mustDecodeCertificate(t, secret.Data[corev1.TLSCertKey]))
+	if len(vault.revoked) != 1 || vault.revoked[0] != expected {
+		t.Error("Expected revoked certificate:", expected, "got:", vault.revoked)
+	}
+}
+
+func TestIsUserCertificateUpToDate(t *testing.T) {
+	_, server := newFakeVault(t)
+	manager := newMock(t, newMockCluster(server.URL))
+	ctx := context.Background()
+	user := newMockUser()
+	user.Spec.IncludeJKS = false
+
+	if _, err := manager.ReconcileUserCertificate(ctx, user, manager.client.Scheme(), "cluster.local"); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	secret := &corev1.Secret{}
+	if err := manager.client.Get(ctx, types.NamespacedName{Name: "test-secret", Namespace: testNamespace}, secret); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	cert := mustDecodeCertificate(t, secret.Data[corev1.TLSCertKey])
+
+	if !isUserCertificateUpToDate(secret, user, time.Now()) {
+		t.Error("Expected the issued certificate to be up to date")
+	}
+	if isUserCertificateUpToDate(secret, user, cert.NotAfter.Add(-cert.NotAfter.Sub(cert.NotBefore)/4)) {
+		t.Error("Expected the certificate to be renewed in the last third of its lifetime")
+	}
+	user.Spec.IncludeJKS = true
+	if isUserCertificateUpToDate(secret, user, time.Now()) {
+		t.Error("Expected the certificate to be issued again when the keystores are missing")
+	}
+	user.Spec.IncludeJKS = false
+	user.Spec.KeystoreFormats = []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPEMBundle}
+	if isUserCertificateUpToDate(secret, user, time.Now()) {
+		t.Error("Expected the certificate to be issued again when the PEM bundle is missing")
+	}
+}
+
+func mustDecodeCertificate(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+	cert, err := certutil.DecodeCertificate(data)
+	if err != nil {
+		t.Fatal("Expected valid certificate, got:", err)
+	}
+	return cert
+}
@@ -231,6 +231,7 @@ func appendGeneratedBrokerCapacities(kafkaCluster *v1beta1.KafkaCluster, log log
 				if err != nil {
 					return nil, errors.WrapIfWithDetails(err, "could not generate broker disks config for broker", v1beta1.BrokerIdLabelKey, broker.Id)
 				}
+				addRemovedBrokerDisks(brokerDisks, kafkaCluster.Status.BrokersState[brokerId])
 				brokerCapacity = BrokerCapacity{
 					BrokerID: strconv.Itoa(int(broker.Id)),
 					Capacity: Capacity{
@@ -349,6 +350,20 @@ func generateBrokerDisks(brokerState v1beta1.Broker, kafkaClusterSpec v1beta1.Ka
 	return logDirs, nil
 }
 
+// addRemovedBrokerDisks keeps the disks removed from the spec in the capacity config until Cruise Control moves
+// the replicas off them. The minimal capacity makes sure that no replicas are moved onto these disks meanwhile.
+func addRemovedBrokerDisks(logDirs map[string]string, brokerState v1beta1.BrokerState) {
+	for mountPath, volumeState := range brokerState.GracefulActionState.VolumeStates {
+		if !volumeState.CruiseControlVolumeState.IsDiskRemoval() || volumeState.CruiseControlVolumeState.IsSucceeded() {
+			continue
+		}
+		logDir := util.StorageConfigKafkaMountPath(mountPath)
+		if _, ok := logDirs[logDir]; !ok {
+			logDirs[logDir] = fmt.Sprintf("%d", MinLogDirSizeInMB)
+		}
+	}
+}
+
 func parseMountPathWithSize(storage v1beta1.StorageConfig) int64 {
 	var q *resource.Quantity
 	if storage.PvcSpec != nil {
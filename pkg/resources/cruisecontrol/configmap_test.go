@@ -1017,3 +1017,28 @@ func TestGenerateCapacityConfigWithUserProvidedInput(t *testing.T) {
 		})
 	}
 }
+
+func TestAddRemovedBrokerDisks(t *testing.T) {
+	logDirs := map[string]string{
+		"/kafka-logs/kafka": "10000",
+	}
+	brokerState := v1beta1.BrokerState{
+		GracefulActionState: v1beta1.GracefulActionState{
+			VolumeStates: map[string]v1beta1.VolumeState{
+				"/kafka-logs":  {CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+				"/kafka-logs2": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalRunning},
+				"/kafka-logs3": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalSucceeded},
+			},
+		},
+	}
+
+	addRemovedBrokerDisks(logDirs, brokerState)
+
+	expected := map[string]string{
+		"/kafka-logs/kafka":  "10000",
+		"/kafka-logs2/kafka": "1",
+	}
+	if !reflect.DeepEqual(logDirs, expected) {
+		t.Errorf("expected: %v, got: %v", expected, logDirs)
+	}
+}
@@ -50,6 +50,8 @@ func (r *Reconciler) allBrokerService() runtime.Object {
 			SessionAffinity: corev1.ServiceAffinityNone,
 			Selector:        apiutil.LabelsForKafka(r.KafkaCluster.GetName()),
 			Ports:           usedPorts,
+			// the operator has to be able to bootstrap through the brokers whose replicas in sync readiness gate is pending
+			PublishNotReadyAddresses: r.KafkaCluster.Spec.RollingUpgradeConfig.ReplicasInSyncReadinessGate,
 		},
 	}
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"emperror.dev/errors"
@@ -123,11 +124,12 @@ func (r *Reconciler) getConfigProperties(bConfig *v1beta1.BrokerConfig, id int32
 	if err != nil {
 		log.Error(err, "could not get mountPaths from broker configmap", v1beta1.BrokerIdLabelKey, id)
 	}
+	mountPathsOld = filterRemovedMountPaths(mountPathsOld, r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(id))])
 	mountPathsNew := generateStorageConfig(bConfig.StorageConfigs)
 	mountPathsMerged, isMountPathRemoved := mergeMountPaths(mountPathsOld, mountPathsNew)
 
 	if isMountPathRemoved {
-		log.Error(errors.New("removed storage is found in the KafkaCluster CR"), "removing storage from broker is not supported", v1beta1.BrokerIdLabelKey, id, "mountPaths", mountPathsOld, "mountPaths in kafkaCluster CR ", mountPathsNew)
+		log.Info("removed storage is kept in the broker configuration until its graceful removal finishes", v1beta1.BrokerIdLabelKey, id, "mountPaths", mountPathsOld, "mountPaths in kafkaCluster CR ", mountPathsNew)
 	}
 
 	if len(mountPathsMerged) != 0 {
@@ -136,6 +138,11 @@ func (r *Reconciler) getConfigProperties(bConfig *v1beta1.BrokerConfig, id int32
 		}
 	}
 
+	// Add tiered storage configuration
+	if ts := r.KafkaCluster.Spec.GetTieredStorageConfig(bConfig); ts != nil {
+		config.Merge(generateTieredStorageConfig(ts, r.KafkaCluster.Spec.ListenersConfig.InternalListeners, log))
+	}
+
 	// Add superuser configuration
 	su := strings.Join(generateSuperUsers(superUsers), ";")
 	if su != "" {
@@ -171,6 +178,25 @@ func mergeMountPaths(mountPathsOld, mountPathsNew []string) ([]string, bool) {
 	return mountPathsMerged, isMountPathRemoved
 }
 
+// filterRemovedMountPaths drops the mountPaths of the disks which were already drained by Cruise Control
+func filterRemovedMountPaths(mountPaths []string, brokerState v1beta1.BrokerState) []string {
+	filtered := make([]string, 0, len(mountPaths))
+	for _, mountPath := range mountPaths {
+		removed := false
+		for volumeMountPath, volumeState := range brokerState.GracefulActionState.VolumeStates {
+			if volumeState.CruiseControlVolumeState == v1beta1.GracefulDiskRemovalSucceeded &&
+				util.StorageConfigKafkaMountPath(volumeMountPath) == mountPath {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			filtered = append(filtered, mountPath)
+		}
+	}
+	return filtered
+}
+
 func generateSuperUsers(users []string) (suStrings []string) {
 	suStrings = make([]string, 0)
 	for _, x := range users {
@@ -181,7 +207,7 @@ func generateSuperUsers(users []string) (suStrings []string) {
 
 func (r *Reconciler) configMap(id int32, brokerConfig *v1beta1.BrokerConfig, extListenerStatuses,
 	intListenerStatuses, controllerIntListenerStatuses map[string]v1beta1.ListenerStatusList,
-	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) *corev1.ConfigMap {
+	serverPasses map[string]string, clientPass string, superUsers []string, configSecretFiles configSecretFiles, log logr.Logger) *corev1.ConfigMap {
 	brokerConf := &corev1.ConfigMap{
 		ObjectMeta: templates.ObjectMeta(
 			fmt.Sprintf(brokerConfigTemplate+"-%d", r.KafkaCluster.Name, id),
@@ -192,7 +218,7 @@ func (r *Reconciler) configMap(id int32, brokerConfig *v1beta1.BrokerConfig, ext
 			r.KafkaCluster,
 		),
 		Data: map[string]string{kafkautils.ConfigPropertyName: r.generateBrokerConfig(id, brokerConfig, extListenerStatuses,
-			intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)},
+			intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, configSecretFiles, log)},
 	}
 	if brokerConfig.Log4jConfig != "" {
 		brokerConf.Data["log4j.properties"] = brokerConfig.Log4jConfig
@@ -252,6 +278,34 @@ func generateStorageConfig(sConfig []v1beta1.StorageConfig) []string {
 	return mountPaths
 }
 
+func generateTieredStorageConfig(ts *v1beta1.TieredStorageConfig, iListeners []v1beta1.InternalListenerConfig, log logr.Logger) *properties.Properties {
+	config := properties.NewProperties()
+
+	tsConfig := map[string]string{
+		kafkautils.KafkaConfigRemoteLogStorageSystemEnable:      "true",
+		kafkautils.KafkaConfigRemoteLogStorageManagerClassName:  ts.RemoteStorageManagerClassName,
+		kafkautils.KafkaConfigRemoteLogStorageManagerClassPath:  tieredStoragePluginsPath + "/*",
+		kafkautils.KafkaConfigRemoteLogMetadataManagerClassName: ts.GetRemoteLogMetadataManagerClassName(),
+	}
+	// The topic based remote log metadata manager uses the inter broker listener to reach the metadata topic
+	for _, iListener := range iListeners {
+		if iListener.UsedForInnerBrokerCommunication {
+			tsConfig[kafkautils.KafkaConfigRemoteLogMetadataManagerListenerName] = strings.ToUpper(iListener.Name)
+			break
+		}
+	}
+	for k, v := range ts.Config {
+		tsConfig[kafkautils.KafkaConfigRemoteLogStorageManagerConfigPrefix+k] = v
+	}
+
+	for k, v := range tsConfig {
+		if err := config.Set(k, v); err != nil {
+			log.Error(err, fmt.Sprintf("setting '%s' parameter in broker configuration resulted an error", k))
+		}
+	}
+	return config
+}
+
 func generateControlPlaneListener(iListeners []v1beta1.InternalListenerConfig) string {
 	controlPlaneListener := ""
 
@@ -267,6 +321,7 @@ func generateControlPlaneListener(iListeners []v1beta1.InternalListenerConfig) s
 func generateListenerSpecificConfig(l *v1beta1.ListenersConfig, serverPasses map[string]string, log logr.Logger) *properties.Properties {
 	var (
 		interBrokerListenerName   string
+		interBrokerSASLMechanism  v1beta1.SASLMechanism
 		securityProtocolMapConfig []string
 		listenerConfig            []string
 	)
@@ -287,7 +342,14 @@ func generateListenerSpecificConfig(l *v1beta1.ListenersConfig, serverPasses map
 		listenerConfig = append(listenerConfig, fmt.Sprintf("%s://:%d", upperedListenerName, iListener.ContainerPort))
 		// Add internal listeners SSL configuration
 		if iListener.Type == v1beta1.SecurityProtocolSSL {
-			generateListenerSSLConfig(config, iListener.Name, iListener.SSLClientAuth, serverPasses[iListener.Name], log)
+			generateListenerSSLConfig(config, iListener.Name, iListener.SSLClientAuth, listenerKeystoreFormat(l, iListener.CommonListenerSpec), serverPasses[iListener.Name], log)
+		}
+		// Add internal listeners SASL configuration
+		if iListener.Type.IsSasl() && iListener.SASL != nil {
+			generateListenerSASLConfig(config, iListener.CommonListenerSpec, log)
+			if iListener.UsedForInnerBrokerCommunication || (iListener.UsedForControllerCommunication && interBrokerSASLMechanism == "") {
+				interBrokerSASLMechanism = iListener.SASL.GetInterBrokerMechanism()
+			}
 		}
 	}
 
@@ -298,7 +360,17 @@ func generateListenerSpecificConfig(l *v1beta1.ListenersConfig, serverPasses map
 		listenerConfig = append(listenerConfig, fmt.Sprintf("%s://:%d", upperedListenerName, eListener.ContainerPort))
 		// Add external listeners SSL configuration
 		if eListener.Type == v1beta1.SecurityProtocolSSL {
-			generateListenerSSLConfig(config, eListener.Name, eListener.SSLClientAuth, serverPasses[eListener.Name], log)
+			generateListenerSSLConfig(config, eListener.Name, eListener.SSLClientAuth, listenerKeystoreFormat(l, eListener.CommonListenerSpec), serverPasses[eListener.Name], log)
+		}
+		// Add external listeners SASL configuration
+		if eListener.Type.IsSasl() && eListener.SASL != nil {
+			generateListenerSASLConfig(config, eListener.CommonListenerSpec, log)
+		}
+	}
+	// The brokers authenticate with the same mechanism on the inter broker and on the control plane listener
+	if interBrokerSASLMechanism != "" {
+		if err := config.Set(kafkautils.KafkaConfigSASLMechanismInterBrokerProtocol, string(interBrokerSASLMechanism)); err != nil {
+			log.Error(err, fmt.Sprintf("setting '%s' parameter in broker configuration resulted an error", kafkautils.KafkaConfigSASLMechanismInterBrokerProtocol))
 		}
 	}
 	if err := config.Set(kafkautils.KafkaConfigListenerSecurityProtocolMap, securityProtocolMapConfig); err != nil {
@@ -313,21 +385,48 @@ func generateListenerSpecificConfig(l *v1beta1.ListenersConfig, serverPasses map
 	return config
 }
 
-func generateListenerSSLConfig(config *properties.Properties, name string, sslClientAuth v1beta1.SSLClientAuthentication, password string, log logr.Logger) {
+// listenerKeystoreFormat returns the keystore format the listener reads its server certificate from.
+// Custom server certificate secrets are always read as JKS.
+func listenerKeystoreFormat(l *v1beta1.ListenersConfig, commonSpec v1beta1.CommonListenerSpec) v1beta1.KeystoreFormat {
+	if l.SSLSecrets == nil || commonSpec.GetServerSSLCertSecretName() != "" {
+		return v1beta1.KeystoreFormatJKS
+	}
+	return l.SSLSecrets.GetListenerKeystoreFormat()
+}
+
+func generateListenerSSLConfig(config *properties.Properties, name string, sslClientAuth v1beta1.SSLClientAuthentication,
+	keystoreFormat v1beta1.KeystoreFormat, password string, log logr.Logger) {
 	var listenerSSLConfig map[string]string
 	namedKeystorePath := fmt.Sprintf(listenerServerKeyStorePathTemplate, serverKeystorePath, name)
-	keyStoreType := "JKS"
-	keyStoreLoc := namedKeystorePath + "/" + v1alpha1.TLSJKSKeyStore
-	trustStoreType := "JKS"
-	trustStoreLoc := namedKeystorePath + "/" + v1alpha1.TLSJKSTrustStore
 
-	listenerSSLConfig = map[string]string{
-		fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyStoreLocation):   keyStoreLoc,
-		fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreLocation): trustStoreLoc,
-		fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeystoreType):       keyStoreType,
-		fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreType):     trustStoreType,
-		fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStorePassword): password,
-		fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyStorePassword):   password,
+	switch keystoreFormat {
+	case v1beta1.KeystoreFormatPEMBundle:
+		// PEM keystores hold an encrypted private key and must not have a keystore or truststore password
+		listenerSSLConfig = map[string]string{
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyStoreLocation):   namedKeystorePath + "/" + v1alpha1.TLSPEMKeyStore,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreLocation): namedKeystorePath + "/" + v1alpha1.TLSPEMTrustStore,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeystoreType):       "PEM",
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreType):     "PEM",
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyPassword):        password,
+		}
+	case v1beta1.KeystoreFormatPKCS12:
+		listenerSSLConfig = map[string]string{
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyStoreLocation):   namedKeystorePath + "/" + v1alpha1.TLSPKCS12KeyStore,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreLocation): namedKeystorePath + "/" + v1alpha1.TLSPKCS12TrustStore,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeystoreType):       "PKCS12",
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreType):     "PKCS12",
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStorePassword): password,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyStorePassword):   password,
+		}
+	default:
+		listenerSSLConfig = map[string]string{
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyStoreLocation):   namedKeystorePath + "/" + v1alpha1.TLSJKSKeyStore,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreLocation): namedKeystorePath + "/" + v1alpha1.TLSJKSTrustStore,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeystoreType):       "JKS",
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStoreType):     "JKS",
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLTrustStorePassword): password,
+			fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, name, kafkautils.KafkaConfigSSLKeyStorePassword):   password,
+		}
 	}
 
 	// enable 2-way SSL authentication if SSL is enabled but this field is not provided in the listener config
@@ -384,8 +483,12 @@ func mergeSuperUsersPropertyValue(source *properties.Properties, target *propert
 
 func (r Reconciler) generateBrokerConfig(id int32, brokerConfig *v1beta1.BrokerConfig, extListenerStatuses,
 	intListenerStatuses, controllerIntListenerStatuses map[string]v1beta1.ListenerStatusList,
-	serverPasses map[string]string, clientPass string, superUsers []string, log logr.Logger) string {
-	finalBrokerConfig := getBrokerReadOnlyConfig(id, r.KafkaCluster, log)
+	serverPasses map[string]string, clientPass string, superUsers []string, configSecretFiles configSecretFiles, log logr.Logger) string {
+	finalBrokerConfig := kafkautils.GetBrokerReadOnlyConfig(id, r.KafkaCluster, log)
+
+	// Add the configurations set from secret keys
+	configSecretRefs := r.KafkaCluster.Spec.GetConfigSecretRefs(brokerConfig)
+	finalBrokerConfig.Merge(generateConfigSecretConfig(configSecretRefs, configSecretFiles, finalBrokerConfig, log))
 
 	// Get operator generated configuration
 	opGenConf := r.getConfigProperties(brokerConfig, id, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
@@ -406,32 +509,3 @@ func (r Reconciler) generateBrokerConfig(id int32, brokerConfig *v1beta1.BrokerC
 
 	return finalBrokerConfig.String()
 }
-
-// TODO move this into api in the future (adamantal)
-func getBrokerReadOnlyConfig(id int32, kafkaCluster *v1beta1.KafkaCluster, log logr.Logger) *properties.Properties {
-	// Parse cluster-wide readonly configuration
-	finalBrokerConfig, err := properties.NewFromString(kafkaCluster.Spec.ReadOnlyConfig)
-	if err != nil {
-		log.Error(err, "failed to parse readonly cluster configuration")
-	}
-
-	// Parse readonly broker configuration
-	var parsedReadOnlyBrokerConfig *properties.Properties
-	// Find configuration for broker with id
-	for _, broker := range kafkaCluster.Spec.Brokers {
-		if broker.Id == id {
-			parsedReadOnlyBrokerConfig, err = properties.NewFromString(broker.ReadOnlyConfig)
-			if err != nil {
-				log.Error(err, fmt.Sprintf("failed to parse readonly broker configuration for broker with id: %d", id))
-			}
-			break
-		}
-	}
-
-	// Merge cluster-wide configuration into broker-level configuration
-	if parsedReadOnlyBrokerConfig != nil {
-		finalBrokerConfig.Merge(parsedReadOnlyBrokerConfig)
-	}
-
-	return finalBrokerConfig
-}
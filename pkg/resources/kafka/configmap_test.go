@@ -126,6 +126,52 @@ func TestMergeMountPaths(t *testing.T) {
 	}
 }
 
+func TestFilterRemovedMountPaths(t *testing.T) {
+	tests := []struct {
+		testName           string
+		mountPaths         []string
+		brokerState        v1beta1.BrokerState
+		expectedMountPaths []string
+	}{
+		{
+			testName:           "no volume states",
+			mountPaths:         []string{"/kafka-logs/kafka", "/kafka-logs2/kafka"},
+			expectedMountPaths: []string{"/kafka-logs/kafka", "/kafka-logs2/kafka"},
+		},
+		{
+			testName:   "disk removal is in progress",
+			mountPaths: []string{"/kafka-logs/kafka", "/kafka-logs2/kafka"},
+			brokerState: v1beta1.BrokerState{
+				GracefulActionState: v1beta1.GracefulActionState{
+					VolumeStates: map[string]v1beta1.VolumeState{
+						"/kafka-logs2": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalRunning},
+					},
+				},
+			},
+			expectedMountPaths: []string{"/kafka-logs/kafka", "/kafka-logs2/kafka"},
+		},
+		{
+			testName:   "disk removal succeeded",
+			mountPaths: []string{"/kafka-logs/kafka", "/kafka-logs2/kafka"},
+			brokerState: v1beta1.BrokerState{
+				GracefulActionState: v1beta1.GracefulActionState{
+					VolumeStates: map[string]v1beta1.VolumeState{
+						"/kafka-logs":  {CruiseControlVolumeState: v1beta1.GracefulDiskRebalanceSucceeded},
+						"/kafka-logs2": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalSucceeded},
+					},
+				},
+			},
+			expectedMountPaths: []string{"/kafka-logs/kafka"},
+		},
+	}
+	for _, test := range tests {
+		mountPaths := filterRemovedMountPaths(test.mountPaths, test.brokerState)
+		if !reflect.DeepEqual(mountPaths, test.expectedMountPaths) {
+			t.Errorf("testName: %s, expected: %s, got: %s", test.testName, test.expectedMountPaths, mountPaths)
+		}
+	}
+}
+
 func TestGenerateBrokerConfig(t *testing.T) { //nolint funlen
 	tests := []struct {
 		testName                  string
@@ -683,7 +729,7 @@ zookeeper.connect=example.zk:2181/`,
 				superUsers = []string{"CN=kafka-headless.kafka.svc.cluster.local"}
 			}
 
-			generatedConfig := r.generateBrokerConfig(0, r.KafkaCluster.Spec.Brokers[0].BrokerConfig, map[string]v1beta1.ListenerStatusList{}, map[string]v1beta1.ListenerStatusList{}, controllerListenerStatus, serverPasses, clientPass, superUsers, logr.Discard())
+			generatedConfig := r.generateBrokerConfig(0, r.KafkaCluster.Spec.Brokers[0].BrokerConfig, map[string]v1beta1.ListenerStatusList{}, map[string]v1beta1.ListenerStatusList{}, controllerListenerStatus, serverPasses, clientPass, superUsers, nil, logr.Discard())
 
 			generated, err := properties.NewFromString(generatedConfig)
 			if err != nil {
@@ -701,3 +747,131 @@ zookeeper.connect=example.zk:2181/`,
 		})
 	}
 }
+
+func TestGenerateListenerSpecificConfigKeystoreFormat(t *testing.T) {
+	tests := []struct {
+		testName       string
+		sslSecrets     *v1beta1.SSLSecrets
+		serverSSLCert  string
+		expectedConfig string
+	}{
+		{
+			testName:   "jks",
+			sslSecrets: &v1beta1.SSLSecrets{},
+			expectedConfig: `listener.name.internal.ssl.client.auth=required
+listener.name.internal.ssl.keystore.location=/var/run/secrets/java.io/keystores/server/internal/keystore.jks
+listener.name.internal.ssl.keystore.password=serverpassword
+listener.name.internal.ssl.keystore.type=JKS
+listener.name.internal.ssl.truststore.location=/var/run/secrets/java.io/keystores/server/internal/truststore.jks
+listener.name.internal.ssl.truststore.password=serverpassword
+listener.name.internal.ssl.truststore.type=JKS`,
+		},
+		{
+			testName:   "pkcs12",
+			sslSecrets: &v1beta1.SSLSecrets{ListenerKeystoreFormat: v1beta1.KeystoreFormatPKCS12},
+			expectedConfig: `listener.name.internal.ssl.client.auth=required
+listener.name.internal.ssl.keystore.location=/var/run/secrets/java.io/keystores/server/internal/keystore.p12
+listener.name.internal.ssl.keystore.password=serverpassword
+listener.name.internal.ssl.keystore.type=PKCS12
+listener.name.internal.ssl.truststore.location=/var/run/secrets/java.io/keystores/server/internal/truststore.p12
+listener.name.internal.ssl.truststore.password=serverpassword
+listener.name.internal.ssl.truststore.type=PKCS12`,
+		},
+		{
+			testName:   "pem-bundle",
+			sslSecrets: &v1beta1.SSLSecrets{ListenerKeystoreFormat: v1beta1.KeystoreFormatPEMBundle},
+			expectedConfig: `listener.name.internal.ssl.client.auth=required
+listener.name.internal.ssl.key.password=serverpassword
+listener.name.internal.ssl.keystore.location=/var/run/secrets/java.io/keystores/server/internal/keystore.pem
+listener.name.internal.ssl.keystore.type=PEM
+listener.name.internal.ssl.truststore.location=/var/run/secrets/java.io/keystores/server/internal/truststore.pem
+listener.name.internal.ssl.truststore.type=PEM`,
+		},
+		{
+			testName:      "custom server certificate",
+			sslSecrets:    &v1beta1.SSLSecrets{ListenerKeystoreFormat: v1beta1.KeystoreFormatPEMBundle},
+			serverSSLCert: "custom-server-cert",
+			expectedConfig: `listener.name.internal.ssl.client.auth=required
+listener.name.internal.ssl.keystore.location=/var/run/secrets/java.io/keystores/server/internal/keystore.jks
+listener.name.internal.ssl.keystore.password=serverpassword
+listener.name.internal.ssl.keystore.type=JKS
+listener.name.internal.ssl.truststore.location=/var/run/secrets/java.io/keystores/server/internal/truststore.jks
+listener.name.internal.ssl.truststore.password=serverpassword
+listener.name.internal.ssl.truststore.type=JKS`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.testName, func(t *testing.T) {
+			listenersConfig := &v1beta1.ListenersConfig{
+				InternalListeners: []v1beta1.InternalListenerConfig{{
+					CommonListenerSpec: v1beta1.CommonListenerSpec{
+						Type:          v1beta1.SecurityProtocolSSL,
+						Name:          "internal",
+						ContainerPort: 9092,
+					},
+					UsedForInnerBrokerCommunication: true,
+				}},
+				SSLSecrets: test.sslSecrets,
+			}
+			if test.serverSSLCert != "" {
+				listenersConfig.InternalListeners[0].ServerSSLCertSecret = &v1.LocalObjectReference{Name: test.serverSSLCert}
+			}
+
+			generated := generateListenerSpecificConfig(listenersConfig, map[string]string{"internal": "serverpassword"}, logr.Discard())
+			for _, key := range []string{kafkautils.KafkaConfigListenerSecurityProtocolMap, kafkautils.KafkaConfigInterBrokerListenerName,
+				kafkautils.KafkaConfigListeners} {
+				generated.Delete(key)
+			}
+
+			expected, err := properties.NewFromString(test.expectedConfig)
+			if err != nil {
+				t.Fatalf("failed parsing expected configuration as Properties: %s", test.expectedConfig)
+			}
+			if !expected.Equal(generated) {
+				t.Errorf("the expected config is:\n%s\nreceived:\n%s\n", test.expectedConfig, generated)
+			}
+		})
+	}
+}
+
+func TestGenerateTieredStorageConfig(t *testing.T) {
+	tieredStorage := &v1beta1.TieredStorageConfig{
+		RemoteStorageManagerClassName: "io.aiven.kafka.tieredstorage.RemoteStorageManager",
+		PluginImage:                   "tiered-storage-plugin:latest",
+		Config: map[string]string{
+			"storage.backend.class": "io.aiven.kafka.tieredstorage.storage.s3.S3Storage",
+			"storage.s3.region":     "eu-west-1",
+		},
+	}
+	internalListeners := []v1beta1.InternalListenerConfig{
+		{
+			CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "controller"},
+		},
+		{
+			CommonListenerSpec:              v1beta1.CommonListenerSpec{Name: "internal"},
+			UsedForInnerBrokerCommunication: true,
+		},
+	}
+
+	expected := properties.NewProperties()
+	for k, v := range map[string]string{
+		"remote.log.storage.system.enable":          "true",
+		"remote.log.storage.manager.class.name":     "io.aiven.kafka.tieredstorage.RemoteStorageManager",
+		"remote.log.storage.manager.class.path":     "/opt/kafka/libs/tiered-storage/*",
+		"remote.log.metadata.manager.class.name":    "org.apache.kafka.server.log.remote.metadata.storage.TopicBasedRemoteLogMetadataManager",
+		"remote.log.metadata.manager.listener.name": "INTERNAL",
+		"rsm.config.storage.backend.class":          "io.aiven.kafka.tieredstorage.storage.s3.S3Storage",
+		"rsm.config.storage.s3.region":              "eu-west-1",
+	} {
+		if err := expected.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := generateTieredStorageConfig(tieredStorage, internalListeners, logr.Discard())
+	if !config.Equal(expected) {
+		t.Errorf("expected: %s, got: %s", expected, config)
+	}
+}
@@ -15,6 +15,7 @@
 package kafka
 
 import (
+	"sort"
 	"strconv"
 
 	"emperror.dev/errors"
@@ -44,20 +45,15 @@ func (r *Reconciler) reconcilePerBrokerDynamicConfig(brokerId int32, brokerConfi
 		return errors.WrapIf(err, "could not parse broker configuration")
 	}
 
-	currentPerBrokerConfigState := r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(brokerId))].PerBrokerConfigurationState
+	brokerState := r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(brokerId))]
+	currentPerBrokerConfigState := brokerState.PerBrokerConfigurationState
 	if fullPerBrokerConfig.Len() == 0 && currentPerBrokerConfigState != v1beta1.PerBrokerConfigOutOfSync {
 		return nil
 	}
 
 	// overwrite configs from configmap
-	configsFromConfigMap, err := properties.NewFromString(configMap.Data[kafka.ConfigPropertyName])
-	if err != nil {
-		return errors.WrapIf(err, "could not parse broker configuration from configmap")
-	}
-	for _, perBrokerConfig := range kafka.PerBrokerConfigs {
-		if configProperty, ok := configsFromConfigMap.Get(perBrokerConfig); ok {
-			fullPerBrokerConfig.Put(configProperty)
-		}
+	if err := mergePerBrokerConfigsFromConfigMap(fullPerBrokerConfig, configMap); err != nil {
+		return err
 	}
 
 	// query the current config
@@ -67,7 +63,19 @@ func (r *Reconciler) reconcilePerBrokerDynamicConfig(brokerId int32, brokerConfi
 		return errors.WrapIfWithDetails(err, "could not describe broker config", v1beta1.BrokerIdLabelKey, brokerId)
 	}
 
-	if shouldUpdatePerBrokerConfig(response, fullPerBrokerConfig) {
+	// the changes of the configurations set from secrets can not be described, they are reported as pending
+	// per-broker config changes when the broker configmap is updated
+	if shouldUpdatePerBrokerConfig(response, fullPerBrokerConfig) || hasPendingPerBrokerConfigChanges(brokerState) {
+		if configChanges := getPerBrokerConfigChanges(response, fullPerBrokerConfig); len(configChanges) > 0 {
+			pendingConfigChanges := k8sutil.PendingConfigChanges{
+				Types:   []v1beta1.ConfigChangeType{v1beta1.ConfigChangeDynamicPerBroker},
+				Changes: configChanges,
+			}
+			if statusErr := k8sutil.UpdateBrokerStatus(r.Client, []string{strconv.Itoa(int(brokerId))}, r.KafkaCluster, pendingConfigChanges, log); statusErr != nil {
+				return errors.WrapIfWithDetails(statusErr, "updating pending per-broker config changes failed", v1beta1.BrokerIdLabelKey, brokerId)
+			}
+		}
+
 		if currentPerBrokerConfigState == v1beta1.PerBrokerConfigInSync {
 			log.V(1).Info("setting per broker config status to out of sync")
 			statusErr := k8sutil.UpdateBrokerStatus(r.Client, []string{strconv.Itoa(int(brokerId))}, r.KafkaCluster, v1beta1.PerBrokerConfigOutOfSync, log)
@@ -118,7 +126,27 @@ func (r *Reconciler) reconcilePerBrokerDynamicConfig(brokerId int32, brokerConfi
 	return nil
 }
 
-func (r *Reconciler) reconcileClusterWideDynamicConfig() error {
+// mergePerBrokerConfigsFromConfigMap adds the per-broker configurations generated into the broker configmap to the config
+func mergePerBrokerConfigsFromConfigMap(config *properties.Properties, configMap *corev1.ConfigMap) error {
+	configsFromConfigMap, err := properties.NewFromString(configMap.Data[kafka.ConfigPropertyName])
+	if err != nil {
+		return errors.WrapIf(err, "could not parse broker configuration from configmap")
+	}
+	for _, perBrokerConfig := range kafka.PerBrokerConfigs {
+		if configProperty, ok := configsFromConfigMap.Get(perBrokerConfig); ok {
+			config.Put(configProperty)
+		}
+	}
+	// configurations set from secrets which can be updated dynamically
+	for _, key := range configsFromConfigMap.Keys() {
+		if configProperty, ok := configsFromConfigMap.Get(key); ok && kafka.IsDynamicConfigSecretRef(key, configProperty.Value()) {
+			config.Put(configProperty)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileClusterWideDynamicConfig(log logr.Logger) error {
 	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
 	if err != nil {
 		return errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
@@ -141,6 +169,11 @@ func (r *Reconciler) reconcileClusterWideDynamicConfig() error {
 	}
 
 	if !currentClusterWideConfig.Equal(parsedClusterWideConfig) {
+		configChanges := kafka.GetConfigChanges(currentClusterWideConfig, parsedClusterWideConfig, v1beta1.ConfigChangeDynamicClusterWide)
+		if err = r.updateClusterWidePendingConfigChanges(configChanges, log); err != nil {
+			return err
+		}
+
 		err = kClient.AlterClusterWideConfig(util.ConvertPropertiesToMapStringPointer(parsedClusterWideConfig), true)
 		if err != nil {
 			return errors.WrapIf(err, "validation of cluster wide config update failed")
@@ -152,16 +185,59 @@ func (r *Reconciler) reconcileClusterWideDynamicConfig() error {
 		}
 	}
 
+	return r.updateClusterWidePendingConfigChanges(nil, log)
+}
+
+// updateClusterWidePendingConfigChanges sets the pending cluster-wide config changes in the state of every broker
+func (r *Reconciler) updateClusterWidePendingConfigChanges(configChanges []v1beta1.PendingConfigChange, log logr.Logger) error {
+	brokerIDs := make([]string, 0, len(r.KafkaCluster.Status.BrokersState))
+	needsUpdate := len(configChanges) > 0
+	for brokerID, brokerState := range r.KafkaCluster.Status.BrokersState {
+		brokerIDs = append(brokerIDs, brokerID)
+		for _, configChange := range brokerState.PendingConfigChanges {
+			if configChange.Type == v1beta1.ConfigChangeDynamicClusterWide {
+				needsUpdate = true
+			}
+		}
+	}
+	if !needsUpdate || len(brokerIDs) == 0 {
+		return nil
+	}
+	sort.Strings(brokerIDs)
+
+	pendingConfigChanges := k8sutil.PendingConfigChanges{
+		Types:   []v1beta1.ConfigChangeType{v1beta1.ConfigChangeDynamicClusterWide},
+		Changes: configChanges,
+	}
+	if err := k8sutil.UpdateBrokerStatus(r.Client, brokerIDs, r.KafkaCluster, pendingConfigChanges, log); err != nil {
+		return errors.WrapIf(err, "updating pending cluster wide config changes failed")
+	}
 	return nil
 }
 
+// getPerBrokerConfigChanges returns the changes of the per-broker configurations described by the broker
+func getPerBrokerConfigChanges(response []*sarama.ConfigEntry, brokerConfig *properties.Properties) []v1beta1.PendingConfigChange {
+	currentConfig := properties.NewProperties()
+	desiredConfig := properties.NewProperties()
+	for _, conf := range response {
+		if val, ok := brokerConfig.Get(conf.Name); ok && isComparablePerBrokerConfig(conf, val.Value()) {
+			// Setting string value for a property is not going to run into error
+			//nolint:errcheck
+			currentConfig.Set(conf.Name, conf.Value)
+			//nolint:errcheck
+			desiredConfig.Set(conf.Name, val.Value())
+		}
+	}
+	return kafka.GetConfigChanges(currentConfig, desiredConfig, v1beta1.ConfigChangeDynamicPerBroker)
+}
+
 func shouldUpdatePerBrokerConfig(response []*sarama.ConfigEntry, brokerConfig *properties.Properties) bool {
 	if brokerConfig == nil {
 		return false
 	}
 
 	for _, conf := range response {
-		if val, ok := brokerConfig.Get(conf.Name); ok {
+		if val, ok := brokerConfig.Get(conf.Name); ok && isComparablePerBrokerConfig(conf, val.Value()) {
 			if val.Value() != conf.Value {
 				return true
 			}
@@ -170,3 +246,22 @@ func shouldUpdatePerBrokerConfig(response []*sarama.ConfigEntry, brokerConfig *p
 
 	return false
 }
+
+// isComparablePerBrokerConfig returns false for sensitive configurations set from secrets, since the brokers don't return
+// the values of sensitive configurations
+func isComparablePerBrokerConfig(conf *sarama.ConfigEntry, value string) bool {
+	return !conf.Sensitive || !kafka.IsConfigSecretReference(value)
+}
+
+// hasPendingPerBrokerConfigChanges returns true if there are per-broker config changes the broker is not updated with
+func hasPendingPerBrokerConfigChanges(brokerState v1beta1.BrokerState) bool {
+	if brokerState.PerBrokerConfigurationState == v1beta1.PerBrokerConfigInSync {
+		return false
+	}
+	for _, configChange := range brokerState.PendingConfigChanges {
+		if configChange.Type == v1beta1.ConfigChangeDynamicPerBroker {
+			return true
+		}
+	}
+	return false
+}
@@ -15,10 +15,12 @@
 package kafka
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/Shopify/sarama"
 
+	"github.com/banzaicloud/koperator/api/v1beta1"
 	properties "github.com/banzaicloud/koperator/properties/pkg"
 )
 
@@ -95,3 +97,86 @@ config2=value3
 		}
 	}
 }
+
+func TestGetPerBrokerConfigChanges(t *testing.T) {
+	response := []*sarama.ConfigEntry{
+		{Name: "config1", Value: "value1"},
+		{Name: "config2", Value: "value2"},
+		{Name: "config3", Value: "value3"},
+	}
+	brokerConf, err := properties.NewFromString("config1=value1\nconfig2=value4\nconfig4=value4\n")
+	if err != nil {
+		t.Fatalf("failed parsing Properties from string: %v", err)
+	}
+
+	expected := []v1beta1.PendingConfigChange{
+		{Key: "config2", OldValue: "value2", NewValue: "value4", Type: v1beta1.ConfigChangeDynamicPerBroker},
+	}
+	if result := getPerBrokerConfigChanges(response, brokerConf); !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected: %+v, got: %+v", expected, result)
+	}
+}
+
+func TestShouldUpdatePerBrokerConfigSecretRefs(t *testing.T) {
+	reference := "${file:/var/run/secrets/kafka/config/a.properties:sasl.jaas.config}"
+	brokerConf, err := properties.NewFromString("sasl.jaas.config=" + reference + "\n")
+	if err != nil {
+		t.Fatalf("failed parsing Properties from string: %v", err)
+	}
+
+	// the brokers don't return the values of sensitive configurations
+	response := []*sarama.ConfigEntry{{Name: "sasl.jaas.config", Sensitive: true}}
+	if shouldUpdatePerBrokerConfig(response, brokerConf) {
+		t.Error("sensitive configurations set from secrets should not be compared")
+	}
+	if changes := getPerBrokerConfigChanges(response, brokerConf); len(changes) != 0 {
+		t.Errorf("expected no changes, got: %+v", changes)
+	}
+
+	response = []*sarama.ConfigEntry{{Name: "sasl.jaas.config", Value: "plain"}}
+	if !shouldUpdatePerBrokerConfig(response, brokerConf) {
+		t.Error("non-sensitive configurations should be compared")
+	}
+}
+
+func TestHasPendingPerBrokerConfigChanges(t *testing.T) {
+	pendingConfigChanges := []v1beta1.PendingConfigChange{
+		{Key: "sasl.jaas.config", OldValue: "[hidden]", NewValue: "[hidden]", Type: v1beta1.ConfigChangeDynamicPerBroker},
+	}
+	testCases := []struct {
+		Description string
+		BrokerState v1beta1.BrokerState
+		Expected    bool
+	}{
+		{
+			Description: "no pending changes",
+			BrokerState: v1beta1.BrokerState{PerBrokerConfigurationState: v1beta1.PerBrokerConfigOutOfSync},
+		},
+		{
+			Description: "pending per-broker changes",
+			BrokerState: v1beta1.BrokerState{PerBrokerConfigurationState: v1beta1.PerBrokerConfigOutOfSync, PendingConfigChanges: pendingConfigChanges},
+			Expected:    true,
+		},
+		{
+			Description: "pending per-broker changes after a failed update",
+			BrokerState: v1beta1.BrokerState{PerBrokerConfigurationState: v1beta1.PerBrokerConfigError, PendingConfigChanges: pendingConfigChanges},
+			Expected:    true,
+		},
+		{
+			Description: "per-broker configuration in sync",
+			BrokerState: v1beta1.BrokerState{PerBrokerConfigurationState: v1beta1.PerBrokerConfigInSync, PendingConfigChanges: pendingConfigChanges},
+		},
+		{
+			Description: "only restart required changes",
+			BrokerState: v1beta1.BrokerState{
+				PerBrokerConfigurationState: v1beta1.PerBrokerConfigOutOfSync,
+				PendingConfigChanges:        []v1beta1.PendingConfigChange{{Key: "broker.rack", NewValue: "a", Type: v1beta1.ConfigChangeRestartRequired}},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		if result := hasPendingPerBrokerConfigChanges(testCase.BrokerState); result != testCase.Expected {
+			t.Errorf("test case failed: %s, expected: %v, got: %v", testCase.Description, testCase.Expected, result)
+		}
+	}
+}
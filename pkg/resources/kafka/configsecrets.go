@@ -0,0 +1,239 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiutil "github.com/banzaicloud/koperator/api/util"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/resources/templates"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+// configSecretKey identifies the value of a configuration set from a secret key. Configurations generated by the
+// operator from secrets, e.g. the JAAS configurations of the SASL listeners, have no secret name and key.
+type configSecretKey struct {
+	config     string
+	secretName string
+	secretKey  string
+}
+
+// configSecretFiles maps the configurations set from secret keys to the properties files holding their values
+type configSecretFiles map[configSecretKey]string
+
+func newConfigSecretKey(ref v1beta1.ConfigSecretRef) configSecretKey {
+	return configSecretKey{
+		config:     ref.Config,
+		secretName: ref.SecretKeyRef.Name,
+		secretKey:  ref.SecretKeyRef.Key,
+	}
+}
+
+// reconcileConfigSecrets renders the values of the secret keys referenced by the configSecretRefs of the brokers and the
+// JAAS configurations of the SASL listeners into properties files, which are stored in a secret mounted to the broker pods.
+// The files are named after the hash of their content, so the references in the broker configuration change whenever the
+// value of a secret key changes.
+func (r *Reconciler) reconcileConfigSecrets(ctx context.Context, log logr.Logger) (configSecretFiles, error) {
+	files := make(configSecretFiles)
+	data := make(map[string][]byte)
+	secrets := make(map[string]*corev1.Secret)
+	hasConfigSecretRefs := false
+
+	// getSecret returns nil when the secret does not exist
+	getSecret := func(name string) (*corev1.Secret, error) {
+		if secret, ok := secrets[name]; ok {
+			return secret, nil
+		}
+		secret := &corev1.Secret{}
+		err := r.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: r.KafkaCluster.Namespace}, secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, errorfactory.New(errorfactory.APIFailure{}, err, "getting secret failed", "secret", name)
+		}
+		if apierrors.IsNotFound(err) {
+			secret = nil
+		}
+		secrets[name] = secret
+		return secret, nil
+	}
+
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to reconcile resource")
+		}
+		for _, ref := range r.KafkaCluster.Spec.GetConfigSecretRefs(brokerConfig) {
+			hasConfigSecretRefs = true
+			key := newConfigSecretKey(ref)
+			if _, ok := files[key]; ok {
+				continue
+			}
+
+			secret, err := getSecret(ref.SecretKeyRef.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			var (
+				value []byte
+				ok    bool
+			)
+			if secret != nil {
+				value, ok = secret.Data[ref.SecretKeyRef.Key]
+			}
+			if secret == nil || !ok {
+				if ref.SecretKeyRef.Optional != nil && *ref.SecretKeyRef.Optional {
+					log.V(1).Info("optional secret key of broker configuration is missing", "config", ref.Config,
+						"secret", ref.SecretKeyRef.Name, "key", ref.SecretKeyRef.Key)
+					continue
+				}
+				return nil, errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("secret key not found"),
+					"secret key referenced by configSecretRefs is missing", "config", ref.Config,
+					"secret", ref.SecretKeyRef.Name, "key", ref.SecretKeyRef.Key)
+			}
+
+			fileName, content, err := generateConfigSecretFile(ref.Config, string(value))
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not generate properties file for broker configuration", "config", ref.Config)
+			}
+			files[key] = fileName
+			data[fileName] = content
+		}
+	}
+
+	jaasConfigs, err := generateSASLJAASConfigs(r.KafkaCluster.Spec.ListenersConfig, getSecret)
+	if err != nil {
+		return nil, err
+	}
+	for config, value := range jaasConfigs {
+		hasConfigSecretRefs = true
+		fileName, content, err := generateConfigSecretFile(config, value)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "could not generate properties file for broker configuration", "config", config)
+		}
+		files[configSecretKey{config: config}] = fileName
+		data[fileName] = content
+	}
+
+	if !hasConfigSecretRefs {
+		return files, r.deleteConfigSecrets(ctx)
+	}
+
+	if err := k8sutil.Reconcile(log, r.Client, r.configSecrets(data), r.KafkaCluster); err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to reconcile resource", "resource", "Secret")
+	}
+	return files, nil
+}
+
+// configSecrets returns the secret holding the properties files of the configurations set from secret keys
+func (r *Reconciler) configSecrets(data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: templates.ObjectMeta(
+			fmt.Sprintf(configSecretsTemplate, r.KafkaCluster.Name),
+			apiutil.LabelsForKafka(r.KafkaCluster.Name),
+			r.KafkaCluster,
+		),
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+func (r *Reconciler) deleteConfigSecrets(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	secret.SetName(fmt.Sprintf(configSecretsTemplate, r.KafkaCluster.Name))
+	secret.SetNamespace(r.KafkaCluster.Namespace)
+	if err := r.Client.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "deleting resource failed", "kind", "Secret", "name", secret.GetName())
+	}
+	return nil
+}
+
+// generateConfigSecretFile returns the name and the content of the properties file holding the value of the configuration
+func generateConfigSecretFile(config, value string) (string, []byte, error) {
+	p := properties.NewProperties()
+	if err := p.Set(config, value); err != nil {
+		return "", nil, err
+	}
+	content := []byte(p.String())
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:16]) + ".properties", content, nil
+}
+
+// generateConfigSecretConfig returns the broker configurations set from secret keys and the ones generated from secrets,
+// which reference the properties files mounted to the broker pod. The FileConfigProvider is added to the config providers
+// set in the read-only configuration.
+func generateConfigSecretConfig(refs []v1beta1.ConfigSecretRef, files configSecretFiles, readOnlyConfig *properties.Properties, log logr.Logger) *properties.Properties {
+	config := properties.NewProperties()
+	// the generated configurations are set on every broker and can be overridden by the configSecretRefs
+	for key, fileName := range files {
+		if key.secretName != "" {
+			continue
+		}
+		if err := config.Set(key.config, kafkautils.ConfigSecretReference(fileName, key.config)); err != nil {
+			log.Error(err, fmt.Sprintf("setting '%s' in broker configuration resulted an error", key.config))
+		}
+	}
+	for _, ref := range refs {
+		fileName, ok := files[newConfigSecretKey(ref)]
+		if !ok {
+			continue
+		}
+		if err := config.Set(ref.Config, kafkautils.ConfigSecretReference(fileName, ref.Config)); err != nil {
+			log.Error(err, fmt.Sprintf("setting '%s' in broker configuration resulted an error", ref.Config))
+		}
+	}
+	if config.Len() == 0 {
+		return config
+	}
+
+	var providers []string
+	if p, ok := readOnlyConfig.Get(kafkautils.KafkaConfigConfigProviders); ok && strings.TrimSpace(p.Value()) != "" {
+		for _, provider := range strings.Split(p.Value(), ",") {
+			providers = append(providers, strings.TrimSpace(provider))
+		}
+	}
+	hasFileProvider := false
+	for _, provider := range providers {
+		if provider == kafkautils.FileConfigProviderName {
+			hasFileProvider = true
+		}
+	}
+	if !hasFileProvider {
+		providers = append(providers, kafkautils.FileConfigProviderName)
+	}
+
+	if err := config.Set(kafkautils.KafkaConfigConfigProviders, strings.Join(providers, ",")); err != nil {
+		log.Error(err, fmt.Sprintf("setting '%s' in broker configuration resulted an error", kafkautils.KafkaConfigConfigProviders))
+	}
+	providerClassKey := fmt.Sprintf("%s.%s.class", kafkautils.KafkaConfigConfigProviders, kafkautils.FileConfigProviderName)
+	if err := config.Set(providerClassKey, kafkautils.FileConfigProviderClass); err != nil {
+		log.Error(err, fmt.Sprintf("setting '%s' in broker configuration resulted an error", providerClassKey))
+	}
+	return config
+}
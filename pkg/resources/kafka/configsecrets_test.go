@@ -0,0 +1,160 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/util"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+func configSecretRef(config, secretName, secretKey string) v1beta1.ConfigSecretRef {
+	return v1beta1.ConfigSecretRef{
+		Config: config,
+		SecretKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  secretKey,
+		},
+	}
+}
+
+func TestReconcileConfigSecrets(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-credentials", Namespace: "kafka"},
+		Data: map[string][]byte{
+			"jaas":       []byte(`org.apache.kafka.common.security.plain.PlainLoginModule required username="admin" password="admin";`),
+			"truststore": []byte("changeit"),
+		},
+	}
+	optionalRef := configSecretRef("rsm.config.access.key", "kafka-credentials", "access-key")
+	optionalRef.SecretKeyRef.Optional = util.BoolPointer(true)
+
+	kafkaCluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			ConfigSecretRefs: []v1beta1.ConfigSecretRef{
+				configSecretRef("listener.name.external.ssl.truststore.password", "kafka-credentials", "truststore"),
+				optionalRef,
+			},
+			Brokers: []v1beta1.Broker{
+				{Id: 0},
+				{Id: 1, BrokerConfig: &v1beta1.BrokerConfig{ConfigSecretRefs: []v1beta1.ConfigSecretRef{
+					configSecretRef("listener.name.sasl_ssl.plain.sasl.jaas.config", "kafka-credentials", "jaas"),
+				}}},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	r := New(fakeClient, nil, kafkaCluster, nil)
+
+	files, err := r.reconcileConfigSecrets(ctx, logr.Discard())
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var configSecrets corev1.Secret
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "kafka-config-secrets", Namespace: "kafka"}, &configSecrets))
+	require.Len(t, configSecrets.Data, 2)
+	jaasFile := files[newConfigSecretKey(kafkaCluster.Spec.Brokers[1].BrokerConfig.ConfigSecretRefs[0])]
+	content, err := properties.NewFromString(string(configSecrets.Data[jaasFile]))
+	require.NoError(t, err)
+	jaasConfig, ok := content.Get("listener.name.sasl_ssl.plain.sasl.jaas.config")
+	require.True(t, ok)
+	require.Equal(t, string(secret.Data["jaas"]), jaasConfig.Value())
+
+	// the name of the file changes with the value of the secret key
+	secret.Data["jaas"] = []byte(`org.apache.kafka.common.security.plain.PlainLoginModule required username="admin" password="secret";`)
+	require.NoError(t, fakeClient.Update(ctx, secret))
+	updatedFiles, err := r.reconcileConfigSecrets(ctx, logr.Discard())
+	require.NoError(t, err)
+	require.NotEqual(t, jaasFile, updatedFiles[newConfigSecretKey(kafkaCluster.Spec.Brokers[1].BrokerConfig.ConfigSecretRefs[0])])
+
+	// missing secret keys which are not optional fail the reconciliation
+	kafkaCluster.Spec.ConfigSecretRefs = append(kafkaCluster.Spec.ConfigSecretRefs, configSecretRef("sasl.jaas.config", "missing", "jaas"))
+	_, err = r.reconcileConfigSecrets(ctx, logr.Discard())
+	require.ErrorAs(t, err, &errorfactory.ResourceNotReady{})
+
+	// the secret is deleted when there are no configSecretRefs
+	kafkaCluster.Spec.ConfigSecretRefs = nil
+	kafkaCluster.Spec.Brokers[1].BrokerConfig = nil
+	files, err = r.reconcileConfigSecrets(ctx, logr.Discard())
+	require.NoError(t, err)
+	require.Empty(t, files)
+	err = fakeClient.Get(ctx, client.ObjectKey{Name: "kafka-config-secrets", Namespace: "kafka"}, &configSecrets)
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestGenerateConfigSecretConfig(t *testing.T) {
+	refs := []v1beta1.ConfigSecretRef{
+		configSecretRef("sasl.jaas.config", "kafka-credentials", "jaas"),
+		configSecretRef("ssl.truststore.password", "kafka-credentials", "missing"),
+	}
+	files := configSecretFiles{newConfigSecretKey(refs[0]): "0123.properties"}
+
+	testCases := []struct {
+		testName       string
+		readOnlyConfig string
+		expected       string
+	}{
+		{
+			testName: "file config provider is added",
+			expected: `config.providers=file
+config.providers.file.class=org.apache.kafka.common.config.provider.FileConfigProvider
+sasl.jaas.config=${file:/var/run/secrets/kafka/config/0123.properties:sasl.jaas.config}
+`,
+		},
+		{
+			testName:       "file config provider is appended to the config providers",
+			readOnlyConfig: "config.providers=vault",
+			expected: `config.providers=vault,file
+config.providers.file.class=org.apache.kafka.common.config.provider.FileConfigProvider
+sasl.jaas.config=${file:/var/run/secrets/kafka/config/0123.properties:sasl.jaas.config}
+`,
+		},
+		{
+			testName:       "file config provider is already set",
+			readOnlyConfig: "config.providers=file, vault",
+			expected: `config.providers=file,vault
+config.providers.file.class=org.apache.kafka.common.config.provider.FileConfigProvider
+sasl.jaas.config=${file:/var/run/secrets/kafka/config/0123.properties:sasl.jaas.config}
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.testName, func(t *testing.T) {
+			readOnlyConfig, err := properties.NewFromString(test.readOnlyConfig)
+			require.NoError(t, err)
+			config := generateConfigSecretConfig(refs, files, readOnlyConfig, logr.Discard())
+			config.Sort()
+			require.Equal(t, test.expected, config.String())
+		})
+	}
+
+	require.Equal(t, 0, generateConfigSecretConfig(refs[1:], files, properties.NewProperties(), logr.Discard()).Len())
+}
@@ -16,6 +16,7 @@ package kafka
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"reflect"
 	"sort"
@@ -81,6 +82,15 @@ const (
 	MetricsHealthCheck = "/-/healthy"
 	MetricsPort        = 9020
 
+	tieredStoragePluginsVolumeName = "tiered-storage-plugins"
+	tieredStoragePluginsPath       = "/opt/kafka/libs/tiered-storage"
+
+	configSecretsTemplate   = "%s-config-secrets"
+	configSecretsVolumeName = "config-secrets"
+
+	saslKeytabVolumeNameTemplate = "sasl-%s-keytab"
+	saslKeytabPath               = "/var/run/secrets/kafka/sasl"
+
 	// missingBrokerDownScaleRunningPriority the priority is used  for missing brokers where there is an incomplete downscale operation
 	missingBrokerDownScaleRunningPriority brokerReconcilePriority = iota
 	// newBrokerReconcilePriority the priority used  for brokers that were just added to the cluster used to define its priority in the reconciliation order
@@ -98,6 +108,7 @@ type Reconciler struct {
 	resources.Reconciler
 	kafkaClientProvider        kafkaclient.Provider
 	CruiseControlScalerFactory func(ctx context.Context, kafkaCluster *banzaiv1beta1.KafkaCluster) (scale.CruiseControlScaler, error)
+	ServedCertificateGetter    func(address string) (*x509.Certificate, error)
 }
 
 // New creates a new reconciler for Kafka
@@ -110,10 +121,13 @@ func New(client client.Client, directClient client.Reader, cluster *v1beta1.Kafk
 		},
 		kafkaClientProvider:        kafkaClientProvider,
 		CruiseControlScalerFactory: scale.ScaleFactoryFn(),
+		ServedCertificateGetter:    getServedCertificate,
 	}
 }
 
-func getBrokerAzMap(cluster *v1beta1.KafkaCluster) map[int32]string {
+// GetBrokerAzMap returns the availability zone of the brokers based on their broker.rack read-only config. When the
+// zone of any of the brokers is unknown every broker is considered to be in a different zone keyed by its ID.
+func GetBrokerAzMap(cluster *v1beta1.KafkaCluster) map[int32]string {
 	brokerAzMap := make(map[int32]string)
 	for _, broker := range cluster.Spec.Brokers {
 		readOnlyConfigs, err := properties.NewFromString(broker.ReadOnlyConfig)
@@ -268,6 +282,11 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		return err
 	}
 
+	configSecretFiles, err := r.reconcileConfigSecrets(ctx, log)
+	if err != nil {
+		return err
+	}
+
 	brokersVolumes := make(map[string][]*corev1.PersistentVolumeClaim, len(r.KafkaCluster.Spec.Brokers))
 	for _, broker := range r.KafkaCluster.Spec.Brokers {
 		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
@@ -302,6 +321,11 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		}
 	}
 
+	err = r.reconcileKafkaPvcRemoval(ctx, log)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to reconcile resource", "resources", "PersistentVolumeClaim")
+	}
+
 	var brokerPods corev1.PodList
 	matchingLabels := client.MatchingLabels(apiutil.LabelsForKafka(r.KafkaCluster.Name))
 	err = r.Client.List(ctx, &brokerPods, client.ListOption(client.InNamespace(r.KafkaCluster.Namespace)), client.ListOption(matchingLabels))
@@ -309,10 +333,14 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		return errors.WrapIf(err, "failed to list broker pods that belong to Kafka cluster")
 	}
 
+	readinessGatesPending := r.reconcileReplicasInSyncReadinessGate(ctx, log, brokerPods.Items)
+
 	runningBrokers := make(map[string]struct{})
-	for _, b := range brokerPods.Items {
+	brokerPodsByID := make(map[string]*corev1.Pod, len(brokerPods.Items))
+	for i, b := range brokerPods.Items {
 		brokerID := b.GetLabels()[v1beta1.BrokerIdLabelKey]
 		runningBrokers[brokerID] = struct{}{}
+		brokerPodsByID[brokerID] = &brokerPods.Items[i]
 	}
 
 	controllerID, err := r.determineControllerId()
@@ -336,6 +364,7 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 
 	reorderedBrokers := reorderBrokers(runningBrokers, boundPersistentVolumeClaims, r.KafkaCluster.Spec.Brokers, r.KafkaCluster.Status.BrokersState, controllerID, log)
 	allBrokerDynamicConfigSucceeded := true
+	listenerCertificatesReloading := false
 	for _, broker := range reorderedBrokers {
 		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
 		if err != nil {
@@ -344,14 +373,14 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 
 		var configMap *corev1.ConfigMap
 		if r.KafkaCluster.Spec.RackAwareness == nil {
-			configMap = r.configMap(broker.Id, brokerConfig, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
+			configMap = r.configMap(broker.Id, brokerConfig, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, configSecretFiles, log)
 			err := k8sutil.Reconcile(log, r.Client, configMap, r.KafkaCluster)
 			if err != nil {
 				return errors.WrapIfWithDetails(err, "failed to reconcile resource", "resource", configMap.GetObjectKind().GroupVersionKind())
 			}
 		} else if brokerState, ok := r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(broker.Id))]; ok {
 			if brokerState.RackAwarenessState != "" {
-				configMap = r.configMap(broker.Id, brokerConfig, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, log)
+				configMap = r.configMap(broker.Id, brokerConfig, extListenerStatuses, intListenerStatuses, controllerIntListenerStatuses, serverPasses, clientPass, superUsers, configSecretFiles, log)
 				err := k8sutil.Reconcile(log, r.Client, configMap, r.KafkaCluster)
 				if err != nil {
 					return errors.WrapIfWithDetails(err, "failed to reconcile resource", "resource", configMap.GetObjectKind().GroupVersionKind())
@@ -363,6 +392,7 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		if err != nil {
 			return errors.WrapIfWithDetails(err, "failed to list PVC's")
 		}
+		pvcs = filterRemovedPvcs(pvcs, r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(broker.Id))])
 
 		if !r.KafkaCluster.Spec.HeadlessServiceEnabled {
 			o := r.service(broker.Id, brokerConfig)
@@ -386,7 +416,14 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		if err != nil {
 			log.Error(err, "setting dynamic configs has failed", v1beta1.BrokerIdLabelKey, broker.Id)
 			allBrokerDynamicConfigSucceeded = false
+			continue
+		}
+		reloading, err := r.reconcileListenerCertificates(broker.Id, brokerConfig, configMap, brokerPodsByID[strconv.Itoa(int(broker.Id))], log)
+		if err != nil {
+			log.Error(err, "reloading listener certificates has failed", v1beta1.BrokerIdLabelKey, broker.Id)
+			allBrokerDynamicConfigSucceeded = false
 		}
+		listenerCertificatesReloading = listenerCertificatesReloading || reloading
 	}
 
 	if !allBrokerDynamicConfigSucceeded {
@@ -397,7 +434,7 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 			"clusterNamespace", r.KafkaCluster.Namespace)
 	}
 
-	if err = r.reconcileClusterWideDynamicConfig(); err != nil {
+	if err = r.reconcileClusterWideDynamicConfig(log); err != nil {
 		return err
 	}
 
@@ -425,6 +462,18 @@ func (r *Reconciler) Reconcile(log logr.Logger) error {
 		}
 	}
 
+	if listenerCertificatesReloading {
+		// re-reconcile to check whether the brokers serve the renewed listener certificates
+		return errorfactory.New(errorfactory.PerBrokerConfigNotReady{}, errors.New("listener certificates are being reloaded"),
+			"renewed listener certificates are not served yet")
+	}
+
+	if readinessGatesPending {
+		// re-reconcile to set the readiness gate once the brokers have caught up with their replicas
+		return errorfactory.New(errorfactory.BrokersNotReady{}, errors.New("replicas in sync readiness gate is pending"),
+			"brokers are still catching up with their replicas")
+	}
+
 	log.V(1).Info("Reconciled")
 
 	return nil
@@ -625,12 +674,8 @@ func (r *Reconciler) getClientPasswordKeyAndUser() (string, string, error) {
 	return clientPass, CN, nil
 }
 
-func getListenerSSLCertSecret(client client.Reader, commonSpec v1beta1.CommonListenerSpec, clusterName string, clusterNamespace string) (*corev1.Secret, error) {
-	// Use default SSL cert secret
-	secretNamespacedName := types.NamespacedName{Name: fmt.Sprintf(pkicommon.BrokerServerCertTemplate, clusterName), Namespace: clusterNamespace}
-	if commonSpec.GetServerSSLCertSecretName() != "" {
-		secretNamespacedName = types.NamespacedName{Name: commonSpec.GetServerSSLCertSecretName(), Namespace: clusterNamespace}
-	}
+func getListenerSSLCertSecret(client client.Reader, commonSpec v1beta1.CommonListenerSpec, keystoreFormat v1beta1.KeystoreFormat, clusterName string, clusterNamespace string) (*corev1.Secret, error) {
+	secretNamespacedName := types.NamespacedName{Name: ListenerServerCertSecretName(commonSpec, clusterName), Namespace: clusterNamespace}
 	serverSecret := &corev1.Secret{}
 	if err := client.Get(context.TODO(), secretNamespacedName, serverSecret); err != nil {
 		if apierrors.IsNotFound(err) && commonSpec.GetServerSSLCertSecretName() == "" {
@@ -645,6 +690,14 @@ func getListenerSSLCertSecret(client client.Reader, commonSpec v1beta1.CommonLis
 		}
 		return nil, errorfactory.New(errorfactory.ResourceNotReady{}, errors.Errorf("SSL JKS certificate has not generated properly yet into secret: %s", serverSecret.Name), "checking secret data fields")
 	}
+	// The generated secret holds the keystores of the listener keystore format besides the JKS ones
+	if commonSpec.GetServerSSLCertSecretName() == "" {
+		for _, field := range certutil.KeystoreFields([]v1beta1.KeystoreFormat{keystoreFormat}) {
+			if len(serverSecret.Data[field]) == 0 {
+				return nil, errorfactory.New(errorfactory.ResourceNotReady{}, errors.Errorf("SSL %s keystore has not generated properly yet into secret: %s", keystoreFormat, serverSecret.Name), "checking secret data fields")
+			}
+		}
+	}
 
 	return serverSecret, nil
 }
@@ -664,7 +717,8 @@ func (r *Reconciler) getServerPasswordKeysAndUsers() (map[string]string, []strin
 			// if multiple listener use the generated one, because they share the same.
 			if globKeyPass == "" || iListener.GetServerSSLCertSecretName() != "" {
 				// get the appropriate secret: the generated as default or the custom if its specified
-				serverSecret, err = getListenerSSLCertSecret(r.Client, iListener.CommonListenerSpec, r.KafkaCluster.Name, r.KafkaCluster.Namespace)
+				serverSecret, err = getListenerSSLCertSecret(r.Client, iListener.CommonListenerSpec,
+					listenerKeystoreFormat(&r.KafkaCluster.Spec.ListenersConfig, iListener.CommonListenerSpec), r.KafkaCluster.Name, r.KafkaCluster.Namespace)
 				if err != nil {
 					return nil, nil, err
 				}
@@ -695,7 +749,8 @@ func (r *Reconciler) getServerPasswordKeysAndUsers() (map[string]string, []strin
 	for _, eListener := range r.KafkaCluster.Spec.ListenersConfig.ExternalListeners {
 		if eListener.Type == v1beta1.SecurityProtocolSSL {
 			if globKeyPass == "" || eListener.GetServerSSLCertSecretName() != "" {
-				serverSecret, err = getListenerSSLCertSecret(r.Client, eListener.CommonListenerSpec, r.KafkaCluster.Name, r.KafkaCluster.Namespace)
+				serverSecret, err = getListenerSSLCertSecret(r.Client, eListener.CommonListenerSpec,
+					listenerKeystoreFormat(&r.KafkaCluster.Spec.ListenersConfig, eListener.CommonListenerSpec), r.KafkaCluster.Name, r.KafkaCluster.Namespace)
 				if err != nil {
 					return nil, nil, err
 				}
@@ -917,7 +972,7 @@ func (r *Reconciler) handleRollingUpgrade(log logr.Logger, desiredPod, currentPo
 					return err
 				}
 			}
-			kafkaBrokerAvailabilityZoneMap := getBrokerAzMap(r.KafkaCluster)
+			kafkaBrokerAvailabilityZoneMap := GetBrokerAzMap(r.KafkaCluster)
 			currentPodAz, _ := r.getBrokerAz(currentPod, kafkaBrokerAvailabilityZoneMap)
 			if r.KafkaCluster.Spec.RollingUpgradeConfig.ConcurrentBrokerRestartCountPerRack > 1 && r.existsTerminatingPodFromAnotherAz(currentPodAz, terminatingOrPendingPods, kafkaBrokerAvailabilityZoneMap) {
 				return errorfactory.New(errorfactory.ReconcileRollingUpgrade{}, errors.New("pod is still terminating or creating from another AZ"), "rolling upgrade in progress")
@@ -1065,6 +1120,9 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 				if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(desiredPvc); err != nil {
 					return errors.WrapIf(err, "could not apply last state to annotation")
 				}
+				if err := r.restorePvcFromBackup(ctx, brokerId, desiredPvc); err != nil {
+					return err
+				}
 				if err := r.Client.Create(ctx, desiredPvc); err != nil {
 					return errorfactory.New(errorfactory.APIFailure{}, err, "creating resource failed", "kind", desiredType)
 				}
@@ -1092,6 +1150,9 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 				if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(desiredPvc); err != nil {
 					return errors.WrapIf(err, "could not apply last state to annotation")
 				}
+				if err := r.restorePvcFromBackup(ctx, brokerId, desiredPvc); err != nil {
+					return err
+				}
 				if err := r.Client.Create(ctx, desiredPvc); err != nil {
 					return errorfactory.New(errorfactory.APIFailure{}, err, "creating resource failed", "kind", desiredType)
 				}
@@ -1138,6 +1199,219 @@ func (r *Reconciler) reconcileKafkaPvc(ctx context.Context, log logr.Logger, bro
 	return nil
 }
 
+// restorePvcFromBackup sets the data source of the persistent volume claim to the volume snapshot taken by the
+// KafkaClusterBackup referenced in the KafkaCluster CR. The data source is set after the last applied annotation,
+// so it is not reported as a change of the persistent volume claim later on.
+func (r *Reconciler) restorePvcFromBackup(ctx context.Context, brokerId string, pvc *corev1.PersistentVolumeClaim) error {
+	restore := r.KafkaCluster.Spec.RestoreFromBackup
+	if restore == nil || pvc.Spec.DataSource != nil {
+		return nil
+	}
+
+	backup := &v1alpha1.KafkaClusterBackup{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: restore.Name, Namespace: r.KafkaCluster.GetNamespace()}, backup)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return errorfactory.New(errorfactory.ResourceNotReady{}, err, "KafkaClusterBackup to restore from not found", "name", restore.Name)
+		}
+		return errorfactory.New(errorfactory.APIFailure{}, err, "getting resource failed", "kind", "KafkaClusterBackup", "name", restore.Name)
+	}
+	if backup.Status.State != v1alpha1.KafkaClusterBackupSucceeded {
+		return errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("backup has not succeeded"),
+			"KafkaClusterBackup is not ready to restore from", "name", restore.Name, "state", backup.Status.State)
+	}
+
+	mountPath := pvc.Annotations["mountPath"]
+	snapshotName, ok := backup.Status.VolumeSnapshotName(brokerId, mountPath)
+	if !ok {
+		// the volume has been added to the broker after the backup, there is nothing to restore
+		return nil
+	}
+	apiGroup := v1alpha1.VolumeSnapshotAPIGroup
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     v1alpha1.VolumeSnapshotKind,
+		Name:     snapshotName,
+	}
+	return nil
+}
+
+// reconcileKafkaPvcRemoval drives the graceful removal of the broker disks which were removed from the KafkaCluster CR.
+// A removed disk first has to be drained by Cruise Control, then it is removed from the log.dirs and the broker pod,
+// and its PVC is deleted only after no pod uses it anymore.
+func (r *Reconciler) reconcileKafkaPvcRemoval(ctx context.Context, log logr.Logger) error {
+	brokersVolumesState := make(map[string]map[string]v1beta1.VolumeState)
+	var brokerIds []string
+
+	for _, broker := range r.KafkaCluster.Spec.Brokers {
+		brokerConfig, err := broker.GetBrokerConfig(r.KafkaCluster.Spec)
+		if err != nil {
+			return errors.WrapIf(err, "failed to reconcile resource")
+		}
+		brokerId := strconv.Itoa(int(broker.Id))
+		brokerState := r.KafkaCluster.Status.BrokersState[brokerId]
+
+		desiredMountPaths := make(map[string]struct{}, len(brokerConfig.StorageConfigs))
+		for _, storage := range brokerConfig.StorageConfigs {
+			if storage.PvcSpec != nil {
+				desiredMountPaths[storage.MountPath] = struct{}{}
+			}
+		}
+
+		// The removal of a disk which has been added back before Cruise Control started to drain it is revoked
+		var readdedMountPaths []string
+		for mountPath := range desiredMountPaths {
+			volumeState, ok := brokerState.GracefulActionState.VolumeStates[mountPath]
+			if !ok || !volumeState.CruiseControlVolumeState.IsDiskRemovalRevocable() {
+				continue
+			}
+			if err := r.cancelDiskRemoval(ctx, volumeState); err != nil {
+				return err
+			}
+			readdedMountPaths = append(readdedMountPaths, mountPath)
+		}
+		if len(readdedMountPaths) > 0 {
+			sort.Strings(readdedMountPaths)
+			if err := k8sutil.DeleteVolumeStatus(r.Client, brokerId, readdedMountPaths, r.KafkaCluster, log); err != nil {
+				return err
+			}
+			log.Info("removal of the disks added back to the broker revoked", v1beta1.BrokerIdLabelKey, brokerId, "mountPaths", readdedMountPaths)
+		}
+
+		matchingLabels := client.MatchingLabels(
+			apiutil.MergeLabels(
+				apiutil.LabelsForKafka(r.KafkaCluster.Name),
+				map[string]string{v1beta1.BrokerIdLabelKey: brokerId},
+			),
+		)
+		pvcList := &corev1.PersistentVolumeClaimList{}
+		if err := r.Client.List(ctx, pvcList, client.InNamespace(r.KafkaCluster.GetNamespace()), matchingLabels); err != nil {
+			return errorfactory.New(errorfactory.APIFailure{}, err, "getting resource failed", "kind", "PersistentVolumeClaim")
+		}
+		podList := &corev1.PodList{}
+		if err := r.Client.List(ctx, podList, client.InNamespace(r.KafkaCluster.GetNamespace()), matchingLabels); err != nil {
+			return errorfactory.New(errorfactory.APIFailure{}, err, "getting resource failed", "kind", "Pod")
+		}
+		claimsInUse := getClaimsInUse(podList.Items)
+
+		brokerVolumesState := make(map[string]v1beta1.VolumeState)
+		existingMountPaths := make(map[string]struct{}, len(pvcList.Items))
+		for i := range pvcList.Items {
+			pvc := &pvcList.Items[i]
+			mountPath := pvc.Annotations["mountPath"]
+			existingMountPaths[mountPath] = struct{}{}
+			if _, ok := desiredMountPaths[mountPath]; ok {
+				continue
+			}
+
+			volumeState, ok := brokerState.GracefulActionState.VolumeStates[mountPath]
+			switch {
+			case ok && volumeState.CruiseControlVolumeState == v1beta1.GracefulDiskRemovalSucceeded:
+				if _, used := claimsInUse[pvc.Name]; used {
+					log.V(1).Info("waiting for the broker to be restarted without the removed disk", v1beta1.BrokerIdLabelKey, brokerId, "mountPath", mountPath)
+					continue
+				}
+				if pvc.DeletionTimestamp != nil {
+					continue
+				}
+				if err := r.Client.Delete(ctx, pvc); client.IgnoreNotFound(err) != nil {
+					return errorfactory.New(errorfactory.APIFailure{}, err, "deleting resource failed", "kind", "PersistentVolumeClaim", "name", pvc.Name)
+				}
+				log.Info("persistent volume claim of the removed disk deleted", v1beta1.BrokerIdLabelKey, brokerId, "mountPath", mountPath, "name", pvc.Name)
+			case ok && volumeState.CruiseControlVolumeState.IsDiskRemoval():
+				// the disk is being drained by Cruise Control
+				continue
+			case ok && volumeState.CruiseControlVolumeState.IsActive():
+				// the disk removal starts once the ongoing disk rebalance has finished
+				continue
+			default:
+				brokerVolumesState[mountPath] = v1beta1.VolumeState{CruiseControlVolumeState: v1beta1.GracefulDiskRemovalRequired}
+			}
+		}
+
+		if len(brokerVolumesState) > 0 {
+			brokerIds = append(brokerIds, brokerId)
+			brokersVolumesState[brokerId] = brokerVolumesState
+		}
+
+		// Volume states of the already deleted disks are not needed anymore
+		var removedMountPaths []string
+		for mountPath, volumeState := range brokerState.GracefulActionState.VolumeStates {
+			if _, ok := existingMountPaths[mountPath]; !ok && volumeState.CruiseControlVolumeState == v1beta1.GracefulDiskRemovalSucceeded {
+				removedMountPaths = append(removedMountPaths, mountPath)
+			}
+		}
+		if len(removedMountPaths) > 0 {
+			sort.Strings(removedMountPaths)
+			if err := k8sutil.DeleteVolumeStatus(r.Client, brokerId, removedMountPaths, r.KafkaCluster, log); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(brokersVolumesState) > 0 {
+		err := k8sutil.UpdateBrokerStatus(r.Client, brokerIds, r.KafkaCluster, brokersVolumesState, log)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cancelDiskRemoval requests the cancellation of the CruiseControlOperation draining the disk unless it is done already
+func (r *Reconciler) cancelDiskRemoval(ctx context.Context, volumeState v1beta1.VolumeState) error {
+	if volumeState.CruiseControlOperationReference == nil {
+		return nil
+	}
+	operation := &v1alpha1.CruiseControlOperation{}
+	operationName := volumeState.CruiseControlOperationReference.Name
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: operationName, Namespace: r.KafkaCluster.GetNamespace()}, operation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errorfactory.New(errorfactory.APIFailure{}, err, "getting resource failed", "kind", "CruiseControlOperation", "name", operationName)
+	}
+	if operation.IsDone() || operation.Spec.Cancel {
+		return nil
+	}
+	operation.Spec.Cancel = true
+	if err := r.Client.Update(ctx, operation); err != nil {
+		return errorfactory.New(errorfactory.APIFailure{}, err, "updating resource failed", "kind", "CruiseControlOperation", "name", operationName)
+	}
+	return nil
+}
+
+// getClaimsInUse returns the names of the persistent volume claims mounted by the given pods
+func getClaimsInUse(pods []corev1.Pod) map[string]struct{} {
+	claims := make(map[string]struct{})
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				claims[volume.PersistentVolumeClaim.ClaimName] = struct{}{}
+			}
+		}
+	}
+	return claims
+}
+
+// filterRemovedPvcs drops the persistent volume claims of the disks which were already drained by Cruise Control
+// or which are being deleted, so they are not mounted into the broker pod anymore
+func filterRemovedPvcs(pvcs []corev1.PersistentVolumeClaim, brokerState v1beta1.BrokerState) []corev1.PersistentVolumeClaim {
+	filtered := make([]corev1.PersistentVolumeClaim, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		if volumeState, ok := brokerState.GracefulActionState.VolumeStates[pvc.Annotations["mountPath"]]; ok &&
+			volumeState.CruiseControlVolumeState == v1beta1.GracefulDiskRemovalSucceeded {
+			continue
+		}
+		filtered = append(filtered, pvc)
+	}
+	return filtered
+}
+
 // GetBrokersWithPendingOrRunningCCTask returns list of brokers that are either waiting for CC
 // to start executing a broker task (add broker, remove broker, etc) or CC already running a task for it.
 func GetBrokersWithPendingOrRunningCCTask(kafkaCluster *v1beta1.KafkaCluster) []int32 {
@@ -1150,7 +1424,7 @@ func GetBrokersWithPendingOrRunningCCTask(kafkaCluster *v1beta1.KafkaCluster) []
 			} else {
 				// Check if the volumes are rebalancing
 				for _, volumeState := range state.GracefulActionState.VolumeStates {
-					if volumeState.CruiseControlVolumeState == v1beta1.GracefulDiskRebalanceRequired ||
+					if volumeState.CruiseControlVolumeState.IsRequiredState() ||
 						(volumeState.CruiseControlOperationReference != nil && volumeState.CruiseControlVolumeState.IsRunningState()) {
 						brokerIDs = append(brokerIDs, kafkaCluster.Spec.Brokers[i].Id)
 					}
@@ -1387,11 +1661,83 @@ func getPodsInTerminatingOrPendingState(items []corev1.Pod) []corev1.Pod {
 		}
 		if k8sutil.IsPodContainsPendingContainer(&pod) {
 			pods = append(pods, pod)
+		} else if isReplicasInSyncReadinessGatePending(&pod) {
+			// the broker is still catching up with the replicas
+			pods = append(pods, pod)
 		}
 	}
 	return pods
 }
 
+func isReplicasInSyncReadinessGatePending(pod *corev1.Pod) bool {
+	return k8sutil.HasReadinessGate(pod, v1beta1.ReplicasInSyncReadinessGate) &&
+		!k8sutil.IsPodConditionTrue(pod, v1beta1.ReplicasInSyncReadinessGate)
+}
+
+// reconcileReplicasInSyncReadinessGate sets the replicas in sync readiness gate of the running broker pods
+// once the broker has no out-of-sync replicas. The gate is not reverted later, it only tracks the catch-up
+// of the broker after the pod has been (re)started. It returns true while the readiness gate of any of the
+// broker pods is pending.
+func (r *Reconciler) reconcileReplicasInSyncReadinessGate(ctx context.Context, log logr.Logger, pods []corev1.Pod) bool {
+	var pendingPods []*corev1.Pod
+	pending := false
+	for i := range pods {
+		pod := &pods[i]
+		if k8sutil.IsMarkedForDeletion(pod.ObjectMeta) || !isReplicasInSyncReadinessGatePending(pod) {
+			continue
+		}
+		if !k8sutil.IsPodConditionTrue(pod, corev1.ContainersReady) {
+			pending = true
+			continue
+		}
+		pendingPods = append(pendingPods, pod)
+	}
+	if len(pendingPods) == 0 {
+		return pending
+	}
+
+	kClient, closeClient, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		log.Info("could not connect to kafka brokers to check the replicas in sync readiness gate", "error", err.Error())
+		return true
+	}
+	defer closeClient()
+
+	outOfSyncReplicas, err := kClient.OutOfSyncReplicas()
+	if err != nil {
+		log.Info("could not get the out-of-sync replicas to check the replicas in sync readiness gate", "error", err.Error())
+		return true
+	}
+	outOfSyncBrokers := make(map[string]struct{}, len(outOfSyncReplicas))
+	for _, brokerID := range outOfSyncReplicas {
+		outOfSyncBrokers[strconv.Itoa(int(brokerID))] = struct{}{}
+	}
+
+	for _, pod := range pendingPods {
+		brokerID := pod.Labels[v1beta1.BrokerIdLabelKey]
+		if _, ok := outOfSyncBrokers[brokerID]; ok {
+			log.V(1).Info("broker is still catching up with its replicas", v1beta1.BrokerIdLabelKey, brokerID)
+			pending = true
+			continue
+		}
+		original := pod.DeepCopy()
+		setPodCondition(pod, corev1.PodCondition{
+			Type:               v1beta1.ReplicasInSyncReadinessGate,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "ReplicasInSync",
+			Message:            "broker has rejoined the in-sync replica set of all its partitions",
+		})
+		if err := r.Client.Status().Patch(ctx, pod, client.StrategicMergeFrom(original)); err != nil {
+			log.Error(err, "could not set the replicas in sync readiness gate of the broker pod", v1beta1.BrokerIdLabelKey, brokerID)
+			pending = true
+			continue
+		}
+		log.Info("replicas in sync readiness gate of the broker pod is set", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	return pending
+}
+
 func (r *Reconciler) getBrokerAz(pod *corev1.Pod, kafkaBrokerAvailabilityZoneMap map[int32]string) (string, error) {
 	brokerId, err := strconv.ParseInt(pod.Labels[v1beta1.BrokerIdLabelKey], 10, 32)
 	if err != nil {
@@ -1525,3 +1871,13 @@ func generateServicePortForAdditionalPorts(containerPorts []corev1.ContainerPort
 	}
 	return usedPorts
 }
+
+func setPodCondition(pod *corev1.Pod, podCondition corev1.PodCondition) {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == podCondition.Type {
+			pod.Status.Conditions[i] = podCondition
+			return
+		}
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, podCondition)
+}
@@ -25,8 +25,10 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/onsi/gomega"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/banzaicloud/koperator/pkg/scale"
@@ -35,11 +37,15 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
 	"github.com/banzaicloud/koperator/api/v1alpha1"
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	controllerMocks "github.com/banzaicloud/koperator/controllers/tests/mocks"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
 	"github.com/banzaicloud/koperator/pkg/resources"
 	mocks "github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
 )
@@ -1474,6 +1480,33 @@ func TestReconcileConcurrentBrokerRestartsAllowed(t *testing.T) {
 			},
 			errorExpected: true,
 		},
+		{
+			testName: "Pod is not deleted if another broker has not caught up with its replicas yet",
+			kafkaCluster: v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kafka",
+					Namespace: "kafka",
+				},
+				Spec: v1beta1.KafkaClusterSpec{
+					Brokers: []v1beta1.Broker{{Id: 101}, {Id: 201}, {Id: 301}},
+					RollingUpgradeConfig: v1beta1.RollingUpgradeConfig{
+						ReplicasInSyncReadinessGate: true,
+					},
+				},
+				Status: v1beta1.KafkaClusterStatus{State: v1beta1.KafkaClusterRollingUpgrading},
+			},
+			desiredPod: &corev1.Pod{},
+			currentPod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kafka-201"}},
+			pods: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "kafka-101"},
+					Spec:       corev1.PodSpec{ReadinessGates: []corev1.PodReadinessGate{{ConditionType: v1beta1.ReplicasInSyncReadinessGate}}},
+				},
+				{ObjectMeta: metav1.ObjectMeta{Name: "kafka-201"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "kafka-301"}},
+			},
+			errorExpected: true,
+		},
 	}
 
 	mockCtrl := gomock.NewController(t)
@@ -1604,8 +1637,237 @@ func TestGetBrokerAzMap(t *testing.T) {
 
 	for _, test := range testCases {
 		t.Run(test.testName, func(t *testing.T) {
-			azMap := getBrokerAzMap(&test.kafkaCluster)
+			azMap := GetBrokerAzMap(&test.kafkaCluster)
 			assert.Equal(t, test.expectedAzMap, azMap)
 		})
 	}
 }
+
+func TestFilterRemovedPvcs(t *testing.T) {
+	now := metav1.Now()
+	pvcs := []corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pvc-1", Annotations: map[string]string{"mountPath": "/kafka-logs"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pvc-2", Annotations: map[string]string{"mountPath": "/kafka-logs2"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pvc-3", Annotations: map[string]string{"mountPath": "/kafka-logs3"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pvc-4", Annotations: map[string]string{"mountPath": "/kafka-logs4"}, DeletionTimestamp: &now}},
+	}
+	brokerState := v1beta1.BrokerState{
+		GracefulActionState: v1beta1.GracefulActionState{
+			VolumeStates: map[string]v1beta1.VolumeState{
+				"/kafka-logs2": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalRunning},
+				"/kafka-logs3": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalSucceeded},
+			},
+		},
+	}
+
+	filtered := filterRemovedPvcs(pvcs, brokerState)
+
+	var names []string
+	for _, pvc := range filtered {
+		names = append(names, pvc.Name)
+	}
+	assert.Equal(t, []string{"pvc-1", "pvc-2"}, names)
+}
+
+func TestGetClaimsInUse(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{Name: "kafka-data-0", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc-1"}}},
+					{Name: "kafka-data-1", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]struct{}{"pvc-1": {}}, getClaimsInUse(pods))
+}
+
+func TestRestorePvcFromBackup(t *testing.T) {
+	testCases := []struct {
+		testName           string
+		restore            *v1beta1.RestoreFromBackup
+		backupStatus       v1alpha1.KafkaClusterBackupStatus
+		mountPath          string
+		expectedDataSource string
+		errorExpected      bool
+	}{
+		{
+			testName:  "restore is not requested",
+			mountPath: "/kafka-logs",
+		},
+		{
+			testName: "backup has succeeded",
+			restore:  &v1beta1.RestoreFromBackup{Name: "backup"},
+			backupStatus: v1alpha1.KafkaClusterBackupStatus{
+				State: v1alpha1.KafkaClusterBackupSucceeded,
+				Brokers: map[string]v1alpha1.BrokerBackupStatus{
+					"0": {
+						State: v1alpha1.BrokerBackupSucceeded,
+						Snapshots: []v1alpha1.VolumeSnapshotStatus{
+							{MountPath: "/kafka-logs", VolumeSnapshotName: "backup-kafka-0-storage-0", ReadyToUse: true},
+						},
+					},
+				},
+			},
+			mountPath:          "/kafka-logs",
+			expectedDataSource: "backup-kafka-0-storage-0",
+		},
+		{
+			testName: "volume has no snapshot",
+			restore:  &v1beta1.RestoreFromBackup{Name: "backup"},
+			backupStatus: v1alpha1.KafkaClusterBackupStatus{
+				State: v1alpha1.KafkaClusterBackupSucceeded,
+			},
+			mountPath: "/kafka-logs2",
+		},
+		{
+			testName: "backup is still running",
+			restore:  &v1beta1.RestoreFromBackup{Name: "backup"},
+			backupStatus: v1alpha1.KafkaClusterBackupStatus{
+				State: v1alpha1.KafkaClusterBackupRunning,
+			},
+			mountPath:     "/kafka-logs",
+			errorExpected: true,
+		},
+	}
+
+	mockCtrl := gomock.NewController(t)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.testName, func(t *testing.T) {
+			mockClient := mocks.NewMockClient(mockCtrl)
+			kafkaCluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec:       v1beta1.KafkaClusterSpec{RestoreFromBackup: test.restore},
+			}
+			r := New(mockClient, nil, kafkaCluster, new(kafkaclient.MockedProvider))
+
+			if test.restore != nil {
+				mockClient.EXPECT().Get(
+					gomock.Any(),
+					types.NamespacedName{Name: test.restore.Name, Namespace: "kafka"},
+					gomock.AssignableToTypeOf(&v1alpha1.KafkaClusterBackup{}),
+				).Do(func(ctx context.Context, key client.ObjectKey, backup *v1alpha1.KafkaClusterBackup, opts ...client.GetOption) {
+					backup.Status = test.backupStatus
+				}).Return(nil)
+			}
+
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"mountPath": test.mountPath}},
+			}
+			err := r.restorePvcFromBackup(context.Background(), "0", pvc)
+			if test.errorExpected {
+				assert.True(t, errors.As(err, &errorfactory.ResourceNotReady{}))
+				return
+			}
+			assert.NoError(t, err)
+			if test.expectedDataSource == "" {
+				assert.Nil(t, pvc.Spec.DataSource)
+				return
+			}
+			assert.Equal(t, test.expectedDataSource, pvc.Spec.DataSource.Name)
+			assert.Equal(t, v1alpha1.VolumeSnapshotKind, pvc.Spec.DataSource.Kind)
+		})
+	}
+}
+
+func TestReconcileReplicasInSyncReadinessGate(t *testing.T) {
+	readinessGates := []corev1.PodReadinessGate{{ConditionType: v1beta1.ReplicasInSyncReadinessGate}}
+	containersReady := corev1.PodCondition{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}
+	newPod := func(brokerID string, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kafka-" + brokerID,
+				Namespace: "kafka",
+				Labels:    map[string]string{v1beta1.BrokerIdLabelKey: brokerID},
+			},
+			Spec:   corev1.PodSpec{ReadinessGates: readinessGates},
+			Status: corev1.PodStatus{Conditions: conditions},
+		}
+	}
+	pods := []corev1.Pod{
+		// in sync
+		*newPod("0", containersReady),
+		// out of sync
+		*newPod("1", containersReady),
+		// containers are not ready yet
+		*newPod("2"),
+	}
+
+	kafkaCluster := &v1beta1.KafkaCluster{ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"}}
+	fakeClient := fake.NewClientBuilder().WithObjects(&pods[0], &pods[1], &pods[2]).Build()
+
+	mockCtrl := gomock.NewController(t)
+	mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+	mockedKafkaClient.EXPECT().OutOfSyncReplicas().Return([]int32{1, 2}, nil)
+	mockKafkaClientProvider := new(kafkaclient.MockedProvider)
+	mockKafkaClientProvider.On("NewFromCluster", fakeClient, kafkaCluster).Return(mockedKafkaClient, func() {}, nil)
+
+	r := New(fakeClient, nil, kafkaCluster, mockKafkaClientProvider)
+	assert.True(t, r.reconcileReplicasInSyncReadinessGate(context.Background(), logr.Discard(), pods))
+
+	for brokerID, expected := range map[string]bool{"0": true, "1": false, "2": false} {
+		pod := &corev1.Pod{}
+		assert.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "kafka-" + brokerID, Namespace: "kafka"}, pod))
+		assert.Equal(t, expected, k8sutil.IsPodConditionTrue(pod, v1beta1.ReplicasInSyncReadinessGate), "broker %s", brokerID)
+		assert.Equal(t, !expected, isReplicasInSyncReadinessGatePending(pod), "broker %s", brokerID)
+	}
+
+	// nothing is pending once the readiness gates of all the brokers are set
+	inSync := []corev1.Pod{*newPod("0", containersReady, corev1.PodCondition{Type: v1beta1.ReplicasInSyncReadinessGate, Status: corev1.ConditionTrue})}
+	assert.False(t, r.reconcileReplicasInSyncReadinessGate(context.Background(), logr.Discard(), inSync))
+}
+
+func TestReconcileKafkaPvcRemovalRevokesReaddedDisk(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	kafkaCluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			Brokers: []v1beta1.Broker{{
+				Id: 0,
+				BrokerConfig: &v1beta1.BrokerConfig{
+					StorageConfigs: []v1beta1.StorageConfig{
+						{MountPath: "/kafka-logs", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+						{MountPath: "/kafka-logs2", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+					},
+				},
+			}},
+		},
+		Status: v1beta1.KafkaClusterStatus{
+			BrokersState: map[string]v1beta1.BrokerState{"0": {
+				GracefulActionState: v1beta1.GracefulActionState{
+					VolumeStates: map[string]v1beta1.VolumeState{
+						"/kafka-logs2": {
+							CruiseControlVolumeState:        v1beta1.GracefulDiskRemovalScheduled,
+							CruiseControlOperationReference: &corev1.LocalObjectReference{Name: "kafka-removedisks-abcde"},
+						},
+					},
+				},
+			}},
+		},
+	}
+	operation := &v1alpha1.CruiseControlOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-removedisks-abcde", Namespace: "kafka"},
+		Status: v1alpha1.CruiseControlOperationStatus{
+			CurrentTask: &v1alpha1.CruiseControlTask{Operation: v1alpha1.OperationRemoveDisks},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kafkaCluster, operation).Build()
+	r := New(fakeClient, nil, kafkaCluster, nil)
+
+	require.NoError(t, r.reconcileKafkaPvcRemoval(context.Background(), logr.Discard()))
+
+	// the volume state of the disk added back is cleared and its removal is cancelled
+	current := &v1beta1.KafkaCluster{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(kafkaCluster), current))
+	require.NotContains(t, current.Status.BrokersState["0"].GracefulActionState.VolumeStates, "/kafka-logs2")
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(operation), operation))
+	require.True(t, operation.Spec.Cancel)
+}
@@ -0,0 +1,204 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/k8sutil"
+	"github.com/banzaicloud/koperator/pkg/util"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+const (
+	// listenerCertificateReloadTimeout is the time a broker has to serve the renewed certificate of a listener after
+	// the reload of the keystore was requested, before the broker is restarted to load it
+	listenerCertificateReloadTimeout = 5 * time.Minute
+	// listenerCertificateDialTimeout is the timeout of connecting to the listeners to get the served certificates
+	listenerCertificateDialTimeout = 5 * time.Second
+)
+
+// getServedCertificate returns the certificate served by the TLS endpoint on the given address
+func getServedCertificate(address string) (*x509.Certificate, error) {
+	return certutil.GetServedCertificate(address, listenerCertificateDialTimeout)
+}
+
+// reconcileListenerCertificates makes the broker serve the current server certificates of its SSL listeners.
+// Renewed certificates are loaded without restarting the broker by re-setting the keystore location of the listener,
+// which makes Kafka reload the keystore. When the broker doesn't serve the renewed certificate within
+// listenerCertificateReloadTimeout it is restarted. It returns true while any of the reloads is in progress.
+// The served certificate is only checked when the server certificate secret has changed since the last check
+// or while its reload is in progress.
+func (r *Reconciler) reconcileListenerCertificates(brokerID int32, brokerConfig *v1beta1.BrokerConfig, configMap *corev1.ConfigMap, pod *corev1.Pod, log logr.Logger) (bool, error) {
+	brokerState := r.KafkaCluster.Status.BrokersState[strconv.Itoa(int(brokerID))]
+	if configMap == nil || pod == nil || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" ||
+		brokerState.ConfigurationState != v1beta1.ConfigInSync {
+		return false, nil
+	}
+
+	var listeners []v1beta1.CommonListenerSpec
+	for _, iListener := range r.KafkaCluster.Spec.ListenersConfig.InternalListeners {
+		if iListener.Type.IsSSL() {
+			listeners = append(listeners, iListener.CommonListenerSpec)
+		}
+	}
+	for _, eListener := range r.KafkaCluster.Spec.ListenersConfig.ExternalListeners {
+		if eListener.Type.IsSSL() {
+			listeners = append(listeners, eListener.CommonListenerSpec)
+		}
+	}
+
+	listenerCertificates := make(v1beta1.ListenerCertificateStates, len(listeners))
+	var reloadListeners []string
+	restart := false
+	for _, listener := range listeners {
+		previousState, hasPreviousState := brokerState.ListenerCertificates[listener.Name]
+
+		serverSecret, err := getListenerSSLCertSecret(r.Client, listener, listenerKeystoreFormat(&r.KafkaCluster.Spec.ListenersConfig, listener),
+			r.KafkaCluster.Name, r.KafkaCluster.Namespace)
+		if err != nil {
+			return false, err
+		}
+		if hasPreviousState && previousState.ReloadRequestedAt == nil && previousState.SecretResourceVersion == serverSecret.ResourceVersion {
+			listenerCertificates[listener.Name] = previousState
+			continue
+		}
+		expected, err := certutil.ParseKeyStoreToTLSCertificate(serverSecret.Data[v1alpha1.TLSJKSKeyStore], serverSecret.Data[v1alpha1.PasswordKey])
+		if err != nil {
+			return false, errors.WrapIfWithDetails(err, "failed to decode certificate", "secretName", serverSecret.Name)
+		}
+
+		served, err := r.ServedCertificateGetter(net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(listener.ContainerPort))))
+		if err != nil {
+			// the served certificate is checked again on the next reconciliation
+			log.Error(err, "could not get the certificate served by the listener", v1beta1.BrokerIdLabelKey, brokerID, "listener", listener.Name)
+			if hasPreviousState {
+				listenerCertificates[listener.Name] = previousState
+			}
+			continue
+		}
+
+		state := v1beta1.ListenerCertificateState{
+			NotAfter:              metav1.NewTime(served.NotAfter),
+			SecretResourceVersion: serverSecret.ResourceVersion,
+		}
+		if !bytes.Equal(served.Raw, expected.Leaf.Raw) {
+			state.ReloadRequestedAt = previousState.ReloadRequestedAt
+			if state.ReloadRequestedAt == nil {
+				now := metav1.NewTime(time.Now().Truncate(time.Second))
+				state.ReloadRequestedAt = &now
+			}
+			if time.Since(state.ReloadRequestedAt.Time) < listenerCertificateReloadTimeout {
+				reloadListeners = append(reloadListeners, listener.Name)
+			} else {
+				// the reload window starts again after the restart and the served certificate is checked again
+				state.ReloadRequestedAt = nil
+				state.SecretResourceVersion = ""
+				restart = true
+			}
+		}
+		listenerCertificates[listener.Name] = state
+	}
+
+	if !listenerCertificateStatesEqual(brokerState.ListenerCertificates, listenerCertificates) {
+		if err := k8sutil.UpdateBrokerStatus(r.Client, []string{strconv.Itoa(int(brokerID))}, r.KafkaCluster, listenerCertificates, log); err != nil {
+			return false, errors.WrapIfWithDetails(err, "updating status for listener certificates failed", v1beta1.BrokerIdLabelKey, brokerID)
+		}
+	}
+
+	if restart {
+		log.Info("renewed listener certificates were not reloaded in time, restarting broker", v1beta1.BrokerIdLabelKey, brokerID)
+		if err := k8sutil.UpdateBrokerStatus(r.Client, []string{strconv.Itoa(int(brokerID))}, r.KafkaCluster, v1beta1.ConfigOutOfSync, log); err != nil {
+			return false, errors.WrapIfWithDetails(err, "updating status for broker configuration failed", v1beta1.BrokerIdLabelKey, brokerID)
+		}
+		return false, nil
+	}
+
+	if len(reloadListeners) == 0 {
+		return false, nil
+	}
+
+	log.Info("reloading renewed listener certificates", v1beta1.BrokerIdLabelKey, brokerID, "listeners", reloadListeners)
+	return true, r.reloadListenerKeystores(brokerID, brokerConfig, configMap, reloadListeners)
+}
+
+// reloadListenerKeystores makes the broker reload the keystores of the given listeners by altering their keystore
+// locations. Since the per-broker configurations are altered together, the full per-broker configuration is set.
+func (r *Reconciler) reloadListenerKeystores(brokerID int32, brokerConfig *v1beta1.BrokerConfig, configMap *corev1.ConfigMap, listeners []string) error {
+	kClient, close, err := r.kafkaClientProvider.NewFromCluster(r.Client, r.KafkaCluster)
+	if err != nil {
+		return errorfactory.New(errorfactory.BrokersUnreachable{}, err, "could not connect to kafka brokers")
+	}
+	defer close()
+
+	fullPerBrokerConfig, err := properties.NewFromString(brokerConfig.Config)
+	if err != nil {
+		return errors.WrapIf(err, "could not parse broker configuration")
+	}
+	if err := mergePerBrokerConfigsFromConfigMap(fullPerBrokerConfig, configMap); err != nil {
+		return err
+	}
+	configsFromConfigMap, err := properties.NewFromString(configMap.Data[kafkautils.ConfigPropertyName])
+	if err != nil {
+		return errors.WrapIf(err, "could not parse broker configuration from configmap")
+	}
+	for _, listener := range listeners {
+		keyStoreLocation := fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, listener, kafkautils.KafkaConfigSSLKeyStoreLocation)
+		if configProperty, ok := configsFromConfigMap.Get(keyStoreLocation); ok {
+			fullPerBrokerConfig.Put(configProperty)
+		}
+	}
+
+	// validate the config
+	if err := kClient.AlterPerBrokerConfig(brokerID, util.ConvertPropertiesToMapStringPointer(fullPerBrokerConfig), true); err != nil {
+		return errors.WrapIfWithDetails(err, "could not validate listener keystore reload", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	// alter the config
+	if err := kClient.AlterPerBrokerConfig(brokerID, util.ConvertPropertiesToMapStringPointer(fullPerBrokerConfig), false); err != nil {
+		return errors.WrapIfWithDetails(err, "could not reload listener keystores", v1beta1.BrokerIdLabelKey, brokerID)
+	}
+	return nil
+}
+
+func listenerCertificateStatesEqual(a, b v1beta1.ListenerCertificateStates) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, stateA := range a {
+		stateB, ok := b[name]
+		if !ok || !stateA.NotAfter.Equal(&stateB.NotAfter) || !stateA.ReloadRequestedAt.Equal(stateB.ReloadRequestedAt) ||
+			stateA.SecretResourceVersion != stateB.SecretResourceVersion {
+			return false
+		}
+	}
+	return true
+}
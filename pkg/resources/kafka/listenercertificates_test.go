@@ -0,0 +1,234 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/resources/kafka/mocks"
+	"github.com/banzaicloud/koperator/pkg/util"
+	certutil "github.com/banzaicloud/koperator/pkg/util/cert"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+)
+
+func generateListenerCertificate(t *testing.T) (*x509.Certificate, []byte, []byte) {
+	certPEM, keyPEM, _, err := certutil.GenerateTestCert()
+	require.NoError(t, err)
+	keystore, password, err := certutil.GenerateJKSFromByte(certPEM, keyPEM, certPEM)
+	require.NoError(t, err)
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert, keystore, password
+}
+
+func TestReconcileListenerCertificates(t *testing.T) {
+	const keyStoreLocationConfig = "listener.name.internal.ssl.keystore.location"
+	const keyStoreLocation = "/var/run/secrets/java.io/keystores/server/internal/keystore.jks"
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	current, keystore, password := generateListenerCertificate(t)
+	outdated, _, _ := generateListenerCertificate(t)
+
+	longAgo := metav1.NewTime(time.Now().Add(-2 * listenerCertificateReloadTimeout).Truncate(time.Second))
+	testCases := []struct {
+		testName              string
+		served                *x509.Certificate
+		reloadRequestedAt     *metav1.Time
+		expectedReload        bool
+		expectedConfiguration v1beta1.ConfigurationState
+	}{
+		{
+			testName:              "renewed certificate is served",
+			served:                current,
+			expectedConfiguration: v1beta1.ConfigInSync,
+		},
+		{
+			testName:              "renewed certificate is reloaded",
+			served:                outdated,
+			expectedReload:        true,
+			expectedConfiguration: v1beta1.ConfigInSync,
+		},
+		{
+			testName:              "broker is restarted when the renewed certificate is not reloaded in time",
+			served:                outdated,
+			reloadRequestedAt:     &longAgo,
+			expectedConfiguration: v1beta1.ConfigOutOfSync,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.testName, func(t *testing.T) {
+			kafkaCluster := &v1beta1.KafkaCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+				Spec: v1beta1.KafkaClusterSpec{
+					ListenersConfig: v1beta1.ListenersConfig{
+						InternalListeners: []v1beta1.InternalListenerConfig{{
+							CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "internal", Type: v1beta1.SecurityProtocolSSL, ContainerPort: 29092},
+						}},
+					},
+				},
+				Status: v1beta1.KafkaClusterStatus{
+					BrokersState: map[string]v1beta1.BrokerState{"0": {
+						ConfigurationState: v1beta1.ConfigInSync,
+						ListenerCertificates: v1beta1.ListenerCertificateStates{
+							"internal": {NotAfter: metav1.NewTime(test.served.NotAfter), ReloadRequestedAt: test.reloadRequestedAt},
+						},
+					}},
+				},
+			}
+			serverSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka-server-certificate", Namespace: "kafka"},
+				Data: map[string][]byte{
+					v1alpha1.TLSJKSKeyStore:   keystore,
+					v1alpha1.TLSJKSTrustStore: keystore,
+					v1alpha1.PasswordKey:      password,
+				},
+			}
+			configMap := &corev1.ConfigMap{Data: map[string]string{
+				kafkautils.ConfigPropertyName: keyStoreLocationConfig + "=" + keyStoreLocation + "\n",
+			}}
+			pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"}}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kafkaCluster, serverSecret).Build()
+
+			mockCtrl := gomock.NewController(t)
+			mockedKafkaClient := mocks.NewMockKafkaClient(mockCtrl)
+			if test.expectedReload {
+				expectedConfig := map[string]*string{keyStoreLocationConfig: util.StringPointer(keyStoreLocation)}
+				gomock.InOrder(
+					mockedKafkaClient.EXPECT().AlterPerBrokerConfig(int32(0), expectedConfig, true).Return(nil),
+					mockedKafkaClient.EXPECT().AlterPerBrokerConfig(int32(0), expectedConfig, false).Return(nil),
+				)
+			}
+			mockKafkaClientProvider := new(kafkaclient.MockedProvider)
+			mockKafkaClientProvider.On("NewFromCluster", fakeClient, kafkaCluster).Return(mockedKafkaClient, func() {}, nil)
+
+			r := New(fakeClient, nil, kafkaCluster, mockKafkaClientProvider)
+			r.ServedCertificateGetter = func(address string) (*x509.Certificate, error) {
+				require.Equal(t, "10.0.0.1:29092", address)
+				return test.served, nil
+			}
+
+			reloading, err := r.reconcileListenerCertificates(0, &v1beta1.BrokerConfig{}, configMap, pod, logr.Discard())
+			require.NoError(t, err)
+			require.Equal(t, test.expectedReload, reloading)
+
+			var updated v1beta1.KafkaCluster
+			require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(kafkaCluster), &updated))
+			brokerState := updated.Status.BrokersState["0"]
+			require.Equal(t, test.expectedConfiguration, brokerState.ConfigurationState)
+			require.True(t, brokerState.ListenerCertificates["internal"].NotAfter.Time.Equal(test.served.NotAfter))
+			require.Equal(t, test.expectedReload, brokerState.ListenerCertificates["internal"].ReloadRequestedAt != nil)
+		})
+	}
+}
+
+func TestReconcileListenerCertificatesUnchangedSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	current, keystore, password := generateListenerCertificate(t)
+	kafkaCluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			ListenersConfig: v1beta1.ListenersConfig{
+				InternalListeners: []v1beta1.InternalListenerConfig{{
+					CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "internal", Type: v1beta1.SecurityProtocolSaslSSL, ContainerPort: 29092},
+				}},
+			},
+		},
+		Status: v1beta1.KafkaClusterStatus{
+			BrokersState: map[string]v1beta1.BrokerState{"0": {ConfigurationState: v1beta1.ConfigInSync}},
+		},
+	}
+	serverSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-server-certificate", Namespace: "kafka"},
+		Data: map[string][]byte{
+			v1alpha1.TLSJKSKeyStore:   keystore,
+			v1alpha1.TLSJKSTrustStore: keystore,
+			v1alpha1.PasswordKey:      password,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(kafkaCluster, serverSecret).Build()
+	r := New(fakeClient, nil, kafkaCluster, nil)
+	probes := 0
+	r.ServedCertificateGetter = func(string) (*x509.Certificate, error) {
+		probes++
+		return current, nil
+	}
+
+	pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"}}
+	reconcile := func() {
+		reloading, err := r.reconcileListenerCertificates(0, &v1beta1.BrokerConfig{}, &corev1.ConfigMap{}, pod, logr.Discard())
+		require.NoError(t, err)
+		require.False(t, reloading)
+	}
+
+	// the SASL_SSL listener is checked the first time
+	reconcile()
+	require.Equal(t, 1, probes)
+	require.NotEmpty(t, r.KafkaCluster.Status.BrokersState["0"].ListenerCertificates["internal"].SecretResourceVersion)
+
+	// and is not checked again until the secret changes
+	reconcile()
+	require.Equal(t, 1, probes)
+
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(serverSecret), serverSecret))
+	serverSecret.Labels = map[string]string{"renewed": "true"}
+	require.NoError(t, fakeClient.Update(context.Background(), serverSecret))
+	reconcile()
+	require.Equal(t, 2, probes)
+}
+
+func TestReconcileListenerCertificatesSkipsBrokersNotInSync(t *testing.T) {
+	kafkaCluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Status: v1beta1.KafkaClusterStatus{
+			BrokersState: map[string]v1beta1.BrokerState{"0": {ConfigurationState: v1beta1.ConfigOutOfSync}},
+		},
+	}
+	r := New(fake.NewClientBuilder().Build(), nil, kafkaCluster, nil)
+	r.ServedCertificateGetter = func(string) (*x509.Certificate, error) {
+		t.Fatal("served certificate should not be checked")
+		return nil, nil
+	}
+
+	pod := &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"}}
+	reloading, err := r.reconcileListenerCertificates(0, &v1beta1.BrokerConfig{}, &corev1.ConfigMap{}, pod, logr.Discard())
+	require.NoError(t, err)
+	require.False(t, reloading)
+}
@@ -83,6 +83,13 @@ func (r *Reconciler) pod(id int32, brokerConfig *v1beta1.BrokerConfig, pvcs []co
 	}
 
 	dataVolume, dataVolumeMount := generateDataVolumeAndVolumeMount(pvcs, brokerConfig.StorageConfigs)
+	tieredStorage := r.KafkaCluster.Spec.GetTieredStorageConfig(brokerConfig)
+	configSecretRefs := r.KafkaCluster.Spec.GetConfigSecretRefs(brokerConfig)
+
+	affinity := GetAffinity(brokerConfig, r.KafkaCluster)
+	if zone, ok := r.KafkaCluster.Spec.GetBrokerZone(id); ok {
+		affinity = pinBrokerToZone(affinity, r.KafkaCluster.Spec.RackAwareness.ZonePinning.GetZoneLabel(r.KafkaCluster.Spec.RackAwareness), zone)
+	}
 
 	// TODO remove this bash envoy sidecar checker script once sidecar precedence becomes available to Kubernetes(baluchicken)
 	command := []string{"bash", "-c", envoySidecarScript}
@@ -97,7 +104,7 @@ func (r *Reconciler) pod(id int32, brokerConfig *v1beta1.BrokerConfig, pvcs []co
 		Spec: corev1.PodSpec{
 			SecurityContext: brokerConfig.PodSecurityContext,
 			InitContainers:  getInitContainers(brokerConfig, r.KafkaCluster.Spec),
-			Affinity:        getAffinity(brokerConfig, r.KafkaCluster),
+			Affinity:        affinity,
 			Containers: append([]corev1.Container{
 				{
 					Name:  "kafka",
@@ -121,7 +128,7 @@ fi`},
 						},
 					},
 					SecurityContext: brokerConfig.SecurityContext,
-					Env: generateEnvConfig(brokerConfig, []corev1.EnvVar{
+					Env: generateEnvConfig(brokerConfig, append([]corev1.EnvVar{
 						{
 							Name:  "CLASSPATH",
 							Value: "/opt/kafka/libs/extensions/*",
@@ -138,7 +145,7 @@ fi`},
 								},
 							},
 						},
-					}),
+					}, generateTieredStorageEnvs(tieredStorage)...)),
 
 					Command: command,
 					Ports: append(kafkaBrokerContainerPorts, []corev1.ContainerPort{
@@ -148,11 +155,11 @@ fi`},
 							Name:          "metrics",
 						},
 					}...),
-					VolumeMounts: getVolumeMounts(brokerConfig.VolumeMounts, dataVolumeMount, r.KafkaCluster.Spec, r.KafkaCluster.Name),
+					VolumeMounts: getVolumeMounts(brokerConfig.VolumeMounts, dataVolumeMount, r.KafkaCluster.Spec, r.KafkaCluster.Name, tieredStorage, configSecretRefs),
 					Resources:    *brokerConfig.GetResources(),
 				},
 			}, brokerConfig.Containers...),
-			Volumes:                       getVolumes(brokerConfig.Volumes, dataVolume, r.KafkaCluster.Spec, r.KafkaCluster.Name, id),
+			Volumes:                       getVolumes(brokerConfig.Volumes, dataVolume, r.KafkaCluster.Spec, r.KafkaCluster.Name, id, tieredStorage, configSecretRefs),
 			RestartPolicy:                 corev1.RestartPolicyNever,
 			TerminationGracePeriodSeconds: util.Int64Pointer(brokerConfig.GetTerminationGracePeriod()),
 			ImagePullSecrets:              brokerConfig.GetImagePullSecrets(),
@@ -160,8 +167,12 @@ fi`},
 			Tolerations:                   brokerConfig.GetTolerations(),
 			NodeSelector:                  brokerConfig.GetNodeSelector(),
 			PriorityClassName:             brokerConfig.GetPriorityClassName(),
+			TopologySpreadConstraints:     brokerConfig.GetTopologySpreadConstraints(),
 		},
 	}
+	if r.KafkaCluster.Spec.RollingUpgradeConfig.ReplicasInSyncReadinessGate {
+		pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: v1beta1.ReplicasInSyncReadinessGate}}
+	}
 	if r.KafkaCluster.Spec.HeadlessServiceEnabled {
 		pod.Spec.Hostname = fmt.Sprintf("%s-%d", r.KafkaCluster.Name, id)
 		pod.Spec.Subdomain = fmt.Sprintf(kafkautils.HeadlessServiceTemplate, r.KafkaCluster.Name)
@@ -199,6 +210,19 @@ func getInitContainers(brokerConfig *v1beta1.BrokerConfig, kafkaClusterSpec v1be
 		},
 	}...)
 
+	if ts := kafkaClusterSpec.GetTieredStorageConfig(brokerConfig); ts != nil {
+		initContainers = append(initContainers, corev1.Container{
+			Name:    tieredStoragePluginsVolumeName,
+			Image:   ts.PluginImage,
+			Command: []string{"/bin/sh", "-cex", fmt.Sprintf("cp -rv %s/. %s/", ts.GetPluginPath(), tieredStoragePluginsPath)},
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      tieredStoragePluginsVolumeName,
+				MountPath: tieredStoragePluginsPath,
+			}},
+			Resources: k8sutil.GetDefaultInitContainerResourceRequirements(),
+		})
+	}
+
 	sort.Slice(initContainers, func(i, j int) bool {
 		return initContainers[i].Name < initContainers[j].Name
 	})
@@ -207,7 +231,8 @@ func getInitContainers(brokerConfig *v1beta1.BrokerConfig, kafkaClusterSpec v1be
 }
 
 func getVolumeMounts(brokerConfigVolumeMounts, dataVolumeMount []corev1.VolumeMount,
-	kafkaClusterSpec v1beta1.KafkaClusterSpec, kafkaClusterName string) []corev1.VolumeMount {
+	kafkaClusterSpec v1beta1.KafkaClusterSpec, kafkaClusterName string, tieredStorage *v1beta1.TieredStorageConfig,
+	configSecretRefs []v1beta1.ConfigSecretRef) []corev1.VolumeMount {
 	volumeMounts := make([]corev1.VolumeMount, 0, len(brokerConfigVolumeMounts))
 	volumeMounts = append(volumeMounts, brokerConfigVolumeMounts...)
 
@@ -218,6 +243,7 @@ func getVolumeMounts(brokerConfigVolumeMounts, dataVolumeMount []corev1.VolumeMo
 	}
 
 	volumeMounts = append(volumeMounts, generateVolumeMountForListenerCerts(kafkaClusterSpec.ListenersConfig)...)
+	volumeMounts = append(volumeMounts, generateVolumeMountsForSASLKeytabs(kafkaClusterSpec.ListenersConfig)...)
 	volumeMounts = append(volumeMounts, []corev1.VolumeMount{
 		{
 			Name:      brokerConfigMapVolumeMount,
@@ -241,6 +267,21 @@ func getVolumeMounts(brokerConfigVolumeMounts, dataVolumeMount []corev1.VolumeMo
 		},
 	}...)
 
+	if tieredStorage != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      tieredStoragePluginsVolumeName,
+			MountPath: tieredStoragePluginsPath,
+		})
+	}
+
+	if len(configSecretRefs) > 0 || hasSASLConfigSecrets(kafkaClusterSpec.ListenersConfig) {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      configSecretsVolumeName,
+			MountPath: kafkautils.ConfigSecretsPath,
+			ReadOnly:  true,
+		})
+	}
+
 	sort.Slice(volumeMounts, func(i, j int) bool {
 		return volumeMounts[i].Name < volumeMounts[j].Name
 	})
@@ -248,7 +289,8 @@ func getVolumeMounts(brokerConfigVolumeMounts, dataVolumeMount []corev1.VolumeMo
 	return volumeMounts
 }
 
-func getVolumes(brokerConfigVolumes, dataVolume []corev1.Volume, kafkaClusterSpec v1beta1.KafkaClusterSpec, kafkaClusterName string, id int32) []corev1.Volume {
+func getVolumes(brokerConfigVolumes, dataVolume []corev1.Volume, kafkaClusterSpec v1beta1.KafkaClusterSpec, kafkaClusterName string, id int32,
+	tieredStorage *v1beta1.TieredStorageConfig, configSecretRefs []v1beta1.ConfigSecretRef) []corev1.Volume {
 	volumes := make([]corev1.Volume, 0, len(brokerConfigVolumes))
 	// clone the brokerConfig volumes
 	volumes = append(volumes, brokerConfigVolumes...)
@@ -259,6 +301,7 @@ func getVolumes(brokerConfigVolumes, dataVolume []corev1.Volume, kafkaClusterSpe
 	}
 
 	volumes = append(volumes, generateVolumesForListenerCerts(kafkaClusterSpec.ListenersConfig, kafkaClusterName)...)
+	volumes = append(volumes, generateVolumesForSASLKeytabs(kafkaClusterSpec.ListenersConfig)...)
 	volumes = append(volumes, []corev1.Volume{
 		{
 			Name: "exitfile",
@@ -298,6 +341,28 @@ func getVolumes(brokerConfigVolumes, dataVolume []corev1.Volume, kafkaClusterSpe
 		},
 	}...)
 
+	if tieredStorage != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: tieredStoragePluginsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	if len(configSecretRefs) > 0 || hasSASLConfigSecrets(kafkaClusterSpec.ListenersConfig) {
+		volumes = append(volumes, corev1.Volume{
+			Name: configSecretsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: fmt.Sprintf(configSecretsTemplate, kafkaClusterName),
+					// the files hold secret values, they are readable only by the owner and the fsGroup of the pod
+					DefaultMode: util.Int32Pointer(0440),
+				},
+			},
+		})
+	}
+
 	sort.Slice(volumes, func(i, j int) bool {
 		return volumes[i].Name < volumes[j].Name
 	})
@@ -305,15 +370,44 @@ func getVolumes(brokerConfigVolumes, dataVolume []corev1.Volume, kafkaClusterSpe
 	return volumes
 }
 
-// getAffinity returns a default `v1.Affinity` which is generated regarding the `OneBrokerPerNode` value
+// GetAffinity returns a default `v1.Affinity` which is generated regarding the `OneBrokerPerNode` value
 // or if there is any user Affinity definition provided by the user the latter will be used ignoring the value of `OneBrokerPerNode`
-func getAffinity(bc *v1beta1.BrokerConfig, cluster *v1beta1.KafkaCluster) *corev1.Affinity {
+func GetAffinity(bc *v1beta1.BrokerConfig, cluster *v1beta1.KafkaCluster) *corev1.Affinity {
 	if bc.Affinity == nil {
 		return &corev1.Affinity{PodAntiAffinity: generatePodAntiAffinity(cluster.Name, cluster.Spec.OneBrokerPerNode)}
 	}
 	return bc.Affinity
 }
 
+// pinBrokerToZone adds a required node affinity to the given affinity which restricts the scheduling of the broker
+// to the nodes of the given zone. The zone requirement is added to every node selector term, since terms are ORed.
+func pinBrokerToZone(affinity *corev1.Affinity, zoneLabel, zone string) *corev1.Affinity {
+	pinnedAffinity := &corev1.Affinity{}
+	if affinity != nil {
+		pinnedAffinity = affinity.DeepCopy()
+	}
+	zoneRequirement := corev1.NodeSelectorRequirement{
+		Key:      zoneLabel,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{zone},
+	}
+
+	if pinnedAffinity.NodeAffinity == nil {
+		pinnedAffinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if pinnedAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		pinnedAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := pinnedAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	for i := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions, zoneRequirement)
+	}
+	return pinnedAffinity
+}
+
 func generatePodAntiAffinity(clusterName string, hardRuleEnabled bool) *corev1.PodAntiAffinity {
 	podAntiAffinity := corev1.PodAntiAffinity{}
 	if hardRuleEnabled {
@@ -395,17 +489,21 @@ func generateDataVolumeAndVolumeMount(pvcs []corev1.PersistentVolumeClaim, stora
 	return volumes, volumeMounts
 }
 
-func generateVolumeForListenersCertsFromCommonSpec(commonSpec v1beta1.CommonListenerSpec, clusterName string) corev1.Volume {
+// ListenerServerCertSecretName returns the name of the secret holding the server certificate of the listener
+func ListenerServerCertSecretName(commonSpec v1beta1.CommonListenerSpec, clusterName string) string {
 	// Use default one if custom has not specified
-	secretName := fmt.Sprintf(pkicommon.BrokerServerCertTemplate, clusterName)
 	if commonSpec.GetServerSSLCertSecretName() != "" {
-		secretName = commonSpec.GetServerSSLCertSecretName()
+		return commonSpec.GetServerSSLCertSecretName()
 	}
+	return fmt.Sprintf(pkicommon.BrokerServerCertTemplate, clusterName)
+}
+
+func generateVolumeForListenersCertsFromCommonSpec(commonSpec v1beta1.CommonListenerSpec, clusterName string) corev1.Volume {
 	return corev1.Volume{
 		Name: fmt.Sprintf(listenerSSLCertVolumeNameTemplate, commonSpec.Name),
 		VolumeSource: corev1.VolumeSource{
 			Secret: &corev1.SecretVolumeSource{
-				SecretName:  secretName,
+				SecretName:  ListenerServerCertSecretName(commonSpec, clusterName),
 				DefaultMode: util.Int32Pointer(0644),
 			},
 		},
@@ -476,6 +574,24 @@ func generateVolumeMountForClientSSLCerts() corev1.VolumeMount {
 	}
 }
 
+// generateTieredStorageEnvs exposes the remote storage credentials to the broker through environment variables
+func generateTieredStorageEnvs(tieredStorage *v1beta1.TieredStorageConfig) []corev1.EnvVar {
+	if tieredStorage == nil {
+		return nil
+	}
+	envs := make([]corev1.EnvVar, 0, len(tieredStorage.Credentials))
+	for _, credential := range tieredStorage.Credentials {
+		secretKeyRef := credential.SecretKeyRef
+		envs = append(envs, corev1.EnvVar{
+			Name: credential.EnvName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &secretKeyRef,
+			},
+		})
+	}
+	return envs
+}
+
 func generateEnvConfig(brokerConfig *v1beta1.BrokerConfig, defaultEnvVars []corev1.EnvVar) []corev1.EnvVar {
 	envs := map[string]corev1.EnvVar{}
 
@@ -61,7 +61,7 @@ func TestGetAffinity(t *testing.T) {
 
 	cluster.Spec.OneBrokerPerNode = true
 	// expecting old behavior
-	affinity := getAffinity(&nilAffinityBrokerConfig, &cluster)
+	affinity := GetAffinity(&nilAffinityBrokerConfig, &cluster)
 	assert.DeepEqual(t, affinity.PodAntiAffinity, defaultPodAntiAffinity.PodAntiAffinity)
 
 	broker := v1beta1.Broker{
@@ -71,7 +71,7 @@ func TestGetAffinity(t *testing.T) {
 	mergedAffinityBrokerConfig, _ := broker.GetBrokerConfig(cluster.Spec)
 
 	// expecting old behavior
-	affinity = getAffinity(mergedAffinityBrokerConfig, &cluster)
+	affinity = GetAffinity(mergedAffinityBrokerConfig, &cluster)
 	assert.DeepEqual(t, affinity.PodAntiAffinity, defaultPodAntiAffinity.PodAntiAffinity)
 
 	broker = v1beta1.Broker{
@@ -82,21 +82,21 @@ func TestGetAffinity(t *testing.T) {
 	mergedAffinityBrokerConfig2, _ := broker.GetBrokerConfig(cluster.Spec)
 
 	// expecting old behavior
-	affinity = getAffinity(mergedAffinityBrokerConfig2, &cluster)
+	affinity = GetAffinity(mergedAffinityBrokerConfig2, &cluster)
 	assert.DeepEqual(t, affinity.PodAntiAffinity, defaultPodAntiAffinity.PodAntiAffinity)
 
 	nonNilAffinityBrokerConfig := v1beta1.BrokerConfig{Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}}
 
 	cluster.Spec.OneBrokerPerNode = false
 	// still expecting old behavior but with only a preferred anti-affinity
-	affinity = getAffinity(&nilAffinityBrokerConfig, &cluster)
+	affinity = GetAffinity(&nilAffinityBrokerConfig, &cluster)
 	if affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].Weight != int32(100) ||
 		len(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 0 {
 		t.Error("Given affinity does not match expectations")
 	}
 
 	// should just return what was given as an input
-	affinity = getAffinity(&nonNilAffinityBrokerConfig, &cluster)
+	affinity = GetAffinity(&nonNilAffinityBrokerConfig, &cluster)
 	assert.DeepEqual(t, affinity, nonNilAffinityBrokerConfig.Affinity.DeepCopy())
 }
 
@@ -185,3 +185,115 @@ func Test_generateEnvConfig(t *testing.T) {
 		t.Error("Expected:", expected, "Got:", result)
 	}
 }
+
+func TestGetInitContainersWithTieredStorage(t *testing.T) {
+	spec := v1beta1.KafkaClusterSpec{
+		TieredStorage: &v1beta1.TieredStorageConfig{
+			RemoteStorageManagerClassName: "io.aiven.kafka.tieredstorage.RemoteStorageManager",
+			PluginImage:                   "tiered-storage-plugin:latest",
+			PluginPath:                    "/tiered-storage",
+		},
+	}
+
+	initContainers := getInitContainers(&v1beta1.BrokerConfig{}, spec)
+	var pluginContainer *corev1.Container
+	for i := range initContainers {
+		if initContainers[i].Name == tieredStoragePluginsVolumeName {
+			pluginContainer = &initContainers[i]
+		}
+	}
+	if pluginContainer == nil {
+		t.Fatal("expected tiered storage plugin init container")
+	}
+	if pluginContainer.Image != "tiered-storage-plugin:latest" {
+		t.Errorf("expected image: tiered-storage-plugin:latest, got: %s", pluginContainer.Image)
+	}
+	expectedCommand := []string{"/bin/sh", "-cex", "cp -rv /tiered-storage/. /opt/kafka/libs/tiered-storage/"}
+	if !reflect.DeepEqual(pluginContainer.Command, expectedCommand) {
+		t.Errorf("expected command: %v, got: %v", expectedCommand, pluginContainer.Command)
+	}
+
+	initContainers = getInitContainers(&v1beta1.BrokerConfig{}, v1beta1.KafkaClusterSpec{})
+	for _, container := range initContainers {
+		if container.Name == tieredStoragePluginsVolumeName {
+			t.Error("tiered storage plugin init container is not expected when tiered storage is not configured")
+		}
+	}
+}
+
+func TestGenerateTieredStorageEnvs(t *testing.T) {
+	tieredStorage := &v1beta1.TieredStorageConfig{
+		Credentials: []v1beta1.TieredStorageCredential{
+			{
+				EnvName: "AWS_ACCESS_KEY_ID",
+				SecretKeyRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "s3-credentials"},
+					Key:                  "access-key-id",
+				},
+			},
+		},
+	}
+	expected := []corev1.EnvVar{
+		{
+			Name: "AWS_ACCESS_KEY_ID",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "s3-credentials"},
+					Key:                  "access-key-id",
+				},
+			},
+		},
+	}
+
+	if envs := generateTieredStorageEnvs(tieredStorage); !reflect.DeepEqual(envs, expected) {
+		t.Errorf("expected: %v, got: %v", expected, envs)
+	}
+	if envs := generateTieredStorageEnvs(nil); len(envs) != 0 {
+		t.Errorf("expected no envs, got: %v", envs)
+	}
+}
+
+func TestPinBrokerToZone(t *testing.T) {
+	zoneRequirement := corev1.NodeSelectorRequirement{
+		Key:      "topology.kubernetes.io/zone",
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{"us-east-1a"},
+	}
+
+	// no node affinity defined
+	affinity := pinBrokerToZone(&corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}, "topology.kubernetes.io/zone", "us-east-1a")
+	assert.DeepEqual(t, affinity, &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{},
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement}},
+				},
+			},
+		},
+	})
+
+	// the zone is required by every node selector term
+	userRequirement := corev1.NodeSelectorRequirement{
+		Key:      "node.kubernetes.io/instance-type",
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{"m5.xlarge"},
+	}
+	userAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{userRequirement}},
+					{},
+				},
+			},
+		},
+	}
+	affinity = pinBrokerToZone(userAffinity, "topology.kubernetes.io/zone", "us-east-1a")
+	assert.DeepEqual(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{userRequirement, zoneRequirement}},
+		{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement}},
+	})
+	// the affinity given by the user is not modified
+	assert.Equal(t, len(userAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions), 1)
+}
@@ -0,0 +1,282 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/util"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+const (
+	plainLoginModule       = "org.apache.kafka.common.security.plain.PlainLoginModule"
+	scramLoginModule       = "org.apache.kafka.common.security.scram.ScramLoginModule"
+	oauthBearerLoginModule = "org.apache.kafka.common.security.oauthbearer.OAuthBearerLoginModule"
+	krb5LoginModule        = "com.sun.security.auth.module.Krb5LoginModule"
+
+	oauthBearerValidatorCallbackHandler = "org.apache.kafka.common.security.oauthbearer.OAuthBearerValidatorCallbackHandler"
+
+	saslUsernameKey = "username"
+	saslPasswordKey = "password"
+	saslKeytabKey   = "keytab"
+)
+
+// jaasOptionNamePattern matches the option names the JAAS configuration parser of Kafka accepts without quoting
+var jaasOptionNamePattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.-]*$`)
+
+var jaasValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+// saslListeners returns the listeners with typed SASL configuration
+func saslListeners(l v1beta1.ListenersConfig) []v1beta1.CommonListenerSpec {
+	var listeners []v1beta1.CommonListenerSpec
+	for _, iListener := range l.InternalListeners {
+		if iListener.Type.IsSasl() && iListener.SASL != nil {
+			listeners = append(listeners, iListener.CommonListenerSpec)
+		}
+	}
+	for _, eListener := range l.ExternalListeners {
+		if eListener.Type.IsSasl() && eListener.SASL != nil {
+			listeners = append(listeners, eListener.CommonListenerSpec)
+		}
+	}
+	return listeners
+}
+
+// hasSASLConfigSecrets returns true if the JAAS configuration of a listener holds credentials read from secrets
+func hasSASLConfigSecrets(l v1beta1.ListenersConfig) bool {
+	for _, listener := range saslListeners(l) {
+		for _, mechanism := range listener.SASL.Mechanisms {
+			if saslJAASConfigFromSecrets(listener.SASL, mechanism) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// saslJAASConfigFromSecrets returns true if the JAAS configuration of the mechanism holds credentials read from secrets,
+// in which case it is set through the FileConfigProvider instead of the broker configuration
+func saslJAASConfigFromSecrets(sasl *v1beta1.SASLConfig, mechanism v1beta1.SASLMechanism) bool {
+	switch {
+	case mechanism == v1beta1.SASLMechanismPlain:
+		return true
+	case mechanism.IsScram():
+		return sasl.Scram != nil && sasl.Scram.BrokerCredentialsSecret != ""
+	default:
+		return false
+	}
+}
+
+// listenerSASLConfigKey returns the name of the listener configuration specific to the SASL mechanism
+func listenerSASLConfigKey(listener string, mechanism v1beta1.SASLMechanism, config string) string {
+	return fmt.Sprintf("%s.%s.%s.%s", kafkautils.KafkaConfigListenerName, listener, mechanism.ConfigPrefix(), config)
+}
+
+// generateListenerSASLConfig sets the SASL configuration of the listener which does not hold credentials
+func generateListenerSASLConfig(config *properties.Properties, listener v1beta1.CommonListenerSpec, log logr.Logger) {
+	sasl := listener.SASL
+	mechanisms := make([]string, 0, len(sasl.Mechanisms))
+	for _, mechanism := range sasl.Mechanisms {
+		mechanisms = append(mechanisms, string(mechanism))
+	}
+	listenerSASLConfig := map[string]string{
+		fmt.Sprintf("%s.%s.%s", kafkautils.KafkaConfigListenerName, listener.Name, kafkautils.KafkaConfigSASLEnabledMechanisms): strings.Join(mechanisms, ","),
+	}
+
+	for _, mechanism := range sasl.Mechanisms {
+		if saslJAASConfigFromSecrets(sasl, mechanism) {
+			continue
+		}
+		jaasConfigKey := listenerSASLConfigKey(listener.Name, mechanism, kafkautils.KafkaConfigSASLJAASConfig)
+		switch {
+		case mechanism.IsScram():
+			listenerSASLConfig[jaasConfigKey] = jaasConfig(scramLoginModule, nil)
+		case mechanism == v1beta1.SASLMechanismOAuthBearer && sasl.OAuthBearer != nil:
+			listenerSASLConfig[jaasConfigKey] = jaasConfig(oauthBearerLoginModule, nil)
+			listenerSASLConfig[listenerSASLConfigKey(listener.Name, mechanism, kafkautils.KafkaConfigSASLServerCallbackHandlerClass)] = oauthBearerValidatorCallbackHandler
+			listenerSASLConfig[listenerSASLConfigKey(listener.Name, mechanism, kafkautils.KafkaConfigSASLOAuthBearerJWKSEndpointURL)] = sasl.OAuthBearer.JWKSEndpointURL
+			if sasl.OAuthBearer.ExpectedIssuer != "" {
+				listenerSASLConfig[listenerSASLConfigKey(listener.Name, mechanism, kafkautils.KafkaConfigSASLOAuthBearerExpectedIssuer)] = sasl.OAuthBearer.ExpectedIssuer
+			}
+			if len(sasl.OAuthBearer.ExpectedAudience) > 0 {
+				listenerSASLConfig[listenerSASLConfigKey(listener.Name, mechanism, kafkautils.KafkaConfigSASLOAuthBearerExpectedAudience)] = strings.Join(sasl.OAuthBearer.ExpectedAudience, ",")
+			}
+		case mechanism == v1beta1.SASLMechanismGSSAPI && sasl.GSSAPI != nil:
+			listenerSASLConfig[jaasConfigKey] = jaasConfig(krb5LoginModule, [][2]string{
+				{"useKeyTab", "true"},
+				{"storeKey", "true"},
+				{"keyTab", fmt.Sprintf("%s/%s/%s", saslKeytabPath, listener.Name, saslKeytabKey)},
+				{"principal", sasl.GSSAPI.Principal},
+				{"serviceName", sasl.GSSAPI.GetServiceName()},
+			})
+		}
+	}
+
+	for k, v := range listenerSASLConfig {
+		if err := config.Set(k, v); err != nil {
+			log.Error(err, fmt.Sprintf("setting '%s' parameter in broker configuration resulted an error", k))
+		}
+	}
+}
+
+// generateSASLJAASConfigs returns the JAAS configurations of the SASL listeners which hold credentials read from secrets.
+// The getSecret function returns nil when the secret does not exist.
+func generateSASLJAASConfigs(l v1beta1.ListenersConfig, getSecret func(name string) (*corev1.Secret, error)) (map[string]string, error) {
+	jaasConfigs := make(map[string]string)
+	for _, listener := range saslListeners(l) {
+		sasl := listener.SASL
+		for _, mechanism := range sasl.Mechanisms {
+			if !saslJAASConfigFromSecrets(sasl, mechanism) {
+				continue
+			}
+			var (
+				jaas string
+				err  error
+			)
+			switch {
+			case mechanism == v1beta1.SASLMechanismPlain:
+				jaas, err = plainJAASConfig(sasl.Plain, getSecret)
+			case mechanism.IsScram():
+				jaas, err = scramJAASConfig(sasl.Scram, getSecret)
+			}
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(err, "could not generate JAAS configuration", "listener", listener.Name, "mechanism", mechanism)
+			}
+			jaasConfigs[listenerSASLConfigKey(listener.Name, mechanism, kafkautils.KafkaConfigSASLJAASConfig)] = jaas
+		}
+	}
+	return jaasConfigs, nil
+}
+
+// plainJAASConfig returns the JAAS configuration of the PLAIN mechanism holding the passwords of the users
+func plainJAASConfig(plain *v1beta1.PlainSASLConfig, getSecret func(name string) (*corev1.Secret, error)) (string, error) {
+	if plain == nil {
+		return "", errors.New("the configuration of the PLAIN mechanism is missing")
+	}
+	secret, err := getSecret(plain.UsersSecret)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("secret not found"), "users secret of the PLAIN mechanism is missing", "secret", plain.UsersSecret)
+	}
+
+	usernames := make([]string, 0, len(secret.Data))
+	for username := range secret.Data {
+		if !jaasOptionNamePattern.MatchString(username) {
+			return "", errors.NewWithDetails("username can not be used in the JAAS configuration", "secret", plain.UsersSecret, "username", username)
+		}
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+
+	var options [][2]string
+	if plain.BrokerUser != "" {
+		password, ok := secret.Data[plain.BrokerUser]
+		if !ok {
+			return "", errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("secret key not found"), "broker user is missing from the users secret of the PLAIN mechanism",
+				"secret", plain.UsersSecret, "username", plain.BrokerUser)
+		}
+		options = append(options, [2]string{saslUsernameKey, plain.BrokerUser}, [2]string{saslPasswordKey, string(password)})
+	}
+	for _, username := range usernames {
+		options = append(options, [2]string{"user_" + username, string(secret.Data[username])})
+	}
+	return jaasConfig(plainLoginModule, options), nil
+}
+
+// scramJAASConfig returns the JAAS configuration of the SCRAM mechanisms holding the credentials of the brokers
+func scramJAASConfig(scram *v1beta1.ScramSASLConfig, getSecret func(name string) (*corev1.Secret, error)) (string, error) {
+	secret, err := getSecret(scram.BrokerCredentialsSecret)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil {
+		return "", errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("secret not found"), "broker credentials secret of the SCRAM mechanism is missing",
+			"secret", scram.BrokerCredentialsSecret)
+	}
+	options := make([][2]string, 0, 2)
+	for _, key := range []string{saslUsernameKey, saslPasswordKey} {
+		value, ok := secret.Data[key]
+		if !ok {
+			return "", errorfactory.New(errorfactory.ResourceNotReady{}, errors.New("secret key not found"), "broker credentials secret of the SCRAM mechanism is incomplete",
+				"secret", scram.BrokerCredentialsSecret, "key", key)
+		}
+		options = append(options, [2]string{key, string(value)})
+	}
+	return jaasConfig(scramLoginModule, options), nil
+}
+
+// jaasConfig returns the JAAS configuration of the login module with the given options in order
+func jaasConfig(loginModule string, options [][2]string) string {
+	var sb strings.Builder
+	sb.WriteString(loginModule)
+	sb.WriteString(" required")
+	for _, option := range options {
+		sb.WriteString(fmt.Sprintf(" %s=\"%s\"", option[0], jaasValueEscaper.Replace(option[1])))
+	}
+	sb.WriteString(";")
+	return sb.String()
+}
+
+// generateVolumesForSASLKeytabs returns the volumes of the keytabs of the GSSAPI listeners
+func generateVolumesForSASLKeytabs(l v1beta1.ListenersConfig) []corev1.Volume {
+	var volumes []corev1.Volume
+	for _, listener := range saslListeners(l) {
+		if !listener.SASL.HasMechanism(v1beta1.SASLMechanismGSSAPI) || listener.SASL.GSSAPI == nil {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: fmt.Sprintf(saslKeytabVolumeNameTemplate, listener.Name),
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  listener.SASL.GSSAPI.KeytabSecret,
+					Items:       []corev1.KeyToPath{{Key: saslKeytabKey, Path: saslKeytabKey}},
+					// the keytab is readable only by the owner and the fsGroup of the pod, like the config secrets
+					DefaultMode: util.Int32Pointer(0440),
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+// generateVolumeMountsForSASLKeytabs returns the volume mounts of the keytabs of the GSSAPI listeners
+func generateVolumeMountsForSASLKeytabs(l v1beta1.ListenersConfig) []corev1.VolumeMount {
+	var volumeMounts []corev1.VolumeMount
+	for _, listener := range saslListeners(l) {
+		if !listener.SASL.HasMechanism(v1beta1.SASLMechanismGSSAPI) || listener.SASL.GSSAPI == nil {
+			continue
+		}
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      fmt.Sprintf(saslKeytabVolumeNameTemplate, listener.Name),
+			MountPath: fmt.Sprintf("%s/%s", saslKeytabPath, listener.Name),
+			ReadOnly:  true,
+		})
+	}
+	return volumeMounts
+}
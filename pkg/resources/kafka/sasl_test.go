@@ -0,0 +1,200 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/errorfactory"
+	"github.com/banzaicloud/koperator/pkg/util"
+	kafkautils "github.com/banzaicloud/koperator/pkg/util/kafka"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
+)
+
+func testSASLListenersConfig() v1beta1.ListenersConfig {
+	return v1beta1.ListenersConfig{
+		InternalListeners: []v1beta1.InternalListenerConfig{{
+			CommonListenerSpec: v1beta1.CommonListenerSpec{
+				Type:          v1beta1.SecurityProtocolSaslPlaintext,
+				Name:          "internal",
+				ContainerPort: 29092,
+				SASL: &v1beta1.SASLConfig{
+					Mechanisms:           []v1beta1.SASLMechanism{v1beta1.SASLMechanismScramSHA512, v1beta1.SASLMechanismPlain},
+					InterBrokerMechanism: v1beta1.SASLMechanismPlain,
+					Plain:                &v1beta1.PlainSASLConfig{UsersSecret: "kafka-users", BrokerUser: "broker"},
+				},
+			},
+			UsedForInnerBrokerCommunication: true,
+		}},
+		ExternalListeners: []v1beta1.ExternalListenerConfig{{
+			CommonListenerSpec: v1beta1.CommonListenerSpec{
+				Type:          v1beta1.SecurityProtocolSaslSSL,
+				Name:          "external",
+				ContainerPort: 9094,
+				SASL: &v1beta1.SASLConfig{
+					Mechanisms: []v1beta1.SASLMechanism{v1beta1.SASLMechanismOAuthBearer, v1beta1.SASLMechanismGSSAPI},
+					OAuthBearer: &v1beta1.OAuthBearerSASLConfig{
+						JWKSEndpointURL:  "https://idp.example.com/jwks",
+						ExpectedIssuer:   "https://idp.example.com",
+						ExpectedAudience: []string{"kafka", "kafka-external"},
+					},
+					GSSAPI: &v1beta1.GSSAPISASLConfig{Principal: "kafka/kafka.example.com@EXAMPLE.COM", KeytabSecret: "kafka-keytab"},
+				},
+			},
+		}},
+	}
+}
+
+func TestGenerateListenerSpecificConfigSASL(t *testing.T) {
+	listenersConfig := testSASLListenersConfig()
+	generated := generateListenerSpecificConfig(&listenersConfig, nil, logr.Discard())
+	for _, key := range []string{kafkautils.KafkaConfigListenerSecurityProtocolMap, kafkautils.KafkaConfigInterBrokerListenerName,
+		kafkautils.KafkaConfigListeners} {
+		generated.Delete(key)
+	}
+
+	// the JAAS configuration of PLAIN is set from the config secrets
+	expected, err := properties.NewFromString(`listener.name.external.gssapi.sasl.jaas.config=com.sun.security.auth.module.Krb5LoginModule required useKeyTab="true" storeKey="true" keyTab="/var/run/secrets/kafka/sasl/external/keytab" principal="kafka/kafka.example.com@EXAMPLE.COM" serviceName="kafka";
+listener.name.external.oauthbearer.sasl.jaas.config=org.apache.kafka.common.security.oauthbearer.OAuthBearerLoginModule required;
+listener.name.external.oauthbearer.sasl.oauthbearer.expected.audience=kafka,kafka-external
+listener.name.external.oauthbearer.sasl.oauthbearer.expected.issuer=https://idp.example.com
+listener.name.external.oauthbearer.sasl.oauthbearer.jwks.endpoint.url=https://idp.example.com/jwks
+listener.name.external.oauthbearer.sasl.server.callback.handler.class=org.apache.kafka.common.security.oauthbearer.OAuthBearerValidatorCallbackHandler
+listener.name.external.sasl.enabled.mechanisms=OAUTHBEARER,GSSAPI
+listener.name.internal.sasl.enabled.mechanisms=SCRAM-SHA-512,PLAIN
+listener.name.internal.scram-sha-512.sasl.jaas.config=org.apache.kafka.common.security.scram.ScramLoginModule required;
+sasl.mechanism.inter.broker.protocol=PLAIN
+`)
+	require.NoError(t, err)
+	generated.Sort()
+	expected.Sort()
+	require.Equal(t, expected.String(), generated.String())
+}
+
+func TestGenerateSASLJAASConfigs(t *testing.T) {
+	secrets := map[string]*corev1.Secret{
+		"kafka-users": {Data: map[string][]byte{
+			"broker": []byte("broker-secret"),
+			"alice":  []byte(`a"\b`),
+		}},
+		"kafka-broker": {Data: map[string][]byte{
+			"username": []byte("broker"),
+			"password": []byte("scram-secret"),
+		}},
+	}
+	getSecret := func(name string) (*corev1.Secret, error) {
+		return secrets[name], nil
+	}
+
+	listenersConfig := testSASLListenersConfig()
+	listenersConfig.InternalListeners[0].SASL.Scram = &v1beta1.ScramSASLConfig{BrokerCredentialsSecret: "kafka-broker"}
+	jaasConfigs, err := generateSASLJAASConfigs(listenersConfig, getSecret)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"listener.name.internal.plain.sasl.jaas.config": `org.apache.kafka.common.security.plain.PlainLoginModule required username="broker" password="broker-secret" ` +
+			`user_alice="a\"\\b" user_broker="broker-secret";`,
+		"listener.name.internal.scram-sha-512.sasl.jaas.config": `org.apache.kafka.common.security.scram.ScramLoginModule required username="broker" password="scram-secret";`,
+	}, jaasConfigs)
+
+	// the JAAS configuration of SCRAM without broker credentials is set in the broker configuration
+	require.True(t, hasSASLConfigSecrets(listenersConfig))
+	listenersConfig.InternalListeners[0].SASL.Scram = nil
+	jaasConfigs, err = generateSASLJAASConfigs(listenersConfig, getSecret)
+	require.NoError(t, err)
+	require.Len(t, jaasConfigs, 1)
+
+	// the broker user must be one of the users
+	listenersConfig.InternalListeners[0].SASL.Plain.BrokerUser = "admin"
+	_, err = generateSASLJAASConfigs(listenersConfig, getSecret)
+	require.ErrorAs(t, err, &errorfactory.ResourceNotReady{})
+
+	// usernames which can not be set as JAAS options are rejected
+	listenersConfig.InternalListeners[0].SASL.Plain.BrokerUser = ""
+	secrets["kafka-users"].Data["bob@example.com"] = []byte("bob")
+	_, err = generateSASLJAASConfigs(listenersConfig, getSecret)
+	require.Error(t, err)
+
+	// missing users secret
+	delete(secrets, "kafka-users")
+	_, err = generateSASLJAASConfigs(listenersConfig, getSecret)
+	require.ErrorAs(t, err, &errorfactory.ResourceNotReady{})
+
+	listenersConfig.InternalListeners[0].SASL = nil
+	require.False(t, hasSASLConfigSecrets(listenersConfig))
+}
+
+func TestReconcileConfigSecretsSASL(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-users", Namespace: "kafka"},
+		Data:       map[string][]byte{"broker": []byte("broker-secret")},
+	}
+	kafkaCluster := &v1beta1.KafkaCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+		Spec: v1beta1.KafkaClusterSpec{
+			ListenersConfig: testSASLListenersConfig(),
+			Brokers:         []v1beta1.Broker{{Id: 0}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	r := New(fakeClient, nil, kafkaCluster, nil)
+
+	files, err := r.reconcileConfigSecrets(ctx, logr.Discard())
+	require.NoError(t, err)
+	jaasConfigKey := "listener.name.internal.plain.sasl.jaas.config"
+	fileName, ok := files[configSecretKey{config: jaasConfigKey}]
+	require.True(t, ok)
+
+	config := generateConfigSecretConfig(nil, files, properties.NewProperties(), logr.Discard())
+	jaasConfig, ok := config.Get(jaasConfigKey)
+	require.True(t, ok)
+	require.Equal(t, fmt.Sprintf("${file:/var/run/secrets/kafka/config/%s:%s}", fileName, jaasConfigKey), jaasConfig.Value())
+
+	// configSecretRefs override the generated configurations
+	ref := configSecretRef(jaasConfigKey, "kafka-users", "broker")
+	files[newConfigSecretKey(ref)] = "0123.properties"
+	config = generateConfigSecretConfig([]v1beta1.ConfigSecretRef{ref}, files, properties.NewProperties(), logr.Discard())
+	jaasConfig, ok = config.Get(jaasConfigKey)
+	require.True(t, ok)
+	require.Equal(t, "${file:/var/run/secrets/kafka/config/0123.properties:"+jaasConfigKey+"}", jaasConfig.Value())
+}
+
+func TestGenerateVolumesForSASLKeytabs(t *testing.T) {
+	listenersConfig := testSASLListenersConfig()
+	require.Equal(t, []corev1.Volume{{
+		Name: "sasl-external-keytab",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  "kafka-keytab",
+				Items:       []corev1.KeyToPath{{Key: "keytab", Path: "keytab"}},
+				DefaultMode: util.Int32Pointer(0440),
+			},
+		},
+	}}, generateVolumesForSASLKeytabs(listenersConfig))
+	require.Equal(t, []corev1.VolumeMount{{
+		Name:      "sasl-external-keytab",
+		MountPath: "/var/run/secrets/kafka/sasl/external",
+		ReadOnly:  true,
+	}}, generateVolumeMountsForSASLKeytabs(listenersConfig))
+}
@@ -59,6 +59,8 @@ func (r *Reconciler) service(id int32, _ *v1beta1.BrokerConfig) runtime.Object {
 			SessionAffinity: corev1.ServiceAffinityNone,
 			Selector:        apiutil.MergeLabels(apiutil.LabelsForKafka(r.KafkaCluster.Name), map[string]string{v1beta1.BrokerIdLabelKey: fmt.Sprintf("%d", id)}),
 			Ports:           usedPorts,
+			// the broker has to be reachable through its advertised address while it is catching up with its replicas
+			PublishNotReadyAddresses: r.KafkaCluster.Spec.RollingUpgradeConfig.ReplicasInSyncReadinessGate,
 		},
 	}
 }
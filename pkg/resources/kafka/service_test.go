@@ -0,0 +1,63 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/resources"
+)
+
+func TestServicesPublishNotReadyAddresses(t *testing.T) {
+	testCases := []struct {
+		testName                    string
+		replicasInSyncReadinessGate bool
+	}{
+		{
+			testName:                    "replicas in sync readiness gate disabled",
+			replicasInSyncReadinessGate: false,
+		},
+		{
+			testName:                    "replicas in sync readiness gate enabled without headless service",
+			replicasInSyncReadinessGate: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.testName, func(t *testing.T) {
+			r := Reconciler{
+				Reconciler: resources.Reconciler{
+					KafkaCluster: &v1beta1.KafkaCluster{
+						ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "kafka"},
+						Spec: v1beta1.KafkaClusterSpec{
+							HeadlessServiceEnabled: false,
+							RollingUpgradeConfig: v1beta1.RollingUpgradeConfig{
+								ReplicasInSyncReadinessGate: test.replicasInSyncReadinessGate,
+							},
+						},
+					},
+				},
+			}
+
+			require.Equal(t, test.replicasInSyncReadinessGate, r.service(0, nil).(*corev1.Service).Spec.PublishNotReadyAddresses)
+			require.Equal(t, test.replicasInSyncReadinessGate, r.allBrokerService().(*corev1.Service).Spec.PublishNotReadyAddresses)
+		})
+	}
+}
@@ -19,8 +19,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/banzaicloud/go-cruise-control/pkg/api"
@@ -40,33 +43,89 @@ const (
 	paramExcludeRemoved = "exclude_recently_removed_brokers"
 	paramDestbrokerIDs  = "destination_broker_ids"
 	paramRebalanceDisk  = "rebalance_disk"
+	// paramGoals lists the goals of the rebalance operation, the ready default goals are used when it is not set
+	paramGoals             = "goals"
+	paramSkipHardGoalCheck = "skip_hard_goal_check"
+	// paramBrokerIDAndLogDirs lists the log dirs of the remove_disks operation in brokerID-logDir format
+	paramBrokerIDAndLogDirs = "brokerid_and_logdirs"
+	// Constants for the execution related parameters which are common for the add_broker, remove_broker and
+	// rebalance Cruise Control operations
+	paramReplicationThrottle              = "replication_throttle"
+	paramConcurrentPartitionMovements     = "concurrent_partition_movements_per_broker"
+	paramConcurrentLeaderMovements        = "concurrent_leader_movements"
+	paramExecutionProgressCheckIntervalMs = "execution_progress_check_interval_ms"
 	// Cruise Control API returns NullPointerException when a broker storage capacity calculations are missing
 	// from the Cruise Control configurations
 	nullPointerExceptionErrString = "NullPointerException"
 	// This error happens when the Cruise Control has not got enough information from the metrics yet
 	notEnoughValidWindowsExceptionErrString = "NotEnoughValidWindowsException"
+
+	// cruiseControlRequestTimeout bounds the requests sent to Cruise Control. Long running operations are
+	// converted to asynchronous user tasks by Cruise Control well before this timeout.
+	cruiseControlRequestTimeout = time.Minute
 )
 
 var (
 	newCruiseControlScaler   = createNewDefaultCruiseControlScaler
 	addBrokerSupportedParams = map[string]struct{}{
-		paramBrokerID:       {},
-		paramExcludeDemoted: {},
-		paramExcludeRemoved: {},
+		paramBrokerID:                         {},
+		paramExcludeDemoted:                   {},
+		paramExcludeRemoved:                   {},
+		paramReplicationThrottle:              {},
+		paramConcurrentPartitionMovements:     {},
+		paramConcurrentLeaderMovements:        {},
+		paramExecutionProgressCheckIntervalMs: {},
 	}
 	removeBrokerSupportedParams = map[string]struct{}{
-		paramBrokerID:       {},
-		paramExcludeDemoted: {},
-		paramExcludeRemoved: {},
+		paramBrokerID:                         {},
+		paramExcludeDemoted:                   {},
+		paramExcludeRemoved:                   {},
+		paramReplicationThrottle:              {},
+		paramConcurrentPartitionMovements:     {},
+		paramConcurrentLeaderMovements:        {},
+		paramExecutionProgressCheckIntervalMs: {},
+	}
+	removeDisksSupportedParams = map[string]struct{}{
+		paramBrokerIDAndLogDirs: {},
+	}
+	demoteBrokerSupportedParams = map[string]struct{}{
+		paramBrokerID:                         {},
+		paramConcurrentLeaderMovements:        {},
+		paramExecutionProgressCheckIntervalMs: {},
 	}
 	rebalanceSupportedParams = map[string]struct{}{
-		paramDestbrokerIDs:  {},
-		paramRebalanceDisk:  {},
-		paramExcludeDemoted: {},
-		paramExcludeRemoved: {},
+		paramDestbrokerIDs:                    {},
+		paramRebalanceDisk:                    {},
+		paramGoals:                            {},
+		paramSkipHardGoalCheck:                {},
+		paramExcludeDemoted:                   {},
+		paramExcludeRemoved:                   {},
+		paramReplicationThrottle:              {},
+		paramConcurrentPartitionMovements:     {},
+		paramConcurrentLeaderMovements:        {},
+		paramExecutionProgressCheckIntervalMs: {},
 	}
 )
 
+// ExecutionParameters returns the replication throttle and movement concurrency parameters of Cruise Control
+// operations set in the provided CruiseControlTaskSpec. Unset fields are omitted so Cruise Control defaults apply.
+func ExecutionParameters(taskSpec v1beta1.CruiseControlTaskSpec) map[string]string {
+	params := make(map[string]string)
+	if taskSpec.ReplicationThrottle != nil {
+		params[paramReplicationThrottle] = strconv.FormatInt(*taskSpec.ReplicationThrottle, 10)
+	}
+	if taskSpec.ConcurrentPartitionMovementsPerBroker != nil {
+		params[paramConcurrentPartitionMovements] = strconv.FormatInt(int64(*taskSpec.ConcurrentPartitionMovementsPerBroker), 10)
+	}
+	if taskSpec.ConcurrentLeaderMovements != nil {
+		params[paramConcurrentLeaderMovements] = strconv.FormatInt(int64(*taskSpec.ConcurrentLeaderMovements), 10)
+	}
+	if taskSpec.ExecutionProgressCheckIntervalMs != nil {
+		params[paramExecutionProgressCheckIntervalMs] = strconv.FormatInt(*taskSpec.ExecutionProgressCheckIntervalMs, 10)
+	}
+	return params
+}
+
 func ScaleFactoryFn() func(ctx context.Context, kafkaCluster *v1beta1.KafkaCluster) (CruiseControlScaler, error) {
 	return func(ctx context.Context, kafkaCluster *v1beta1.KafkaCluster) (CruiseControlScaler, error) {
 		return NewCruiseControlScaler(ctx, CruiseControlURLFromKafkaCluster(kafkaCluster))
@@ -80,9 +139,15 @@ func NewCruiseControlScaler(ctx context.Context, serverURL string) (CruiseContro
 func createNewDefaultCruiseControlScaler(ctx context.Context, serverURL string) (CruiseControlScaler, error) {
 	log := logr.FromContextOrDiscard(ctx).WithName("Scaler")
 
+	// the same client is used for the endpoints of Cruise Control which are not covered by the Cruise Control client
+	httpClient := &http.Client{
+		Transport: http.DefaultTransport,
+		Timeout:   cruiseControlRequestTimeout,
+	}
 	cfg := &client.Config{
-		ServerURL: serverURL,
-		UserAgent: "koperator",
+		ServerURL:  serverURL,
+		UserAgent:  "koperator",
+		HTTPClient: httpClient,
 	}
 
 	cruisecontrol, err := client.NewClient(cfg)
@@ -91,8 +156,10 @@ func createNewDefaultCruiseControlScaler(ctx context.Context, serverURL string)
 		return nil, err
 	}
 	return &cruiseControlScaler{
-		log:    log,
-		client: cruisecontrol,
+		log:        log,
+		client:     cruisecontrol,
+		serverURL:  serverURL,
+		httpClient: httpClient,
 	}, nil
 }
 
@@ -101,6 +168,9 @@ type cruiseControlScaler struct {
 
 	log    logr.Logger
 	client *client.Client
+	// serverURL and httpClient are used to call the endpoints of Cruise Control which are not covered by the client
+	serverURL  string
+	httpClient *http.Client
 }
 
 // Status returns a StatusTaskResult describing the internal state of Cruise Control.
@@ -213,6 +283,8 @@ func convert(result *types.StateResult) CruiseControlStatus {
 		AnalyzerReady:      result.AnalyzerState.IsProposalReady && goalsReady,
 		ProposalReady:      result.AnalyzerState.IsProposalReady,
 		GoalsReady:         goalsReady,
+		MonitorState:       result.MonitorState.State.String(),
+		ExecutorState:      result.ExecutorState.State.String(),
 		MonitoredWindows:   result.MonitorState.NumMonitoredWindows,
 		MonitoringCoverage: result.MonitorState.MonitoringCoveragePercentage,
 	}
@@ -279,6 +351,21 @@ func parseBrokerIDtoSlice(brokerid string) ([]int32, error) {
 	return brokerIDIntSlice, nil
 }
 
+func parseGoals(goals string) ([]types.Goal, error) {
+	var goalSlice []types.Goal
+	for _, name := range strings.Split(goals, ",") {
+		var goal types.Goal
+		if err := goal.UnmarshalJSON([]byte(name)); err != nil {
+			return nil, err
+		}
+		if goal == types.UndefinedGoal {
+			return nil, fmt.Errorf("unknown Cruise Control goal: %s", name)
+		}
+		goalSlice = append(goalSlice, goal)
+	}
+	return goalSlice, nil
+}
+
 // AddBrokersWithParams requests Cruise Control to add the list of provided brokers to the Kafka cluster
 // by reassigning partition replicas to them. The broker list and operation properties can be added
 // with the use of the params argument.
@@ -309,6 +396,30 @@ func (cc *cruiseControlScaler) AddBrokersWithParams(ctx context.Context, params
 					return nil, err
 				}
 				addBrokerReq.ExcludeRecentlyRemovedBrokers = ret
+			case paramReplicationThrottle:
+				ret, err := strconv.ParseInt(pvalue, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				addBrokerReq.ReplicationThrottle = ret
+			case paramConcurrentPartitionMovements:
+				ret, err := strconv.ParseInt(pvalue, 10, 32)
+				if err != nil {
+					return nil, err
+				}
+				addBrokerReq.ConcurrentPartitionMovementsPerBroker = int32(ret)
+			case paramConcurrentLeaderMovements:
+				ret, err := strconv.ParseInt(pvalue, 10, 32)
+				if err != nil {
+					return nil, err
+				}
+				addBrokerReq.ConcurrentLeaderMovements = int32(ret)
+			case paramExecutionProgressCheckIntervalMs:
+				ret, err := strconv.ParseInt(pvalue, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				addBrokerReq.ExecutionProgressCheckIntervalMs = ret
 			default:
 				return nil, fmt.Errorf("unsupported %s parameter: %s, supported parameters: %s", v1alpha1.OperationAddBroker, param, addBrokerSupportedParams)
 			}
@@ -386,6 +497,30 @@ func (cc *cruiseControlScaler) RemoveBrokersWithParams(ctx context.Context, para
 					return nil, err
 				}
 				rmBrokerReq.ExcludeRecentlyRemovedBrokers = ret
+			case paramReplicationThrottle:
+				ret, err := strconv.ParseInt(pvalue, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				rmBrokerReq.ReplicationThrottle = ret
+			case paramConcurrentPartitionMovements:
+				ret, err := strconv.ParseInt(pvalue, 10, 32)
+				if err != nil {
+					return nil, err
+				}
+				rmBrokerReq.ConcurrentPartitionMovementsPerBroker = int32(ret)
+			case paramConcurrentLeaderMovements:
+				ret, err := strconv.ParseInt(pvalue, 10, 32)
+				if err != nil {
+					return nil, err
+				}
+				rmBrokerReq.ConcurrentLeaderMovements = int32(ret)
+			case paramExecutionProgressCheckIntervalMs:
+				ret, err := strconv.ParseInt(pvalue, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				rmBrokerReq.ExecutionProgressCheckIntervalMs = ret
 			default:
 				return nil, fmt.Errorf("unsupported %s parameter: %s, supported parameters: %s", v1alpha1.OperationRemoveBroker, param, removeBrokerSupportedParams)
 			}
@@ -540,6 +675,98 @@ func (cc *cruiseControlScaler) RemoveBrokers(ctx context.Context, brokerIDs ...s
 	}, nil
 }
 
+// DemoteBrokers requests Cruise Control to move the partition leaderships off from the provided brokers.
+func (cc *cruiseControlScaler) DemoteBrokers(ctx context.Context, brokerIDs ...string) (*Result, error) {
+	if len(brokerIDs) == 0 {
+		return nil, errors.New("no broker id(s) provided for demote brokers request")
+	}
+
+	brokersToDemote, err := parseBrokerIDtoSlice(strings.Join(brokerIDs, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	demoteBrokerReq := api.DemoteBrokerRequestWithDefaults()
+	demoteBrokerReq.BrokerIDs = brokersToDemote
+	demoteBrokerResp, err := cc.client.DemoteBroker(ctx, demoteBrokerReq)
+	if err != nil {
+		return &Result{
+			TaskID:             demoteBrokerResp.TaskID,
+			StartedAt:          demoteBrokerResp.Date,
+			ResponseStatusCode: demoteBrokerResp.StatusCode,
+			RequestURL:         demoteBrokerResp.RequestURL,
+			State:              v1beta1.CruiseControlTaskCompletedWithError,
+			Err:                err,
+		}, err
+	}
+
+	return &Result{
+		TaskID:             demoteBrokerResp.TaskID,
+		StartedAt:          demoteBrokerResp.Date,
+		ResponseStatusCode: demoteBrokerResp.StatusCode,
+		RequestURL:         demoteBrokerResp.RequestURL,
+		Result:             demoteBrokerResp.Result,
+		State:              v1beta1.CruiseControlTaskActive,
+	}, nil
+}
+
+// DemoteBrokersWithParams requests Cruise Control to move the partition leaderships off from the provided brokers.
+// The broker list and operation properties can be added with the use of the params argument.
+func (cc *cruiseControlScaler) DemoteBrokersWithParams(ctx context.Context, params map[string]string) (*Result, error) {
+	demoteBrokerReq := api.DemoteBrokerRequestWithDefaults()
+
+	for param, pvalue := range params {
+		if _, ok := demoteBrokerSupportedParams[param]; !ok {
+			return nil, fmt.Errorf("unsupported %s parameter: %s, supported parameters: %s", v1alpha1.OperationDemoteBroker, param, demoteBrokerSupportedParams)
+		}
+		switch param {
+		case paramBrokerID:
+			ret, err := parseBrokerIDtoSlice(pvalue)
+			if err != nil {
+				return nil, err
+			}
+			demoteBrokerReq.BrokerIDs = ret
+		case paramConcurrentLeaderMovements:
+			ret, err := strconv.ParseInt(pvalue, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			demoteBrokerReq.ConcurrentLeaderMovements = int32(ret)
+		case paramExecutionProgressCheckIntervalMs:
+			ret, err := strconv.ParseInt(pvalue, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			demoteBrokerReq.ExecutionProgressCheckIntervalMs = ret
+		}
+	}
+
+	if len(demoteBrokerReq.BrokerIDs) == 0 {
+		return nil, fmt.Errorf("missing %s parameter for %s", paramBrokerID, v1alpha1.OperationDemoteBroker)
+	}
+
+	demoteBrokerResp, err := cc.client.DemoteBroker(ctx, demoteBrokerReq)
+	if err != nil {
+		return &Result{
+			TaskID:             demoteBrokerResp.TaskID,
+			StartedAt:          demoteBrokerResp.Date,
+			ResponseStatusCode: demoteBrokerResp.StatusCode,
+			RequestURL:         demoteBrokerResp.RequestURL,
+			State:              v1beta1.CruiseControlTaskCompletedWithError,
+			Err:                err,
+		}, err
+	}
+
+	return &Result{
+		TaskID:             demoteBrokerResp.TaskID,
+		StartedAt:          demoteBrokerResp.Date,
+		ResponseStatusCode: demoteBrokerResp.StatusCode,
+		RequestURL:         demoteBrokerResp.RequestURL,
+		Result:             demoteBrokerResp.Result,
+		State:              v1beta1.CruiseControlTaskActive,
+	}, nil
+}
+
 func (cc *cruiseControlScaler) RebalanceWithParams(ctx context.Context, params map[string]string) (*Result, error) {
 	rebalanceReq := &api.RebalanceRequest{
 		AllowCapacityEstimation: true,
@@ -562,6 +789,19 @@ func (cc *cruiseControlScaler) RebalanceWithParams(ctx context.Context, params m
 					return nil, err
 				}
 				rebalanceReq.RebalanceDisk = ret
+			case paramGoals:
+				ret, err := parseGoals(pvalue)
+				if err != nil {
+					return nil, err
+				}
+				rebalanceReq.Goals = ret
+				rebalanceReq.UseReadyDefaultGoals = false
+			case paramSkipHardGoalCheck:
+				ret, err := strconv.ParseBool(pvalue)
+				if err != nil {
+					return nil, err
+				}
+				rebalanceReq.SkipHardGoalCheck = ret
 			case paramExcludeDemoted:
 				ret, err := strconv.ParseBool(pvalue)
 				if err != nil {
@@ -574,6 +814,30 @@ func (cc *cruiseControlScaler) RebalanceWithParams(ctx context.Context, params m
 					return nil, err
 				}
 				rebalanceReq.ExcludeRecentlyRemovedBrokers = ret
+			case paramReplicationThrottle:
+				ret, err := strconv.ParseInt(pvalue, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				rebalanceReq.ReplicationThrottle = ret
+			case paramConcurrentPartitionMovements:
+				ret, err := strconv.ParseInt(pvalue, 10, 32)
+				if err != nil {
+					return nil, err
+				}
+				rebalanceReq.ConcurrentPartitionMovementsPerBroker = int32(ret)
+			case paramConcurrentLeaderMovements:
+				ret, err := strconv.ParseInt(pvalue, 10, 32)
+				if err != nil {
+					return nil, err
+				}
+				rebalanceReq.ConcurrentLeaderMovements = int32(ret)
+			case paramExecutionProgressCheckIntervalMs:
+				ret, err := strconv.ParseInt(pvalue, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				rebalanceReq.ExecutionProgressCheckIntervalMs = ret
 			default:
 				return nil, fmt.Errorf("unsupported %s parameter: %s, supported parameters: %s", v1alpha1.OperationRebalance, param, rebalanceSupportedParams)
 			}
@@ -602,6 +866,65 @@ func (cc *cruiseControlScaler) RebalanceWithParams(ctx context.Context, params m
 	}, nil
 }
 
+// RemoveDisksWithParams moves the partition replicas off the provided log dirs of the brokers via the remove_disks
+// Cruise Control operation. The endpoint is not covered by the Cruise Control client so the request is sent directly.
+func (cc *cruiseControlScaler) RemoveDisksWithParams(ctx context.Context, params map[string]string) (*Result, error) {
+	query := url.Values{}
+	query.Set("json", "true")
+	query.Set("dryrun", "false")
+	for param, pvalue := range params {
+		switch param {
+		case paramBrokerIDAndLogDirs:
+			query.Set(param, pvalue)
+		default:
+			return nil, fmt.Errorf("unsupported %s parameter: %s, supported parameters: %s", v1alpha1.OperationRemoveDisks, param, removeDisksSupportedParams)
+		}
+	}
+	if query.Get(paramBrokerIDAndLogDirs) == "" {
+		return nil, fmt.Errorf("missing %s parameter for %s", paramBrokerIDAndLogDirs, v1alpha1.OperationRemoveDisks)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/%s?%s", strings.TrimSuffix(cc.serverURL, "/"), v1alpha1.OperationRemoveDisks, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "koperator")
+
+	httpResp, err := cc.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.WrapIf(err, "sending remove_disks request to Cruise Control failed")
+	}
+	defer httpResp.Body.Close()
+
+	// remove_disks responds with the same optimization result as remove_broker
+	removeDisksResp := &api.RemoveBrokerResponse{}
+	if err := removeDisksResp.UnmarshalResponse(httpResp); err != nil {
+		return nil, errors.WrapIf(err, "failed to parse remove_disks response of Cruise Control")
+	}
+	if removeDisksResp.Failed() {
+		err = removeDisksResp.Err()
+		return &Result{
+			TaskID:             removeDisksResp.TaskID,
+			StartedAt:          removeDisksResp.Date,
+			ResponseStatusCode: removeDisksResp.StatusCode,
+			RequestURL:         removeDisksResp.RequestURL,
+			State:              v1beta1.CruiseControlTaskCompletedWithError,
+			Err:                err,
+		}, err
+	}
+
+	return &Result{
+		TaskID:             removeDisksResp.TaskID,
+		StartedAt:          removeDisksResp.Date,
+		ResponseStatusCode: removeDisksResp.StatusCode,
+		RequestURL:         removeDisksResp.RequestURL,
+		Result:             removeDisksResp.Result,
+		State:              v1beta1.CruiseControlTaskActive,
+	}, nil
+}
+
 func (cc *cruiseControlScaler) KafkaClusterLoad(ctx context.Context) (*api.KafkaClusterLoadResponse, error) {
 	clusterLoadResp, err := cc.client.KafkaClusterLoad(ctx, api.KafkaClusterLoadRequestWithDefaults())
 	if err != nil {
@@ -714,6 +1037,12 @@ func (cc *cruiseControlScaler) PartitionLeadersReplicasByBroker(ctx context.Cont
 	return clusterStateResp.Result.KafkaBrokerState.ReplicaCountByBrokerID, clusterStateResp.Result.KafkaBrokerState.LeaderCountByBrokerID, nil
 }
 
+// PartitionReplicasByBroker returns the number of partition replicas for every broker in the Kafka cluster.
+func (cc *cruiseControlScaler) PartitionReplicasByBroker(ctx context.Context) (map[string]int32, error) {
+	brokerIDReplicaCounts, _, err := cc.PartitionLeadersReplicasByBroker(ctx)
+	return brokerIDReplicaCounts, err
+}
+
 // BrokerWithLeastPartitionReplicas returns the ID of the broker which host the least partition replicas.
 func (cc *cruiseControlScaler) BrokerWithLeastPartitionReplicas(ctx context.Context) (string, error) {
 	var brokerWithLeastPartitionReplicas string
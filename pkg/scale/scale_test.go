@@ -0,0 +1,120 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scale
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banzaicloud/go-cruise-control/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/util"
+)
+
+func TestExecutionParameters(t *testing.T) {
+	testCases := []struct {
+		testName       string
+		taskSpec       v1beta1.CruiseControlTaskSpec
+		expectedParams map[string]string
+	}{
+		{
+			testName:       "no execution parameters",
+			taskSpec:       v1beta1.CruiseControlTaskSpec{RetryDurationMinutes: 5},
+			expectedParams: map[string]string{},
+		},
+		{
+			testName: "all execution parameters",
+			taskSpec: v1beta1.CruiseControlTaskSpec{
+				ReplicationThrottle:                   util.Int64Pointer(10485760),
+				ConcurrentPartitionMovementsPerBroker: util.Int32Pointer(2),
+				ConcurrentLeaderMovements:             util.Int32Pointer(100),
+				ExecutionProgressCheckIntervalMs:      util.Int64Pointer(5000),
+			},
+			expectedParams: map[string]string{
+				paramReplicationThrottle:              "10485760",
+				paramConcurrentPartitionMovements:     "2",
+				paramConcurrentLeaderMovements:        "100",
+				paramExecutionProgressCheckIntervalMs: "5000",
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedParams, ExecutionParameters(testCase.taskSpec), "testName", testCase.testName)
+	}
+}
+
+func TestRemoveDisksWithParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/kafkacruisecontrol/remove_disks", r.URL.Path)
+		assert.Equal(t, "1-/kafka-logs2/kafka", r.URL.Query().Get(paramBrokerIDAndLogDirs))
+		assert.Equal(t, "false", r.URL.Query().Get("dryrun"))
+
+		w.Header().Set(types.UserTaskIDHTTPHeader, "task-1")
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"progress":[]}`))
+	}))
+	defer server.Close()
+
+	scaler, err := NewCruiseControlScaler(context.Background(), server.URL+"/kafkacruisecontrol")
+	require.NoError(t, err)
+
+	result, err := scaler.RemoveDisksWithParams(context.Background(), map[string]string{
+		paramBrokerIDAndLogDirs: "1-/kafka-logs2/kafka",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", result.TaskID)
+	assert.Equal(t, v1beta1.CruiseControlTaskActive, result.State)
+
+	_, err = scaler.RemoveDisksWithParams(context.Background(), map[string]string{
+		paramBrokerIDAndLogDirs: "1-/kafka-logs2/kafka",
+		paramExcludeRemoved:     "true",
+	})
+	assert.ErrorContains(t, err, "unsupported remove_disks parameter")
+
+	_, err = scaler.RemoveDisksWithParams(context.Background(), map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestDemoteBrokersWithParams(t *testing.T) {
+	scaler, err := NewCruiseControlScaler(context.Background(), "http://localhost:8090/kafkacruisecontrol")
+	require.NoError(t, err)
+
+	_, err = scaler.DemoteBrokersWithParams(context.Background(), map[string]string{
+		paramBrokerID:       "1",
+		paramExcludeRemoved: "true",
+	})
+	assert.ErrorContains(t, err, "unsupported demote_broker parameter")
+
+	_, err = scaler.DemoteBrokersWithParams(context.Background(), map[string]string{
+		paramConcurrentLeaderMovements: "10",
+	})
+	assert.ErrorContains(t, err, "missing brokerid parameter")
+}
+
+func TestParseGoals(t *testing.T) {
+	goals, err := parseGoals("LeaderReplicaDistributionGoal,RackAwareGoal")
+	require.NoError(t, err)
+	assert.Equal(t, []types.Goal{types.LeaderReplicaDistributionGoal, types.RackAwareGoal}, goals)
+
+	_, err = parseGoals("NoSuchGoal")
+	assert.ErrorContains(t, err, "unknown Cruise Control goal")
+}
@@ -33,8 +33,11 @@ type CruiseControlScaler interface {
 	AddBrokersWithParams(ctx context.Context, params map[string]string) (*Result, error)
 	RemoveBrokersWithParams(ctx context.Context, params map[string]string) (*Result, error)
 	RebalanceWithParams(ctx context.Context, params map[string]string) (*Result, error)
+	RemoveDisksWithParams(ctx context.Context, params map[string]string) (*Result, error)
+	DemoteBrokersWithParams(ctx context.Context, params map[string]string) (*Result, error)
 	StopExecution(ctx context.Context) (*Result, error)
 	RemoveBrokers(ctx context.Context, brokerIDs ...string) (*Result, error)
+	DemoteBrokers(ctx context.Context, brokerIDs ...string) (*Result, error)
 	RebalanceDisks(ctx context.Context, brokerIDs ...string) (*Result, error)
 	BrokersWithState(ctx context.Context, states ...KafkaBrokerState) ([]string, error)
 	KafkaClusterState(ctx context.Context) (*types.KafkaClusterState, error)
@@ -75,6 +78,9 @@ type CruiseControlStatus struct {
 	ProposalReady bool
 	GoalsReady    bool
 
+	MonitorState  string
+	ExecutorState string
+
 	MonitoredWindows   float32
 	MonitoringCoverage float64
 }
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"math/big"
 	mathrand "math/rand"
+	"net"
 	"strings"
 	"time"
 
@@ -175,6 +176,11 @@ func GenerateJKSFromByte(certByte []byte, privateKey []byte, caCert []byte) (out
 
 // GenerateJKS creates a JKS with a random password from a client cert/key combination
 func GenerateJKS(certs []*x509.Certificate, privateKey []byte) (out, passw []byte, err error) {
+	return GenerateJKSWithPassword(certs, privateKey, GeneratePass(16))
+}
+
+// GenerateJKSWithPassword creates a JKS with the given password from a client cert/key combination
+func GenerateJKSWithPassword(certs []*x509.Certificate, privateKey []byte, password []byte) (out, passw []byte, err error) {
 	pKeyRaw, err := DecodePrivateKeyBytes(privateKey)
 	if err != nil {
 		return nil, nil, err
@@ -219,7 +225,6 @@ func GenerateJKS(certs []*x509.Certificate, privateKey []byte) (out, passw []byt
 		}
 	}
 
-	password := GeneratePass(16)
 	if err = jksKeyStore.SetPrivateKeyEntry("certs", pkeIn, password); err != nil {
 		return nil, nil, err
 	}
@@ -428,3 +433,41 @@ func ParseKeyStoreToTLSCertificate(keystore, password []byte) (tls.Certificate,
 
 	return x509ClientCert, nil
 }
+
+// GetServedCertificate returns the leaf certificate served by the TLS endpoint on the given address.
+// The certificate is captured during the handshake, so it is returned even if the server requires a client certificate.
+func GetServedCertificate(address string, timeout time.Duration) (*x509.Certificate, error) {
+	var served *x509.Certificate
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
+		// the certificate is only inspected, it is not used to authenticate the server
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("no certificate was served")
+			}
+			cert, err := x509.ParseCertificate(rawCerts[0])
+			if err != nil {
+				return err
+			}
+			served = cert
+			return nil
+		},
+	})
+	if conn != nil {
+		conn.Close()
+	}
+	if served != nil {
+		return served, nil
+	}
+	if err == nil {
+		err = errors.New("no certificate was served")
+	}
+	return nil, errors.WrapIfWithDetails(err, "could not get served certificate", "address", address)
+}
+
+// RenewalTime returns the point in time when the given percentage of a certificate's lifetime has elapsed
+func RenewalTime(cert *x509.Certificate, percentage int32) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(lifetime / 100 * time.Duration(percentage))
+}
@@ -21,11 +21,15 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/pavlo-v-chernykh/keystore-go/v4"
 
@@ -229,3 +233,39 @@ func TestParseKeyStoreToTLSCertificate(t *testing.T) {
 		}
 	}
 }
+
+func TestGetServedCertificate(t *testing.T) {
+	for _, clientAuth := range []tls.ClientAuthType{tls.NoClientCert, tls.RequireAndVerifyClientCert} {
+		server := httptest.NewUnstartedServer(http.NotFoundHandler())
+		server.TLS = &tls.Config{ClientAuth: clientAuth, MinVersion: tls.VersionTLS12}
+		server.StartTLS()
+
+		served, err := GetServedCertificate(server.Listener.Addr().String(), time.Second)
+		server.Close()
+		if err != nil {
+			t.Fatalf("client auth: %v, error should be nil, got: %s", clientAuth, err)
+		}
+		if !bytes.Equal(served.Raw, server.Certificate().Raw) {
+			t.Errorf("client auth: %v, served certificate mismatch", clientAuth)
+		}
+	}
+
+	if _, err := GetServedCertificate("127.0.0.1:1", time.Second); err == nil {
+		t.Error("error shouldn't be nil when nothing listens on the address")
+	}
+}
+
+func TestRenewalTime(t *testing.T) {
+	notBefore := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert := &x509.Certificate{
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(100 * time.Hour),
+	}
+
+	if renewal := RenewalTime(cert, 67); !renewal.Equal(notBefore.Add(67 * time.Hour)) {
+		t.Error("Expected renewal after 67 hours, got:", renewal)
+	}
+	if renewal := RenewalTime(cert, 1); !renewal.Equal(notBefore.Add(time.Hour)) {
+		t.Error("Expected renewal after 1 hour, got:", renewal)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+
+	"emperror.dev/errors"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+// EncryptedPrivateKeyType is the PEM block type of an encrypted PKCS#8 private key
+const EncryptedPrivateKeyType = "ENCRYPTED PRIVATE KEY"
+
+// pkcs8EncryptionOpts encrypts the PKCS#8 private keys with PBES2 using AES-256-CBC and a PBKDF2-HMAC-SHA256 derived key
+var pkcs8EncryptionOpts = &pkcs8.Opts{
+	Cipher: pkcs8.AES256CBC,
+	KDFOpts: pkcs8.PBKDF2Opts{
+		SaltSize:       16,
+		IterationCount: 10000,
+		HMACHash:       crypto.SHA256,
+	},
+}
+
+// KeystoreFields returns the secret fields the given keystore formats are stored in including the password
+func KeystoreFields(formats []v1beta1.KeystoreFormat) []string {
+	if len(formats) == 0 {
+		return nil
+	}
+	fields := []string{v1alpha1.PasswordKey}
+	for _, format := range formats {
+		switch format {
+		case v1beta1.KeystoreFormatJKS:
+			fields = append(fields, v1alpha1.TLSJKSKeyStore, v1alpha1.TLSJKSTrustStore)
+		case v1beta1.KeystoreFormatPKCS12:
+			fields = append(fields, v1alpha1.TLSPKCS12KeyStore, v1alpha1.TLSPKCS12TrustStore)
+		case v1beta1.KeystoreFormatPEMBundle:
+			fields = append(fields, v1alpha1.TLSPEMKeyStore, v1alpha1.TLSPEMTrustStore)
+		}
+	}
+	return fields
+}
+
+// GenerateKeystores returns the secret data of the given keystore formats for a certificate chain and its private key.
+// The keystores are protected by the given password, a random one is generated when it is empty.
+func GenerateKeystores(formats []v1beta1.KeystoreFormat, certs []*x509.Certificate, privateKey []byte, password []byte) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	if len(formats) == 0 {
+		return data, nil
+	}
+	if len(password) == 0 {
+		password = GeneratePass(16)
+	}
+	data[v1alpha1.PasswordKey] = password
+	for _, format := range formats {
+		var keystore, truststore []byte
+		var keystoreField, truststoreField string
+		var err error
+		switch format {
+		case v1beta1.KeystoreFormatJKS:
+			keystore, _, err = GenerateJKSWithPassword(certs, privateKey, password)
+			// the JKS keystore holds the CA certificates as trusted entries as well
+			truststore = keystore
+			keystoreField, truststoreField = v1alpha1.TLSJKSKeyStore, v1alpha1.TLSJKSTrustStore
+		case v1beta1.KeystoreFormatPKCS12:
+			keystore, truststore, err = GeneratePKCS12(certs, privateKey, password)
+			keystoreField, truststoreField = v1alpha1.TLSPKCS12KeyStore, v1alpha1.TLSPKCS12TrustStore
+		case v1beta1.KeystoreFormatPEMBundle:
+			keystore, truststore, err = GeneratePEMBundle(certs, privateKey, password)
+			keystoreField, truststoreField = v1alpha1.TLSPEMKeyStore, v1alpha1.TLSPEMTrustStore
+		default:
+			err = errors.NewWithDetails("unknown keystore format", "format", format)
+		}
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "could not generate keystore", "format", format)
+		}
+		data[keystoreField] = keystore
+		data[truststoreField] = truststore
+	}
+	return data, nil
+}
+
+// GeneratePKCS12 creates a PKCS#12 keystore from a client cert/key combination and a PKCS#12 truststore
+// from the CA certificates of the chain, both protected by the given password
+func GeneratePKCS12(certs []*x509.Certificate, privateKey []byte, password []byte) (keystore, truststore []byte, err error) {
+	if len(certs) == 0 {
+		return nil, nil, errors.New("no certificate to store")
+	}
+	pKeyRaw, err := DecodePrivateKeyBytes(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keystore, err = pkcs12.Modern.Encode(pKeyRaw, certs[0], certs[1:], string(password))
+	if err != nil {
+		return nil, nil, err
+	}
+	truststore, err = pkcs12.Modern.EncodeTrustStore(caCertificates(certs), string(password))
+	if err != nil {
+		return nil, nil, err
+	}
+	return keystore, truststore, nil
+}
+
+// GeneratePEMBundle creates a PEM keystore of the private key encrypted with the given password followed by
+// the certificate chain, as expected by ssl.keystore.type=PEM, and a PEM truststore of the CA certificates of the chain
+func GeneratePEMBundle(certs []*x509.Certificate, privateKey []byte, password []byte) (keystore, truststore []byte, err error) {
+	if len(certs) == 0 {
+		return nil, nil, errors.New("no certificate to store")
+	}
+	pKeyRaw, err := DecodePrivateKeyBytes(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBlock, err := EncryptPKCS8PrivateKey(pKeyRaw, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	var keystoreBuf bytes.Buffer
+	if err = pem.Encode(&keystoreBuf, keyBlock); err != nil {
+		return nil, nil, err
+	}
+	for _, cert := range certs {
+		if err = pem.Encode(&keystoreBuf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return nil, nil, err
+		}
+	}
+	var truststoreBuf bytes.Buffer
+	for _, cert := range caCertificates(certs) {
+		if err = pem.Encode(&truststoreBuf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return nil, nil, err
+		}
+	}
+	return keystoreBuf.Bytes(), truststoreBuf.Bytes(), nil
+}
+
+// EncryptPKCS8PrivateKey encrypts a private key with the given password into an encrypted PKCS#8 PEM block
+func EncryptPKCS8PrivateKey(privateKey interface{}, password []byte) (*pem.Block, error) {
+	der, err := pkcs8.MarshalPrivateKey(privateKey, password, pkcs8EncryptionOpts)
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not encrypt private key")
+	}
+	return &pem.Block{Type: EncryptedPrivateKeyType, Bytes: der}, nil
+}
+
+func caCertificates(certs []*x509.Certificate) []*x509.Certificate {
+	var caCerts []*x509.Certificate
+	for _, cert := range certs {
+		if cert.IsCA {
+			caCerts = append(caCerts, cert)
+		}
+	}
+	return caCerts
+}
@@ -0,0 +1,198 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+
+	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func generateTestChain(t *testing.T) ([]*x509.Certificate, []byte) {
+	t.Helper()
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caRaw, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyPEM, err := GeneratePrivateKeyInPemFormat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := DecodePrivateKeyBytes(keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-user"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leafRaw, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, key.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(leafRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*x509.Certificate{leaf, ca}, keyPEM
+}
+
+func TestGeneratePKCS12(t *testing.T) {
+	certs, keyPEM := generateTestChain(t)
+	keystore, truststore, err := GeneratePKCS12(certs, keyPEM, []byte("changeit"))
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	key, leaf, caCerts, err := pkcs12.DecodeChain(keystore, "changeit")
+	if err != nil {
+		t.Fatal("Expected to decode keystore, got:", err)
+	}
+	expectedKey, _ := DecodePrivateKeyBytes(keyPEM)
+	if !reflect.DeepEqual(key, expectedKey) {
+		t.Error("Expected the private key in the keystore")
+	}
+	if !leaf.Equal(certs[0]) || len(caCerts) != 1 || !caCerts[0].Equal(certs[1]) {
+		t.Error("Expected the certificate chain in the keystore")
+	}
+
+	trusted, err := pkcs12.DecodeTrustStore(truststore, "changeit")
+	if err != nil {
+		t.Fatal("Expected to decode truststore, got:", err)
+	}
+	if len(trusted) != 1 || !trusted[0].Equal(certs[1]) {
+		t.Error("Expected only the CA certificate in the truststore, got:", trusted)
+	}
+}
+
+func TestGeneratePEMBundle(t *testing.T) {
+	certs, keyPEM := generateTestChain(t)
+	keystore, truststore, err := GeneratePEMBundle(certs, keyPEM, []byte("changeit"))
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	keyBlock, rest := pem.Decode(keystore)
+	if keyBlock == nil || keyBlock.Type != EncryptedPrivateKeyType {
+		t.Fatal("Expected an encrypted private key first in the keystore, got:", keyBlock)
+	}
+	expectedKey, _ := DecodePrivateKeyBytes(keyPEM)
+	key, err := pkcs8.ParsePKCS8PrivateKey(keyBlock.Bytes, []byte("changeit"))
+	if err != nil {
+		t.Fatal("Expected the private key to be decrypted, got:", err)
+	}
+	if !reflect.DeepEqual(key, expectedKey) {
+		t.Error("Expected the private key to be decrypted with the password")
+	}
+	chain, err := ParseCertificates(rest)
+	if err != nil {
+		t.Fatal("Expected to parse the certificate chain, got:", err)
+	}
+	if len(chain) != 2 || !chain[0].Certificate.Equal(certs[0]) || !chain[1].Certificate.Equal(certs[1]) {
+		t.Error("Expected the certificate chain after the private key")
+	}
+
+	trusted, err := ParseCertificates(truststore)
+	if err != nil {
+		t.Fatal("Expected to parse the truststore, got:", err)
+	}
+	if len(trusted) != 1 || !trusted[0].Certificate.Equal(certs[1]) {
+		t.Error("Expected only the CA certificate in the truststore")
+	}
+}
+
+func TestGenerateKeystores(t *testing.T) {
+	certs, keyPEM := generateTestChain(t)
+
+	data, err := GenerateKeystores(nil, certs, keyPEM, nil)
+	if err != nil || len(data) != 0 {
+		t.Error("Expected no keystores without formats, got:", data, err)
+	}
+
+	formats := []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatJKS, v1beta1.KeystoreFormatPKCS12, v1beta1.KeystoreFormatPEMBundle}
+	data, err = GenerateKeystores(formats, certs, keyPEM, nil)
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	fields := KeystoreFields(formats)
+	if len(data) != len(fields) {
+		t.Errorf("Expected fields %v, got %d entries", fields, len(data))
+	}
+	for _, field := range fields {
+		if len(data[field]) == 0 {
+			t.Error("Expected field to be generated:", field)
+		}
+	}
+	if _, err = ParseKeyStoreToTLSCertificate(data[v1alpha1.TLSJKSKeyStore], data[v1alpha1.PasswordKey]); err != nil {
+		t.Error("Expected the JKS keystore to be protected by the generated password, got:", err)
+	}
+
+	data, err = GenerateKeystores([]v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPKCS12}, certs, keyPEM, []byte("changeit"))
+	if err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+	if string(data[v1alpha1.PasswordKey]) != "changeit" {
+		t.Error("Expected the given password to be kept, got:", string(data[v1alpha1.PasswordKey]))
+	}
+	if _, _, _, err = pkcs12.DecodeChain(data[v1alpha1.TLSPKCS12KeyStore], "changeit"); err != nil {
+		t.Error("Expected the PKCS#12 keystore to be protected by the given password, got:", err)
+	}
+
+	if _, err = GenerateKeystores([]v1beta1.KeystoreFormat{"unknown"}, certs, keyPEM, nil); err == nil {
+		t.Error("Expected error for unknown keystore format")
+	}
+}
+
+func TestKeystoreFields(t *testing.T) {
+	if fields := KeystoreFields(nil); len(fields) != 0 {
+		t.Error("Expected no fields without formats, got:", fields)
+	}
+	expected := []string{v1alpha1.PasswordKey, v1alpha1.TLSPEMKeyStore, v1alpha1.TLSPEMTrustStore}
+	if fields := KeystoreFields([]v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPEMBundle}); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected %v, got %v", expected, fields)
+	}
+}
@@ -17,21 +17,47 @@ package client
 import (
 	"fmt"
 
+	"emperror.dev/errors"
+
 	"github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/util/kafka"
 )
 
-func UseSSL(cluster *v1beta1.KafkaCluster) bool {
-	return cluster.Spec.ListenersConfig.InternalListeners[determineInternalListenerForInnerCom(cluster.Spec.ListenersConfig.InternalListeners)].Type.IsSSL()
+// AdminClientListener returns the internal listener koperator connects to the brokers through. The listener used for
+// inner broker communication is preferred, otherwise the first listener koperator has credentials for is chosen in
+// the order of the spec. Listeners used only for controller communication are skipped.
+func AdminClientListener(cluster *v1beta1.KafkaCluster) (v1beta1.InternalListenerConfig, error) {
+	listeners := cluster.Spec.ListenersConfig.InternalListeners
+	for _, l := range listeners {
+		if l.UsedForInnerBrokerCommunication && isUsableByAdminClient(cluster, l) {
+			return l, nil
+		}
+	}
+	for _, l := range listeners {
+		if !l.UsedForControllerCommunication && isUsableByAdminClient(cluster, l) {
+			return l, nil
+		}
+	}
+	return v1beta1.InternalListenerConfig{}, errors.New("none of the internal listeners can be used by koperator: " +
+		"ssl listeners require clientSSLCertSecret or sslSecrets, sasl listeners require clientSASL with a mechanism enabled on the listener")
 }
 
-func determineInternalListenerForInnerCom(internalListeners []v1beta1.InternalListenerConfig) int {
-	for id, val := range internalListeners {
-		if val.UsedForInnerBrokerCommunication {
-			return id
+// isUsableByAdminClient returns true if koperator has the credentials the listener requires
+func isUsableByAdminClient(cluster *v1beta1.KafkaCluster, l v1beta1.InternalListenerConfig) bool {
+	if l.Type.IsSSL() && !cluster.Spec.IsClientSSLSecretPresent() && cluster.Spec.ListenersConfig.SSLSecrets == nil {
+		return false
+	}
+	if l.Type.IsSasl() {
+		clientSASL := cluster.Spec.ClientSASL
+		if clientSASL == nil {
+			return false
+		}
+		// Without a typed SASL block the mechanisms are configured through the readOnlyConfig, so any is accepted
+		if l.SASL != nil && !l.SASL.HasMechanism(clientSASL.Mechanism) {
+			return false
 		}
 	}
-	return 0
+	return true
 }
 
 func GenerateKafkaAddressWithoutPort(cluster *v1beta1.KafkaCluster) string {
@@ -48,8 +74,3 @@ func GenerateKafkaAddressWithoutPort(cluster *v1beta1.KafkaCluster) string {
 		cluster.Spec.GetKubernetesClusterDomain(),
 	)
 }
-
-func GenerateKafkaAddress(cluster *v1beta1.KafkaCluster) string {
-	return fmt.Sprintf("%s:%d", GenerateKafkaAddressWithoutPort(cluster),
-		cluster.Spec.ListenersConfig.InternalListeners[determineInternalListenerForInnerCom(cluster.Spec.ListenersConfig.InternalListeners)].ContainerPort)
-}
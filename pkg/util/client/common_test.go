@@ -22,7 +22,7 @@ import (
 	"github.com/banzaicloud/koperator/api/v1beta1"
 )
 
-func TestGenerateKafkaAddress(t *testing.T) {
+func TestGenerateKafkaAddressWithoutPort(t *testing.T) {
 	cluster := &v1beta1.KafkaCluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "test",
@@ -30,13 +30,6 @@ func TestGenerateKafkaAddress(t *testing.T) {
 		},
 		Spec: v1beta1.KafkaClusterSpec{
 			HeadlessServiceEnabled: true,
-			ListenersConfig: v1beta1.ListenersConfig{
-				InternalListeners: []v1beta1.InternalListenerConfig{
-					{
-						CommonListenerSpec: v1beta1.CommonListenerSpec{ContainerPort: 80},
-					},
-				},
-			},
 		},
 	}
 
@@ -46,22 +39,97 @@ func TestGenerateKafkaAddress(t *testing.T) {
 		t.Error("Expected kafka address:", expected, "Got:", generatedHeadlessWithoutPort)
 	}
 
-	generatedHeadless := GenerateKafkaAddress(cluster)
-	expected = "test-headless.test.svc.cluster.local:80"
-	if generatedHeadless != expected {
-		t.Error("Expected kafka address:", expected, "Got:", generatedHeadless)
-	}
-
 	cluster.Spec.HeadlessServiceEnabled = false
 	generatedAllBrokerWithoutPort := GenerateKafkaAddressWithoutPort(cluster)
 	expected = "test-all-broker.test.svc.cluster.local"
 	if generatedAllBrokerWithoutPort != expected {
 		t.Error("Expected kafka address:", expected, "Got:", generatedAllBrokerWithoutPort)
 	}
+}
+
+func TestAdminClientListener(t *testing.T) {
+	plaintext := v1beta1.InternalListenerConfig{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "plaintext", Type: v1beta1.SecurityProtocolPlaintext}}
+	ssl := v1beta1.InternalListenerConfig{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "ssl", Type: v1beta1.SecurityProtocolSSL}}
+	scram := v1beta1.InternalListenerConfig{CommonListenerSpec: v1beta1.CommonListenerSpec{
+		Name: "scram",
+		Type: v1beta1.SecurityProtocolSaslPlaintext,
+		SASL: &v1beta1.SASLConfig{Mechanisms: []v1beta1.SASLMechanism{v1beta1.SASLMechanismScramSHA512}},
+	}}
+	untypedSASL := v1beta1.InternalListenerConfig{CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "sasl", Type: v1beta1.SecurityProtocolSaslPlaintext}}
+	innerBroker := func(l v1beta1.InternalListenerConfig) v1beta1.InternalListenerConfig {
+		l.UsedForInnerBrokerCommunication = true
+		return l
+	}
+	controller := func(l v1beta1.InternalListenerConfig) v1beta1.InternalListenerConfig {
+		l.UsedForControllerCommunication = true
+		return l
+	}
+	scramClient := &v1beta1.ClientSASLConfig{Mechanism: v1beta1.SASLMechanismScramSHA512, CredentialsSecret: "koperator"}
+	oauthClient := &v1beta1.ClientSASLConfig{Mechanism: v1beta1.SASLMechanismOAuthBearer, CredentialsSecret: "koperator"}
+
+	testCases := []struct {
+		testName   string
+		listeners  []v1beta1.InternalListenerConfig
+		sslSecrets *v1beta1.SSLSecrets
+		clientSASL *v1beta1.ClientSASLConfig
+		expected   string
+	}{
+		{
+			testName:   "inner broker listener preferred",
+			listeners:  []v1beta1.InternalListenerConfig{plaintext, innerBroker(untypedSASL)},
+			clientSASL: scramClient,
+			expected:   "sasl",
+		},
+		{
+			testName:  "first usable listener when inner broker listener lacks credentials",
+			listeners: []v1beta1.InternalListenerConfig{innerBroker(scram), ssl, plaintext},
+			expected:  "plaintext",
+		},
+		{
+			testName:   "ssl listener with ssl secrets",
+			listeners:  []v1beta1.InternalListenerConfig{innerBroker(scram), ssl, plaintext},
+			sslSecrets: &v1beta1.SSLSecrets{},
+			expected:   "ssl",
+		},
+		{
+			testName:   "mechanism not enabled on the listener",
+			listeners:  []v1beta1.InternalListenerConfig{innerBroker(scram), untypedSASL},
+			clientSASL: oauthClient,
+			expected:   "sasl",
+		},
+		{
+			testName:   "controller listener skipped",
+			listeners:  []v1beta1.InternalListenerConfig{controller(plaintext), innerBroker(scram)},
+			clientSASL: scramClient,
+			expected:   "scram",
+		},
+		{
+			testName:  "no usable listener",
+			listeners: []v1beta1.InternalListenerConfig{controller(plaintext), innerBroker(scram)},
+		},
+	}
 
-	generatedAllBroker := GenerateKafkaAddress(cluster)
-	expected = "test-all-broker.test.svc.cluster.local:80"
-	if generatedAllBroker != expected {
-		t.Error("Expected kafka address:", expected, "Got:", generatedAllBroker)
+	for _, testCase := range testCases {
+		cluster := &v1beta1.KafkaCluster{
+			Spec: v1beta1.KafkaClusterSpec{
+				ListenersConfig: v1beta1.ListenersConfig{
+					InternalListeners: testCase.listeners,
+					SSLSecrets:        testCase.sslSecrets,
+				},
+				ClientSASL: testCase.clientSASL,
+			},
+		}
+		listener, err := AdminClientListener(cluster)
+		if testCase.expected == "" {
+			if err == nil {
+				t.Errorf("testName: %s, expected error, got listener: %s", testCase.testName, listener.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("testName: %s, expected no error, got: %s", testCase.testName, err)
+		} else if listener.Name != testCase.expected {
+			t.Errorf("testName: %s, expected listener: %s, got: %s", testCase.testName, testCase.expected, listener.Name)
+		}
 	}
 }
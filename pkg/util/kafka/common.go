@@ -25,6 +25,7 @@ import (
 	properties "github.com/banzaicloud/koperator/properties/pkg"
 
 	"github.com/banzaicloud/koperator/api/v1alpha1"
+	"github.com/banzaicloud/koperator/pkg/kafkaconfig"
 	"github.com/banzaicloud/koperator/pkg/util"
 )
 
@@ -39,6 +40,17 @@ var PerBrokerConfigs = []string{
 	KafkaConfigListenerSecurityProtocolMap,
 }
 
+// HiddenConfigValue replaces the value of sensitive configurations in the status of the KafkaCluster
+const HiddenConfigValue = "[hidden]"
+
+// sensitiveConfigs are parts of the names of configurations holding credentials
+var sensitiveConfigs = []string{
+	"password",
+	"jaas.config",
+	"ssl.keystore.key",
+	"secret",
+}
+
 // commonACLString is the raw representation of an ACL allowing Describe on a Topic
 var commonACLString = "User:%s,Topic,%s,%s,Describe,Allow,*"
 
@@ -90,27 +102,130 @@ func GrantsToACLStrings(dn string, grants []v1alpha1.UserTopicGrant) []string {
 }
 
 func ShouldRefreshOnlyPerBrokerConfigs(currentConfigs, desiredConfigs *properties.Properties, log logr.Logger) bool {
-	// Get the diff of the configuration
-	configDiff := currentConfigs.Diff(desiredConfigs)
+	configChanges := GetBrokerConfigChanges(currentConfigs, desiredConfigs)
 
 	// Return if there is no drift in the configuration
-	if len(configDiff) == 0 {
+	if len(configChanges) == 0 {
 		return true
 	}
 
-	log.V(1).Info("configs have been changed", "configs", configDiff)
+	log.V(1).Info("configs have been changed", "configs", configChanges)
 
-	if diff, ok := configDiff[KafkaConfigListenerSecurityProtocolMap]; ok {
-		if listenersSecurityProtocolChanged(diff[0].Value(), diff[1].Value()) {
+	for _, configChange := range configChanges {
+		if configChange.Type == v1beta1.ConfigChangeRestartRequired {
 			return false
 		}
 	}
+	return true
+}
+
+// GetBrokerConfigChanges returns the changes between the current and the desired broker configuration.
+// Changes of PerBrokerConfigs are applied dynamically, unless the security protocol of an existing listener is changed,
+// any other change requires the restart of the broker.
+func GetBrokerConfigChanges(currentConfigs, desiredConfigs *properties.Properties) []v1beta1.PendingConfigChange {
+	configDiff := currentConfigs.Diff(desiredConfigs)
+
+	securityProtocolChanged := false
+	if diff, ok := configDiff[KafkaConfigListenerSecurityProtocolMap]; ok {
+		securityProtocolChanged = listenersSecurityProtocolChanged(diff[0].Value(), diff[1].Value())
+	}
+
+	return newPendingConfigChanges(configDiff, func(key string) v1beta1.ConfigChangeType {
+		if securityProtocolChanged {
+			return v1beta1.ConfigChangeRestartRequired
+		}
+		diff := configDiff[key]
+		if util.StringSliceContains(PerBrokerConfigs, key) ||
+			IsDynamicConfigSecretRef(key, diff[0].Value()) || IsDynamicConfigSecretRef(key, diff[1].Value()) {
+			return v1beta1.ConfigChangeDynamicPerBroker
+		}
+		return v1beta1.ConfigChangeRestartRequired
+	})
+}
+
+// ConfigSecretReference returns the reference of a configuration set from a secret, which is resolved by the
+// FileConfigProvider of the broker from the properties file mounted to the broker pod
+func ConfigSecretReference(fileName, config string) string {
+	return fmt.Sprintf("${%s:%s/%s:%s}", FileConfigProviderName, ConfigSecretsPath, fileName, config)
+}
+
+// IsConfigSecretReference returns true if the value references a configuration set from a secret
+func IsConfigSecretReference(value string) bool {
+	return strings.HasPrefix(value, fmt.Sprintf("${%s:%s/", FileConfigProviderName, ConfigSecretsPath))
+}
+
+// IsDynamicConfigSecretRef returns true if the value references a configuration set from a secret which can be
+// updated per broker dynamically. When the secret changes, these configurations are updated without restarting the broker.
+func IsDynamicConfigSecretRef(key, value string) bool {
+	return IsConfigSecretReference(value) && kafkaconfig.ForVersion("").BrokerConfigScope(key) != kafkaconfig.ScopeReadOnly
+}
+
+// GetConfigChanges returns the changes between the current and the desired configuration with the given change type
+func GetConfigChanges(currentConfigs, desiredConfigs *properties.Properties, changeType v1beta1.ConfigChangeType) []v1beta1.PendingConfigChange {
+	return newPendingConfigChanges(currentConfigs.Diff(desiredConfigs), func(string) v1beta1.ConfigChangeType {
+		return changeType
+	})
+}
+
+func newPendingConfigChanges(configDiff properties.DiffResult, changeType func(key string) v1beta1.ConfigChangeType) []v1beta1.PendingConfigChange {
+	if len(configDiff) == 0 {
+		return nil
+	}
+
+	configChanges := make([]v1beta1.PendingConfigChange, 0, len(configDiff))
+	for _, key := range configDiff.Keys() {
+		diff := configDiff[key]
+		configChanges = append(configChanges, v1beta1.PendingConfigChange{
+			Key:      key,
+			OldValue: configValueForStatus(key, diff[0].Value()),
+			NewValue: configValueForStatus(key, diff[1].Value()),
+			Type:     changeType(key),
+		})
+	}
+	return configChanges
+}
+
+// configValueForStatus hides the value of sensitive configurations, so they don't get exposed in the status
+func configValueForStatus(key, value string) string {
+	if value == "" {
+		return value
+	}
+	for _, sensitiveConfig := range sensitiveConfigs {
+		if strings.Contains(key, sensitiveConfig) {
+			return HiddenConfigValue
+		}
+	}
+	return value
+}
+
+// GetBrokerReadOnlyConfig returns the read-only configuration of the broker merged with the cluster-wide read-only configuration
+func GetBrokerReadOnlyConfig(id int32, kafkaCluster *v1beta1.KafkaCluster, log logr.Logger) *properties.Properties {
+	// Parse cluster-wide readonly configuration
+	finalBrokerConfig, err := properties.NewFromString(kafkaCluster.Spec.ReadOnlyConfig)
+	if err != nil {
+		log.Error(err, "failed to parse readonly cluster configuration")
+		finalBrokerConfig = properties.NewProperties()
+	}
+
+	// Parse readonly broker configuration
+	var parsedReadOnlyBrokerConfig *properties.Properties
+	// Find configuration for broker with id
+	for _, broker := range kafkaCluster.Spec.Brokers {
+		if broker.Id == id {
+			parsedReadOnlyBrokerConfig, err = properties.NewFromString(broker.ReadOnlyConfig)
+			if err != nil {
+				log.Error(err, fmt.Sprintf("failed to parse readonly broker configuration for broker with id: %d", id))
+			}
+			break
+		}
+	}
 
-	for _, perBrokerConfig := range PerBrokerConfigs {
-		delete(configDiff, perBrokerConfig)
+	// Merge cluster-wide configuration into broker-level configuration
+	if parsedReadOnlyBrokerConfig != nil {
+		finalBrokerConfig.Merge(parsedReadOnlyBrokerConfig)
 	}
 
-	return len(configDiff) == 0
+	return finalBrokerConfig
 }
 
 // Security protocol cannot be updated for existing listener
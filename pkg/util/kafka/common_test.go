@@ -15,6 +15,7 @@
 package kafka
 
 import (
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -112,6 +113,86 @@ ssl.client.auth=modified_value_4
 	}
 }
 
+func TestGetBrokerConfigChanges(t *testing.T) {
+	testCases := []struct {
+		Description    string
+		CurrentConfigs string
+		DesiredConfigs string
+		Result         []v1beta1.PendingConfigChange
+	}{
+		{
+			Description:    "configs did not change",
+			CurrentConfigs: "auto.create.topics.enable=false",
+			DesiredConfigs: "auto.create.topics.enable=false",
+			Result:         nil,
+		},
+		{
+			Description: "per-broker and non per-broker configs have changed",
+			CurrentConfigs: `auto.create.topics.enable=false
+ssl.client.auth=none
+num.partitions=1
+`,
+			DesiredConfigs: `auto.create.topics.enable=true
+ssl.client.auth=required
+log.retention.hours=168
+`,
+			Result: []v1beta1.PendingConfigChange{
+				{Key: "auto.create.topics.enable", OldValue: "false", NewValue: "true", Type: v1beta1.ConfigChangeRestartRequired},
+				{Key: "log.retention.hours", NewValue: "168", Type: v1beta1.ConfigChangeRestartRequired},
+				{Key: "num.partitions", OldValue: "1", Type: v1beta1.ConfigChangeRestartRequired},
+				{Key: "ssl.client.auth", OldValue: "none", NewValue: "required", Type: v1beta1.ConfigChangeDynamicPerBroker},
+			},
+		},
+		{
+			Description:    "security protocol of an existing listener changed",
+			CurrentConfigs: "listener.security.protocol.map=listener1:protocol1",
+			DesiredConfigs: "listener.security.protocol.map=listener1:protocol2",
+			Result: []v1beta1.PendingConfigChange{
+				{Key: "listener.security.protocol.map", OldValue: "listener1:protocol1", NewValue: "listener1:protocol2", Type: v1beta1.ConfigChangeRestartRequired},
+			},
+		},
+		{
+			Description:    "values of sensitive configs are hidden",
+			CurrentConfigs: "listener.name.ssl.ssl.keystore.password=secret1",
+			DesiredConfigs: "listener.name.ssl.ssl.keystore.password=secret2",
+			Result: []v1beta1.PendingConfigChange{
+				{Key: "listener.name.ssl.ssl.keystore.password", OldValue: HiddenConfigValue, NewValue: HiddenConfigValue, Type: v1beta1.ConfigChangeRestartRequired},
+			},
+		},
+		{
+			Description: "configs set from secrets changed",
+			CurrentConfigs: `config.providers=file
+listener.name.external.ssl.truststore.password=${file:/var/run/secrets/kafka/config/a.properties:listener.name.external.ssl.truststore.password}
+rsm.config.access.key=${file:/var/run/secrets/kafka/config/b.properties:rsm.config.access.key}
+`,
+			DesiredConfigs: `config.providers=file
+listener.name.external.ssl.truststore.password=${file:/var/run/secrets/kafka/config/c.properties:listener.name.external.ssl.truststore.password}
+rsm.config.access.key=${file:/var/run/secrets/kafka/config/d.properties:rsm.config.access.key}
+sasl.jaas.config=${file:/var/run/secrets/kafka/config/e.properties:sasl.jaas.config}
+`,
+			Result: []v1beta1.PendingConfigChange{
+				{Key: "listener.name.external.ssl.truststore.password", OldValue: HiddenConfigValue, NewValue: HiddenConfigValue, Type: v1beta1.ConfigChangeDynamicPerBroker},
+				{Key: "rsm.config.access.key", OldValue: "${file:/var/run/secrets/kafka/config/b.properties:rsm.config.access.key}",
+					NewValue: "${file:/var/run/secrets/kafka/config/d.properties:rsm.config.access.key}", Type: v1beta1.ConfigChangeRestartRequired},
+				{Key: "sasl.jaas.config", NewValue: HiddenConfigValue, Type: v1beta1.ConfigChangeDynamicPerBroker},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		current, err := properties.NewFromString(testCase.CurrentConfigs)
+		if err != nil {
+			t.Fatalf("failed to parse Properties from string: %s", testCase.CurrentConfigs)
+		}
+		desired, err := properties.NewFromString(testCase.DesiredConfigs)
+		if err != nil {
+			t.Fatalf("failed to parse Properties from string: %s", testCase.DesiredConfigs)
+		}
+		if result := GetBrokerConfigChanges(current, desired); !reflect.DeepEqual(result, testCase.Result) {
+			t.Errorf("test case failed: %s, expected: %+v, got: %+v", testCase.Description, testCase.Result, result)
+		}
+	}
+}
+
 const defaultBrokerConfigGroup = "default"
 
 var MinimalKafkaCluster = &v1beta1.KafkaCluster{
@@ -41,6 +41,37 @@ const (
 	KafkaConfigSSLKeystoreType       = "ssl.keystore.type"
 	KafkaConfigSSLKeyStoreLocation   = "ssl.keystore.location"
 	KafkaConfigSSLKeyStorePassword   = "ssl.keystore.password"
+	KafkaConfigSSLKeyPassword        = "ssl.key.password"
+
+	KafkaConfigSASLEnabledMechanisms            = "sasl.enabled.mechanisms"
+	KafkaConfigSASLMechanismInterBrokerProtocol = "sasl.mechanism.inter.broker.protocol"
+	KafkaConfigSASLJAASConfig                   = "sasl.jaas.config"
+	KafkaConfigSASLServerCallbackHandlerClass   = "sasl.server.callback.handler.class"
+	KafkaConfigSASLOAuthBearerJWKSEndpointURL   = "sasl.oauthbearer.jwks.endpoint.url"
+	KafkaConfigSASLOAuthBearerExpectedIssuer    = "sasl.oauthbearer.expected.issuer"
+	KafkaConfigSASLOAuthBearerExpectedAudience  = "sasl.oauthbearer.expected.audience"
+)
+
+// used for resolving the configurations set from secrets
+const (
+	KafkaConfigConfigProviders = "config.providers"
+
+	// FileConfigProviderName is the name the FileConfigProvider of Kafka is registered with in config.providers
+	FileConfigProviderName = "file"
+	// FileConfigProviderClass is the class of the FileConfigProvider of Kafka
+	FileConfigProviderClass = "org.apache.kafka.common.config.provider.FileConfigProvider"
+	// ConfigSecretsPath is the directory of the broker pods the configurations set from secrets are mounted to
+	ConfigSecretsPath = "/var/run/secrets/kafka/config"
+)
+
+// used for tiered storage configurations
+const (
+	KafkaConfigRemoteLogStorageSystemEnable         = "remote.log.storage.system.enable"
+	KafkaConfigRemoteLogStorageManagerClassName     = "remote.log.storage.manager.class.name"
+	KafkaConfigRemoteLogStorageManagerClassPath     = "remote.log.storage.manager.class.path"
+	KafkaConfigRemoteLogMetadataManagerClassName    = "remote.log.metadata.manager.class.name"
+	KafkaConfigRemoteLogMetadataManagerListenerName = "remote.log.metadata.manager.listener.name"
+	KafkaConfigRemoteLogStorageManagerConfigPrefix  = "rsm.config."
 )
 
 // used for Cruise Control configurations
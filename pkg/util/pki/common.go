@@ -197,6 +197,11 @@ func BrokerUserForCluster(cluster *v1beta1.KafkaCluster, extListenerStatuses map
 		}
 	}
 	additionalHosts = sortAndDedupe(additionalHosts)
+	// the JKS keystores are always kept since the operator reads the broker certificate from them
+	var keystoreFormats []v1beta1.KeystoreFormat
+	if cluster.Spec.ListenersConfig.SSLSecrets != nil {
+		keystoreFormats = []v1beta1.KeystoreFormat{cluster.Spec.ListenersConfig.SSLSecrets.GetListenerKeystoreFormat()}
+	}
 	return &v1alpha1.KafkaUser{
 		ObjectMeta: templates.ObjectMeta(EnsureValidCommonNameLen(GetCommonName(cluster)), LabelsForKafkaPKI(cluster.Name, cluster.Namespace), cluster),
 		Spec: v1alpha1.KafkaUserSpec{
@@ -207,6 +212,7 @@ func BrokerUserForCluster(cluster *v1beta1.KafkaCluster, extListenerStatuses map
 				Name:      cluster.Name,
 				Namespace: cluster.Namespace,
 			},
+			KeystoreFormats: keystoreFormats,
 		},
 	}
 }
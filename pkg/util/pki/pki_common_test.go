@@ -152,6 +152,13 @@ func TestBrokerUserForCluster(t *testing.T) {
 	if !reflect.DeepEqual(user, expected) {
 		t.Errorf("Expected %+v\nGot %+v", expected, user)
 	}
+
+	cluster.Spec.ListenersConfig.SSLSecrets = &v1beta1.SSLSecrets{ListenerKeystoreFormat: v1beta1.KeystoreFormatPEMBundle}
+	user = BrokerUserForCluster(cluster, make(map[string]v1beta1.ListenerStatusList))
+	expected.Spec.KeystoreFormats = []v1beta1.KeystoreFormat{v1beta1.KeystoreFormatPEMBundle}
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("Expected %+v\nGot %+v", expected, user)
+	}
 }
 
 func TestControllerUserForCluster(t *testing.T) {
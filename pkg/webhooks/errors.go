@@ -27,8 +27,10 @@ const (
 	outOfRangeReplicationFactorErrMsg              = "replication factor must be larger than 0 (or set it to be -1 to use the broker's default)"
 	outOfRangePartitionsErrMsg                     = "number of partitions must be larger than 0 (or set it to be -1 to use the broker's default)"
 	unsupportedRemovingStorageMsg                  = "removing storage from a broker is not supported"
+	storageRemovalInProgressErrMsg                 = "adding back a storage to a broker is not supported while its removal is in progress"
 	invalidExternalListenerStartingPortErrMsg      = "invalid external listener starting port number"
 	invalidContainerPortForIngressControllerErrMsg = "invalid trarget port number for ingress controller deployment"
+	tieredStorageNotEnabledErrMsg                  = "remote storage can only be enabled for a topic when tiered storage is enabled on all brokers of the kafka cluster"
 
 	// errorDuringValidationMsg is added to infrastructure errors (e.g. failed to connect), but not to field validation errors
 	errorDuringValidationMsg = "error during validation"
@@ -58,10 +60,18 @@ func IsAdmissionInvalidRemovingStorage(err error) bool {
 	return apierrors.IsInvalid(err) && strings.Contains(err.Error(), unsupportedRemovingStorageMsg)
 }
 
+func IsAdmissionStorageRemovalInProgress(err error) bool {
+	return apierrors.IsInvalid(err) && strings.Contains(err.Error(), storageRemovalInProgressErrMsg)
+}
+
 func IsAdmissionInvalidExternalListenerPort(err error) bool {
 	return apierrors.IsInvalid(err) && strings.Contains(err.Error(), invalidExternalListenerStartingPortErrMsg)
 }
 
+func IsAdmissionTieredStorageNotEnabled(err error) bool {
+	return apierrors.IsInvalid(err) && strings.Contains(err.Error(), tieredStorageNotEnabledErrMsg)
+}
+
 func IsAdmissionErrorDuringValidation(err error) bool {
 	return apierrors.IsInternalError(err) && strings.Contains(err.Error(), errorDuringValidationMsg)
 }
@@ -168,6 +168,16 @@ func TestIsAdmissionInvalidRemovingStorage(t *testing.T) {
 	}
 }
 
+func TestIsAdmissionStorageRemovalInProgress(t *testing.T) {
+	kafkaCluster := banzaicloudv1beta1.KafkaCluster{ObjectMeta: metav1.ObjectMeta{Name: "test-KafkaCluster"}}
+	fieldErrs := append(field.ErrorList{}, field.Invalid(field.NewPath("spec").Child("brokers").Index(0).Child("storageConfig").Index(1),
+		"/kafka-logs2", storageRemovalInProgressErrMsg+", state of the removal: GracefulDiskRemovalRunning"))
+	err := apierrors.NewInvalid(kafkaCluster.GetObjectKind().GroupVersionKind().GroupKind(), kafkaCluster.Name, fieldErrs)
+
+	require.True(t, IsAdmissionStorageRemovalInProgress(err))
+	require.False(t, IsAdmissionInvalidRemovingStorage(err))
+}
+
 func TestIsAdmissionInvalidExternalListenerPort(t *testing.T) {
 	testCases := []struct {
 		testName  string
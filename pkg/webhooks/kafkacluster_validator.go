@@ -17,6 +17,9 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"emperror.dev/errors"
 	"golang.org/x/exp/slices"
@@ -28,7 +31,10 @@ import (
 	"github.com/go-logr/logr"
 
 	banzaicloudv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/kafkaconfig"
 	"github.com/banzaicloud/koperator/pkg/util"
+	"github.com/banzaicloud/koperator/pkg/util/kafka"
+	properties "github.com/banzaicloud/koperator/properties/pkg"
 )
 
 type KafkaClusterValidator struct {
@@ -41,7 +47,7 @@ func (s KafkaClusterValidator) ValidateUpdate(ctx context.Context, oldObj, newOb
 	kafkaClusterNew := newObj.(*banzaicloudv1beta1.KafkaCluster)
 	log := s.Log.WithValues("name", kafkaClusterNew.GetName(), "namespace", kafkaClusterNew.GetNamespace())
 
-	fieldErr, err := checkBrokerStorageRemoval(&kafkaClusterOld.Spec, &kafkaClusterNew.Spec)
+	fieldErr, err := checkBrokerStorageRemoval(&kafkaClusterOld.Spec, &kafkaClusterNew.Spec, &kafkaClusterOld.Status)
 	if err != nil {
 		log.Error(err, errorDuringValidationMsg)
 		return apierrors.NewInternalError(errors.WithMessage(err, errorDuringValidationMsg))
@@ -55,6 +61,12 @@ func (s KafkaClusterValidator) ValidateUpdate(ctx context.Context, oldObj, newOb
 		allErrs = append(allErrs, listenerErrs...)
 	}
 
+	allErrs = append(allErrs, checkZonePinning(&kafkaClusterNew.Spec)...)
+	allErrs = append(allErrs, checkKafkaConfigs(&kafkaClusterOld.Spec, kafkaClusterNew)...)
+	allErrs = append(allErrs, checkConfigSecretRefs(kafkaClusterNew)...)
+	allErrs = append(allErrs, checkSSLSecrets(&kafkaClusterNew.Spec)...)
+	allErrs = append(allErrs, checkBrokerZoneChange(&kafkaClusterOld.Spec, &kafkaClusterNew.Spec)...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -75,6 +87,11 @@ func (s KafkaClusterValidator) ValidateCreate(ctx context.Context, obj runtime.O
 		allErrs = append(allErrs, listenerErrs...)
 	}
 
+	allErrs = append(allErrs, checkZonePinning(&kafkaCluster.Spec)...)
+	allErrs = append(allErrs, checkKafkaConfigs(nil, kafkaCluster)...)
+	allErrs = append(allErrs, checkConfigSecretRefs(kafkaCluster)...)
+	allErrs = append(allErrs, checkSSLSecrets(&kafkaCluster.Spec)...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -89,8 +106,10 @@ func (s KafkaClusterValidator) ValidateDelete(ctx context.Context, obj runtime.O
 	return nil
 }
 
-// checkBrokerStorageRemoval checks if there is any broker storage which has been removed. If yes, admission will be rejected
-func checkBrokerStorageRemoval(kafkaClusterSpecOld, kafkaClusterSpecNew *banzaicloudv1beta1.KafkaClusterSpec) (*field.Error, error) {
+// checkBrokerStorageRemoval checks whether the broker storages can be removed. Storages backed by persistent volume claims
+// are removed gracefully, so their removal is allowed unless it would leave the broker without any storage. Adding back
+// a storage whose graceful removal is still in progress is rejected.
+func checkBrokerStorageRemoval(kafkaClusterSpecOld, kafkaClusterSpecNew *banzaicloudv1beta1.KafkaClusterSpec, kafkaClusterStatus *banzaicloudv1beta1.KafkaClusterStatus) (*field.Error, error) {
 	for j := range kafkaClusterSpecOld.Brokers {
 		brokerOld := &kafkaClusterSpecOld.Brokers[j]
 		for k := range kafkaClusterSpecNew.Brokers {
@@ -110,6 +129,9 @@ func checkBrokerStorageRemoval(kafkaClusterSpecOld, kafkaClusterSpecNew *banzaic
 				if err != nil {
 					return nil, err
 				}
+				if len(brokerConfigsOld.StorageConfigs) > 0 && len(brokerConfigsNew.StorageConfigs) == 0 {
+					return field.Invalid(field.NewPath("spec").Child("brokers").Index(k).Child("storageConfigs"), brokerNew.Id, unsupportedRemovingStorageMsg+", a broker must keep at least one storage"), nil
+				}
 				for e := range brokerConfigsOld.StorageConfigs {
 					storageConfigOld := &brokerConfigsOld.StorageConfigs[e]
 					isStorageFound := false
@@ -121,19 +143,44 @@ func checkBrokerStorageRemoval(kafkaClusterSpecOld, kafkaClusterSpecNew *banzaic
 							break
 						}
 					}
-					if !isStorageFound {
+					// Only the storages backed by persistent volume claims can be drained and removed gracefully
+					if !isStorageFound && storageConfigOld.PvcSpec == nil && storageConfigOld.EmptyDir != nil {
 						fromConfigGroup := getMissingMounthPathLocation(storageConfigOld.MountPath, kafkaClusterSpecOld, int32(k))
 						if fromConfigGroup != nil && *fromConfigGroup {
-							return field.Invalid(field.NewPath("spec").Child("brokers").Index(k).Child("brokerConfigGroup"), brokerNew.BrokerConfigGroup, fmt.Sprintf("%s, missing storageConfig mounthPath: %s", unsupportedRemovingStorageMsg, storageConfigOld.MountPath)), nil
+							return field.Invalid(field.NewPath("spec").Child("brokers").Index(k).Child("brokerConfigGroup"), brokerNew.BrokerConfigGroup, fmt.Sprintf("%s, missing emptyDir storageConfig mounthPath: %s", unsupportedRemovingStorageMsg, storageConfigOld.MountPath)), nil
 						}
-						return field.NotFound(field.NewPath("spec").Child("brokers").Index(k).Child("storageConfig").Index(e), storageConfigOld.MountPath+", "+unsupportedRemovingStorageMsg), nil
+						return field.NotFound(field.NewPath("spec").Child("brokers").Index(k).Child("storageConfig").Index(e), storageConfigOld.MountPath+", "+unsupportedRemovingStorageMsg+" for emptyDir storage"), nil
 					}
 				}
 			}
 		}
 	}
+
+	if kafkaClusterStatus == nil {
+		return nil, nil
+	}
+	for k := range kafkaClusterSpecNew.Brokers {
+		brokerNew := &kafkaClusterSpecNew.Brokers[k]
+		brokerState, ok := kafkaClusterStatus.BrokersState[strconv.Itoa(int(brokerNew.Id))]
+		if !ok {
+			continue
+		}
+		brokerConfigsNew, err := brokerNew.GetBrokerConfig(*kafkaClusterSpecNew)
+		if err != nil {
+			return nil, err
+		}
+		for e := range brokerConfigsNew.StorageConfigs {
+			mountPath := brokerConfigsNew.StorageConfigs[e].MountPath
+			// the storage can be added back until Cruise Control starts to drain it
+			if volumeState, ok := brokerState.GracefulActionState.VolumeStates[mountPath]; ok && volumeState.CruiseControlVolumeState.IsDiskRemoval() &&
+				!volumeState.CruiseControlVolumeState.IsDiskRemovalRevocable() {
+				return field.Invalid(field.NewPath("spec").Child("brokers").Index(k).Child("storageConfig").Index(e), mountPath, fmt.Sprintf("%s, state of the removal: %s", storageRemovalInProgressErrMsg, volumeState.CruiseControlVolumeState)), nil
+			}
+		}
+	}
 	return nil, nil
 }
+
 func getMissingMounthPathLocation(mounthPath string, kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec, brokerId int32) (fromConfigGroup *bool) {
 	if brokerId < 0 || int(brokerId) >= len(kafkaClusterSpec.Brokers) {
 		return nil
@@ -160,6 +207,232 @@ func getMissingMounthPathLocation(mounthPath string, kafkaClusterSpec *banzaiclo
 }
 
 // checkListeners validates the spec.listenersConfig object
+// checkZonePinning checks that the zone label is one of the rack awareness labels and the zones are unique
+func checkZonePinning(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	if kafkaClusterSpec.RackAwareness == nil || kafkaClusterSpec.RackAwareness.ZonePinning == nil {
+		return nil
+	}
+	var allErrs field.ErrorList
+	rackAwareness := kafkaClusterSpec.RackAwareness
+	zonePinningPath := field.NewPath("spec").Child("rackAwareness").Child("zonePinning")
+
+	zoneLabel := rackAwareness.ZonePinning.GetZoneLabel(rackAwareness)
+	if !util.StringSliceContains(rackAwareness.Labels, zoneLabel) {
+		allErrs = append(allErrs, field.Invalid(zonePinningPath.Child("label"), zoneLabel, "zone label must be one of the rack awareness labels"))
+	}
+
+	zones := make(map[string]struct{}, len(rackAwareness.ZonePinning.Zones))
+	for i, zone := range rackAwareness.ZonePinning.Zones {
+		if _, ok := zones[zone]; ok {
+			allErrs = append(allErrs, field.Duplicate(zonePinningPath.Child("zones").Index(i), zone))
+		}
+		zones[zone] = struct{}{}
+	}
+	return allErrs
+}
+
+// checkSSLSecrets checks that the configuration of the Vault PKI backend is set when it is used
+func checkSSLSecrets(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	sslSecrets := kafkaClusterSpec.ListenersConfig.SSLSecrets
+	if sslSecrets == nil || sslSecrets.PKIBackend != banzaicloudv1beta1.PKIBackendVault || sslSecrets.VaultConfig != nil {
+		return nil
+	}
+	return field.ErrorList{field.Required(field.NewPath("spec").Child("listenersConfig").Child("sslSecrets").Child("vaultConfig"),
+		"vaultConfig must be set when the pkiBackend is vault")}
+}
+
+// checkBrokerZoneChange rejects the modification of the zone pinning which would move existing brokers to another zone,
+// since the persistent volumes of the brokers can not follow them. When the zone pinning is enabled the rack the broker
+// is already running in must contain the zone it would be pinned to.
+func checkBrokerZoneChange(kafkaClusterSpecOld, kafkaClusterSpecNew *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, brokerOld := range kafkaClusterSpecOld.Brokers {
+		zoneOld, pinnedOld := kafkaClusterSpecOld.GetBrokerZone(brokerOld.Id)
+		for i, brokerNew := range kafkaClusterSpecNew.Brokers {
+			if brokerNew.Id != brokerOld.Id {
+				continue
+			}
+			zoneNew, pinnedNew := kafkaClusterSpecNew.GetBrokerZone(brokerNew.Id)
+			if !pinnedNew {
+				break
+			}
+			if pinnedOld && zoneNew != zoneOld {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("brokers").Index(i),
+					fmt.Sprintf("zone pinning change would move broker %d from zone %s to zone %s", brokerNew.Id, zoneOld, zoneNew)))
+			}
+			if rack, found := getBrokerRack(&brokerOld); !pinnedOld && found && !slices.Contains(strings.Split(rack, ","), zoneNew) {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("brokers").Index(i),
+					fmt.Sprintf("enabling zone pinning would move broker %d from rack %s to zone %s", brokerNew.Id, rack, zoneNew)))
+			}
+			break
+		}
+	}
+	return allErrs
+}
+
+// getBrokerRack returns the broker.rack read-only config of the broker which is set once the rack of the broker is known
+func getBrokerRack(broker *banzaicloudv1beta1.Broker) (string, bool) {
+	readOnlyConfigs, err := properties.NewFromString(broker.ReadOnlyConfig)
+	if err != nil {
+		return "", false
+	}
+	rack, found := readOnlyConfigs.Get("broker.rack")
+	if !found {
+		return "", false
+	}
+	return rack.Value(), true
+}
+
+// kafkaConfigSection is a broker configuration set in the KafkaCluster
+type kafkaConfigSection struct {
+	path    *field.Path
+	section kafkaconfig.Section
+	config  string
+	// unchanged is true when the configuration is the same as in the old KafkaCluster
+	unchanged bool
+}
+
+// checkKafkaConfigs validates the broker configurations of the KafkaCluster against the configuration catalog of the
+// Kafka version running in the cluster. On update only the modified configurations are validated, so configurations
+// accepted earlier do not block unrelated changes.
+func checkKafkaConfigs(kafkaClusterSpecOld *banzaicloudv1beta1.KafkaClusterSpec, kafkaCluster *banzaicloudv1beta1.KafkaCluster) field.ErrorList {
+	specNew := &kafkaCluster.Spec
+	specOld := kafkaClusterSpecOld
+	if specOld == nil {
+		specOld = &banzaicloudv1beta1.KafkaClusterSpec{}
+	}
+
+	sections := []kafkaConfigSection{
+		{
+			path:      field.NewPath("spec").Child("readOnlyConfig"),
+			section:   kafkaconfig.SectionReadOnly,
+			config:    specNew.ReadOnlyConfig,
+			unchanged: kafkaClusterSpecOld != nil && specOld.ReadOnlyConfig == specNew.ReadOnlyConfig,
+		},
+		{
+			path:      field.NewPath("spec").Child("clusterWideConfig"),
+			section:   kafkaconfig.SectionClusterWide,
+			config:    specNew.ClusterWideConfig,
+			unchanged: kafkaClusterSpecOld != nil && specOld.ClusterWideConfig == specNew.ClusterWideConfig,
+		},
+	}
+
+	groupNames := make([]string, 0, len(specNew.BrokerConfigGroups))
+	for name := range specNew.BrokerConfigGroups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		groupOld, found := specOld.BrokerConfigGroups[name]
+		sections = append(sections, kafkaConfigSection{
+			path:      field.NewPath("spec").Child("brokerConfigGroups").Key(name).Child("config"),
+			section:   kafkaconfig.SectionPerBroker,
+			config:    specNew.BrokerConfigGroups[name].Config,
+			unchanged: found && groupOld.Config == specNew.BrokerConfigGroups[name].Config,
+		})
+	}
+
+	brokersOld := make(map[int32]banzaicloudv1beta1.Broker, len(specOld.Brokers))
+	for _, broker := range specOld.Brokers {
+		brokersOld[broker.Id] = broker
+	}
+	for i, broker := range specNew.Brokers {
+		brokerOld, found := brokersOld[broker.Id]
+		sections = append(sections, kafkaConfigSection{
+			path:      field.NewPath("spec").Child("brokers").Index(i).Child("readOnlyConfig"),
+			section:   kafkaconfig.SectionReadOnly,
+			config:    broker.ReadOnlyConfig,
+			unchanged: found && brokerOld.ReadOnlyConfig == broker.ReadOnlyConfig,
+		})
+		if broker.BrokerConfig != nil {
+			sections = append(sections, kafkaConfigSection{
+				path:      field.NewPath("spec").Child("brokers").Index(i).Child("brokerConfig").Child("config"),
+				section:   kafkaconfig.SectionPerBroker,
+				config:    broker.BrokerConfig.Config,
+				unchanged: found && brokerOld.BrokerConfig != nil && brokerOld.BrokerConfig.Config == broker.BrokerConfig.Config,
+			})
+		}
+	}
+
+	catalog := kafkaconfig.ForCluster(kafkaCluster)
+	var allErrs field.ErrorList
+	for _, section := range sections {
+		if section.unchanged || strings.TrimSpace(section.config) == "" {
+			continue
+		}
+		config, err := properties.NewFromString(section.config)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(section.path, "", fmt.Sprintf("could not parse configuration: %s", err)))
+			continue
+		}
+		configMap := make(map[string]string, config.Len())
+		for _, key := range config.Keys() {
+			if p, ok := config.Get(key); ok {
+				configMap[key] = p.Value()
+			}
+		}
+		for _, violation := range catalog.Validate(section.section, configMap) {
+			allErrs = append(allErrs, field.Invalid(section.path, violation.Key, violation.Message))
+		}
+	}
+	return allErrs
+}
+
+// checkConfigSecretRefs validates the configSecretRefs of the KafkaCluster, the brokerConfigGroups and the brokers
+func checkConfigSecretRefs(kafkaCluster *banzaicloudv1beta1.KafkaCluster) field.ErrorList {
+	spec := &kafkaCluster.Spec
+	catalog := kafkaconfig.ForCluster(kafkaCluster)
+
+	allErrs := validateConfigSecretRefs(catalog, field.NewPath("spec").Child("configSecretRefs"), spec.ConfigSecretRefs)
+
+	groupNames := make([]string, 0, len(spec.BrokerConfigGroups))
+	for name := range spec.BrokerConfigGroups {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		allErrs = append(allErrs, validateConfigSecretRefs(catalog,
+			field.NewPath("spec").Child("brokerConfigGroups").Key(name).Child("configSecretRefs"),
+			spec.BrokerConfigGroups[name].ConfigSecretRefs)...)
+	}
+
+	for i, broker := range spec.Brokers {
+		if broker.BrokerConfig == nil {
+			continue
+		}
+		allErrs = append(allErrs, validateConfigSecretRefs(catalog,
+			field.NewPath("spec").Child("brokers").Index(i).Child("brokerConfig").Child("configSecretRefs"),
+			broker.BrokerConfig.ConfigSecretRefs)...)
+	}
+	return allErrs
+}
+
+func validateConfigSecretRefs(catalog *kafkaconfig.Catalog, path *field.Path, refs []banzaicloudv1beta1.ConfigSecretRef) field.ErrorList {
+	var allErrs field.ErrorList
+	configs := make(map[string]bool, len(refs))
+	for i, ref := range refs {
+		refPath := path.Index(i)
+		if ref.SecretKeyRef.Name == "" {
+			allErrs = append(allErrs, field.Required(refPath.Child("secretKeyRef").Child("name"), "secret name must be set"))
+		}
+		if ref.SecretKeyRef.Key == "" {
+			allErrs = append(allErrs, field.Required(refPath.Child("secretKeyRef").Child("key"), "secret key must be set"))
+		}
+		if configs[ref.Config] {
+			allErrs = append(allErrs, field.Duplicate(refPath.Child("config"), ref.Config))
+			continue
+		}
+		configs[ref.Config] = true
+
+		// the value is resolved by the FileConfigProvider of the brokers, so only the name of the configuration is validated
+		reference := kafka.ConfigSecretReference("", ref.Config)
+		for _, violation := range catalog.Validate(kafkaconfig.SectionReadOnly, map[string]string{ref.Config: reference}) {
+			allErrs = append(allErrs, field.Invalid(refPath.Child("config"), violation.Key, violation.Message))
+		}
+	}
+	return allErrs
+}
+
 func checkInternalAndExternalListeners(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -167,9 +440,145 @@ func checkInternalAndExternalListeners(kafkaClusterSpec *banzaicloudv1beta1.Kafk
 
 	allErrs = append(allErrs, checkExternalListeners(kafkaClusterSpec)...)
 
+	allErrs = append(allErrs, checkListenersSASL(kafkaClusterSpec)...)
+
+	allErrs = append(allErrs, checkClientSASL(kafkaClusterSpec)...)
+
+	return allErrs
+}
+
+// checkClientSASL checks that koperator has SASL credentials when it can only connect to the brokers through SASL
+// listeners and that the credentials can be used with the selected mechanism
+func checkClientSASL(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	clientSASLPath := field.NewPath("spec").Child("clientSASL")
+	clientSASL := kafkaClusterSpec.ClientSASL
+	if clientSASL == nil {
+		saslOnly := false
+		for _, iListener := range kafkaClusterSpec.ListenersConfig.InternalListeners {
+			if iListener.UsedForControllerCommunication {
+				continue
+			}
+			if !iListener.Type.IsSasl() {
+				return nil
+			}
+			saslOnly = true
+		}
+		if saslOnly {
+			return field.ErrorList{field.Required(clientSASLPath,
+				"clientSASL must be set as all internal listeners koperator can connect through use SASL")}
+		}
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	if !clientSASL.Mechanism.IsScram() && clientSASL.Mechanism != banzaicloudv1beta1.SASLMechanismOAuthBearer {
+		allErrs = append(allErrs, field.NotSupported(clientSASLPath.Child("mechanism"), clientSASL.Mechanism, []string{
+			string(banzaicloudv1beta1.SASLMechanismScramSHA256), string(banzaicloudv1beta1.SASLMechanismScramSHA512), string(banzaicloudv1beta1.SASLMechanismOAuthBearer),
+		}))
+	}
+	switch {
+	case clientSASL.Mechanism == banzaicloudv1beta1.SASLMechanismOAuthBearer && clientSASL.TokenEndpointURL == "":
+		allErrs = append(allErrs, field.Required(clientSASLPath.Child("tokenEndpointURL"),
+			fmt.Sprintf("tokenEndpointURL is required for the %s mechanism", clientSASL.Mechanism)))
+	case clientSASL.Mechanism != banzaicloudv1beta1.SASLMechanismOAuthBearer && clientSASL.TokenEndpointURL != "":
+		allErrs = append(allErrs, field.Forbidden(clientSASLPath.Child("tokenEndpointURL"),
+			fmt.Sprintf("tokenEndpointURL can only be set for the %s mechanism", banzaicloudv1beta1.SASLMechanismOAuthBearer)))
+	}
+	return allErrs
+}
+
+// checkListenersSASL checks that the SASL configuration of the listeners matches their protocol and the enabled mechanisms
+func checkListenersSASL(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	listenersPath := field.NewPath("spec").Child("listenersConfig")
+	var interBrokerMechanisms []banzaicloudv1beta1.SASLMechanism
+	for i, iListener := range kafkaClusterSpec.ListenersConfig.InternalListeners {
+		path := listenersPath.Child("internalListeners").Index(i)
+		allErrs = append(allErrs, checkListenerSASL(path, iListener.CommonListenerSpec)...)
+		if iListener.Type.IsSasl() && iListener.SASL != nil && (iListener.UsedForInnerBrokerCommunication || iListener.UsedForControllerCommunication) {
+			allErrs = append(allErrs, checkInterBrokerSASLMechanism(path.Child("sasl"), iListener.SASL)...)
+			interBrokerMechanisms = append(interBrokerMechanisms, iListener.SASL.GetInterBrokerMechanism())
+		}
+	}
+	for i, eListener := range kafkaClusterSpec.ListenersConfig.ExternalListeners {
+		allErrs = append(allErrs, checkListenerSASL(listenersPath.Child("externalListeners").Index(i), eListener.CommonListenerSpec)...)
+	}
+	// sasl.mechanism.inter.broker.protocol is shared by the inter broker and the control plane listener
+	if len(interBrokerMechanisms) == 2 && interBrokerMechanisms[0] != interBrokerMechanisms[1] {
+		allErrs = append(allErrs, field.Invalid(listenersPath.Child("internalListeners"), interBrokerMechanisms,
+			"the inter broker and the control plane listener must use the same inter broker SASL mechanism"))
+	}
+	return allErrs
+}
+
+// checkListenerSASL checks the SASL configuration of a listener
+func checkListenerSASL(path *field.Path, listener banzaicloudv1beta1.CommonListenerSpec) field.ErrorList {
+	sasl := listener.SASL
+	if sasl == nil {
+		return nil
+	}
+	saslPath := path.Child("sasl")
+	if !listener.Type.IsSasl() {
+		return field.ErrorList{field.Forbidden(saslPath,
+			fmt.Sprintf("sasl can only be set for %s and %s listeners", banzaicloudv1beta1.SecurityProtocolSaslPlaintext, banzaicloudv1beta1.SecurityProtocolSaslSSL))}
+	}
+
+	var allErrs field.ErrorList
+	mechanisms := make(map[banzaicloudv1beta1.SASLMechanism]struct{}, len(sasl.Mechanisms))
+	for i, mechanism := range sasl.Mechanisms {
+		if _, ok := mechanisms[mechanism]; ok {
+			allErrs = append(allErrs, field.Duplicate(saslPath.Child("mechanisms").Index(i), mechanism))
+		}
+		mechanisms[mechanism] = struct{}{}
+	}
+
+	hasScram := sasl.HasMechanism(banzaicloudv1beta1.SASLMechanismScramSHA256) || sasl.HasMechanism(banzaicloudv1beta1.SASLMechanismScramSHA512)
+	for _, mechanismConfig := range []struct {
+		name      string
+		mechanism string
+		enabled   bool
+		set       bool
+		required  bool
+	}{
+		{name: "plain", mechanism: string(banzaicloudv1beta1.SASLMechanismPlain), enabled: sasl.HasMechanism(banzaicloudv1beta1.SASLMechanismPlain), set: sasl.Plain != nil, required: true},
+		{name: "scram", mechanism: "SCRAM-SHA-256 or SCRAM-SHA-512", enabled: hasScram, set: sasl.Scram != nil},
+		{name: "oauthBearer", mechanism: string(banzaicloudv1beta1.SASLMechanismOAuthBearer), enabled: sasl.HasMechanism(banzaicloudv1beta1.SASLMechanismOAuthBearer), set: sasl.OAuthBearer != nil, required: true},
+		{name: "gssapi", mechanism: string(banzaicloudv1beta1.SASLMechanismGSSAPI), enabled: sasl.HasMechanism(banzaicloudv1beta1.SASLMechanismGSSAPI), set: sasl.GSSAPI != nil, required: true},
+	} {
+		switch {
+		case mechanismConfig.enabled && !mechanismConfig.set && mechanismConfig.required:
+			allErrs = append(allErrs, field.Required(saslPath.Child(mechanismConfig.name),
+				fmt.Sprintf("%s must be set when %s is enabled", mechanismConfig.name, mechanismConfig.mechanism)))
+		case !mechanismConfig.enabled && mechanismConfig.set:
+			allErrs = append(allErrs, field.Forbidden(saslPath.Child(mechanismConfig.name),
+				fmt.Sprintf("%s can only be set when %s is enabled", mechanismConfig.name, mechanismConfig.mechanism)))
+		}
+	}
+
+	if sasl.InterBrokerMechanism != "" && !sasl.HasMechanism(sasl.InterBrokerMechanism) {
+		allErrs = append(allErrs, field.Invalid(saslPath.Child("interBrokerMechanism"), sasl.InterBrokerMechanism,
+			"the inter broker mechanism must be enabled on the listener"))
+	}
 	return allErrs
 }
 
+// checkInterBrokerSASLMechanism checks that the brokers have credentials for the inter broker mechanism of the listener
+func checkInterBrokerSASLMechanism(saslPath *field.Path, sasl *banzaicloudv1beta1.SASLConfig) field.ErrorList {
+	mechanism := sasl.GetInterBrokerMechanism()
+	switch {
+	case mechanism == banzaicloudv1beta1.SASLMechanismOAuthBearer:
+		return field.ErrorList{field.Invalid(saslPath.Child("interBrokerMechanism"), mechanism,
+			"OAUTHBEARER can not be used for inter broker communication")}
+	case mechanism == banzaicloudv1beta1.SASLMechanismPlain && sasl.Plain != nil && sasl.Plain.BrokerUser == "":
+		return field.ErrorList{field.Required(saslPath.Child("plain").Child("brokerUser"),
+			"brokerUser must be set when PLAIN is used for inter broker communication")}
+	case mechanism.IsScram() && (sasl.Scram == nil || sasl.Scram.BrokerCredentialsSecret == ""):
+		return field.ErrorList{field.Required(saslPath.Child("scram").Child("brokerCredentialsSecret"),
+			fmt.Sprintf("brokerCredentialsSecret must be set when %s is used for inter broker communication", mechanism))}
+	}
+	return nil
+}
+
 func checkInternalListeners(kafkaClusterSpec *banzaicloudv1beta1.KafkaClusterSpec) field.ErrorList {
 	return checkUniqueListenerContainerPort(kafkaClusterSpec.ListenersConfig)
 }
@@ -16,6 +16,7 @@ package webhooks
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -33,7 +34,9 @@ func TestCheckBrokerStorageRemoval(t *testing.T) {
 		testName            string
 		kafkaClusterSpecNew v1beta1.KafkaClusterSpec
 		kafkaClusterSpecOld v1beta1.KafkaClusterSpec
+		kafkaClusterStatus  v1beta1.KafkaClusterStatus
 		isValid             bool
+		expectedErrMsg      string
 	}{
 		{
 			testName: "there is no storage remove",
@@ -207,7 +210,7 @@ func TestCheckBrokerStorageRemoval(t *testing.T) {
 					},
 				},
 			},
-			isValid: false,
+			isValid: true,
 		},
 		{
 			testName: "when there is storage remove",
@@ -245,7 +248,7 @@ func TestCheckBrokerStorageRemoval(t *testing.T) {
 					},
 				},
 			},
-			isValid: false,
+			isValid: true,
 		},
 		{
 			testName: "when added a new one",
@@ -425,7 +428,7 @@ func TestCheckBrokerStorageRemoval(t *testing.T) {
 					},
 				},
 			},
-			isValid: false,
+			isValid: true,
 		},
 		{
 			testName: "when there is no such brokerConfigGroup",
@@ -479,10 +482,184 @@ func TestCheckBrokerStorageRemoval(t *testing.T) {
 			},
 			isValid: false,
 		},
+		{
+			testName: "when emptyDir storage is removed",
+			kafkaClusterSpecNew: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			kafkaClusterSpecOld: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+								{MountPath: "logs2", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+							},
+						},
+					},
+				},
+			},
+			isValid: false,
+		},
+		{
+			testName: "when all the storages of a broker are removed",
+			kafkaClusterSpecNew: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id:           1,
+						BrokerConfig: &v1beta1.BrokerConfig{},
+					},
+				},
+			},
+			kafkaClusterSpecOld: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			isValid: false,
+		},
+		{
+			testName: "when a storage under removal is added back",
+			kafkaClusterSpecNew: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+								{MountPath: "logs2", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			kafkaClusterSpecOld: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			kafkaClusterStatus: v1beta1.KafkaClusterStatus{
+				BrokersState: map[string]v1beta1.BrokerState{
+					"1": {
+						GracefulActionState: v1beta1.GracefulActionState{
+							VolumeStates: map[string]v1beta1.VolumeState{
+								"logs2": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalRunning},
+							},
+						},
+					},
+				},
+			},
+			isValid:        false,
+			expectedErrMsg: storageRemovalInProgressErrMsg,
+		},
+		{
+			testName: "when a storage is added back before its removal is started",
+			kafkaClusterSpecNew: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+								{MountPath: "logs2", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			kafkaClusterSpecOld: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			kafkaClusterStatus: v1beta1.KafkaClusterStatus{
+				BrokersState: map[string]v1beta1.BrokerState{
+					"1": {
+						GracefulActionState: v1beta1.GracefulActionState{
+							VolumeStates: map[string]v1beta1.VolumeState{
+								"logs2": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalScheduled},
+							},
+						},
+					},
+				},
+			},
+			isValid: true,
+		},
+		{
+			testName: "when a storage is added back after its removal is cancelled",
+			kafkaClusterSpecNew: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+								{MountPath: "logs2", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			kafkaClusterSpecOld: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{
+					{
+						Id: 1,
+						BrokerConfig: &v1beta1.BrokerConfig{
+							StorageConfigs: []v1beta1.StorageConfig{
+								{MountPath: "logs1", PvcSpec: &corev1.PersistentVolumeClaimSpec{}},
+							},
+						},
+					},
+				},
+			},
+			kafkaClusterStatus: v1beta1.KafkaClusterStatus{
+				BrokersState: map[string]v1beta1.BrokerState{
+					"1": {
+						GracefulActionState: v1beta1.GracefulActionState{
+							VolumeStates: map[string]v1beta1.VolumeState{
+								"logs2": {CruiseControlVolumeState: v1beta1.GracefulDiskRemovalPaused},
+							},
+						},
+					},
+				},
+			},
+			isValid: true,
+		},
 	}
 
 	for _, testCase := range testCases {
-		res, err := checkBrokerStorageRemoval(&testCase.kafkaClusterSpecOld, &testCase.kafkaClusterSpecNew)
+		res, err := checkBrokerStorageRemoval(&testCase.kafkaClusterSpecOld, &testCase.kafkaClusterSpecNew, &testCase.kafkaClusterStatus)
 		if err != nil {
 			t.Errorf("testName: %s, err should be nil, got %s", testCase.testName, err)
 		}
@@ -490,6 +667,8 @@ func TestCheckBrokerStorageRemoval(t *testing.T) {
 			t.Errorf("Message: %s, testName: %s", res.Error(), testCase.testName)
 		} else if res == nil && !testCase.isValid {
 			t.Errorf("there should be storage removal, testName: %s", testCase.testName)
+		} else if res != nil && !strings.Contains(res.Error(), testCase.expectedErrMsg) {
+			t.Errorf("Message: %s should contain %s, testName: %s", res.Error(), testCase.expectedErrMsg, testCase.testName)
 		}
 	}
 }
@@ -851,3 +1030,442 @@ func TestCheckTargetPortsCollisionForEnvoy(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckZonePinning(t *testing.T) {
+	zonePinningPath := field.NewPath("spec").Child("rackAwareness").Child("zonePinning")
+	testCases := []struct {
+		testName      string
+		rackAwareness *v1beta1.RackAwareness
+		expected      field.ErrorList
+	}{
+		{
+			testName: "no zone pinning",
+			rackAwareness: &v1beta1.RackAwareness{
+				Labels: []string{"topology.kubernetes.io/zone"},
+			},
+			expected: nil,
+		},
+		{
+			testName: "valid zone pinning with the default label",
+			rackAwareness: &v1beta1.RackAwareness{
+				Labels:      []string{"topology.kubernetes.io/zone"},
+				ZonePinning: &v1beta1.ZonePinning{Zones: []string{"us-east-1a", "us-east-1b"}},
+			},
+			expected: nil,
+		},
+		{
+			testName: "zone label is not a rack awareness label",
+			rackAwareness: &v1beta1.RackAwareness{
+				Labels:      []string{"topology.kubernetes.io/region"},
+				ZonePinning: &v1beta1.ZonePinning{Zones: []string{"us-east-1a"}, Label: "topology.kubernetes.io/zone"},
+			},
+			expected: append(field.ErrorList{},
+				field.Invalid(zonePinningPath.Child("label"), "topology.kubernetes.io/zone", "zone label must be one of the rack awareness labels")),
+		},
+		{
+			testName: "duplicate zones",
+			rackAwareness: &v1beta1.RackAwareness{
+				Labels:      []string{"topology.kubernetes.io/zone"},
+				ZonePinning: &v1beta1.ZonePinning{Zones: []string{"us-east-1a", "us-east-1a"}},
+			},
+			expected: append(field.ErrorList{},
+				field.Duplicate(zonePinningPath.Child("zones").Index(1), "us-east-1a")),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			got := checkZonePinning(&v1beta1.KafkaClusterSpec{RackAwareness: testCase.rackAwareness})
+			require.Equal(t, testCase.expected, got)
+		})
+	}
+}
+
+func TestCheckBrokerZoneChange(t *testing.T) {
+	brokers := []v1beta1.Broker{{Id: 0}, {Id: 1}, {Id: 2}}
+	rackAwareness := func(zones ...string) *v1beta1.RackAwareness {
+		return &v1beta1.RackAwareness{
+			Labels:      []string{"topology.kubernetes.io/zone"},
+			ZonePinning: &v1beta1.ZonePinning{Zones: zones},
+		}
+	}
+	rackedBrokers := func(racks ...string) []v1beta1.Broker {
+		racked := make([]v1beta1.Broker, 0, len(racks))
+		for i, rack := range racks {
+			racked = append(racked, v1beta1.Broker{Id: int32(i), ReadOnlyConfig: "broker.rack=" + rack + "\n"})
+		}
+		return racked
+	}
+	testCases := []struct {
+		testName string
+		specOld  v1beta1.KafkaClusterSpec
+		specNew  v1beta1.KafkaClusterSpec
+		expected field.ErrorList
+	}{
+		{
+			testName: "zone pinning enabled for brokers with unknown rack",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: brokers},
+			specNew:  v1beta1.KafkaClusterSpec{Brokers: brokers, RackAwareness: rackAwareness("a", "b")},
+			expected: nil,
+		},
+		{
+			testName: "zone pinning enabled for brokers in the pinned zones",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: rackedBrokers("a", "region,b", "a")},
+			specNew:  v1beta1.KafkaClusterSpec{Brokers: rackedBrokers("a", "region,b", "a"), RackAwareness: rackAwareness("a", "b")},
+			expected: nil,
+		},
+		{
+			testName: "zone pinning enabled for a broker in another zone",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: rackedBrokers("a", "a", "a")},
+			specNew:  v1beta1.KafkaClusterSpec{Brokers: rackedBrokers("a", "a", "a"), RackAwareness: rackAwareness("a", "b")},
+			expected: append(field.ErrorList{},
+				field.Forbidden(field.NewPath("spec").Child("brokers").Index(1), "enabling zone pinning would move broker 1 from rack a to zone b")),
+		},
+		{
+			testName: "new broker added",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: brokers[:2], RackAwareness: rackAwareness("a", "b")},
+			specNew:  v1beta1.KafkaClusterSpec{Brokers: brokers, RackAwareness: rackAwareness("a", "b")},
+			expected: nil,
+		},
+		{
+			testName: "zone added",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: brokers, RackAwareness: rackAwareness("a", "b")},
+			specNew:  v1beta1.KafkaClusterSpec{Brokers: brokers, RackAwareness: rackAwareness("a", "b", "c")},
+			expected: append(field.ErrorList{},
+				field.Forbidden(field.NewPath("spec").Child("brokers").Index(2), "zone pinning change would move broker 2 from zone a to zone c")),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			got := checkBrokerZoneChange(&testCase.specOld, &testCase.specNew)
+			require.Equal(t, testCase.expected, got)
+		})
+	}
+}
+
+func TestCheckKafkaConfigs(t *testing.T) {
+	testCases := []struct {
+		testName string
+		specOld  *v1beta1.KafkaClusterSpec
+		specNew  v1beta1.KafkaClusterSpec
+		expected field.ErrorList
+	}{
+		{
+			testName: "valid configurations",
+			specNew: v1beta1.KafkaClusterSpec{
+				ReadOnlyConfig:    "auto.create.topics.enable=false\nlog.retention.hours=168",
+				ClusterWideConfig: "background.threads=2",
+				BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+					"default": {Config: "ssl.keystore.location=/keystore.jks"},
+				},
+				Brokers: []v1beta1.Broker{{Id: 0, ReadOnlyConfig: "broker.rack=a"}},
+			},
+			expected: nil,
+		},
+		{
+			testName: "invalid configurations on create",
+			specNew: v1beta1.KafkaClusterSpec{
+				ReadOnlyConfig:    "log.retention.hour=168",
+				ClusterWideConfig: "auto.create.topics.enable=false",
+				BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+					"default": {Config: "num.io.threads=many"},
+				},
+				Brokers: []v1beta1.Broker{{Id: 0, BrokerConfig: &v1beta1.BrokerConfig{Config: "log.retention.hours=168"}}},
+			},
+			expected: append(field.ErrorList{},
+				field.Invalid(field.NewPath("spec").Child("readOnlyConfig"), "log.retention.hour", "unknown configuration, did you mean log.retention.hours?"),
+				field.Invalid(field.NewPath("spec").Child("clusterWideConfig"), "auto.create.topics.enable",
+					"read-only configuration can not be updated dynamically, it must be set in readOnlyConfig"),
+				field.Invalid(field.NewPath("spec").Child("brokerConfigGroups").Key("default").Child("config"), "num.io.threads", "value must be a valid int"),
+				field.Invalid(field.NewPath("spec").Child("brokers").Index(0).Child("brokerConfig").Child("config"), "log.retention.hours",
+					"read-only configuration can not be updated dynamically, it must be set in readOnlyConfig"),
+			),
+		},
+		{
+			testName: "unchanged invalid configurations on update",
+			specOld: &v1beta1.KafkaClusterSpec{
+				ReadOnlyConfig: "log.retention.hour=168",
+				Brokers:        []v1beta1.Broker{{Id: 0, ReadOnlyConfig: "num.partitions=0"}},
+			},
+			specNew: v1beta1.KafkaClusterSpec{
+				ReadOnlyConfig: "log.retention.hour=168",
+				Brokers:        []v1beta1.Broker{{Id: 0, ReadOnlyConfig: "num.partitions=0"}, {Id: 1, ReadOnlyConfig: "num.partitions=0"}},
+			},
+			expected: append(field.ErrorList{},
+				field.Invalid(field.NewPath("spec").Child("brokers").Index(1).Child("readOnlyConfig"), "num.partitions", "value must be at least 1"),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			got := checkKafkaConfigs(testCase.specOld, &v1beta1.KafkaCluster{Spec: testCase.specNew})
+			require.Equal(t, testCase.expected, got)
+		})
+	}
+}
+
+func TestCheckConfigSecretRefs(t *testing.T) {
+	ref := func(config, secretName, secretKey string) v1beta1.ConfigSecretRef {
+		return v1beta1.ConfigSecretRef{
+			Config: config,
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  secretKey,
+			},
+		}
+	}
+	kafkaCluster := &v1beta1.KafkaCluster{
+		Spec: v1beta1.KafkaClusterSpec{
+			ConfigSecretRefs: []v1beta1.ConfigSecretRef{
+				ref("listener.name.external.ssl.truststore.password", "credentials", "truststore"),
+				ref("ssl.trustsore.password", "credentials", "truststore"),
+			},
+			BrokerConfigGroups: map[string]v1beta1.BrokerConfig{
+				"default": {ConfigSecretRefs: []v1beta1.ConfigSecretRef{
+					ref("sasl.jaas.config", "credentials", "jaas"),
+					ref("sasl.jaas.config", "credentials", "jaas"),
+				}},
+			},
+			Brokers: []v1beta1.Broker{
+				{Id: 0},
+				{Id: 1, BrokerConfig: &v1beta1.BrokerConfig{ConfigSecretRefs: []v1beta1.ConfigSecretRef{
+					ref("rsm.config.access.key", "", ""),
+				}}},
+			},
+		},
+	}
+
+	expected := append(field.ErrorList{},
+		field.Invalid(field.NewPath("spec").Child("configSecretRefs").Index(1).Child("config"), "ssl.trustsore.password",
+			"unknown configuration, did you mean ssl.truststore.password?"),
+		field.Duplicate(field.NewPath("spec").Child("brokerConfigGroups").Key("default").Child("configSecretRefs").Index(1).Child("config"),
+			"sasl.jaas.config"),
+		field.Required(field.NewPath("spec").Child("brokers").Index(1).Child("brokerConfig").Child("configSecretRefs").Index(0).Child("secretKeyRef").Child("name"),
+			"secret name must be set"),
+		field.Required(field.NewPath("spec").Child("brokers").Index(1).Child("brokerConfig").Child("configSecretRefs").Index(0).Child("secretKeyRef").Child("key"),
+			"secret key must be set"),
+	)
+	require.Equal(t, expected, checkConfigSecretRefs(kafkaCluster))
+}
+
+func TestCheckSSLSecrets(t *testing.T) {
+	vaultConfigPath := field.NewPath("spec").Child("listenersConfig").Child("sslSecrets").Child("vaultConfig")
+	testCases := []struct {
+		testName   string
+		sslSecrets *v1beta1.SSLSecrets
+		expected   field.ErrorList
+	}{
+		{
+			testName: "no ssl secrets",
+		},
+		{
+			testName:   "cert-manager backend",
+			sslSecrets: &v1beta1.SSLSecrets{PKIBackend: v1beta1.PKIBackendCertManager},
+		},
+		{
+			testName: "vault backend with vault config",
+			sslSecrets: &v1beta1.SSLSecrets{
+				PKIBackend:  v1beta1.PKIBackendVault,
+				VaultConfig: &v1beta1.VaultConfig{AuthRole: "koperator", PKIPath: "pki", IssueRole: "kafka"},
+			},
+		},
+		{
+			testName:   "vault backend without vault config",
+			sslSecrets: &v1beta1.SSLSecrets{PKIBackend: v1beta1.PKIBackendVault},
+			expected: append(field.ErrorList{},
+				field.Required(vaultConfigPath, "vaultConfig must be set when the pkiBackend is vault")),
+		},
+	}
+
+	for _, testCase := range testCases {
+		spec := &v1beta1.KafkaClusterSpec{ListenersConfig: v1beta1.ListenersConfig{SSLSecrets: testCase.sslSecrets}}
+		got := checkSSLSecrets(spec)
+		require.Equal(t, testCase.expected, got, "testName", testCase.testName)
+	}
+}
+
+func TestCheckListenersSASL(t *testing.T) {
+	internalPath := field.NewPath("spec").Child("listenersConfig").Child("internalListeners")
+	externalPath := field.NewPath("spec").Child("listenersConfig").Child("externalListeners")
+	plain := &v1beta1.PlainSASLConfig{UsersSecret: "kafka-users", BrokerUser: "broker"}
+	oauthBearer := &v1beta1.OAuthBearerSASLConfig{JWKSEndpointURL: "https://idp.example.com/jwks"}
+	testCases := []struct {
+		testName          string
+		internalListeners []v1beta1.InternalListenerConfig
+		externalListeners []v1beta1.ExternalListenerConfig
+		expected          field.ErrorList
+	}{
+		{
+			testName: "valid SASL listeners",
+			internalListeners: []v1beta1.InternalListenerConfig{{
+				CommonListenerSpec: v1beta1.CommonListenerSpec{
+					Name: "internal",
+					Type: v1beta1.SecurityProtocolSaslSSL,
+					SASL: &v1beta1.SASLConfig{
+						Mechanisms: []v1beta1.SASLMechanism{v1beta1.SASLMechanismScramSHA512, v1beta1.SASLMechanismPlain},
+						Plain:      plain,
+						Scram:      &v1beta1.ScramSASLConfig{BrokerCredentialsSecret: "kafka-broker"},
+					},
+				},
+				UsedForInnerBrokerCommunication: true,
+			}},
+			externalListeners: []v1beta1.ExternalListenerConfig{{
+				CommonListenerSpec: v1beta1.CommonListenerSpec{
+					Name: "external",
+					Type: v1beta1.SecurityProtocolSaslPlaintext,
+					SASL: &v1beta1.SASLConfig{
+						Mechanisms:  []v1beta1.SASLMechanism{v1beta1.SASLMechanismOAuthBearer},
+						OAuthBearer: oauthBearer,
+					},
+				},
+			}},
+		},
+		{
+			testName: "sasl on ssl listener",
+			externalListeners: []v1beta1.ExternalListenerConfig{{
+				CommonListenerSpec: v1beta1.CommonListenerSpec{
+					Name: "external",
+					Type: v1beta1.SecurityProtocolSSL,
+					SASL: &v1beta1.SASLConfig{Mechanisms: []v1beta1.SASLMechanism{v1beta1.SASLMechanismScramSHA256}},
+				},
+			}},
+			expected: append(field.ErrorList{},
+				field.Forbidden(externalPath.Index(0).Child("sasl"), "sasl can only be set for sasl_plaintext and sasl_ssl listeners")),
+		},
+		{
+			testName: "mechanism configuration mismatch",
+			externalListeners: []v1beta1.ExternalListenerConfig{{
+				CommonListenerSpec: v1beta1.CommonListenerSpec{
+					Name: "external",
+					Type: v1beta1.SecurityProtocolSaslSSL,
+					SASL: &v1beta1.SASLConfig{
+						Mechanisms:           []v1beta1.SASLMechanism{v1beta1.SASLMechanismGSSAPI, v1beta1.SASLMechanismGSSAPI},
+						InterBrokerMechanism: v1beta1.SASLMechanismPlain,
+						Plain:                plain,
+						OAuthBearer:          oauthBearer,
+					},
+				},
+			}},
+			expected: append(field.ErrorList{},
+				field.Duplicate(externalPath.Index(0).Child("sasl").Child("mechanisms").Index(1), v1beta1.SASLMechanismGSSAPI),
+				field.Forbidden(externalPath.Index(0).Child("sasl").Child("plain"), "plain can only be set when PLAIN is enabled"),
+				field.Forbidden(externalPath.Index(0).Child("sasl").Child("oauthBearer"), "oauthBearer can only be set when OAUTHBEARER is enabled"),
+				field.Required(externalPath.Index(0).Child("sasl").Child("gssapi"), "gssapi must be set when GSSAPI is enabled"),
+				field.Invalid(externalPath.Index(0).Child("sasl").Child("interBrokerMechanism"), v1beta1.SASLMechanismPlain,
+					"the inter broker mechanism must be enabled on the listener")),
+		},
+		{
+			testName: "inter broker mechanisms without broker credentials",
+			internalListeners: []v1beta1.InternalListenerConfig{
+				{
+					CommonListenerSpec: v1beta1.CommonListenerSpec{
+						Name: "internal",
+						Type: v1beta1.SecurityProtocolSaslPlaintext,
+						SASL: &v1beta1.SASLConfig{Mechanisms: []v1beta1.SASLMechanism{v1beta1.SASLMechanismScramSHA256}},
+					},
+					UsedForInnerBrokerCommunication: true,
+				},
+				{
+					CommonListenerSpec: v1beta1.CommonListenerSpec{
+						Name: "controller",
+						Type: v1beta1.SecurityProtocolSaslPlaintext,
+						SASL: &v1beta1.SASLConfig{
+							Mechanisms:  []v1beta1.SASLMechanism{v1beta1.SASLMechanismOAuthBearer},
+							OAuthBearer: oauthBearer,
+						},
+					},
+					UsedForControllerCommunication: true,
+				},
+			},
+			expected: append(field.ErrorList{},
+				field.Required(internalPath.Index(0).Child("sasl").Child("scram").Child("brokerCredentialsSecret"),
+					"brokerCredentialsSecret must be set when SCRAM-SHA-256 is used for inter broker communication"),
+				field.Invalid(internalPath.Index(1).Child("sasl").Child("interBrokerMechanism"), v1beta1.SASLMechanismOAuthBearer,
+					"OAUTHBEARER can not be used for inter broker communication"),
+				field.Invalid(internalPath, []v1beta1.SASLMechanism{v1beta1.SASLMechanismScramSHA256, v1beta1.SASLMechanismOAuthBearer},
+					"the inter broker and the control plane listener must use the same inter broker SASL mechanism")),
+		},
+	}
+
+	for _, testCase := range testCases {
+		spec := &v1beta1.KafkaClusterSpec{ListenersConfig: v1beta1.ListenersConfig{
+			InternalListeners: testCase.internalListeners,
+			ExternalListeners: testCase.externalListeners,
+		}}
+		got := checkListenersSASL(spec)
+		require.Equal(t, testCase.expected, got, "testName", testCase.testName)
+	}
+}
+
+func TestCheckClientSASL(t *testing.T) {
+	clientSASLPath := field.NewPath("spec").Child("clientSASL")
+	saslListener := v1beta1.InternalListenerConfig{
+		CommonListenerSpec:              v1beta1.CommonListenerSpec{Name: "internal", Type: v1beta1.SecurityProtocolSaslSSL},
+		UsedForInnerBrokerCommunication: true,
+	}
+	controllerListener := v1beta1.InternalListenerConfig{
+		CommonListenerSpec:             v1beta1.CommonListenerSpec{Name: "controller", Type: v1beta1.SecurityProtocolPlaintext},
+		UsedForControllerCommunication: true,
+	}
+	plaintextListener := v1beta1.InternalListenerConfig{
+		CommonListenerSpec: v1beta1.CommonListenerSpec{Name: "plaintext", Type: v1beta1.SecurityProtocolPlaintext},
+	}
+	testCases := []struct {
+		testName          string
+		internalListeners []v1beta1.InternalListenerConfig
+		clientSASL        *v1beta1.ClientSASLConfig
+		expected          field.ErrorList
+	}{
+		{
+			testName:          "non SASL listener available",
+			internalListeners: []v1beta1.InternalListenerConfig{saslListener, plaintextListener},
+		},
+		{
+			testName:          "SASL only listeners without client SASL",
+			internalListeners: []v1beta1.InternalListenerConfig{controllerListener, saslListener},
+			expected: append(field.ErrorList{},
+				field.Required(clientSASLPath, "clientSASL must be set as all internal listeners koperator can connect through use SASL")),
+		},
+		{
+			testName:          "SCRAM client SASL",
+			internalListeners: []v1beta1.InternalListenerConfig{saslListener},
+			clientSASL:        &v1beta1.ClientSASLConfig{Mechanism: v1beta1.SASLMechanismScramSHA256, CredentialsSecret: "koperator"},
+		},
+		{
+			testName:          "unsupported client SASL mechanism",
+			internalListeners: []v1beta1.InternalListenerConfig{saslListener},
+			clientSASL:        &v1beta1.ClientSASLConfig{Mechanism: v1beta1.SASLMechanismGSSAPI, CredentialsSecret: "koperator"},
+			expected: append(field.ErrorList{},
+				field.NotSupported(clientSASLPath.Child("mechanism"), v1beta1.SASLMechanismGSSAPI, []string{"SCRAM-SHA-256", "SCRAM-SHA-512", "OAUTHBEARER"})),
+		},
+		{
+			testName:          "OAUTHBEARER client SASL without token endpoint",
+			internalListeners: []v1beta1.InternalListenerConfig{saslListener},
+			clientSASL:        &v1beta1.ClientSASLConfig{Mechanism: v1beta1.SASLMechanismOAuthBearer, CredentialsSecret: "koperator"},
+			expected: append(field.ErrorList{},
+				field.Required(clientSASLPath.Child("tokenEndpointURL"), "tokenEndpointURL is required for the OAUTHBEARER mechanism")),
+		},
+		{
+			testName:          "SCRAM client SASL with token endpoint",
+			internalListeners: []v1beta1.InternalListenerConfig{saslListener},
+			clientSASL: &v1beta1.ClientSASLConfig{
+				Mechanism:         v1beta1.SASLMechanismScramSHA512,
+				CredentialsSecret: "koperator",
+				TokenEndpointURL:  "https://idp.example.com/token",
+			},
+			expected: append(field.ErrorList{},
+				field.Forbidden(clientSASLPath.Child("tokenEndpointURL"), "tokenEndpointURL can only be set for the OAUTHBEARER mechanism")),
+		},
+	}
+
+	for _, testCase := range testCases {
+		spec := &v1beta1.KafkaClusterSpec{
+			ListenersConfig: v1beta1.ListenersConfig{InternalListeners: testCase.internalListeners},
+			ClientSASL:      testCase.clientSASL,
+		}
+		got := checkClientSASL(spec)
+		require.Equal(t, testCase.expected, got, "testName", testCase.testName)
+	}
+}
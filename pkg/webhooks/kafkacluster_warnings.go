@@ -0,0 +1,105 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	banzaicloudv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
+	"github.com/banzaicloud/koperator/pkg/util/kafka"
+)
+
+// configChangeWarningHandler decorates the KafkaCluster validating webhook with a warning
+// when the admitted update changes configurations which require the restart of brokers
+type configChangeWarningHandler struct {
+	handler admission.Handler
+	decoder *admission.Decoder
+	log     logr.Logger
+}
+
+// WithConfigChangeWarnings adds a warning to the admitted KafkaCluster updates which roll brokers due to configuration changes
+func WithConfigChangeWarnings(webhook *admission.Webhook, log logr.Logger) *admission.Webhook {
+	webhook.Handler = &configChangeWarningHandler{
+		handler: webhook.Handler,
+		log:     log,
+	}
+	return webhook
+}
+
+// InjectDecoder injects the decoder into the handler and into the decorated handler
+func (h *configChangeWarningHandler) InjectDecoder(decoder *admission.Decoder) error {
+	h.decoder = decoder
+	_, err := admission.InjectDecoderInto(decoder, h.handler)
+	return err
+}
+
+// Handle handles admission requests
+func (h *configChangeWarningHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	response := h.handler.Handle(ctx, req)
+	if !response.Allowed || req.Operation != admissionv1.Update || h.decoder == nil {
+		return response
+	}
+
+	var kafkaClusterOld, kafkaClusterNew banzaicloudv1beta1.KafkaCluster
+	if err := h.decoder.DecodeRaw(req.OldObject, &kafkaClusterOld); err != nil {
+		h.log.Error(err, "could not decode old KafkaCluster")
+		return response
+	}
+	if err := h.decoder.DecodeRaw(req.Object, &kafkaClusterNew); err != nil {
+		h.log.Error(err, "could not decode KafkaCluster")
+		return response
+	}
+
+	return response.WithWarnings(configChangeWarnings(&kafkaClusterOld, &kafkaClusterNew, h.log)...)
+}
+
+// configChangeWarnings returns a warning when the read-only configuration of existing brokers changes in a way
+// which can not be applied dynamically, so the brokers are going to be restarted
+func configChangeWarnings(kafkaClusterOld, kafkaClusterNew *banzaicloudv1beta1.KafkaCluster, log logr.Logger) []string {
+	existingBrokers := make(map[int32]struct{}, len(kafkaClusterOld.Spec.Brokers))
+	for _, broker := range kafkaClusterOld.Spec.Brokers {
+		existingBrokers[broker.Id] = struct{}{}
+	}
+
+	var rollingBrokers []string
+	for _, broker := range kafkaClusterNew.Spec.Brokers {
+		if _, ok := existingBrokers[broker.Id]; !ok {
+			continue
+		}
+		configChanges := kafka.GetBrokerConfigChanges(
+			kafka.GetBrokerReadOnlyConfig(broker.Id, kafkaClusterOld, log),
+			kafka.GetBrokerReadOnlyConfig(broker.Id, kafkaClusterNew, log))
+		for _, configChange := range configChanges {
+			if configChange.Type == banzaicloudv1beta1.ConfigChangeRestartRequired {
+				rollingBrokers = append(rollingBrokers, strconv.Itoa(int(broker.Id)))
+				break
+			}
+		}
+	}
+
+	if len(rollingBrokers) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("the configuration change requires a rolling restart of %d broker(s): %s",
+		len(rollingBrokers), strings.Join(rollingBrokers, ", "))}
+}
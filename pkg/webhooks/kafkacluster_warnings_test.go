@@ -0,0 +1,82 @@
+// Copyright © 2023 Cisco Systems, Inc. and/or its affiliates
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/koperator/api/v1beta1"
+)
+
+func TestConfigChangeWarnings(t *testing.T) {
+	brokers := []v1beta1.Broker{{Id: 0}, {Id: 1}, {Id: 2}}
+	testCases := []struct {
+		testName string
+		specOld  v1beta1.KafkaClusterSpec
+		specNew  v1beta1.KafkaClusterSpec
+		expected []string
+	}{
+		{
+			testName: "configuration did not change",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: brokers, ReadOnlyConfig: "auto.create.topics.enable=false"},
+			specNew:  v1beta1.KafkaClusterSpec{Brokers: brokers, ReadOnlyConfig: "auto.create.topics.enable=false"},
+			expected: nil,
+		},
+		{
+			testName: "cluster read-only configuration changed",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: brokers, ReadOnlyConfig: "auto.create.topics.enable=false"},
+			specNew:  v1beta1.KafkaClusterSpec{Brokers: brokers, ReadOnlyConfig: "auto.create.topics.enable=true"},
+			expected: []string{"the configuration change requires a rolling restart of 3 broker(s): 0, 1, 2"},
+		},
+		{
+			testName: "broker read-only configuration changed",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: brokers},
+			specNew: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1, ReadOnlyConfig: "num.partitions=3"}, {Id: 2}},
+			},
+			expected: []string{"the configuration change requires a rolling restart of 1 broker(s): 1"},
+		},
+		{
+			testName: "overridden cluster read-only configuration changed",
+			specOld: v1beta1.KafkaClusterSpec{
+				Brokers:        []v1beta1.Broker{{Id: 0, ReadOnlyConfig: "num.partitions=3"}, {Id: 1}},
+				ReadOnlyConfig: "num.partitions=1",
+			},
+			specNew: v1beta1.KafkaClusterSpec{
+				Brokers:        []v1beta1.Broker{{Id: 0, ReadOnlyConfig: "num.partitions=3"}, {Id: 1}},
+				ReadOnlyConfig: "num.partitions=2",
+			},
+			expected: []string{"the configuration change requires a rolling restart of 1 broker(s): 1"},
+		},
+		{
+			testName: "new broker added",
+			specOld:  v1beta1.KafkaClusterSpec{Brokers: brokers[:2]},
+			specNew: v1beta1.KafkaClusterSpec{
+				Brokers: []v1beta1.Broker{{Id: 0}, {Id: 1}, {Id: 2, ReadOnlyConfig: "num.partitions=3"}},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			got := configChangeWarnings(&v1beta1.KafkaCluster{Spec: testCase.specOld}, &v1beta1.KafkaCluster{Spec: testCase.specNew}, logr.Discard())
+			require.Equal(t, testCase.expected, got)
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	banzaicloudv1beta1 "github.com/banzaicloud/koperator/api/v1beta1"
 	"github.com/banzaicloud/koperator/pkg/k8sutil"
 	"github.com/banzaicloud/koperator/pkg/kafkaclient"
+	"github.com/banzaicloud/koperator/pkg/kafkaconfig"
 	"github.com/banzaicloud/koperator/pkg/util"
 )
 
@@ -127,6 +128,14 @@ func (s *KafkaTopicValidator) validateKafkaTopic(ctx context.Context, log logr.L
 		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("clusterRef").Child("name"), clusterName, logMsg))
 	}
 
+	if topic.Spec.IsRemoteStorageEnabled() && !cluster.Spec.IsTieredStorageEnabled() {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("remoteStorage"), topic.Spec.RemoteStorage, tieredStorageNotEnabledErrMsg))
+	}
+
+	for _, violation := range kafkaconfig.ForCluster(cluster).Validate(kafkaconfig.SectionTopic, topic.Spec.GetConfig()) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("config"), violation.Key, violation.Message))
+	}
+
 	fieldErr, err := s.checkExistingKafkaTopicCRs(ctx, clusterNamespace, topic)
 	if err != nil {
 		return nil, err
@@ -186,7 +195,7 @@ func (s *KafkaTopicValidator) checkKafka(ctx context.Context, topic *banzaicloud
 						fmt.Sprintf(`When creating KafkaTopic CR for existing topic, initially its replication factor must be the same as what the existing kafka topic has (given: %v present: %v)`, topic.Spec.ReplicationFactor, existing.ReplicationFactor)))
 				}
 
-				if diff := cmp.Diff(existing.ConfigEntries, util.MapStringStringPointer(topic.Spec.Config), cmpopts.EquateEmpty()); diff != "" {
+				if diff := cmp.Diff(existing.ConfigEntries, util.MapStringStringPointer(topic.Spec.GetConfig()), cmpopts.EquateEmpty()); diff != "" {
 					allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("config"), topic.Spec.Partitions,
 						fmt.Sprintf(`When creating KafkaTopic CR for existing topic, initially its configuration must be the same as the existing kafka topic configuration.
 						Difference: %s`, diff)))
@@ -359,3 +359,94 @@ func TestValidateTopic(t *testing.T) {
 		t.Error("Expected not allowed for reason: kafka does not support changing the replication factor")
 	}
 }
+
+func TestValidateTopicRemoteStorage(t *testing.T) {
+	topic := newMockTopic()
+	topic.Spec.Partitions = 2
+	topic.Spec.ReplicationFactor = 1
+	topic.Spec.RemoteStorage = &v1alpha1.TopicRemoteStorage{Enabled: true}
+
+	cluster := newMockCluster()
+	cluster.Spec.Brokers = []v1beta1.Broker{{Id: 0}}
+	client, _, returnMockedKafkaClient := newMockClients(cluster)
+	if err := client.Create(context.TODO(), cluster); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	kafkaTopicValidator := KafkaTopicValidator{
+		Client:              client,
+		NewKafkaFromCluster: returnMockedKafkaClient,
+	}
+
+	// remote storage is rejected when tiered storage is not enabled on the cluster
+	fieldErrorList, err := kafkaTopicValidator.validateKafkaTopic(context.Background(), logr.Discard(), topic)
+	if err != nil {
+		t.Errorf("err should be nil, got: %s", err)
+	}
+	if len(fieldErrorList) != 1 {
+		t.Errorf("Expected not allowed due to tiered storage not enabled, got %d errors", len(fieldErrorList))
+	} else if !strings.Contains(fieldErrorList.ToAggregate().Error(), tieredStorageNotEnabledErrMsg) {
+		t.Errorf("Expected not allowed for reason: %s", tieredStorageNotEnabledErrMsg)
+	}
+
+	cluster.Spec.TieredStorage = &v1beta1.TieredStorageConfig{
+		RemoteStorageManagerClassName: "io.aiven.kafka.tieredstorage.RemoteStorageManager",
+		PluginImage:                   "tiered-storage-plugin:latest",
+	}
+	if err := client.Update(context.TODO(), cluster); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	fieldErrorList, err = kafkaTopicValidator.validateKafkaTopic(context.Background(), logr.Discard(), topic)
+	if err != nil {
+		t.Errorf("err should be nil, got: %s", err)
+	}
+	if len(fieldErrorList) != 0 {
+		t.Errorf("Expected allowed, got: %s", fieldErrorList.ToAggregate())
+	}
+}
+
+func TestValidateTopicConfig(t *testing.T) {
+	topic := newMockTopic()
+	topic.Spec.Partitions = 2
+	topic.Spec.ReplicationFactor = 1
+	topic.Spec.Config = map[string]string{
+		"cleanup.policy":   "compact",
+		"log.retention.ms": "1000",
+		"retention.m":      "1000",
+	}
+
+	cluster := newMockCluster()
+	cluster.Spec.Brokers = []v1beta1.Broker{{Id: 0}}
+	client, _, returnMockedKafkaClient := newMockClients(cluster)
+	if err := client.Create(context.TODO(), cluster); err != nil {
+		t.Fatal("Expected no error, got:", err)
+	}
+
+	kafkaTopicValidator := KafkaTopicValidator{
+		Client:              client,
+		NewKafkaFromCluster: returnMockedKafkaClient,
+	}
+
+	fieldErrorList, err := kafkaTopicValidator.validateKafkaTopic(context.Background(), logr.Discard(), topic)
+	if err != nil {
+		t.Errorf("err should be nil, got: %s", err)
+	}
+	if len(fieldErrorList) != 2 {
+		t.Fatalf("Expected not allowed due to invalid topic configurations, got %d errors", len(fieldErrorList))
+	}
+	for _, expected := range []string{"use retention.ms instead", "did you mean retention.ms?"} {
+		if !strings.Contains(fieldErrorList.ToAggregate().Error(), expected) {
+			t.Errorf("Expected not allowed for reason: %s, got: %s", expected, fieldErrorList.ToAggregate())
+		}
+	}
+
+	topic.Spec.Config = map[string]string{"cleanup.policy": "compact", "retention.ms": "1000"}
+	fieldErrorList, err = kafkaTopicValidator.validateKafkaTopic(context.Background(), logr.Discard(), topic)
+	if err != nil {
+		t.Errorf("err should be nil, got: %s", err)
+	}
+	if len(fieldErrorList) != 0 {
+		t.Errorf("Expected allowed, got: %s", fieldErrorList.ToAggregate())
+	}
+}
@@ -285,8 +285,9 @@ func (p *Properties) String() string {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
 
-	for _, key := range p.Keys() {
-		if prop, found := p.Get(key); found {
+	for _, key := range p.unsafeKeys() {
+		if prop, found := p.properties[key]; found {
+			props.WriteString(prop.commentString())
 			props.WriteString(fmt.Sprintf("%s\n", prop))
 		}
 	}
@@ -389,13 +390,53 @@ func (d DiffResult) Keys() []string {
 	return keys
 }
 
+// Added returns the Property objects which are only present in the compared Properties ordered by their keys.
+func (d DiffResult) Added() []Property {
+	var added []Property
+	for _, k := range d.Keys() {
+		if diff := d[k]; !diff[0].IsValid() && diff[1].IsValid() {
+			added = append(added, diff[1])
+		}
+	}
+	return added
+}
+
+// Removed returns the Property objects which are missing from the compared Properties ordered by their keys.
+func (d DiffResult) Removed() []Property {
+	var removed []Property
+	for _, k := range d.Keys() {
+		if diff := d[k]; diff[0].IsValid() && !diff[1].IsValid() {
+			removed = append(removed, diff[0])
+		}
+	}
+	return removed
+}
+
+// Changed returns the pairs of the original and the compared Property objects with different values
+// ordered by their keys.
+func (d DiffResult) Changed() [][2]Property {
+	var changed [][2]Property
+	for _, k := range d.Keys() {
+		if diff := d[k]; diff[0].IsValid() && diff[1].IsValid() {
+			changed = append(changed, diff)
+		}
+	}
+	return changed
+}
+
 // String returns a human readable representation of DiffResult.
 func (d DiffResult) String() string {
 	var s strings.Builder
 	for _, k := range d.Keys() {
 		diff, ok := d[k]
-		if ok {
-			s.WriteString(fmt.Sprintf("- %s\n+ %s\n", diff[0], diff[1]))
+		if !ok {
+			continue
+		}
+		if diff[0].IsValid() {
+			s.WriteString(fmt.Sprintf("- %s\n", diff[0]))
+		}
+		if diff[1].IsValid() {
+			s.WriteString(fmt.Sprintf("+ %s\n", diff[1]))
 		}
 	}
 	return s.String()
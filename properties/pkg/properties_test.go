@@ -674,12 +674,36 @@ func TestProperties_String(t *testing.T) {
 	t.Run("Convert to string", func(t *testing.T) {
 		g := NewGomegaWithT(t)
 
-		expectedString := `test.key=test.value
+		expectedString := `# this is a comment line
+test.key=test.value
+# this is a comment line
 test.key2=test.value2
+# this is a comment line
 test.key3=test.value3
 `
 		g.Expect(fmt.Sprint(p)).Should(Equal(expectedString))
 	})
+
+	t.Run("Escape keys and values", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		p := NewProperties()
+		p.put(Property{"test key:1", " value with leading space", ""})
+		p.put(Property{"test.key2", "C:\\path\twith\ttabs", ""})
+		p.put(Property{"test.key3", "#not a comment", ""})
+		p.put(Property{"test.key4", "multi\nline", ""})
+		p.put(Property{"test.key5", "caf\u00e9", ""})
+		p.put(Property{"test.key6", "listener://host:9092,key=value", ""})
+
+		expectedString := `test\ key\:1=\ value with leading space
+test.key2=C:\\path\twith\ttabs
+test.key3=\#not a comment
+test.key4=multi\nline
+test.key5=caf\u00e9
+test.key6=listener://host:9092,key=value
+`
+		g.Expect(p.String()).Should(Equal(expectedString))
+	})
 }
 
 func TestProperties_MarshalJSON(t *testing.T) {
@@ -954,4 +978,41 @@ func TestProperties_DiffMap(t *testing.T) {
 		expectedKeys := []string{"test.key", "test.key2", "test.key3"}
 		g.Expect(keys).Should(Equal(expectedKeys))
 	})
+
+	t.Run("Added, removed and changed properties", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		g.Expect(diffMap.Added()).Should(BeNil())
+		g.Expect(diffMap.Removed()).Should(Equal([]Property{
+			{"test.key2", "p1", "this is a comment line"},
+			{"test.key3", "p1", "this is a comment line"},
+		}))
+		g.Expect(diffMap.Changed()).Should(Equal([][2]Property{
+			{
+				{"test.key", "p1", "this is a comment line"},
+				{"test.key", "p2", "this is a comment line"},
+			},
+		}))
+
+		added := DiffResult{"test.key4": [2]Property{{}, {"test.key4", "p2", ""}}}
+		g.Expect(added.Added()).Should(Equal([]Property{{"test.key4", "p2", ""}}))
+		g.Expect(added.Removed()).Should(BeNil())
+		g.Expect(added.Changed()).Should(BeNil())
+	})
+
+	t.Run("String", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		diff := DiffResult{
+			"test.key":  [2]Property{{"test.key", "p1", ""}, {"test.key", "p2", ""}},
+			"test.key2": [2]Property{{"test.key2", "p1", ""}, {}},
+			"test.key3": [2]Property{{}, {"test.key3", "p2", ""}},
+		}
+		expected := `- test.key=p1
++ test.key=p2
+- test.key2=p1
++ test.key3=p2
+`
+		g.Expect(diff.String()).Should(Equal(expected))
+	})
 }
@@ -75,13 +75,33 @@ func (p *Property) Equal(t Property) bool {
 }
 
 // String implements the Stringer interface.
+// The key and the value are escaped according to the format of Java .properties files.
 func (p Property) String() string {
 	if p.IsValid() {
-		return fmt.Sprintf("%s%s%s", EscapeSeparators(p.key), DefaultSeparator, p.value)
+		return fmt.Sprintf("%s%s%s", escape(p.key, true), DefaultSeparator, escape(p.value, false))
 	}
 	return ""
 }
 
+// commentString returns the comment of the Property as comment lines of Java .properties files.
+func (p Property) commentString() string {
+	if p.comment == "" {
+		return ""
+	}
+	var comment strings.Builder
+	for _, line := range strings.Split(p.comment, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!"):
+			comment.WriteString(line)
+		default:
+			comment.WriteString("# " + line)
+		}
+		comment.WriteString("\n")
+	}
+	return comment.String()
+}
+
 // Int converts the Property value to Int64.
 func (p Property) Int() (int64, error) {
 	return strconv.ParseInt(p.value, 10, 64)
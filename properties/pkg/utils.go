@@ -16,10 +16,14 @@ package properties
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf16"
 
 	"emperror.dev/errors"
 )
@@ -178,18 +182,25 @@ type Loader struct {
 }
 
 func (l *Loader) Load(r io.Reader) (*Properties, error) {
-	l.load(r)
+	if err := l.load(r); err != nil {
+		return nil, err
+	}
 	return l.parse()
 }
 
-func (l *Loader) load(r io.Reader) {
+func (l *Loader) load(r io.Reader) error {
 	sc := bufio.NewScanner(r)
 	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
-		l.lines = append(l.lines, line)
+		l.lines = append(l.lines, sc.Text())
 	}
+	return sc.Err()
 }
 
+// parse builds Properties from the loaded lines following the format of Java .properties files:
+// lines ending with an odd number of backslashes are continued on the next line where leading whitespaces are skipped,
+// lines starting with # or ! are comments which are attached to the following property, and escape sequences
+// (including \uXXXX) are resolved in both keys and values.
+// Unlike Java, trailing whitespaces are trimmed from every line and lines without a key-value separator are rejected.
 func (l *Loader) parse() (*Properties, error) {
 	// New Properties object.
 	newProperties := NewProperties()
@@ -197,49 +208,79 @@ func (l *Loader) parse() (*Properties, error) {
 	// Temporary store for holding chunks of multiline property
 	var property strings.Builder
 	var comment strings.Builder
+	continued := false
 
-	for _, line := range l.lines {
-		// Ignore empty lines
-		if line == "" {
-			property.Reset()
-			comment.Reset()
-			continue
+	addProperty := func() error {
+		// Parse property from the string
+		p, err := newPropertyFromString(property.String(), strings.TrimRight(comment.String(), "\n"))
+		if err != nil {
+			return err
 		}
 
-		// Comment lines start with either # or ! characters.
-		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
-			if _, err := comment.WriteString(line + "\n"); err != nil {
+		// Reset property string
+		property.Reset()
+		comment.Reset()
+		continued = false
+
+		// Add Property to Properties object
+		newProperties.Put(p)
+		return nil
+	}
+
+	for _, line := range l.lines {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case continued && line == "":
+			// Empty line ends the multiline property
+			if err := addProperty(); err != nil {
 				return nil, err
 			}
 			continue
+		case continued:
+		case line == "":
+			// Keep empty lines between comments to preserve the layout of the comment block
+			if comment.Len() > 0 {
+				comment.WriteString("\n")
+			}
+			continue
+		case strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!"):
+			// Comment lines start with either # or ! characters.
+			comment.WriteString(line + "\n")
+			continue
 		}
 
-		// Check for multiline property by looking for \ as the last character escaping the new line.
-		if strings.HasSuffix(line, "\\") {
-			if _, err := property.WriteString(strings.TrimSuffix(line, "\\")); err != nil {
-				return nil, err
-			}
+		// An odd number of backslashes at the end of the line escapes the new line.
+		if hasLineContinuation(line) {
+			property.WriteString(strings.TrimSuffix(line, string(EscapeChar)))
+			continued = true
 			continue
 		}
 
 		property.WriteString(line)
-
-		// Parse property from the string
-		p, err := newPropertyFromString(property.String(), comment.String())
-		if err != nil {
+		if err := addProperty(); err != nil {
 			return nil, err
 		}
+	}
 
-		// Reset property string
-		property.Reset()
-		comment.Reset()
-
-		// Add Property to Properties object
-		newProperties.Put(p)
+	// The last line of the document may also end with a backslash
+	if continued {
+		if err := addProperty(); err != nil {
+			return nil, err
+		}
 	}
 	return newProperties, nil
 }
 
+// hasLineContinuation returns true if the line ends with an odd number of backslashes.
+func hasLineContinuation(line string) bool {
+	backslashes := 0
+	for i := len(line) - 1; i >= 0 && line[i] == EscapeChar; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
 func NewLoader() *Loader {
 	return &Loader{}
 }
@@ -277,19 +318,144 @@ func Max(x, y int) int {
 }
 
 // Return a new Property by parsing prop string and including the provided comment string.
+// The key ends at the first unescaped '=', ':' or whitespace character, whitespaces around the separator are skipped.
 func newPropertyFromString(prop string, comment string) (Property, error) {
-	// Get the index of the separator.
-	_, idx, err := GetSeparator(prop)
-	// Return error if getting the separator resulted an error
-	// or the index of the separator is 0 which means that the property is invalid.
-	if err != nil || idx == 0 {
+	r := []rune(strings.TrimSpace(prop))
+
+	// Find the end of the key
+	keyEnd := -1
+	for idx := 0; idx < len(r); idx++ {
+		if r[idx] == EscapeChar {
+			idx++
+			continue
+		}
+		if strings.ContainsRune(Separators, r[idx]) || unicode.IsSpace(r[idx]) {
+			keyEnd = idx
+			break
+		}
+	}
+	// Return error if there is no separator or the key is empty which means that the property is invalid.
+	if keyEnd <= 0 {
 		return Property{}, errors.NewWithDetails("properties: invalid property", "property", prop)
 	}
-	// Parse the property name using the separator and remove the escaping of separator characters
-	// as we already know where the key part ends and the value part starts.
-	key := UnEscapeSeparators(strings.TrimSpace(prop[0:idx]))
-	// Parse the value part of the property.
-	value := strings.TrimSpace(prop[idx+1:])
+
+	// Skip the separator and the whitespaces around it
+	valueStart := keyEnd
+	for valueStart < len(r) && unicode.IsSpace(r[valueStart]) {
+		valueStart++
+	}
+	if valueStart < len(r) && (r[valueStart] == '=' || r[valueStart] == ':') {
+		valueStart++
+		for valueStart < len(r) && unicode.IsSpace(r[valueStart]) {
+			valueStart++
+		}
+	}
+
+	key, err := unescape(r[:keyEnd])
+	if err != nil {
+		return Property{}, errors.WrapIfWithDetails(err, "properties: invalid property key", "property", prop)
+	}
+	value, err := unescape(r[valueStart:])
+	if err != nil {
+		return Property{}, errors.WrapIfWithDetails(err, "properties: invalid property value", "property", prop)
+	}
 
 	return Property{key: key, value: value, comment: comment}, nil
 }
+
+// unescape resolves the escape sequences of Java .properties files.
+func unescape(r []rune) (string, error) {
+	var s strings.Builder
+	for idx := 0; idx < len(r); idx++ {
+		c := r[idx]
+		if c != EscapeChar {
+			s.WriteRune(c)
+			continue
+		}
+		idx++
+		if idx == len(r) {
+			break
+		}
+		switch c = r[idx]; c {
+		case 't':
+			s.WriteRune('\t')
+		case 'n':
+			s.WriteRune('\n')
+		case 'r':
+			s.WriteRune('\r')
+		case 'f':
+			s.WriteRune('\f')
+		case 'u':
+			code, err := parseUnicodeEscape(r, idx+1)
+			if err != nil {
+				return "", err
+			}
+			idx += 4
+			// Characters outside of the Basic Multilingual Plane are encoded as UTF-16 surrogate pairs
+			if utf16.IsSurrogate(code) && idx+2 < len(r) && r[idx+1] == EscapeChar && r[idx+2] == 'u' {
+				if low, err := parseUnicodeEscape(r, idx+3); err == nil {
+					if decoded := utf16.DecodeRune(code, low); decoded != unicode.ReplacementChar {
+						code = decoded
+						idx += 6
+					}
+				}
+			}
+			s.WriteRune(code)
+		default:
+			s.WriteRune(c)
+		}
+	}
+	return s.String(), nil
+}
+
+// parseUnicodeEscape parses the 4 hexadecimal digits of an \\uXXXX escape sequence starting at the given index
+func parseUnicodeEscape(r []rune, idx int) (rune, error) {
+	if idx+4 > len(r) {
+		return 0, errors.New("properties: malformed \\uXXXX encoding")
+	}
+	code, err := strconv.ParseUint(string(r[idx:idx+4]), 16, 16)
+	if err != nil {
+		return 0, errors.New("properties: malformed \\uXXXX encoding")
+	}
+	return rune(code), nil
+}
+
+// escape returns the given s string escaped according to the format of Java .properties files.
+// Keys have their separators and whitespaces escaped, values only have their leading whitespaces escaped,
+// non-ASCII characters are written as \uXXXX as Java reads .properties files with ISO 8859-1 encoding.
+func escape(s string, isKey bool) string {
+	var escaped strings.Builder
+	for idx, c := range []rune(s) {
+		switch {
+		case c == EscapeChar:
+			escaped.WriteString("\\\\")
+		case c == '\t':
+			escaped.WriteString("\\t")
+		case c == '\n':
+			escaped.WriteString("\\n")
+		case c == '\r':
+			escaped.WriteString("\\r")
+		case c == '\f':
+			escaped.WriteString("\\f")
+		case c == ' ' && (isKey || idx == 0):
+			escaped.WriteString("\\ ")
+		case isKey && (c == '=' || c == ':'):
+			escaped.WriteRune(EscapeChar)
+			escaped.WriteRune(c)
+		case idx == 0 && (c == '#' || c == '!'):
+			escaped.WriteRune(EscapeChar)
+			escaped.WriteRune(c)
+		case c < 0x20 || c > 0x7e:
+			if c > 0xffff {
+				for _, r := range utf16.Encode([]rune{c}) {
+					escaped.WriteString(fmt.Sprintf("\\u%04x", r))
+				}
+				continue
+			}
+			escaped.WriteString(fmt.Sprintf("\\u%04x", c))
+		default:
+			escaped.WriteRune(c)
+		}
+	}
+	return escaped.String()
+}
@@ -255,3 +255,117 @@ func TestNewPropertyFromString(t *testing.T) {
 		g.Expect(err).Should(HaveOccurred())
 	})
 }
+
+func TestNewFromStringJavaFormat(t *testing.T) {
+	t.Run("Line continuations", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		p, err := NewFromString(`sasl.jaas.config=org.apache.kafka.common.security.plain.PlainLoginModule required \
+    username="admin" \
+    password="admin-secret";
+fruits                           apple, banana, pear, \
+                                 cantaloupe, watermelon, \
+                                 kiwi, mango
+path=C:\\dir\\
+next.key=value
+# comment \
+not.a.comment=value
+`)
+		g.Expect(err).Should(Succeed())
+		g.Expect(p.Keys()).Should(Equal([]string{"sasl.jaas.config", "fruits", "path", "next.key", "not.a.comment"}))
+
+		v, _ := p.Get("sasl.jaas.config")
+		g.Expect(v.Value()).Should(Equal(`org.apache.kafka.common.security.plain.PlainLoginModule required username="admin" password="admin-secret";`))
+		v, _ = p.Get("fruits")
+		g.Expect(v.Value()).Should(Equal("apple, banana, pear, cantaloupe, watermelon, kiwi, mango"))
+		v, _ = p.Get("path")
+		g.Expect(v.Value()).Should(Equal(`C:\dir\`))
+	})
+
+	t.Run("Continuation ended by an empty line or the end of the document", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		p, err := NewFromString("test.key=value \\\n\ntest.key2=value2 \\")
+		g.Expect(err).Should(Succeed())
+
+		v, _ := p.Get("test.key")
+		g.Expect(v.Value()).Should(Equal("value"))
+		v, _ = p.Get("test.key2")
+		g.Expect(v.Value()).Should(Equal("value2"))
+	})
+
+	t.Run("Escape sequences", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		p, err := NewFromString(`test\ key\=1 = caf\u00e9\tand\ncrème
+emoji=\ud83d\ude00
+test.key2 : \ leading space
+test.key3 \#value`)
+		g.Expect(err).Should(Succeed())
+
+		v, _ := p.Get("test key=1")
+		g.Expect(v.Value()).Should(Equal("café\tand\ncrème"))
+		v, _ = p.Get("emoji")
+		g.Expect(v.Value()).Should(Equal("😀"))
+		v, _ = p.Get("test.key2")
+		g.Expect(v.Value()).Should(Equal(" leading space"))
+		v, _ = p.Get("test.key3")
+		g.Expect(v.Value()).Should(Equal("#value"))
+	})
+
+	t.Run("Malformed unicode escape", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		_, err := NewFromString(`test.key=\u00g1`)
+		g.Expect(err).Should(HaveOccurred())
+		_, err = NewFromString(`test.key=\u00`)
+		g.Expect(err).Should(HaveOccurred())
+	})
+
+	t.Run("Comments", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		p, err := NewFromString(`# Header
+
+# Broker settings
+! Second line
+test.key=value
+test.key2=value2
+`)
+		g.Expect(err).Should(Succeed())
+
+		v, _ := p.Get("test.key")
+		g.Expect(v.Comment()).Should(Equal("# Header\n\n# Broker settings\n! Second line"))
+		v, _ = p.Get("test.key2")
+		g.Expect(v.Comment()).Should(BeEmpty())
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+
+		document := `# Header
+
+# Broker settings
+zookeeper.connect=zk\:2181/kafka
+test\ key=\ value with\ttab
+listeners=INTERNAL://:29092,CONTROLLER://:29093
+# JAAS
+sasl.jaas.config=org.apache.kafka.common.security.plain.PlainLoginModule required username\="admin";
+unicode=caf\u00e9
+`
+		p, err := NewFromString(document)
+		g.Expect(err).Should(Succeed())
+
+		p2, err := NewFromString(p.String())
+		g.Expect(err).Should(Succeed())
+		g.Expect(p2.Equal(p)).Should(BeTrue())
+		g.Expect(p2.Keys()).Should(Equal(p.Keys()))
+		g.Expect(p2.String()).Should(Equal(p.String()))
+
+		v, _ := p2.Get("test key")
+		g.Expect(v.Value()).Should(Equal(" value with\ttab"))
+		v, _ = p2.Get("zookeeper.connect")
+		g.Expect(v.Value()).Should(Equal("zk:2181/kafka"))
+		g.Expect(v.Comment()).Should(Equal("# Header\n\n# Broker settings"))
+	})
+}
@@ -106,6 +106,8 @@ func koperatorCRDs() []string {
 		"kafkaclusters.kafka.banzaicloud.io",
 		"kafkausers.kafka.banzaicloud.io",
 		"cruisecontroloperations.kafka.banzaicloud.io",
+		"kafkaalertactions.kafka.banzaicloud.io",
+		"kafkaclusterbackups.kafka.banzaicloud.io",
 	}
 }
 
@@ -116,6 +118,8 @@ func koperatorRelatedResourceKinds() []string {
 		"kafkaclusters.kafka.banzaicloud.io",
 		"kafkausers.kafka.banzaicloud.io",
 		"cruisecontroloperations.kafka.banzaicloud.io",
+		"kafkaalertactions.kafka.banzaicloud.io",
+		"kafkaclusterbackups.kafka.banzaicloud.io",
 		"istiomeshgateways.servicemesh.cisco.com",
 		"virtualservices.networking.istio.io",
 		"gateways.networking.istio.io",
@@ -45,6 +45,8 @@ var (
 			Namespace:    "kafka",
 			LocalCRDSubpaths: []string{
 				"crds/cruisecontroloperations.yaml",
+				"crds/kafkaalertactions.yaml",
+				"crds/kafkaclusterbackups.yaml",
 				"crds/kafkaclusters.yaml",
 				"crds/kafkatopics.yaml",
 				"crds/kafkausers.yaml",
@@ -67,6 +67,8 @@ func (helmDescriptor *helmDescriptor) crdPath() (string, error) { //nolint:unuse
 		helmDescriptor.ReleaseName,
 		[]string{
 			"crds/cruisecontroloperations.yaml",
+			"crds/kafkaalertactions.yaml",
+			"crds/kafkaclusterbackups.yaml",
 			"crds/kafkaclusters.yaml",
 			"crds/kafkatopics.yaml",
 			"crds/kafkausers.yaml",